@@ -22,6 +22,7 @@ func adminCommands() []*cobra.Command {
 		adminMigrations(),
 		adminPlugins(),
 		adminBroadcasts(),
+		adminQuotas(),
 		adminErrors(),
 		adminCurl(),
 	}
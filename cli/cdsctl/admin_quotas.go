@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminQuotasCmd = cli.Command{
+	Name:  "quotas",
+	Short: "Manage CDS quotas on concurrent jobs",
+}
+
+func adminQuotas() *cobra.Command {
+	return cli.NewCommand(adminQuotasCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminQuotaListCmd, adminQuotaListRun, nil),
+		cli.NewCommand(adminQuotaCreateCmd, adminQuotaCreateRun, nil),
+		cli.NewCommand(adminQuotaUpdateCmd, adminQuotaUpdateRun, nil),
+		cli.NewCommand(adminQuotaDeleteCmd, adminQuotaDeleteRun, nil),
+	})
+}
+
+var adminQuotaLimitFlags = []cli.Flag{
+	{
+		Name:    "max-jobs",
+		Usage:   "Maximum number of concurrent jobs, 0 means no limit",
+		IsValid: isPositiveInt,
+	},
+	{
+		Name:    "max-workers-by-model",
+		Usage:   "Maximum number of concurrent workers for each worker model, 0 means no limit",
+		IsValid: isPositiveInt,
+	},
+	{
+		Name:    "max-memory",
+		Usage:   "Maximum memory in MB used by concurrent jobs, 0 means no limit",
+		IsValid: isPositiveInt,
+	},
+}
+
+func isPositiveInt(s string) bool {
+	if s == "" {
+		return true
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	return err == nil && i >= 0
+}
+
+func setQuotaLimits(v cli.Values, q *sdk.Quota) {
+	for flag, value := range map[string]*int64{
+		"max-jobs":             &q.MaxJobs,
+		"max-workers-by-model": &q.MaxWorkersByModel,
+		"max-memory":           &q.MaxMemory,
+	} {
+		if s := v.GetString(flag); s != "" {
+			*value, _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+}
+
+var adminQuotaListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS quotas",
+}
+
+func adminQuotaListRun(v cli.Values) (cli.ListResult, error) {
+	qs, err := client.AdminQuotaList()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(qs), nil
+}
+
+var adminQuotaCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create a quota on a project or on a group",
+	Flags: append([]cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Key of the project limited by the quota",
+		},
+		{
+			Name:  "group",
+			Usage: "Name of the group limited by the quota, it applies to all the projects where the group has write permission",
+		},
+	}, adminQuotaLimitFlags...),
+	Example: `limit the project MYPROJ to 10 concurrent jobs:
+
+	cdsctl admin quotas create --project MYPROJ --max-jobs 10
+
+limit the projects of the group my-team to 20GB of memory and 5 workers by model:
+
+	cdsctl admin quotas create --group my-team --max-memory 20480 --max-workers-by-model 5
+	`,
+	Aliases: []string{"add"},
+}
+
+func adminQuotaCreateRun(v cli.Values) error {
+	q := sdk.Quota{
+		ProjectKey: v.GetString("project"),
+		GroupName:  v.GetString("group"),
+	}
+	setQuotaLimits(v, &q)
+	if err := q.IsValid(); err != nil {
+		return err
+	}
+	if err := client.AdminQuotaCreate(&q); err != nil {
+		return err
+	}
+	fmt.Printf("Quota %d created on %s\n", q.ID, q)
+	return nil
+}
+
+var adminQuotaUpdateCmd = cli.Command{
+	Name:  "update",
+	Short: "Update the limits of a quota, limits that are not given are unchanged",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+	Flags:   adminQuotaLimitFlags,
+	Example: `cdsctl admin quotas update 1 --max-jobs 0`,
+}
+
+func adminQuotaUpdateRun(v cli.Values) error {
+	id, err := v.GetInt64("id")
+	if err != nil {
+		return err
+	}
+	qs, err := client.AdminQuotaList()
+	if err != nil {
+		return err
+	}
+	for i := range qs {
+		if qs[i].ID != id {
+			continue
+		}
+		setQuotaLimits(v, &qs[i])
+		return client.AdminQuotaUpdate(&qs[i])
+	}
+	return fmt.Errorf("quota %d not found", id)
+}
+
+var adminQuotaDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS quota",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func adminQuotaDeleteRun(v cli.Values) error {
+	id, err := v.GetInt64("id")
+	if err != nil {
+		return err
+	}
+	return client.AdminQuotaDelete(id)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+)
+
+var projectQuotaCmd = cli.Command{
+	Name:  "quota",
+	Short: "Show the usage of the quotas that apply to a CDS project",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+type projectQuotaUsage struct {
+	Target            string `cli:"target,key"`
+	Jobs              int64  `cli:"jobs"`
+	MaxJobs           int64  `cli:"max_jobs"`
+	Memory            int64  `cli:"memory"`
+	MaxMemory         int64  `cli:"max_memory"`
+	WorkersByModel    string `cli:"workers_by_model"`
+	MaxWorkersByModel int64  `cli:"max_workers_by_model"`
+}
+
+func projectQuota() *cobra.Command {
+	return cli.NewListCommand(projectQuotaCmd, projectQuotaRun, nil, withAllCommandModifiers()...)
+}
+
+func projectQuotaRun(v cli.Values) (cli.ListResult, error) {
+	us, err := client.ProjectQuotaUsage(v.GetString(_ProjectKey))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]projectQuotaUsage, len(us))
+	for i, u := range us {
+		models := make([]string, 0, len(u.WorkersByModel))
+		for m, n := range u.WorkersByModel {
+			models = append(models, m+"="+strconv.FormatInt(n, 10))
+		}
+		sort.Strings(models)
+		res[i] = projectQuotaUsage{
+			Target:            u.Quota.String(),
+			Jobs:              u.Jobs,
+			MaxJobs:           u.Quota.MaxJobs,
+			Memory:            u.Memory,
+			MaxMemory:         u.Quota.MaxMemory,
+			WorkersByModel:    strings.Join(models, ","),
+			MaxWorkersByModel: u.Quota.MaxWorkersByModel,
+		}
+	}
+	return cli.AsListResult(res), nil
+}
@@ -55,6 +55,7 @@ type jobCLI struct {
 	Duration     time.Duration `cli:"-"`
 	BookedBy     string        `cli:"booked_by"`
 	TriggeredBy  string        `cli:"triggered_by"`
+	Priority     int64         `cli:"priority"`
 }
 
 func getJobQueue(status ...string) ([]jobCLI, error) {
@@ -82,6 +83,7 @@ func getJobQueue(status ...string) ([]jobCLI, error) {
 			Duration:     time.Since(jr.Queued),
 			BookedBy:     jr.BookedBy.Name,
 			TriggeredBy:  getVarsInPbj("cds.triggered_by.username", jr.Parameters),
+			Priority:     jr.Priority,
 		}
 	}
 
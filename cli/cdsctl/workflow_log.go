@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -17,7 +21,13 @@ var workflowLogCmd = cli.Command{
 	Name:    "logs",
 	Aliases: []string{"log"},
 	Short:   "Manage CDS Workflow Run Logs",
-	Long: `Download logs from a workflow run.
+	Long: `Print or download logs from a workflow run.
+
+	# print all logs on latest run
+	$ cdsctl workflow logs KEY WF
+
+	# print new logs of run number 1 as soon as they are sent by the workers, until the end of the run
+	$ cdsctl workflow logs KEY WF 1 --follow --pattern="MyJob"
 
 	# list all logs files on latest run
 	$ cdsctl workflow logs list KEY WF
@@ -33,13 +43,40 @@ var workflowLogCmd = cli.Command{
 	# this will download file WF-1.0-pipeline.myPipeline-stage.MyStage-job.MyJob-status.Success-step.0.log
 
 `,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	OptionalArgs: []cli.Arg{
+		{
+			Name: "run-number",
+			IsValid: func(s string) bool {
+				match, _ := regexp.MatchString(`[0-9]?`, s)
+				return match
+			},
+			Weight: 1,
+		},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "pattern",
+			Usage: "Filter on log filename",
+		},
+		{
+			Name:      "follow",
+			ShortHand: "f",
+			Type:      cli.FlagBool,
+			Usage:     "Print new logs as soon as they are sent by the workers, until the end of the run",
+		},
+	},
 }
 
 func workflowLog() *cobra.Command {
-	return cli.NewCommand(workflowLogCmd, nil, []*cobra.Command{
+	return cli.NewCommand(workflowLogCmd, workflowLogRun, []*cobra.Command{
 		cli.NewCommand(workflowLogListCmd, workflowLogListRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowLogDownloadCmd, workflowLogDownloadRun, nil, withAllCommandModifiers()...),
-	})
+		cli.NewListCommand(workflowLogSearchCmd, workflowLogSearchRun, nil, withAllCommandModifiers()...),
+	}, withAllCommandModifiers()...)
 }
 
 var workflowLogListCmd = cli.Command{
@@ -96,6 +133,128 @@ func workflowLogSearchNumber(v cli.Values) (int64, error) {
 	return runNumber, nil
 }
 
+func workflowLogPattern(v cli.Values) (*regexp.Regexp, error) {
+	if v.GetString("pattern") == "" {
+		return nil, nil
+	}
+	reg, err := regexp.Compile(v.GetString("pattern"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid pattern %s: %v", v.GetString("pattern"), err)
+	}
+	return reg, nil
+}
+
+func workflowLogRun(v cli.Values) error {
+	runNumber, err := workflowLogSearchNumber(v)
+	if err != nil {
+		return err
+	}
+	reg, err := workflowLogPattern(v)
+	if err != nil {
+		return err
+	}
+
+	if v.GetBool("follow") {
+		return workflowLogFollow(v, runNumber, reg)
+	}
+
+	wr, err := client.WorkflowRunGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber)
+	if err != nil {
+		return err
+	}
+	for _, log := range workflowLogProcess(wr) {
+		if reg != nil && !reg.MatchString(log.getFilename()) {
+			continue
+		}
+		buildState, err := client.WorkflowNodeRunJobStep(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber, log.runID, log.jobID, log.stepOrder)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("==> %s <==\n%s\n", log.getFilename(), buildState.StepLogs.Val)
+	}
+	return nil
+}
+
+// workflowLogFollow streams the logs of all the steps of a workflow run until the end of the run.
+// Steps are discovered while the run is in progress, each line is prefixed by its pipeline, job and step.
+func workflowLogFollow(v cli.Values, runNumber int64, reg *regexp.Regexp) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var printMutex sync.Mutex
+	followed := map[string]bool{}
+	tick := time.NewTicker(2 * time.Second)
+	defer tick.Stop()
+	for {
+		wr, err := client.WorkflowRunGet(v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber)
+		if err != nil {
+			return err
+		}
+		for _, log := range workflowLogProcess(wr) {
+			key := fmt.Sprintf("%d-%d", log.jobID, log.stepOrder)
+			if followed[key] || (reg != nil && !reg.MatchString(log.getFilename())) {
+				continue
+			}
+			followed[key] = true
+			wg.Add(1)
+			go func(log workflowLogDetail) {
+				defer wg.Done()
+				workflowLogFollowStep(ctx, v, runNumber, log, &printMutex)
+			}(log)
+		}
+		if sdk.StatusIsTerminated(wr.Status) {
+			wg.Wait()
+			return nil
+		}
+		<-tick.C
+	}
+}
+
+// workflowLogFollowStep prints the lines of a step log until the step is terminated.
+// The stream is resumed from the last received offset if the connection is lost.
+func workflowLogFollowStep(ctx context.Context, v cli.Values, runNumber int64, log workflowLogDetail, printMutex *sync.Mutex) {
+	prefix := fmt.Sprintf("[%s/%s/%d] ", log.pipelineName, log.jobName, log.stepOrder)
+	var offset int64
+	var partial string
+	printLines := func(val string, flush bool) {
+		lines := strings.Split(partial+val, "\n")
+		partial = lines[len(lines)-1]
+		lines = lines[:len(lines)-1]
+		if flush && partial != "" {
+			lines = append(lines, partial)
+			partial = ""
+		}
+		printMutex.Lock()
+		defer printMutex.Unlock()
+		for _, l := range lines {
+			fmt.Println(prefix + l)
+		}
+	}
+
+	for ctx.Err() == nil {
+		chunks := make(chan sdk.StepLogChunk)
+		var errStream error
+		go func() {
+			errStream = client.WorkflowNodeRunJobStepLogStream(ctx, v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber, log.runID, log.jobID, log.stepOrder, offset, chunks)
+			close(chunks)
+		}()
+		var done bool
+		for c := range chunks {
+			offset = c.Offset + int64(len(c.Val))
+			printLines(c.Val, c.Done)
+			done = done || c.Done
+		}
+		if done {
+			return
+		}
+		if errStream != nil {
+			fmt.Fprintf(os.Stderr, "%sconnection lost, resuming at offset %d: %v\n", prefix, offset, errStream)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
 func workflowLogListRun(v cli.Values) error {
 	runNumber, err := workflowLogSearchNumber(v)
 	if err != nil {
@@ -221,18 +380,14 @@ func workflowLogDownloadRun(v cli.Values) error {
 	}
 	logs := workflowLogProcess(wr)
 
-	var reg *regexp.Regexp
-	if v.GetString("pattern") != "" {
-		var errp error
-		reg, errp = regexp.Compile(v.GetString("pattern"))
-		if errp != nil {
-			return fmt.Errorf("Invalid pattern %s: %v", v.GetString("pattern"), errp)
-		}
+	reg, err := workflowLogPattern(v)
+	if err != nil {
+		return err
 	}
 
 	var ok bool
 	for _, log := range logs {
-		if v.GetString("pattern") != "" && !reg.MatchString(log.getFilename()) {
+		if reg != nil && !reg.MatchString(log.getFilename()) {
 			continue
 		}
 
@@ -260,3 +415,70 @@ func workflowLogDownloadRun(v cli.Values) error {
 	}
 	return nil
 }
+
+var workflowLogSearchCmd = cli.Command{
+	Name:  "search",
+	Short: "Search a regex in the step logs of workflow runs",
+	Long: `Search a regex in the step logs of the runs of a workflow, or of all the workflows of a project that you can read.
+By default the logs of the last 24 hours are searched, the time window cannot exceed 31 days.
+The most recent step logs are searched first, the search stops after 100MB of logs or 30 seconds.
+
+	# search in the logs of the workflow WF during the last 24 hours
+	$ cdsctl workflow logs search KEY "panic: .*" --workflow WF
+
+	# search in the logs of all the workflows of the project during a time window
+	$ cdsctl workflow logs search KEY "connection refused" --from 2020-01-01T00:00:00Z --to 2020-01-08T00:00:00Z --limit 500
+
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "regex"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "workflow",
+			Usage: "Search only in the logs of given workflow",
+		},
+		{
+			Name:  "from",
+			Usage: "Start of the time window (RFC3339 date, example: 2020-01-01T00:00:00Z)",
+		},
+		{
+			Name:  "to",
+			Usage: "End of the time window (RFC3339 date), default is now",
+		},
+		{
+			Name:  "limit",
+			Usage: "Max number of lines returned",
+		},
+	},
+}
+
+func workflowLogSearchRun(v cli.Values) (cli.ListResult, error) {
+	var from, to time.Time
+	var err error
+	if v.GetString("from") != "" {
+		from, err = time.Parse(time.RFC3339, v.GetString("from"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date %s: %v", v.GetString("from"), err)
+		}
+	}
+	if v.GetString("to") != "" {
+		to, err = time.Parse(time.RFC3339, v.GetString("to"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date %s: %v", v.GetString("to"), err)
+		}
+	}
+	limit, err := v.GetInt64("limit")
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := client.WorkflowLogsSearch(v.GetString(_ProjectKey), v.GetString("workflow"), v.GetString("regex"), from, to, int(limit))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(results), nil
+}
@@ -85,6 +85,11 @@ var workflowRunManualCmd = cli.Command{
 			Usage:     "Synchronise your pipelines with your last editions. Must be used with flag run-number",
 			Type:      cli.FlagBool,
 		},
+		{
+			Name:  "dry-run",
+			Usage: "Display the nodes and jobs that would be triggered without running the workflow, use --verbose to display their parameters",
+			Type:  cli.FlagBool,
+		},
 	},
 }
 
@@ -92,6 +97,9 @@ func workflowRunManualRun(v cli.Values) error {
 	if v.GetBool("sync") && v.GetString("run-number") == "" {
 		return fmt.Errorf("Could not use flag --sync without flag --run-number")
 	}
+	if v.GetBool("sync") && v.GetBool("dry-run") {
+		return fmt.Errorf("Could not use flag --sync with flag --dry-run")
+	}
 
 	manual := sdk.WorkflowNodeRunManual{}
 	if strings.TrimSpace(v.GetString("data")) != "" {
@@ -184,6 +192,15 @@ func workflowRunManualRun(v cli.Values) error {
 		}
 	}
 
+	if v.GetBool("dry-run") {
+		plan, err := client.WorkflowRunPlan(v.GetString(_ProjectKey), v.GetString(_WorkflowName), manual, runNumber, fromNodeID)
+		if err != nil {
+			return err
+		}
+		workflowRunPrintPlan(plan, v.GetBool("verbose"))
+		return nil
+	}
+
 	w, err := client.WorkflowRunFromManual(v.GetString(_ProjectKey), v.GetString(_WorkflowName), manual, runNumber, fromNodeID)
 	if err != nil {
 		return err
@@ -213,3 +230,47 @@ func workflowRunManualRun(v cli.Values) error {
 
 	return workflowRunInteractive(v, w, configUser.URLUI)
 }
+
+func workflowRunPrintPlan(plan *sdk.WorkflowRunPlan, verbose bool) {
+	fmt.Printf("Workflow %s #%d would run as follows (jobs are supposed to succeed):\n", plan.WorkflowName, plan.Number)
+	for _, n := range plan.Nodes {
+		switch {
+		case n.Triggered:
+			fmt.Printf("- %s (%s): triggered, status %s\n", n.NodeName, n.NodeType, n.Status)
+		case n.BlockingCondition != "":
+			fmt.Printf("- %s (%s): not triggered, blocked by condition %q\n", n.NodeName, n.NodeType, n.BlockingCondition)
+		default:
+			fmt.Printf("- %s (%s): not triggered\n", n.NodeName, n.NodeType)
+		}
+		for _, c := range n.Conditions {
+			if c.Error != "" {
+				fmt.Printf("    condition %q: error %s\n", c.Condition, c.Error)
+				continue
+			}
+			fmt.Printf("    condition %q: %t\n", c.Condition, c.Result)
+		}
+		for _, j := range n.Jobs {
+			fmt.Printf("    stage %s, job %s: %s\n", j.Stage, j.Name, j.Status)
+			if j.Model != "" {
+				fmt.Printf("      model: %s\n", j.Model)
+			}
+			for _, r := range j.Requirements {
+				if r.Type == sdk.ModelRequirement {
+					continue
+				}
+				fmt.Printf("      requirement %s: %s\n", r.Type, r.Value)
+			}
+			for _, e := range j.Errors {
+				fmt.Printf("      error: %s\n", e)
+			}
+		}
+		if verbose {
+			for _, p := range n.BuildParameters {
+				fmt.Printf("    %s=%s\n", p.Name, p.Value)
+			}
+		}
+	}
+	for _, i := range plan.Infos {
+		fmt.Printf("%s: %s\n", i.Type, i.UserMessage)
+	}
+}
@@ -154,6 +154,7 @@ func loadChildren(ctx context.Context, db gorp.SqlExecutor, as ...*sdk.Action) e
 			child.Optional = edges[i].Optional
 			child.AlwaysExecuted = edges[i].AlwaysExecuted
 			child.Enabled = edges[i].Enabled
+			child.Timeout = edges[i].Timeout
 
 			// replace action parameter with value configured by user when he created the child action
 			params := make([]sdk.Parameter, len(child.Parameters))
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"regexp"
 	"runtime/pprof"
 	"strings"
 	"time"
@@ -37,6 +38,7 @@ import (
 	"github.com/ovh/cds/engine/api/feature"
 	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/engine/api/mail"
+	"github.com/ovh/cds/engine/api/mergequeue"
 	"github.com/ovh/cds/engine/api/metrics"
 	"github.com/ovh/cds/engine/api/migrate"
 	"github.com/ovh/cds/engine/api/notification"
@@ -171,6 +173,7 @@ type Configuration struct {
 			DisableSSL          bool   `toml:"disableSSL" json:"disableSSL" commented:"true"`                                  //optional
 			ForcePathStyle      bool   `toml:"forcePathStyle" json:"forcePathStyle" commented:"true"`                          //optional
 		} `toml:"awss3" json:"awss3"`
+		CacheMaxSize int64 `toml:"cacheMaxSize" default:"10737418240" comment:"Max size in bytes of the worker caches of a project in a storage integration, least recently used caches are deleted above it (default: 10GB, 0: no limit)" json:"cacheMaxSize"`
 	} `toml:"artifact" comment:"Either filesystem local storage or Openstack Swift Storage are supported" json:"artifact"`
 	Features struct {
 		Izanami struct {
@@ -189,9 +192,21 @@ type Configuration struct {
 		URL         string `toml:"url" comment:"Example: http://localhost:9000" json:"url"`
 	} `toml:"graylog" json:"graylog" comment:"###########################\n Graylog Search. \n When CDS API generates errors, you can fetch them with cdsctl. \n Examples: \n $ cdsctl admin errors get <error-id> \n $ cdsctl admin errors get 55f6e977-d39b-11e8-8513-0242ac110007 \n##########################"`
 	Log struct {
-		StepMaxSize    int64 `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
-		ServiceMaxSize int64 `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
+		StepMaxSize    int64  `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
+		ServiceMaxSize int64  `toml:"serviceMaxSize" default:"15728640" comment:"Max service logs size in bytes (default: 15MB)" json:"serviceMaxSize"`
+		StepStorage    string `toml:"stepStorage" default:"database" comment:"Storage of step logs content: database or objectstore (uses the artifact storage). Existing step logs are moved when objectstore is selected" json:"stepStorage"`
 	} `toml:"log" json:"log" comment:"###########################\n Log settings.\n##########################"`
+	Queue struct {
+		ManualRunPriority     int64  `toml:"manualRunPriority" default:"10" comment:"Priority bonus of the jobs of manual runs" json:"manualRunPriority"`
+		ReleaseBranchPriority int64  `toml:"releaseBranchPriority" default:"5" comment:"Priority bonus of the jobs of runs on a release branch" json:"releaseBranchPriority"`
+		ReleaseBranchPattern  string `toml:"releaseBranchPattern" default:"^(master|main|release/.*)$" comment:"Regular expression matching the release branches" json:"releaseBranchPattern"`
+		AgingPeriod           int64  `toml:"agingPeriod" default:"300" comment:"A job gets one more priority point for each period (in seconds) spent in queue, so low priority jobs are not starved (0: disabled)" json:"agingPeriod"`
+		BuildingJobPenalty    int64  `toml:"buildingJobPenalty" default:"1" comment:"Priority points lost by the jobs of a project for each of its building jobs, to share the workers between projects" json:"buildingJobPenalty"`
+	} `toml:"queue" json:"queue" comment:"###########################\n Job queue priorities.\n Hatcheries start the jobs with the highest priority first, while sharing workers between projects.\n##########################"`
+	Worker struct {
+		MinVersion         string `toml:"minVersion" comment:"Minimum version of the workers allowed to register (example: 0.46.0). Older workers are rejected" json:"minVersion"`
+		RecommendedVersion string `toml:"recommendedVersion" comment:"Recommended version of the workers. Older workers update themselves from the downloadable binaries before taking a job" json:"recommendedVersion"`
+	} `toml:"worker" json:"worker" comment:"###########################\n Worker versions.\n##########################"`
 }
 
 // ServiceConfiguration is the configuration of external service
@@ -272,6 +287,7 @@ type API struct {
 		DatabaseConns            *stats.Int64Measure
 	}
 	AuthenticationDrivers map[sdk.AuthConsumerType]sdk.AuthDriver
+	jobPriority           workflow.JobPriorityConfig
 }
 
 // ApplyConfiguration apply an object of type api.Configuration after checking it
@@ -288,6 +304,16 @@ func (a *API) ApplyConfiguration(config interface{}) error {
 
 	a.Common.ServiceType = services.TypeAPI
 	a.Common.ServiceName = a.Config.Name
+
+	a.jobPriority = workflow.JobPriorityConfig{
+		ManualRun:          a.Config.Queue.ManualRunPriority,
+		ReleaseBranch:      a.Config.Queue.ReleaseBranchPriority,
+		AgingPeriod:        time.Duration(a.Config.Queue.AgingPeriod) * time.Second,
+		BuildingJobPenalty: a.Config.Queue.BuildingJobPenalty,
+	}
+	if a.Config.Queue.ReleaseBranchPattern != "" {
+		a.jobPriority.ReleaseBranchPattern = regexp.MustCompile(a.Config.Queue.ReleaseBranchPattern)
+	}
 	return nil
 }
 
@@ -364,6 +390,24 @@ func (a *API) CheckConfiguration(config interface{}) error {
 		return errors.New("invalid given authentication rsa private key")
 	}
 
+	if aConfig.Queue.ReleaseBranchPattern != "" {
+		if _, err := regexp.Compile(aConfig.Queue.ReleaseBranchPattern); err != nil {
+			return fmt.Errorf("Invalid queue release branch pattern: %v", err)
+		}
+	}
+
+	for _, v := range []string{aConfig.Worker.MinVersion, aConfig.Worker.RecommendedVersion} {
+		if v == "" {
+			continue
+		}
+		if _, err := semver.ParseTolerant(v); err != nil {
+			return fmt.Errorf("Invalid worker version %s: %v", v, err)
+		}
+	}
+	if aConfig.Worker.MinVersion != "" && aConfig.Worker.RecommendedVersion != "" && sdk.VersionIsOlder(aConfig.Worker.RecommendedVersion, aConfig.Worker.MinVersion) {
+		return fmt.Errorf("Invalid worker recommended version %s: it should not be older than the min version %s", aConfig.Worker.RecommendedVersion, aConfig.Worker.MinVersion)
+	}
+
 	return nil
 }
 
@@ -493,6 +537,9 @@ func (a *API) Serve(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("cannot initialize storage: %v", err)
 	}
+	if err := workflow.SetLogStorage(a.Config.Log.StepStorage, a.SharedStorage); err != nil {
+		return fmt.Errorf("cannot initialize step logs storage: %v", err)
+	}
 
 	log.Info(ctx, "Initializing database connection...")
 	//Intialize database
@@ -723,6 +770,16 @@ func (a *API) Serve(ctx context.Context) error {
 		return migrate.RefactorProjectIntegrationCrypto(ctx, a.DBConnectionFactory.GetDBMap())
 	}})
 
+	migrate.Add(ctx, sdk.Migration{Name: "GenerateWebHookSecrets", Release: "0.46.0", Blocker: false, Automatic: true, ExecFunc: func(ctx context.Context) error {
+		return migrate.GenerateWebHookSecrets(ctx, a.DBConnectionFactory.GetDBMap(), a.Cache)
+	}})
+
+	if a.Config.Log.StepStorage == workflow.LogStorageObjectStore {
+		migrate.Add(ctx, sdk.Migration{Name: "MoveStepLogsToObjectStore", Release: "0.44.0", Blocker: false, Automatic: true, ExecFunc: func(ctx context.Context) error {
+			return migrate.MoveStepLogsToObjectStore(ctx, a.DBConnectionFactory.GetDBMap())
+		}})
+	}
+
 	isFreshInstall, errF := version.IsFreshInstall(a.mustDB())
 	if errF != nil {
 		return sdk.WrapError(errF, "Unable to check if it's a fresh installation of CDS")
@@ -812,6 +869,14 @@ func (a *API) Serve(ctx context.Context) error {
 		func(ctx context.Context) {
 			workflow.Initialize(ctx, a.DBConnectionFactory.GetDBMap, a.Cache, a.Config.URL.UI, a.Config.DefaultOS, a.Config.DefaultArch)
 		}, a.PanicDump())
+	sdk.GoRoutine(ctx, "mergequeue.Initialize",
+		func(ctx context.Context) {
+			mergequeue.Initialize(ctx, a.DBConnectionFactory.GetDBMap, a.Cache, a.startMergeQueueRun, a.stopMergeQueueRun)
+		}, a.PanicDump())
+	sdk.GoRoutine(ctx, "api.manageTimedOutJobs",
+		func(ctx context.Context) {
+			a.manageTimedOutJobs(ctx)
+		}, a.PanicDump())
 	sdk.GoRoutine(ctx, "PushInElasticSearch",
 		func(ctx context.Context) {
 			event.PushInElasticSearch(ctx, a.mustDB(), a.Cache)
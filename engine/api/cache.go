@@ -8,10 +8,86 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workercache"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
+type cacheSizeReader struct {
+	io.ReadCloser
+	size int64
+}
+
+func (r *cacheSizeReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// saveCacheEntry records the metadata of a pushed cache, then deletes the least recently used caches of the project
+// in the storage integration if their total size exceeds the limit.
+func (api *API) saveCacheEntry(ctx context.Context, storageDriver objectstore.Driver, projectKey, integrationName, tag string, size int64) error {
+	proj, err := project.Load(api.mustDB(), projectKey)
+	if err != nil {
+		return err
+	}
+
+	e := sdk.CacheEntry{
+		ProjectID:       proj.ID,
+		IntegrationName: integrationName,
+		Tag:             tag,
+		Key:             sdk.CacheKeyFromTag(tag),
+		Size:            size,
+	}
+	if err := workercache.Upsert(api.mustDB(), &e); err != nil {
+		return err
+	}
+
+	if err := workercache.Evict(ctx, api.mustDB(), storageDriver, proj.Key, proj.ID, integrationName, api.Config.Artifact.CacheMaxSize); err != nil {
+		log.Error(ctx, "cannot evict caches of project %s: %v", proj.Key, err)
+	}
+	return nil
+}
+
+// touchCacheEntry updates the last access date of a pulled cache, errors are only logged.
+func (api *API) touchCacheEntry(ctx context.Context, projectKey, integrationName, tag string) {
+	proj, err := project.Load(api.mustDB(), projectKey)
+	if err == nil {
+		err = workercache.UpdateLastAccess(api.mustDB(), proj.ID, integrationName, tag)
+	}
+	if err != nil {
+		log.Error(ctx, "cannot update last access of cache %s: %v", tag, err)
+	}
+}
+
+// cacheObjectExists checks that the object of a cache is in the storage when workers upload it with a temporary url.
+// The entry of a missing object is removed so the cache is handled as a miss.
+func (api *API) cacheObjectExists(ctx context.Context, storageDriver objectstore.Driver, projectKey, integrationName, tag string) (bool, error) {
+	store, ok := storageDriver.(objectstore.DriverWithRedirect)
+	if !ok || !storageDriver.TemporaryURLSupported() {
+		return true, nil
+	}
+	_, err := store.Size(&sdk.Cache{Name: "cache.tar", Project: projectKey, Tag: tag})
+	if err == nil {
+		return true, nil
+	}
+	if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return false, err
+	}
+
+	log.Info(ctx, "cache %s of project %s is missing in storage %s, its entry is removed", tag, projectKey, integrationName)
+	proj, err := project.Load(api.mustDB(), projectKey)
+	if err != nil {
+		return false, err
+	}
+	if err := workercache.DeleteByTag(api.mustDB(), proj.ID, integrationName, tag); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
 func (api *API) postPushCacheHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if isWorker := isWorker(ctx); !isWorker {
@@ -43,11 +119,12 @@ func (api *API) postPushCacheHandler() service.Handler {
 			return err
 		}
 
-		if _, err := storageDriver.Store(&cacheObject, r.Body); err != nil {
+		body := &cacheSizeReader{ReadCloser: r.Body}
+		if _, err := storageDriver.Store(&cacheObject, body); err != nil {
 			return sdk.WrapError(err, "cannot store cache")
 		}
 
-		return nil
+		return api.saveCacheEntry(ctx, storageDriver, vars[permProjectKey], vars["integrationName"], tag, body.size)
 	}
 }
 
@@ -77,6 +154,16 @@ func (api *API) getPullCacheHandler() service.Handler {
 			return err
 		}
 
+		exists, err := api.cacheObjectExists(ctx, storageDriver, vars[permProjectKey], vars["integrationName"], tag)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "cache %s not found", tag)
+		}
+
+		api.touchCacheEntry(ctx, vars[permProjectKey], vars["integrationName"], tag)
+
 		s, temporaryURLSupported := storageDriver.(objectstore.DriverWithRedirect)
 		if storageDriver.TemporaryURLSupported() && temporaryURLSupported { // with temp URL
 			fURL, _, err := s.FetchURL(&cacheObject)
@@ -137,6 +224,7 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 			Tag:     tag,
 		}
 
+		// The cache entry is saved by the callback once the content is uploaded to the storage
 		url, key, err := store.StoreURL(&cacheObject, "application/tar")
 		if err != nil {
 			return sdk.WrapError(err, "cannot store cache")
@@ -148,6 +236,50 @@ func (api *API) postPushCacheWithTempURLHandler() service.Handler {
 	}
 }
 
+func (api *API) postPushCacheWithTempURLCallbackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		tag := vars["tag"]
+
+		// check tag name pattern
+		regexp := sdk.NamePatternRegex
+		if !regexp.MatchString(tag) {
+			return sdk.WithStack(sdk.ErrInvalidName)
+		}
+
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, vars[permProjectKey], vars["integrationName"])
+		if err != nil {
+			return err
+		}
+
+		store, ok := storageDriver.(objectstore.DriverWithRedirect)
+		if !ok {
+			return sdk.WrapError(sdk.ErrNotImplemented, "cast error")
+		}
+
+		cacheObject := sdk.Cache{
+			Name:    "cache.tar",
+			Project: vars[permProjectKey],
+			Tag:     tag,
+		}
+
+		// The size of the cache is read from the storage as the content was uploaded directly by the worker
+		size, err := store.Size(&cacheObject)
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return sdk.NewErrorFrom(sdk.ErrNotFound, "cache %s was not uploaded", tag)
+			}
+			return err
+		}
+
+		return api.saveCacheEntry(ctx, storageDriver, vars[permProjectKey], vars["integrationName"], tag, size)
+	}
+}
+
 func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if isWorker := isWorker(ctx); !isWorker {
@@ -179,13 +311,62 @@ func (api *API) getPullCacheWithTempURLHandler() service.Handler {
 			Tag:     tag,
 		}
 
+		exists, err := api.cacheObjectExists(ctx, storageDriver, vars[permProjectKey], vars["integrationName"], tag)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "cache %s not found", tag)
+		}
+
 		url, key, err := store.FetchURL(&cacheObject)
 		if err != nil {
 			return sdk.WrapError(err, "cannot get tmp URL")
 		}
+		api.touchCacheEntry(ctx, vars[permProjectKey], vars["integrationName"], tag)
 		cacheObject.TmpURL = url
 		cacheObject.SecretKey = key
 
 		return service.WriteJSON(w, cacheObject, http.StatusOK)
 	}
 }
+
+func (api *API) getCacheEntryHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		key := r.FormValue("key")
+		restoreKeys := r.URL.Query()["restoreKey"]
+		if key == "" && len(restoreKeys) == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing cache key")
+		}
+
+		proj, err := project.Load(api.mustDB(), vars[permProjectKey])
+		if err != nil {
+			return err
+		}
+
+		storageDriver, err := objectstore.GetDriver(ctx, api.mustDB(), api.SharedStorage, vars[permProjectKey], vars["integrationName"])
+		if err != nil {
+			return err
+		}
+
+		// The entry of a missing object is removed by the check, the next matching entry is then resolved
+		for {
+			e, err := workercache.Resolve(ctx, api.mustDB(), proj.ID, vars["integrationName"], key, restoreKeys)
+			if err != nil {
+				return err
+			}
+			exists, err := api.cacheObjectExists(ctx, storageDriver, proj.Key, vars["integrationName"], e.Tag)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return service.WriteJSON(w, e, http.StatusOK)
+			}
+		}
+	}
+}
@@ -23,6 +23,7 @@ func Key(args ...string) string {
 //Store is an interface
 type Store interface {
 	Get(key string, value interface{}) (bool, error)
+	Exists(keys ...string) ([]bool, error)
 	Set(key string, value interface{}) error
 	SetWithTTL(key string, value interface{}, ttl int) error
 	SetWithDuration(key string, value interface{}, duration time.Duration) error
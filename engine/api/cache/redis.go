@@ -91,6 +91,26 @@ func (s *RedisStore) Get(key string, value interface{}) (bool, error) {
 	return false, nil
 }
 
+// Exists checks with a single request if each given key is in redis
+func (s *RedisStore) Exists(keys ...string) ([]bool, error) {
+	if s.Client == nil {
+		return nil, sdk.WithStack(fmt.Errorf("redis> cannot get redis client"))
+	}
+
+	res := make([]bool, len(keys))
+	if len(keys) == 0 {
+		return res, nil
+	}
+	vals, err := s.Client.MGet(keys...).Result()
+	if err != nil {
+		return nil, sdk.WrapError(err, "redis> mget error")
+	}
+	for i := range vals {
+		res[i] = vals[i] != nil
+	}
+	return res, nil
+}
+
 // SetWithTTL a value in local store (0 for eternity)
 func (s *RedisStore) SetWithTTL(key string, value interface{}, ttl int) error {
 	if s.Client == nil {
@@ -2,8 +2,13 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
 	"path"
 
 	"github.com/gorilla/mux"
@@ -40,3 +45,49 @@ func (api *API) downloadHandler() service.Handler {
 		return nil
 	}
 }
+
+// downloadInfosHandler returns the checksum and the signature of a downloadable binary,
+// used by the workers to check a binary before updating themselves.
+func (api *API) downloadInfosHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["name"]
+		binaryOS := vars["os"]
+		arch := vars["arch"]
+		variant := FormString(r, "variant")
+
+		filename := sdk.GetArtifactFilename(name, binaryOS, arch, variant)
+		filepath := path.Join(api.Config.Directories.Download, filename)
+
+		f, err := os.Open(filepath)
+		if err != nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "binary %s is not available", filename)
+		}
+		defer f.Close() // nolint
+
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return sdk.WrapError(err, "cannot compute checksum of %s", filepath)
+		}
+
+		available := true
+		res := sdk.DownloadableResource{
+			Name:      name,
+			OS:        binaryOS,
+			Arch:      arch,
+			Variant:   variant,
+			Filename:  filename,
+			Available: &available,
+			SHA256:    hex.EncodeToString(h.Sum(nil)),
+		}
+
+		sig, err := ioutil.ReadFile(filepath + ".sig")
+		if err == nil {
+			res.Signature = sig
+		} else if !os.IsNotExist(err) {
+			return sdk.WrapError(err, "cannot read signature of %s", filepath)
+		}
+
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
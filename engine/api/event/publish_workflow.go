@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
 )
 
 // publishWorkflowEvent publish workflow event
@@ -34,10 +35,25 @@ func publishWorkflowEvent(ctx context.Context, payload interface{}, key, workflo
 	publishEvent(ctx, event)
 }
 
+// withoutHooksSecrets returns a copy of the given Workflow without the secrets of its hooks
+func withoutHooksSecrets(ctx context.Context, w sdk.Workflow) sdk.Workflow {
+	var res sdk.Workflow
+	bts, err := json.Marshal(w)
+	if err == nil {
+		err = json.Unmarshal(bts, &res)
+	}
+	if err != nil {
+		log.Error(ctx, "cannot copy workflow %s: %v", w.Name, err)
+		return sdk.Workflow{ID: w.ID, Name: w.Name, ProjectKey: w.ProjectKey}
+	}
+	res.FilterHooksConfig(sdk.HookConfigWebHookSecret)
+	return res
+}
+
 // PublishWorkflowAdd publishes an event for the creation of the given Workflow
 func PublishWorkflowAdd(ctx context.Context, projKey string, w sdk.Workflow, u sdk.Identifiable) {
 	e := sdk.EventWorkflowAdd{
-		Workflow: w,
+		Workflow: withoutHooksSecrets(ctx, w),
 	}
 	publishWorkflowEvent(ctx, e, projKey, w.Name, w.EventIntegrations, u)
 }
@@ -45,8 +61,8 @@ func PublishWorkflowAdd(ctx context.Context, projKey string, w sdk.Workflow, u s
 // PublishWorkflowUpdate publishes an event for the update of the given Workflow
 func PublishWorkflowUpdate(ctx context.Context, projKey string, w sdk.Workflow, oldw sdk.Workflow, u sdk.Identifiable) {
 	e := sdk.EventWorkflowUpdate{
-		NewWorkflow: w,
-		OldWorkflow: oldw,
+		NewWorkflow: withoutHooksSecrets(ctx, w),
+		OldWorkflow: withoutHooksSecrets(ctx, oldw),
 	}
 	publishWorkflowEvent(ctx, e, projKey, w.Name, w.EventIntegrations, u)
 }
@@ -54,7 +70,7 @@ func PublishWorkflowUpdate(ctx context.Context, projKey string, w sdk.Workflow,
 // PublishWorkflowDelete publishes an event for the deletion of the given Workflow
 func PublishWorkflowDelete(ctx context.Context, projKey string, w sdk.Workflow, u sdk.Identifiable) {
 	e := sdk.EventWorkflowDelete{
-		Workflow: w,
+		Workflow: withoutHooksSecrets(ctx, w),
 	}
 	publishWorkflowEvent(ctx, e, projKey, w.Name, w.EventIntegrations, u)
 }
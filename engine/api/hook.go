@@ -9,6 +9,7 @@ import (
 
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -99,3 +100,136 @@ func (api *API) getHookPollingVCSEvents() service.Handler {
 		return service.WriteJSON(w, repoEvents, http.StatusOK)
 	}
 }
+
+func (api *API) getHookChangedFilesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		// This handler can only be called by a service managed by an admin
+		if isService := isService(ctx); !isService && !isAdmin(ctx) {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		uuid := vars["uuid"]
+		branch := r.FormValue("branch")
+		base := r.FormValue("base")
+		head := r.FormValue("head")
+		if branch == "" || head == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "branch and head parameters are mandatory")
+		}
+
+		proj, app, client, err := api.loadHookRepository(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		repo, err := client.RepoByFullname(ctx, app.RepositoryFullname)
+		if err != nil {
+			return sdk.WrapError(err, "cannot get repo %s", app.RepositoryFullname)
+		}
+		cloneURL := repo.HTTPCloneURL
+		if app.RepositoryStrategy.ConnectionType == "ssh" {
+			cloneURL = repo.SSHCloneURL
+		}
+
+		// Without base commit, only the head commit is checked
+		commits := []string{head}
+		if base != "" {
+			vcsCommits, err := client.CommitsBetweenRefs(ctx, app.RepositoryFullname, base, head)
+			if err != nil {
+				return sdk.WrapError(err, "cannot get commits between %s and %s", base, head)
+			}
+			commits = make([]string, len(vcsCommits))
+			for i := range vcsCommits {
+				commits[i] = vcsCommits[i].Hash
+			}
+		}
+
+		files := []string{}
+		if len(commits) > 0 {
+			files, err = workflow.LoadChangedFiles(ctx, api.mustDB(), api.Cache, *proj, *app, cloneURL, branch, head, commits)
+			if err != nil {
+				return err
+			}
+		}
+
+		return service.WriteJSON(w, files, http.StatusOK)
+	}
+}
+
+func (api *API) getHookPullRequestsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		// This handler can only be called by a service managed by an admin
+		if isService := isService(ctx); !isService && !isAdmin(ctx) {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		uuid := vars["uuid"]
+		base := r.FormValue("base")
+		if base == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "base parameter is mandatory")
+		}
+
+		_, app, client, err := api.loadHookRepository(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		prs, err := client.PullRequests(ctx, app.RepositoryFullname)
+		if err != nil {
+			return sdk.WrapError(err, "cannot get pull requests on %s", app.RepositoryFullname)
+		}
+
+		// Only the opened pull requests targeting the base branch are returned
+		res := make([]sdk.VCSPullRequest, 0, len(prs))
+		for _, pr := range prs {
+			if pr.Closed || pr.Merged || pr.Base.Branch.DisplayID != base {
+				continue
+			}
+			res = append(res, pr)
+		}
+
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+// loadHookRepository loads the project, the application of the root node of the workflow of a hook and the client for its repository
+func (api *API) loadHookRepository(ctx context.Context, uuid string) (*sdk.Project, *sdk.Application, sdk.VCSAuthorizedClient, error) {
+	h, err := workflow.LoadHookByUUID(api.mustDB(), uuid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	workflowID, err := strconv.ParseInt(h.Config[sdk.HookConfigWorkflowID].Value, 10, 64)
+	if err != nil {
+		return nil, nil, nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid workflow id for hook %s", uuid)
+	}
+
+	proj, err := project.Load(api.mustDB(), h.Config[sdk.HookConfigProject].Value, project.LoadOptions.WithClearKeys)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	wf, err := workflow.LoadByID(ctx, api.mustDB(), api.Cache, *proj, workflowID, workflow.LoadOptions{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if wf.WorkflowData.Node.Context == nil || wf.WorkflowData.Node.Context.ApplicationID == 0 {
+		return nil, nil, nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no application found on the root node of workflow %s", wf.Name)
+	}
+	app, err := application.LoadByIDWithClearVCSStrategyPassword(api.mustDB(), wf.WorkflowData.Node.Context.ApplicationID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	//get the client for the repositories manager
+	vcsServer := repositoriesmanager.GetProjectVCSServer(*proj, app.VCSServer)
+	if vcsServer == nil {
+		return nil, nil, nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no vcs server %s found on project %s", app.VCSServer, proj.Key)
+	}
+	client, err := repositoriesmanager.AuthorizedClient(ctx, api.mustDB(), api.Cache, proj.Key, vcsServer)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return proj, app, client, nil
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/mergequeue"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// postHookMergeQueueHandler adds an approved pull request in the merge queue of a repository webhook
+func (api *API) postHookMergeQueueHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		// This handler can only be called by a service managed by an admin
+		if isService := isService(ctx); !isService && !isAdmin(ctx) {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		vars := mux.Vars(r)
+		uuid := vars["uuid"]
+
+		var req sdk.MergeQueueEntry
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if req.PullRequestID <= 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pull request id")
+		}
+
+		h, err := workflow.LoadHookByUUID(api.mustDB(), uuid)
+		if err != nil {
+			return err
+		}
+		if method, _ := sdk.MergeQueueConfig(h.Config); method == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "merge queue is disabled for hook %s", uuid)
+		}
+		workflowID, err := strconv.ParseInt(h.Config[sdk.HookConfigWorkflowID].Value, 10, 64)
+		if err != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid workflow id for hook %s", uuid)
+		}
+
+		proj, app, client, err := api.loadHookRepository(ctx, uuid)
+		if err != nil {
+			return err
+		}
+
+		pr, err := client.PullRequest(ctx, app.RepositoryFullname, req.PullRequestID)
+		if err != nil {
+			return err
+		}
+		if pr.Merged || pr.Closed {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "pull request #%d is closed", pr.ID)
+		}
+
+		existing, err := mergequeue.LoadActiveByPullRequest(ctx, api.mustDB(), uuid, pr.ID)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+		if existing != nil {
+			if existing.HeadHash == pr.Head.Commit.Hash {
+				return service.WriteJSON(w, existing, http.StatusOK)
+			}
+			if err := mergequeue.Remove(ctx, api.mustDB(), api.Cache, api.stopMergeQueueRun, *existing, "the pull request was updated and approved again"); err != nil {
+				return err
+			}
+		}
+
+		author := pr.User.Name
+		if author == "" {
+			author = pr.User.DisplayName
+		}
+		e := sdk.MergeQueueEntry{
+			ProjectID:         proj.ID,
+			WorkflowID:        workflowID,
+			HookUUID:          uuid,
+			Repository:        app.RepositoryFullname,
+			BaseBranch:        pr.Base.Branch.DisplayID,
+			PullRequestID:     pr.ID,
+			PullRequestTitle:  pr.Title,
+			PullRequestAuthor: author,
+			HeadHash:          pr.Head.Commit.Hash,
+			Fork:              pr.IsFork(),
+			Status:            sdk.MergeQueueStatusQueued,
+			AuthConsumerID:    getAPIConsumer(ctx).ID,
+		}
+		if err := mergequeue.Insert(api.mustDB(), &e); err != nil {
+			return err
+		}
+		mergequeue.SendStatus(ctx, client, proj.Key, h.Config[sdk.HookConfigWorkflow].Value, e)
+
+		return service.WriteJSON(w, e, http.StatusCreated)
+	}
+}
+
+// getWorkflowMergeQueueHandler returns the pull requests in the merge queues of a workflow
+func (api *API) getWorkflowMergeQueueHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		proj, err := project.Load(api.mustDB(), key)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, *proj, name, workflow.LoadOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow %s/%s", key, name)
+		}
+
+		es, err := mergequeue.LoadAllActiveByWorkflowID(ctx, api.mustDB(), wf.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, es, http.StatusOK)
+	}
+}
+
+// deleteWorkflowMergeQueueEntryHandler removes a pull request from the merge queue of a workflow
+func (api *API) deleteWorkflowMergeQueueEntryHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		id, err := requestVarInt(r, "entryID")
+		if err != nil {
+			return err
+		}
+
+		proj, err := project.Load(api.mustDB(), key)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project %s", key)
+		}
+		wf, err := workflow.Load(ctx, api.mustDB(), api.Cache, *proj, name, workflow.LoadOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "cannot load workflow %s/%s", key, name)
+		}
+
+		e, err := mergequeue.LoadByID(ctx, api.mustDB(), id)
+		if err != nil {
+			return err
+		}
+		if e.WorkflowID != wf.ID {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		if !e.IsActive() {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "pull request #%d is not in the merge queue anymore", e.PullRequestID)
+		}
+
+		if err := mergequeue.Remove(ctx, api.mustDB(), api.Cache, api.stopMergeQueueRun, *e, fmt.Sprintf("removed by %s", getAPIConsumer(ctx).GetUsername())); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// startMergeQueueRun starts a run of a workflow for the candidate of a pull request in a merge queue
+func (api *API) startMergeQueueRun(ctx context.Context, projectKey string, wf *sdk.Workflow, event sdk.WorkflowNodeRunHookEvent, consumer *sdk.AuthConsumer) (*sdk.WorkflowRun, error) {
+	opts := &sdk.WorkflowRunPostHandlerOption{Hook: &event}
+	wr, err := workflow.CreateRun(api.mustDB(), wf, opts, consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	sdk.GoRoutine(context.Background(), fmt.Sprintf("api.initWorkflowRun-%d", wr.ID), func(ctx context.Context) {
+		api.initWorkflowRun(ctx, projectKey, wf, wr, opts, consumer)
+	}, api.PanicDump())
+
+	return wr, nil
+}
+
+// stopMergeQueueRun stops the run of an outdated candidate of a pull request in a merge queue
+func (api *API) stopMergeQueueRun(ctx context.Context, proj *sdk.Project, wr *sdk.WorkflowRun, consumer *sdk.AuthConsumer) error {
+	report, err := stopWorkflowRun(ctx, api.mustDB, api.Cache, proj, wr, consumer, 0)
+	if err != nil {
+		return sdk.WrapError(err, "unable to stop workflow run %d", wr.ID)
+	}
+	go WorkflowSendEvent(context.Background(), api.mustDB(), api.Cache, *proj, report)
+	return nil
+}
@@ -0,0 +1,91 @@
+package mergequeue
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+const activeStatuses = "'" + sdk.MergeQueueStatusQueued + "', '" + sdk.MergeQueueStatusBuilding + "', '" + sdk.MergeQueueStatusPassed + "'"
+
+func getAll(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.MergeQueueEntry, error) {
+	var es []dbMergeQueueEntry
+	if err := gorpmapping.GetAll(ctx, db, q, &es); err != nil {
+		return nil, sdk.WrapError(err, "cannot get merge queue entries")
+	}
+	res := make([]sdk.MergeQueueEntry, len(es))
+	for i := range es {
+		res[i] = sdk.MergeQueueEntry(es[i])
+	}
+	return res, nil
+}
+
+// LoadAllActive returns the pull requests of all the merge queues, ordered by their position in the queues.
+func LoadAllActive(ctx context.Context, db gorp.SqlExecutor) ([]sdk.MergeQueueEntry, error) {
+	return getAll(ctx, db, gorpmapping.NewQuery("SELECT * FROM merge_queue_entry WHERE status IN ("+activeStatuses+") ORDER BY id"))
+}
+
+// LoadAllActiveByWorkflowID returns the pull requests in the merge queues of a workflow.
+func LoadAllActiveByWorkflowID(ctx context.Context, db gorp.SqlExecutor, workflowID int64) ([]sdk.MergeQueueEntry, error) {
+	query := gorpmapping.NewQuery("SELECT * FROM merge_queue_entry WHERE workflow_id = $1 AND status IN (" + activeStatuses + ") ORDER BY id").Args(workflowID)
+	return getAll(ctx, db, query)
+}
+
+// LoadAllActiveByQueue returns the pull requests in the merge queue of a base branch for a repository hook, ordered by their position.
+func LoadAllActiveByQueue(ctx context.Context, db gorp.SqlExecutor, hookUUID, baseBranch string) ([]sdk.MergeQueueEntry, error) {
+	query := gorpmapping.NewQuery("SELECT * FROM merge_queue_entry WHERE hook_uuid = $1 AND base_branch = $2 AND status IN ("+activeStatuses+") ORDER BY id").
+		Args(hookUUID, baseBranch)
+	return getAll(ctx, db, query)
+}
+
+// LoadByID returns an entry of a merge queue.
+func LoadByID(ctx context.Context, db gorp.SqlExecutor, id int64) (*sdk.MergeQueueEntry, error) {
+	es, err := getAll(ctx, db, gorpmapping.NewQuery("SELECT * FROM merge_queue_entry WHERE id = $1").Args(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(es) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &es[0], nil
+}
+
+// LoadActiveByPullRequest returns the entry of a pull request still in the merge queue of a hook.
+func LoadActiveByPullRequest(ctx context.Context, db gorp.SqlExecutor, hookUUID string, pullRequestID int) (*sdk.MergeQueueEntry, error) {
+	query := gorpmapping.NewQuery("SELECT * FROM merge_queue_entry WHERE hook_uuid = $1 AND pull_request_id = $2 AND status IN ("+activeStatuses+")").
+		Args(hookUUID, pullRequestID)
+	es, err := getAll(ctx, db, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(es) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &es[0], nil
+}
+
+// Insert a pull request in a merge queue.
+func Insert(db gorp.SqlExecutor, e *sdk.MergeQueueEntry) error {
+	e.Created = time.Now()
+	e.LastModified = e.Created
+	dbE := dbMergeQueueEntry(*e)
+	if err := gorpmapping.Insert(db, &dbE); err != nil {
+		return sdk.WrapError(err, "cannot insert merge queue entry")
+	}
+	*e = sdk.MergeQueueEntry(dbE)
+	return nil
+}
+
+// Update an entry of a merge queue.
+func Update(db gorp.SqlExecutor, e *sdk.MergeQueueEntry) error {
+	e.LastModified = time.Now()
+	dbE := dbMergeQueueEntry(*e)
+	if err := gorpmapping.Update(db, &dbE); err != nil {
+		return sdk.WrapError(err, "cannot update merge queue entry %d", e.ID)
+	}
+	return nil
+}
@@ -0,0 +1,600 @@
+package mergequeue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// StartRunFunc starts a run of a workflow for the candidate of a pull request in a merge queue.
+type StartRunFunc func(ctx context.Context, projectKey string, wf *sdk.Workflow, event sdk.WorkflowNodeRunHookEvent, consumer *sdk.AuthConsumer) (*sdk.WorkflowRun, error)
+
+// StopRunFunc stops the run of an outdated candidate of a pull request in a merge queue.
+type StopRunFunc func(ctx context.Context, proj *sdk.Project, wr *sdk.WorkflowRun, consumer *sdk.AuthConsumer) error
+
+// Initialize starts the routine that builds and merges the pull requests of the merge queues.
+func Initialize(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.Store, startRun StartRunFunc, stopRun StopRunFunc) {
+	tick := time.NewTicker(15 * time.Second)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "Exiting merge queue ticker: %v", ctx.Err())
+				return
+			}
+		case <-tick.C:
+			if err := processAll(ctx, DBFunc(), store, startRun, stopRun); err != nil {
+				log.Warning(ctx, "mergequeue.processAll> %v", err)
+			}
+		}
+	}
+}
+
+func processAll(ctx context.Context, db *gorp.DbMap, store cache.Store, startRun StartRunFunc, stopRun StopRunFunc) error {
+	entries, err := LoadAllActive(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	// Entries are grouped by hook and base branch, each group is a queue
+	done := make(map[string]struct{})
+	for _, e := range entries {
+		k := lockKey(e.HookUUID, e.BaseBranch)
+		if _, ok := done[k]; ok {
+			continue
+		}
+		done[k] = struct{}{}
+		if err := processQueue(ctx, db, store, startRun, stopRun, e.HookUUID, e.BaseBranch); err != nil {
+			log.Error(ctx, "mergequeue.processAll> unable to process merge queue of %s for hook %s: %v", e.BaseBranch, e.HookUUID, err)
+		}
+	}
+	return nil
+}
+
+func lockKey(hookUUID, baseBranch string) string {
+	return cache.Key("mergequeue", "lock", hookUUID, baseBranch)
+}
+
+// lock prevents two API instances from processing the same queue.
+func lock(store cache.Store, hookUUID, baseBranch string) (func(), bool, error) {
+	k := lockKey(hookUUID, baseBranch)
+	locked, err := store.Lock(k, 5*time.Minute, 0, 1)
+	if err != nil || !locked {
+		return nil, false, err
+	}
+	return func() { _ = store.Unlock(k) }, true, nil
+}
+
+func processQueue(ctx context.Context, db *gorp.DbMap, store cache.Store, startRun StartRunFunc, stopRun StopRunFunc, hookUUID, baseBranch string) error {
+	unlock, locked, err := lock(store, hookUUID, baseBranch)
+	if err != nil || !locked {
+		return err
+	}
+	defer unlock()
+
+	entries, err := LoadAllActiveByQueue(ctx, db, hookUUID, baseBranch)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	q, err := loadQueue(ctx, db, store, hookUUID, startRun, stopRun)
+	if sdk.ErrorIs(err, sdk.ErrNotFound) {
+		// The hook or the workflow was deleted, there is nothing left to build
+		for i := range entries {
+			entries[i].Status = sdk.MergeQueueStatusRemoved
+			entries[i].Message = "the repository webhook of the workflow was removed"
+			if err := Update(db, &entries[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if q.method == "" {
+		for i := range entries {
+			if err := q.leave(ctx, &entries[i], sdk.MergeQueueStatusRemoved, "the merge queue of the workflow was disabled"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return q.process(ctx, entries)
+}
+
+// Remove removes a pull request from its merge queue, the candidates of the next pull requests are rebuilt.
+func Remove(ctx context.Context, db *gorp.DbMap, store cache.Store, stopRun StopRunFunc, e sdk.MergeQueueEntry, message string) error {
+	unlock, locked, err := lock(store, e.HookUUID, e.BaseBranch)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return sdk.NewErrorFrom(sdk.ErrConflict, "the merge queue is being processed, please retry later")
+	}
+	defer unlock()
+
+	entries, err := LoadAllActiveByQueue(ctx, db, e.HookUUID, e.BaseBranch)
+	if err != nil {
+		return err
+	}
+
+	q, err := loadQueue(ctx, db, store, e.HookUUID, nil, stopRun)
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for i := range entries {
+		switch {
+		case entries[i].ID == e.ID:
+			found = true
+			if err := q.leave(ctx, &entries[i], sdk.MergeQueueStatusRemoved, message); err != nil {
+				return err
+			}
+		case found && entries[i].Status != sdk.MergeQueueStatusQueued:
+			if err := q.requeue(ctx, &entries[i]); err != nil {
+				return err
+			}
+		}
+	}
+	if !found {
+		return sdk.NewErrorFrom(sdk.ErrNotFound, "pull request #%d is not in the merge queue", e.PullRequestID)
+	}
+	return nil
+}
+
+// queue builds and merges the pull requests of the merge queue of a base branch.
+type queue struct {
+	projectKey string
+	workflow   *sdk.Workflow
+	hookUUID   string
+	method     string
+	depth      int
+	client     sdk.VCSAuthorizedClient
+	update     func(e *sdk.MergeQueueEntry) error
+	runStatus  func(ctx context.Context, e sdk.MergeQueueEntry) (string, error)
+	startRun   func(ctx context.Context, e sdk.MergeQueueEntry, event sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
+	stopRun    func(ctx context.Context, e sdk.MergeQueueEntry) error
+}
+
+func loadQueue(ctx context.Context, db *gorp.DbMap, store cache.Store, hookUUID string, startRun StartRunFunc, stopRun StopRunFunc) (*queue, error) {
+	h, err := workflow.LoadHookByUUID(db, hookUUID)
+	if err != nil {
+		return nil, err
+	}
+	workflowID, err := strconv.ParseInt(h.Config[sdk.HookConfigWorkflowID].Value, 10, 64)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid workflow id for hook %s", hookUUID)
+	}
+
+	proj, err := project.Load(db, h.Config[sdk.HookConfigProject].Value,
+		project.LoadOptions.WithVariables,
+		project.LoadOptions.WithFeatures(store),
+		project.LoadOptions.WithIntegrations,
+		project.LoadOptions.WithApplicationVariables,
+		project.LoadOptions.WithApplicationWithDeploymentStrategies,
+		project.LoadOptions.WithEnvironments,
+		project.LoadOptions.WithPipelines,
+		project.LoadOptions.WithClearKeys,
+	)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := workflow.LoadByID(ctx, db, store, *proj, workflowID, workflow.LoadOptions{
+		DeepPipeline:     true,
+		Base64Keys:       true,
+		WithIcon:         true,
+		WithIntegrations: true,
+		WithTemplate:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if wf.WorkflowData.Node.Context == nil || wf.WorkflowData.Node.Context.ApplicationID == 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no application found on the root node of workflow %s", wf.Name)
+	}
+	app, err := application.LoadByID(db, wf.WorkflowData.Node.Context.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+	vcsServer := repositoriesmanager.GetProjectVCSServer(*proj, app.VCSServer)
+	if vcsServer == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no vcs server %s found on project %s", app.VCSServer, proj.Key)
+	}
+	client, err := repositoriesmanager.AuthorizedClient(ctx, db, store, proj.Key, vcsServer)
+	if err != nil {
+		return nil, err
+	}
+
+	method, depth := sdk.MergeQueueConfig(h.Config)
+	return &queue{
+		projectKey: proj.Key,
+		workflow:   wf,
+		hookUUID:   hookUUID,
+		method:     method,
+		depth:      depth,
+		client:     client,
+		update: func(e *sdk.MergeQueueEntry) error {
+			return Update(db, e)
+		},
+		runStatus: func(ctx context.Context, e sdk.MergeQueueEntry) (string, error) {
+			wr, err := workflow.LoadRunByID(db, e.WorkflowRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				// The run was deleted, the candidate has to be considered as failed
+				return sdk.StatusStopped, nil
+			}
+			if err != nil {
+				return "", err
+			}
+			return wr.Status, nil
+		},
+		startRun: func(ctx context.Context, e sdk.MergeQueueEntry, event sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error) {
+			consumer, err := authentication.LoadConsumerByID(ctx, db, e.AuthConsumerID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+			if err != nil {
+				return nil, err
+			}
+			return startRun(ctx, proj.Key, wf, event, consumer)
+		},
+		stopRun: func(ctx context.Context, e sdk.MergeQueueEntry) error {
+			wr, err := workflow.LoadRunByID(db, e.WorkflowRunID, workflow.LoadRunOptions{})
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if sdk.StatusIsTerminated(wr.Status) {
+				return nil
+			}
+			consumer, err := authentication.LoadConsumerByID(ctx, db, e.AuthConsumerID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+			if err != nil {
+				return err
+			}
+			return stopRun(ctx, proj, wr, consumer)
+		},
+	}, nil
+}
+
+// process checks the pull requests of the queue and the runs of their candidates,
+// merges the pull requests at the head of the queue whose candidate passed and builds the next candidates.
+func (q *queue) process(ctx context.Context, entries []sdk.MergeQueueEntry) error {
+	var active []*sdk.MergeQueueEntry
+	// Once a pull request leaves the queue, the candidates of the next ones have to be rebuilt without it
+	var invalidated bool
+	for i := range entries {
+		e := &entries[i]
+		if invalidated && e.Status != sdk.MergeQueueStatusQueued {
+			if err := q.requeue(ctx, e); err != nil {
+				return err
+			}
+		}
+
+		pr, err := q.client.PullRequest(ctx, e.Repository, e.PullRequestID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case pr.Merged || pr.Closed:
+			if err := q.leave(ctx, e, sdk.MergeQueueStatusRemoved, "the pull request was closed"); err != nil {
+				return err
+			}
+			invalidated = true
+			continue
+		case pr.Head.Commit.Hash != e.HeadHash:
+			if err := q.leave(ctx, e, sdk.MergeQueueStatusEjected, "the pull request was updated, it has to be approved again"); err != nil {
+				return err
+			}
+			invalidated = true
+			continue
+		case pr.Base.Branch.DisplayID != e.BaseBranch:
+			if err := q.leave(ctx, e, sdk.MergeQueueStatusEjected, "the base branch of the pull request was changed"); err != nil {
+				return err
+			}
+			invalidated = true
+			continue
+		}
+
+		if e.Status == sdk.MergeQueueStatusBuilding {
+			status, err := q.runStatus(ctx, *e)
+			if err != nil {
+				return err
+			}
+			if sdk.StatusIsTerminated(status) {
+				if status != sdk.StatusSuccess {
+					if err := q.leave(ctx, e, sdk.MergeQueueStatusEjected, fmt.Sprintf("the candidate failed in run #%d", e.WorkflowRunNumber)); err != nil {
+						return err
+					}
+					invalidated = true
+					continue
+				}
+				e.Status = sdk.MergeQueueStatusPassed
+				e.Message = fmt.Sprintf("the candidate passed in run #%d", e.WorkflowRunNumber)
+				if err := q.save(ctx, e); err != nil {
+					return err
+				}
+			}
+		}
+		active = append(active, e)
+	}
+
+	// Merge the pull requests at the head of the queue
+	for len(active) > 0 && active[0].Status == sdk.MergeQueueStatusPassed {
+		e := active[0]
+		base, err := q.client.Branch(ctx, e.Repository, e.BaseBranch)
+		if err != nil {
+			return err
+		}
+		if base.LatestCommit != e.BaseHash {
+			// The base branch was updated outside of the queue, all the candidates are outdated
+			if err := q.requeueAll(ctx, active); err != nil {
+				return err
+			}
+			break
+		}
+
+		if err := q.client.PullRequestMerge(ctx, e.Repository, e.PullRequestID, sdk.VCSPullRequestMergeRequest{
+			Method: q.method,
+			Hash:   e.HeadHash,
+		}); err != nil {
+			if !sdk.ErrorIs(err, sdk.ErrConflict) {
+				return err
+			}
+			if err := q.leave(ctx, e, sdk.MergeQueueStatusEjected, "the repository manager refused to merge the pull request"); err != nil {
+				return err
+			}
+			active = active[1:]
+			if err := q.requeueAll(ctx, active); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := q.leave(ctx, e, sdk.MergeQueueStatusMerged, fmt.Sprintf("merged into %s", e.BaseBranch)); err != nil {
+			return err
+		}
+		active = active[1:]
+
+		// The next candidates were built on top of the merged pull request, so they are still up to date
+		merged, err := q.client.Branch(ctx, e.Repository, e.BaseBranch)
+		if err != nil {
+			return err
+		}
+		for _, a := range active {
+			if a.Status != sdk.MergeQueueStatusQueued && a.BaseHash == e.BaseHash {
+				a.BaseHash = merged.LatestCommit
+				if err := q.update(a); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Build the candidates of the queued pull requests
+	var previous []*sdk.MergeQueueEntry
+	for _, e := range active {
+		if e.Status != sdk.MergeQueueStatusQueued {
+			previous = append(previous, e)
+			continue
+		}
+		if len(previous) >= q.depth {
+			break
+		}
+		started, err := q.startCandidate(ctx, e, previous)
+		if err != nil {
+			return err
+		}
+		if started {
+			previous = append(previous, e)
+		}
+	}
+
+	return nil
+}
+
+// startCandidate creates the candidate branch of a pull request from the base branch, merges the previous pull requests
+// of the queue and the pull request in it, then starts the workflow on the candidate.
+// It returns false if the pull request was ejected.
+func (q *queue) startCandidate(ctx context.Context, e *sdk.MergeQueueEntry, previous []*sdk.MergeQueueEntry) (bool, error) {
+	base, err := q.client.Branch(ctx, e.Repository, e.BaseBranch)
+	if err != nil {
+		return false, err
+	}
+
+	branch := sdk.MergeQueueCandidateBranch(e.BaseBranch, e.PullRequestID)
+	if err := q.client.CreateBranch(ctx, e.Repository, branch, base.LatestCommit); err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotImplemented) {
+			return false, q.leave(ctx, e, sdk.MergeQueueStatusEjected, "the repository manager does not support merge queues")
+		}
+		return false, err
+	}
+	e.CandidateBranch = branch
+
+	var candidate sdk.VCSCommit
+	var ids []string
+	var fork bool
+	for _, p := range append(previous, e) {
+		candidate, err = q.client.MergeBranch(ctx, e.Repository, sdk.VCSBranchMergeRequest{
+			Branch:  branch,
+			Head:    p.HeadHash,
+			Message: fmt.Sprintf("Merge pull request #%d into %s", p.PullRequestID, branch),
+		})
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrConflict) {
+				return false, q.leave(ctx, e, sdk.MergeQueueStatusEjected, "the pull request conflicts with the base branch or with the pull requests ahead in the queue")
+			}
+			return false, err
+		}
+		ids = append(ids, strconv.Itoa(p.PullRequestID))
+		fork = fork || p.Fork
+	}
+
+	event := sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: q.hookUUID,
+		Payload:              candidatePayload(*e, base.LatestCommit, candidate.Hash, ids, fork),
+	}
+	wr, err := q.startRun(ctx, *e, event)
+	if err != nil {
+		log.Error(ctx, "mergequeue.startCandidate> unable to start the run of the candidate of pull request #%d: %v", e.PullRequestID, err)
+		return false, q.leave(ctx, e, sdk.MergeQueueStatusEjected, "the workflow could not be started on the candidate")
+	}
+
+	e.Status = sdk.MergeQueueStatusBuilding
+	e.Message = fmt.Sprintf("the candidate is built in run #%d", wr.Number)
+	e.BaseHash = base.LatestCommit
+	e.CandidateHash = candidate.Hash
+	e.WorkflowRunID = wr.ID
+	e.WorkflowRunNumber = wr.Number
+	return true, q.save(ctx, e)
+}
+
+// candidatePayload returns the payload of the run of a candidate.
+// The candidate is a fork if one of the pull requests it contains comes from a fork, so that the secrets are filtered.
+func candidatePayload(e sdk.MergeQueueEntry, baseHash, candidateHash string, pullRequestIDs []string, fork bool) map[string]string {
+	shortHash := candidateHash
+	if len(shortHash) >= 7 {
+		shortHash = shortHash[:7]
+	}
+	return map[string]string{
+		"git.hook":                      "merge_queue",
+		"git.repository":                e.Repository,
+		"git.branch":                    e.CandidateBranch,
+		"git.hash":                      candidateHash,
+		"git.hash.short":                shortHash,
+		"git.branch.dest":               e.BaseBranch,
+		"git.hash.dest":                 baseHash,
+		"git.author":                    e.PullRequestAuthor,
+		"git.message":                   fmt.Sprintf("Merge queue candidate of pull request #%d", e.PullRequestID),
+		"git.pr.id":                     strconv.Itoa(e.PullRequestID),
+		"git.pr.title":                  e.PullRequestTitle,
+		"git.pr.author":                 e.PullRequestAuthor,
+		"git.pr.head.hash":              e.HeadHash,
+		"git.pr.fork":                   strconv.FormatBool(fork),
+		"git.merge_queue.pull_requests": strings.Join(pullRequestIDs, ","),
+		"cds.triggered_by.username":     e.PullRequestAuthor,
+	}
+}
+
+// requeue puts a pull request back in the queue, its candidate will be rebuilt.
+func (q *queue) requeue(ctx context.Context, e *sdk.MergeQueueEntry) error {
+	q.stopCandidate(ctx, e)
+	q.deleteCandidate(ctx, e)
+	e.Status = sdk.MergeQueueStatusQueued
+	e.Message = "the candidate will be rebuilt because the queue changed"
+	e.BaseHash = ""
+	e.CandidateHash = ""
+	e.WorkflowRunID = 0
+	e.WorkflowRunNumber = 0
+	return q.save(ctx, e)
+}
+
+func (q *queue) requeueAll(ctx context.Context, entries []*sdk.MergeQueueEntry) error {
+	for _, e := range entries {
+		if e.Status == sdk.MergeQueueStatusQueued {
+			continue
+		}
+		if err := q.requeue(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leave removes a pull request from the queue. The author is notified on the pull request when it is ejected.
+func (q *queue) leave(ctx context.Context, e *sdk.MergeQueueEntry, status, message string) error {
+	q.stopCandidate(ctx, e)
+	q.deleteCandidate(ctx, e)
+	e.Status = status
+	e.Message = message
+	if err := q.save(ctx, e); err != nil {
+		return err
+	}
+	if status == sdk.MergeQueueStatusEjected {
+		comment := sdk.VCSPullRequestCommentRequest{
+			Message: fmt.Sprintf("This pull request was ejected from the merge queue of workflow %s/%s: %s.", q.projectKey, q.workflow.Name, message),
+		}
+		comment.ID = e.PullRequestID
+		if err := q.client.PullRequestComment(ctx, e.Repository, comment); err != nil {
+			log.Error(ctx, "mergequeue.leave> unable to comment pull request #%d: %v", e.PullRequestID, err)
+		}
+	}
+	return nil
+}
+
+// stopCandidate stops the run of the candidate of a pull request that is still building, its result would be outdated.
+func (q *queue) stopCandidate(ctx context.Context, e *sdk.MergeQueueEntry) {
+	if e.Status != sdk.MergeQueueStatusBuilding || e.WorkflowRunID == 0 {
+		return
+	}
+	if err := q.stopRun(ctx, *e); err != nil {
+		log.Error(ctx, "mergequeue.stopCandidate> unable to stop run #%d of the candidate of pull request #%d: %v", e.WorkflowRunNumber, e.PullRequestID, err)
+	}
+}
+
+func (q *queue) deleteCandidate(ctx context.Context, e *sdk.MergeQueueEntry) {
+	if e.CandidateBranch == "" {
+		return
+	}
+	if err := q.client.DeleteBranch(ctx, e.Repository, e.CandidateBranch); err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		log.Error(ctx, "mergequeue.deleteCandidate> unable to delete branch %s: %v", e.CandidateBranch, err)
+	}
+	e.CandidateBranch = ""
+}
+
+// save updates the entry and sets its status on the pull request.
+func (q *queue) save(ctx context.Context, e *sdk.MergeQueueEntry) error {
+	if err := q.update(e); err != nil {
+		return err
+	}
+	SendStatus(ctx, q.client, q.projectKey, q.workflow.Name, *e)
+	return nil
+}
+
+// SendStatus sets the status of an entry of a merge queue on the head commit of its pull request.
+func SendStatus(ctx context.Context, client sdk.VCSAuthorizedClient, projectKey, workflowName string, e sdk.MergeQueueEntry) {
+	payload, _ := json.Marshal(sdk.EventMergeQueue{
+		RepositoryFullName: e.Repository,
+		Hash:               e.HeadHash,
+		PullRequestID:      e.PullRequestID,
+		Status:             e.Status,
+		Description:        statusDescription(e),
+		WorkflowRunNumber:  e.WorkflowRunNumber,
+	})
+	evt := sdk.Event{
+		EventType:    fmt.Sprintf("%T", sdk.EventMergeQueue{}),
+		Payload:      payload,
+		Timestamp:    time.Now(),
+		ProjectKey:   projectKey,
+		WorkflowName: workflowName,
+	}
+	if err := client.SetStatus(ctx, evt); err != nil {
+		log.Error(ctx, "mergequeue.SendStatus> unable to set status of pull request #%d: %v", e.PullRequestID, err)
+	}
+}
+
+func statusDescription(e sdk.MergeQueueEntry) string {
+	if e.Status == sdk.MergeQueueStatusQueued && e.Message == "" {
+		return "waiting in the merge queue"
+	}
+	if e.Message == "" {
+		return strings.ToLower(e.Status)
+	}
+	return e.Message
+}
@@ -0,0 +1,257 @@
+package mergequeue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// fakeClient implements the part of a repositories manager client used by the merge queue
+type fakeClient struct {
+	sdk.VCSAuthorizedClient
+	base      string
+	prs       map[int]*sdk.VCSPullRequest
+	branches  map[string][]string
+	conflicts map[string]bool
+	merged    []int
+	comments  []int
+	statuses  []sdk.EventMergeQueue
+}
+
+func newFakeClient(prIDs ...int) *fakeClient {
+	c := &fakeClient{
+		base:      "base-0",
+		prs:       make(map[int]*sdk.VCSPullRequest),
+		branches:  make(map[string][]string),
+		conflicts: make(map[string]bool),
+	}
+	for _, id := range prIDs {
+		pr := &sdk.VCSPullRequest{ID: id}
+		pr.Head.Commit.Hash = fmt.Sprintf("head-%d", id)
+		pr.Base.Branch.DisplayID = "master"
+		c.prs[id] = pr
+	}
+	return c
+}
+
+func (c *fakeClient) PullRequest(ctx context.Context, repo string, id int) (sdk.VCSPullRequest, error) {
+	return *c.prs[id], nil
+}
+
+func (c *fakeClient) Branch(ctx context.Context, repo, branch string) (*sdk.VCSBranch, error) {
+	return &sdk.VCSBranch{DisplayID: branch, LatestCommit: c.base}, nil
+}
+
+func (c *fakeClient) CreateBranch(ctx context.Context, repo, branch, hash string) error {
+	c.branches[branch] = []string{hash}
+	return nil
+}
+
+func (c *fakeClient) DeleteBranch(ctx context.Context, repo, branch string) error {
+	delete(c.branches, branch)
+	return nil
+}
+
+func (c *fakeClient) MergeBranch(ctx context.Context, repo string, merge sdk.VCSBranchMergeRequest) (sdk.VCSCommit, error) {
+	if c.conflicts[merge.Head] {
+		return sdk.VCSCommit{}, sdk.WithStack(sdk.ErrConflict)
+	}
+	c.branches[merge.Branch] = append(c.branches[merge.Branch], merge.Head)
+	return sdk.VCSCommit{Hash: fmt.Sprintf("candidate-%d", len(c.branches[merge.Branch]))}, nil
+}
+
+func (c *fakeClient) PullRequestMerge(ctx context.Context, repo string, id int, merge sdk.VCSPullRequestMergeRequest) error {
+	c.merged = append(c.merged, id)
+	c.base = fmt.Sprintf("merge-%d", id)
+	c.prs[id].Merged = true
+	return nil
+}
+
+func (c *fakeClient) PullRequestComment(ctx context.Context, repo string, comment sdk.VCSPullRequestCommentRequest) error {
+	c.comments = append(c.comments, comment.ID)
+	return nil
+}
+
+func (c *fakeClient) SetStatus(ctx context.Context, evt sdk.Event) error {
+	var e sdk.EventMergeQueue
+	if err := json.Unmarshal(evt.Payload, &e); err != nil {
+		return err
+	}
+	c.statuses = append(c.statuses, e)
+	return nil
+}
+
+type fakeRuns struct {
+	statuses map[int64]string
+	payloads map[int64]map[string]string
+	stopped  []int64
+}
+
+func newTestQueue(client *fakeClient, depth int) (*queue, *fakeRuns) {
+	runs := &fakeRuns{
+		statuses: make(map[int64]string),
+		payloads: make(map[int64]map[string]string),
+	}
+	q := &queue{
+		projectKey: "PROJ",
+		workflow:   &sdk.Workflow{Name: "build"},
+		hookUUID:   "hook-uuid",
+		method:     sdk.VCSMergeMethodMerge,
+		depth:      depth,
+		client:     client,
+		update:     func(e *sdk.MergeQueueEntry) error { return nil },
+		runStatus: func(ctx context.Context, e sdk.MergeQueueEntry) (string, error) {
+			return runs.statuses[e.WorkflowRunID], nil
+		},
+		startRun: func(ctx context.Context, e sdk.MergeQueueEntry, event sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error) {
+			id := int64(len(runs.statuses) + 1)
+			runs.statuses[id] = sdk.StatusBuilding
+			runs.payloads[id] = event.Payload
+			return &sdk.WorkflowRun{ID: id, Number: id}, nil
+		},
+		stopRun: func(ctx context.Context, e sdk.MergeQueueEntry) error {
+			if !sdk.StatusIsTerminated(runs.statuses[e.WorkflowRunID]) {
+				runs.statuses[e.WorkflowRunID] = sdk.StatusStopped
+				runs.stopped = append(runs.stopped, e.WorkflowRunID)
+			}
+			return nil
+		},
+	}
+	return q, runs
+}
+
+func newTestEntries(prIDs ...int) []sdk.MergeQueueEntry {
+	var entries []sdk.MergeQueueEntry
+	for i, id := range prIDs {
+		entries = append(entries, sdk.MergeQueueEntry{
+			ID:            int64(i + 1),
+			HookUUID:      "hook-uuid",
+			Repository:    "ovh/cds",
+			BaseBranch:    "master",
+			PullRequestID: id,
+			HeadHash:      fmt.Sprintf("head-%d", id),
+			Status:        sdk.MergeQueueStatusQueued,
+		})
+	}
+	return entries
+}
+
+// processActive processes the active entries of the queue like the routine does after loading them from the database
+func processActive(t *testing.T, q *queue, entries []sdk.MergeQueueEntry) {
+	var active []sdk.MergeQueueEntry
+	var indexes []int
+	for i := range entries {
+		if entries[i].IsActive() {
+			active = append(active, entries[i])
+			indexes = append(indexes, i)
+		}
+	}
+	require.NoError(t, q.process(context.TODO(), active))
+	for i, idx := range indexes {
+		entries[idx] = active[i]
+	}
+}
+
+func Test_processBuildsAndMergesCandidates(t *testing.T) {
+	client := newFakeClient(10, 11, 12)
+	q, runs := newTestQueue(client, 2)
+	entries := newTestEntries(10, 11, 12)
+
+	// The two first pull requests are built, the second candidate contains the first pull request
+	processActive(t, q, entries)
+	assert.Equal(t, sdk.MergeQueueStatusBuilding, entries[0].Status)
+	assert.Equal(t, sdk.MergeQueueStatusBuilding, entries[1].Status)
+	assert.Equal(t, sdk.MergeQueueStatusQueued, entries[2].Status)
+	assert.Equal(t, []string{"base-0", "head-10", "head-11"}, client.branches["cds-merge-queue/master/pr-11"])
+	assert.Equal(t, "cds-merge-queue/master/pr-11", runs.payloads[2]["git.branch"])
+	assert.Equal(t, "10,11", runs.payloads[2]["git.merge_queue.pull_requests"])
+	assert.Equal(t, "false", runs.payloads[2]["git.pr.fork"])
+
+	// The first candidate passed, its pull request is merged and the third pull request is built
+	runs.statuses[1] = sdk.StatusSuccess
+	processActive(t, q, entries)
+	assert.Equal(t, []int{10}, client.merged)
+	assert.Equal(t, sdk.MergeQueueStatusMerged, entries[0].Status)
+	assert.Equal(t, "merge-10", entries[1].BaseHash)
+	assert.Equal(t, sdk.MergeQueueStatusBuilding, entries[2].Status)
+	assert.Equal(t, []string{"merge-10", "head-11", "head-12"}, client.branches["cds-merge-queue/master/pr-12"])
+	assert.NotContains(t, client.branches, "cds-merge-queue/master/pr-10")
+
+	// The second candidate passed, it was built on top of the merged pull request so it is merged without a rebuild
+	runs.statuses[2] = sdk.StatusSuccess
+	processActive(t, q, entries)
+	assert.Equal(t, []int{10, 11}, client.merged)
+	assert.Equal(t, sdk.MergeQueueStatusMerged, entries[1].Status)
+	assert.Equal(t, sdk.MergeQueueStatusBuilding, entries[2].Status)
+	assert.Empty(t, client.comments)
+}
+
+func Test_processEjectsFailedCandidate(t *testing.T) {
+	client := newFakeClient(10, 11)
+	q, runs := newTestQueue(client, 2)
+	entries := newTestEntries(10, 11)
+
+	processActive(t, q, entries)
+	require.Equal(t, sdk.MergeQueueStatusBuilding, entries[1].Status)
+
+	// The first candidate failed, the second one is rebuilt without the first pull request
+	runs.statuses[1] = sdk.StatusFail
+	processActive(t, q, entries)
+	assert.Equal(t, sdk.MergeQueueStatusEjected, entries[0].Status)
+	assert.Equal(t, []int{10}, client.comments)
+	assert.Equal(t, sdk.MergeQueueStatusBuilding, entries[1].Status)
+	assert.Equal(t, int64(3), entries[1].WorkflowRunID)
+	assert.Equal(t, []string{"base-0", "head-11"}, client.branches["cds-merge-queue/master/pr-11"])
+	assert.Empty(t, client.merged)
+
+	// The outdated run of the second candidate was stopped, the failed one was left as is
+	assert.Equal(t, []int64{2}, runs.stopped)
+
+	last := client.statuses[len(client.statuses)-1]
+	assert.Equal(t, 11, last.PullRequestID)
+	assert.Equal(t, sdk.MergeQueueStatusBuilding, last.Status)
+}
+
+func Test_processEjectsUpdatedAndConflictingPullRequests(t *testing.T) {
+	client := newFakeClient(10, 11)
+	client.conflicts["head-11"] = true
+	q, runs := newTestQueue(client, 1)
+	entries := newTestEntries(10, 11)
+
+	processActive(t, q, entries)
+	assert.Equal(t, sdk.MergeQueueStatusBuilding, entries[0].Status)
+	assert.Equal(t, sdk.MergeQueueStatusQueued, entries[1].Status)
+
+	// The first pull request was updated after its approval, the second one conflicts with the base branch
+	client.prs[10].Head.Commit.Hash = "head-10-bis"
+	processActive(t, q, entries)
+	assert.Equal(t, sdk.MergeQueueStatusEjected, entries[0].Status)
+	assert.Equal(t, "the pull request was updated, it has to be approved again", entries[0].Message)
+	assert.Equal(t, sdk.MergeQueueStatusEjected, entries[1].Status)
+	assert.Equal(t, []int{10, 11}, client.comments)
+	assert.Empty(t, client.branches)
+	assert.Equal(t, []int64{1}, runs.stopped)
+}
+
+func Test_processRebuildsWhenBaseBranchMoved(t *testing.T) {
+	client := newFakeClient(10)
+	q, runs := newTestQueue(client, 1)
+	entries := newTestEntries(10)
+
+	processActive(t, q, entries)
+	runs.statuses[1] = sdk.StatusSuccess
+	client.base = "base-1"
+
+	// The candidate passed but the base branch was updated meanwhile, it is rebuilt instead of being merged
+	processActive(t, q, entries)
+	assert.Empty(t, client.merged)
+	assert.Equal(t, sdk.MergeQueueStatusBuilding, entries[0].Status)
+	assert.Equal(t, "base-1", entries[0].BaseHash)
+	assert.Equal(t, int64(2), entries[0].WorkflowRunID)
+}
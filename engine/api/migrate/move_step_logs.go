@@ -0,0 +1,50 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// MoveStepLogsToObjectStore moves the content of the step logs kept in database to the object store log storage.
+func MoveStepLogsToObjectStore(ctx context.Context, db *gorp.DbMap) error {
+	var lastID int64
+	for {
+		var ids []int64
+		if _, err := db.Select(&ids, `
+			SELECT id FROM workflow_node_run_job_logs
+			WHERE storage = $1 AND id > $2
+			ORDER BY id
+			LIMIT 1000`, workflow.LogStorageDatabase, lastID); err != nil {
+			return sdk.WrapError(err, "unable to select step logs")
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, id := range ids {
+			if err := moveStepLogToObjectStore(db, id); err != nil {
+				log.Error(ctx, "migrate.MoveStepLogsToObjectStore> unable to move step log %d: %v", id, err)
+			}
+		}
+		lastID = ids[len(ids)-1]
+		log.Info(ctx, "migrate.MoveStepLogsToObjectStore> step logs moved until %d", lastID)
+	}
+}
+
+func moveStepLogToObjectStore(db *gorp.DbMap, id int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	if err := workflow.MoveStepLogToStorage(tx, id, workflow.LogStorageObjectStore); err != nil {
+		return err
+	}
+	return sdk.WithStack(tx.Commit())
+}
@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// GenerateWebHookSecrets generates the secrets of the repository webhooks created before they were introduced.
+// The workflows are updated so the secrets are sent to the hooks service and to the repository managers.
+func GenerateWebHookSecrets(ctx context.Context, db *gorp.DbMap, store cache.Store) error {
+	q := `
+	SELECT DISTINCT w_node.workflow_id FROM w_node_hook
+	JOIN w_node ON w_node.id = w_node_hook.node_id
+	JOIN workflow_hook_model ON workflow_hook_model.id = w_node_hook.hook_model_id
+	WHERE workflow_hook_model.name = $1
+	AND COALESCE(w_node_hook.config->$2->>'value', '') = ''`
+	var ids []int64
+	if _, err := db.Select(&ids, q, sdk.RepositoryWebHookModelName, sdk.HookConfigWebHookSecret); err != nil {
+		return sdk.WrapError(err, "unable to select workflow")
+	}
+
+	var mError = new(sdk.MultiError)
+	for _, id := range ids {
+		if err := generateWebHookSecrets(ctx, db, store, id); err != nil {
+			mError.Append(err)
+			log.Error(ctx, "migrate.GenerateWebHookSecrets> unable to update workflow %d: %v", id, err)
+		}
+	}
+
+	if mError.IsEmpty() {
+		return nil
+	}
+	return mError
+}
+
+func generateWebHookSecrets(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowID int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+
+	defer tx.Rollback() // nolint
+
+	projectID, err := tx.SelectInt("SELECT project_id FROM workflow WHERE id = $1", workflowID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WithStack(err)
+	}
+
+	if projectID == 0 {
+		return nil
+	}
+
+	proj, err := project.LoadByID(tx, projectID,
+		project.LoadOptions.WithApplicationWithDeploymentStrategies,
+		project.LoadOptions.WithPipelines,
+		project.LoadOptions.WithEnvironments,
+		project.LoadOptions.WithIntegrations)
+	if err != nil {
+		return sdk.WrapError(err, "unable to load project %d", projectID)
+	}
+
+	w, err := workflow.LoadAndLockByID(ctx, tx, store, *proj, workflowID, workflow.LoadOptions{})
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// The hooks registration generates the missing secrets
+	if err := workflow.Update(ctx, tx, store, *proj, w, workflow.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+	log.Info(ctx, "migrate.generateWebHookSecrets> webhook secrets of workflow %s/%s (%d) have been generated", proj.Name, w.Name, w.ID)
+
+	return nil
+}
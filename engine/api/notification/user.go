@@ -174,9 +174,12 @@ func ShouldSendUserWorkflowNotification(ctx context.Context, notif sdk.WorkflowN
 func checkConditions(ctx context.Context, conditions sdk.WorkflowNodeConditions, params []sdk.Parameter) bool {
 	var conditionsOK bool
 	var errc error
-	if conditions.LuaScript == "" {
+	switch {
+	case conditions.Expression != "":
+		conditionsOK, errc = sdk.WorkflowCheckConditionExpression(conditions.Expression, params)
+	case conditions.LuaScript == "":
 		conditionsOK, errc = sdk.WorkflowCheckConditions(conditions.PlainConditions, params)
-	} else {
+	default:
 		luacheck, err := luascript.NewCheck()
 		if err != nil {
 			log.Error(ctx, "notification check condition error: %s", err)
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"path"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -189,6 +191,22 @@ func (s *AWSS3Store) DeleteContainer(ctx context.Context, path string) error {
 	return nil
 }
 
+// Size returns the size of an object
+func (s *AWSS3Store) Size(o Object) (int64, error) {
+	s3n := s3.New(s.sess)
+	out, err := s3n.HeadObject(&s3.HeadObjectInput{
+		Key:    aws.String(s.getObjectPath(o)),
+		Bucket: aws.String(s.bucketName),
+	})
+	if err != nil {
+		if reqErr, ok := err.(awserr.RequestFailure); ok && reqErr.StatusCode() == http.StatusNotFound {
+			return 0, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return 0, sdk.WrapError(err, "AWS-S3-Store> Unable to get object %s", s.getObjectPath(o))
+	}
+	return aws.Int64Value(out.ContentLength), nil
+}
+
 // FetchURL returns a temporary url and a secret key to fetch an object
 func (s *AWSS3Store) FetchURL(o Object) (string, string, error) {
 	log.Debug("AWS-S3-Store> FetchURL")
@@ -215,6 +215,22 @@ func (s *SwiftStore) StoreURL(o Object, contentType string) (string, string, err
 	return url, string(key), nil
 }
 
+// Size returns the size of an object
+func (s *SwiftStore) Size(o Object) (int64, error) {
+	container := s.containerPrefix + o.GetPath()
+	object := o.GetName()
+	escape(container, object)
+
+	info, _, err := s.Object(container, object)
+	if err != nil {
+		if err.Error() == swift.ObjectNotFound.Text {
+			return 0, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return 0, sdk.WrapError(err, "Unable to get object %s/%s", container, object)
+	}
+	return info.Bytes, nil
+}
+
 // ServeStaticFilesURL returns a temporary url and a secret key to serve static files in a container
 func (s *SwiftStore) ServeStaticFilesURL(o Object, entrypoint string) (string, string, error) {
 	if !swiftServeStaticFileEnabled {
@@ -158,6 +158,11 @@ func deleteWorkflowRunsHistory(ctx context.Context, db gorp.SqlExecutor, store c
 			continue
 		}
 
+		if err := deleteStepLogs(ctx, db, workflowRunID); err != nil {
+			log.Error(ctx, "deleteStepLogs> error while deleting step logs: %v", err)
+			continue
+		}
+
 		res, err := db.Exec("DELETE FROM workflow_run WHERE workflow_run.id = $1", workflowRunID)
 		if err != nil {
 			log.Error(ctx, "deleteWorkflowRunsHistory> unable to delete workflow run %d: %v", workflowRunID, err)
@@ -253,3 +258,17 @@ func DeleteArtifacts(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 
 	return nil
 }
+
+// deleteStepLogs removes step logs content from log storages, indexes are deleted with the workflow run
+func deleteStepLogs(ctx context.Context, db gorp.SqlExecutor, workflowRunID int64) error {
+	var nodeRunIDs []int64
+	if _, err := db.Select(&nodeRunIDs, "SELECT id FROM workflow_node_run WHERE workflow_run_id = $1", workflowRunID); err != nil {
+		return sdk.WrapError(err, "cannot load node runs of workflow run %d", workflowRunID)
+	}
+	for _, id := range nodeRunIDs {
+		if err := workflow.DeleteNodeRunLogs(ctx, db, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/quota"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getQuotasHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		qs, err := quota.LoadAll(ctx, api.mustDB())
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, qs, http.StatusOK)
+	}
+}
+
+func (api *API) postQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var q sdk.Quota
+		if err := service.UnmarshalBody(r, &q); err != nil {
+			return err
+		}
+		if err := q.IsValid(); err != nil {
+			return err
+		}
+
+		if err := quota.Insert(ctx, api.mustDB(), &q); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, q, http.StatusCreated)
+	}
+}
+
+func (api *API) putQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		old, err := quota.LoadByID(ctx, api.mustDB(), id)
+		if err != nil {
+			return err
+		}
+
+		var q sdk.Quota
+		if err := service.UnmarshalBody(r, &q); err != nil {
+			return err
+		}
+		if err := q.IsValid(); err != nil {
+			return err
+		}
+		q.ID = old.ID
+
+		if err := quota.Update(ctx, api.mustDB(), &q); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, q, http.StatusOK)
+	}
+}
+
+func (api *API) deleteQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "id")
+		if err != nil {
+			return err
+		}
+
+		q, err := quota.LoadByID(ctx, api.mustDB(), id)
+		if err != nil {
+			return err
+		}
+
+		if err := quota.Delete(api.mustDB(), *q); err != nil {
+			return err
+		}
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+func (api *API) getProjectQuotaUsageHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		key := mux.Vars(r)[permProjectKey]
+
+		p, err := project.Load(api.mustDB(), key)
+		if err != nil {
+			return err
+		}
+
+		usages, err := quota.ComputeProjectUsage(ctx, api.mustDB(), api.Cache, p.ID)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, usages, http.StatusOK)
+	}
+}
@@ -0,0 +1,140 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAll(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]sdk.Quota, error) {
+	var qs []dbQuota
+	if err := gorpmapping.GetAll(ctx, db, q, &qs); err != nil {
+		return nil, sdk.WrapError(err, "cannot get quotas")
+	}
+	res := make([]sdk.Quota, len(qs))
+	for i := range qs {
+		res[i] = sdk.Quota(qs[i])
+		if err := fillTarget(ctx, db, &res[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// fillTarget sets the key of the project or the name of the group of the quota.
+func fillTarget(ctx context.Context, db gorp.SqlExecutor, q *sdk.Quota) error {
+	if q.ProjectID != nil {
+		p, err := project.LoadByID(db, *q.ProjectID)
+		if err != nil {
+			return err
+		}
+		q.ProjectKey = p.Key
+	}
+	if q.GroupID != nil {
+		g, err := group.LoadByID(ctx, db, *q.GroupID)
+		if err != nil {
+			return err
+		}
+		q.GroupName = g.Name
+	}
+	return nil
+}
+
+// LoadAll returns all the quotas.
+func LoadAll(ctx context.Context, db gorp.SqlExecutor) ([]sdk.Quota, error) {
+	return getAll(ctx, db, gorpmapping.NewQuery("SELECT * FROM quota ORDER BY id"))
+}
+
+// LoadByID returns a quota.
+func LoadByID(ctx context.Context, db gorp.SqlExecutor, id int64) (*sdk.Quota, error) {
+	qs, err := getAll(ctx, db, gorpmapping.NewQuery("SELECT * FROM quota WHERE id = $1").Args(id))
+	if err != nil {
+		return nil, err
+	}
+	if len(qs) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &qs[0], nil
+}
+
+// LoadAllByProjectID returns the quota of a project and the quotas of the groups that own it.
+func LoadAllByProjectID(ctx context.Context, db gorp.SqlExecutor, projectID int64) ([]sdk.Quota, error) {
+	links, err := group.LoadLinksGroupProjectForProjectIDs(ctx, db, []int64{projectID})
+	if err != nil {
+		return nil, err
+	}
+	var groupIDs []int64
+	for _, l := range links {
+		if l.Role >= sdk.PermissionReadWriteExecute {
+			groupIDs = append(groupIDs, l.GroupID)
+		}
+	}
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM quota
+		WHERE project_id = $1 OR group_id = ANY(string_to_array($2, ',')::int[])
+		ORDER BY id`).Args(projectID, gorpmapping.IDsToQueryString(groupIDs))
+	return getAll(ctx, db, query)
+}
+
+// Insert a quota, its project key or group name should be set.
+func Insert(ctx context.Context, db gorp.SqlExecutor, q *sdk.Quota) error {
+	if err := resolveTarget(ctx, db, q); err != nil {
+		return err
+	}
+	dbQ := dbQuota(*q)
+	if err := gorpmapping.Insert(db, &dbQ); err != nil {
+		if e, ok := sdk.Cause(err).(*pq.Error); ok && e.Code == gorpmapping.ViolateUniqueKeyPGCode {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "a quota already exists for %s", q)
+		}
+		return sdk.WrapError(err, "cannot insert quota")
+	}
+	*q = sdk.Quota(dbQ)
+	return nil
+}
+
+// Update the limits of a quota.
+func Update(ctx context.Context, db gorp.SqlExecutor, q *sdk.Quota) error {
+	if err := resolveTarget(ctx, db, q); err != nil {
+		return err
+	}
+	dbQ := dbQuota(*q)
+	if err := gorpmapping.Update(db, &dbQ); err != nil {
+		return sdk.WrapError(err, "cannot update quota %d", q.ID)
+	}
+	return nil
+}
+
+// Delete a quota.
+func Delete(db gorp.SqlExecutor, q sdk.Quota) error {
+	dbQ := dbQuota(q)
+	if err := gorpmapping.Delete(db, &dbQ); err != nil {
+		return sdk.WrapError(err, "cannot delete quota %d", q.ID)
+	}
+	return nil
+}
+
+// resolveTarget sets the project or group id of the quota from its project key or group name.
+func resolveTarget(ctx context.Context, db gorp.SqlExecutor, q *sdk.Quota) error {
+	q.ProjectID, q.GroupID = nil, nil
+	if q.ProjectKey != "" {
+		p, err := project.Load(db, q.ProjectKey)
+		if err != nil {
+			return err
+		}
+		q.ProjectID = &p.ID
+	}
+	if q.GroupName != "" {
+		g, err := group.LoadByName(ctx, db, q.GroupName)
+		if err != nil {
+			return err
+		}
+		q.GroupID = &g.ID
+	}
+	return nil
+}
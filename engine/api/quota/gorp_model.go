@@ -0,0 +1,12 @@
+package quota
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbQuota sdk.Quota
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbQuota{}, "quota", true, "id"))
+}
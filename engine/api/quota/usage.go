@@ -0,0 +1,120 @@
+package quota
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// projectIDs returns the ids of the projects concerned by a quota.
+func projectIDs(ctx context.Context, db gorp.SqlExecutor, q sdk.Quota) ([]int64, error) {
+	if q.ProjectID != nil {
+		return []int64{*q.ProjectID}, nil
+	}
+	if q.GroupID == nil {
+		return nil, nil
+	}
+	links, err := group.LoadLinksGroupProjectForGroupID(ctx, db, *q.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for _, l := range links {
+		if l.Role >= sdk.PermissionReadWriteExecute {
+			ids = append(ids, l.ProjectID)
+		}
+	}
+	return ids, nil
+}
+
+// ComputeUsage returns the current usage of a quota: building jobs and waiting jobs booked by a hatchery
+// are counted, except the job with given id.
+func ComputeUsage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, q sdk.Quota, excludedJobID int64) (sdk.QuotaUsage, error) {
+	usage := sdk.QuotaUsage{Quota: q}
+	ids, err := projectIDs(ctx, db, q)
+	if err != nil || len(ids) == 0 {
+		return usage, err
+	}
+	rows, err := workflow.CountNodeJobRunsUsageByProjectIDs(ctx, db, store, ids, excludedJobID)
+	if err != nil {
+		return usage, err
+	}
+	for _, r := range rows {
+		usage.Jobs += r.Jobs
+		usage.Memory += r.Memory
+		if r.Model != "" {
+			if usage.WorkersByModel == nil {
+				usage.WorkersByModel = make(map[string]int64)
+			}
+			usage.WorkersByModel[r.Model] += r.Jobs
+		}
+	}
+	return usage, nil
+}
+
+// ComputeProjectUsage returns the usage of all the quotas of a project.
+func ComputeProjectUsage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, projectID int64) ([]sdk.QuotaUsage, error) {
+	qs, err := LoadAllByProjectID(ctx, db, projectID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]sdk.QuotaUsage, 0, len(qs))
+	for _, q := range qs {
+		u, err := ComputeUsage(ctx, db, store, q, 0)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, u)
+	}
+	return res, nil
+}
+
+// lockKey returns the cache key locked while a job is checked and started against a quota.
+func lockKey(q sdk.Quota) string {
+	return cache.Key("quota", "lock", strconv.FormatInt(q.ID, 10))
+}
+
+// CheckAndStartJob returns an ErrJobQuotaExceeded error if starting given job with given worker model would exceed
+// one of the quotas of its project, else it calls start to book or take the job. The quotas are locked until start
+// returns so concurrent jobs can't exceed them. The worker model is only checked if not empty.
+func CheckAndStartJob(ctx context.Context, db gorp.SqlExecutor, store cache.Store, j sdk.WorkflowNodeJobRun, model string, start func() error) error {
+	// Quotas are ordered by id so they are always locked in the same order
+	qs, err := LoadAllByProjectID(ctx, db, j.ProjectID)
+	if err != nil {
+		return err
+	}
+	for _, q := range qs {
+		k := lockKey(q)
+		locked, err := store.Lock(k, time.Minute, 50, 100)
+		if err != nil {
+			return sdk.WrapError(err, "cannot lock %s", k)
+		}
+		if !locked {
+			return sdk.NewErrorFrom(sdk.ErrLocked, "%s is being checked by another job", q)
+		}
+		defer func() {
+			if err := store.Unlock(k); err != nil {
+				log.Error(ctx, "cannot unlock %s: %v", k, err)
+			}
+		}()
+	}
+
+	for _, q := range qs {
+		u, err := ComputeUsage(ctx, db, store, q, j.ID)
+		if err != nil {
+			return err
+		}
+		if err := u.Check(j, model); err != nil {
+			return err
+		}
+	}
+	return start()
+}
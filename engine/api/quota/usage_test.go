@@ -0,0 +1,139 @@
+package quota_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/quota"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// insertQuotaJobs starts runs of a test workflow and returns the ids of their jobs.
+func insertQuotaJobs(t *testing.T, db *gorp.DbMap, store cache.Store, proj *sdk.Project, nb int) []int64 {
+	u, _ := assets.InsertAdminUser(t, db)
+	consumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+
+	w := assets.InsertTestWorkflow(t, db, store, proj, sdk.RandomString(10))
+	w, err = workflow.Load(context.TODO(), db, store, *proj, w.Name, workflow.LoadOptions{DeepPipeline: true})
+	require.NoError(t, err)
+
+	for i := 0; i < nb; i++ {
+		wr, err := workflow.CreateRun(db, w, nil, u)
+		require.NoError(t, err)
+		wr.Workflow = *w
+		_, err = workflow.StartWorkflowRun(context.TODO(), db, store, *proj, wr, &sdk.WorkflowRunPostHandlerOption{
+			Manual: &sdk.WorkflowNodeRunManual{Username: u.Username},
+		}, consumer, nil)
+		require.NoError(t, err)
+	}
+
+	var ids []int64
+	_, err = db.Select(&ids, "SELECT id FROM workflow_node_run_job WHERE project_id = $1 ORDER BY id", proj.ID)
+	require.NoError(t, err)
+	require.Len(t, ids, nb)
+	return ids
+}
+
+// setQuotaJob sets the status, the worker model and the memory requirement of a job.
+func setQuotaJob(t *testing.T, db gorp.SqlExecutor, id int64, status, model, memory string) {
+	reqs := "[]"
+	if memory != "" {
+		reqs = `[{"name":"memory","type":"` + sdk.MemoryRequirement + `","value":"` + memory + `"}]`
+	}
+	_, err := db.Exec(`
+		UPDATE workflow_node_run_job
+		SET status = $2, model = NULLIF($3, ''), job = jsonb_set(job, '{action,requirements}', $4::jsonb)
+		WHERE id = $1`, id, status, model, reqs)
+	require.NoError(t, err)
+}
+
+func TestComputeUsageAndCheckAndStartJob(t *testing.T) {
+	db, store, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, store, key, key)
+	ids := insertQuotaJobs(t, db, store, proj, 5)
+
+	// Two building jobs, one waiting job booked by a hatchery and two waiting jobs
+	setQuotaJob(t, db, ids[0], sdk.StatusBuilding, "go", "1024")
+	setQuotaJob(t, db, ids[1], sdk.StatusBuilding, "go", "")
+	setQuotaJob(t, db, ids[2], sdk.StatusWaiting, "", "512")
+	setQuotaJob(t, db, ids[3], sdk.StatusWaiting, "", "")
+	setQuotaJob(t, db, ids[4], sdk.StatusWaiting, "", "")
+	_, err := workflow.BookNodeJobRun(context.TODO(), store, ids[2], &sdk.Service{CanonicalService: sdk.CanonicalService{ID: 1, Name: "my-hatchery"}})
+	require.NoError(t, err)
+
+	q := sdk.Quota{ProjectKey: proj.Key, MaxJobs: 3}
+	require.NoError(t, quota.Insert(context.TODO(), db, &q))
+
+	u, err := quota.ComputeUsage(context.TODO(), db, store, q, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), u.Jobs)
+	assert.Equal(t, int64(1536), u.Memory)
+	assert.Equal(t, int64(2), u.WorkersByModel["go"])
+
+	u, err = quota.ComputeUsage(context.TODO(), db, store, q, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), u.Jobs)
+	assert.Equal(t, int64(512), u.Memory)
+
+	// The quota is full, the job is not started
+	j3, err := workflow.LoadNodeJobRun(context.TODO(), db, store, ids[3])
+	require.NoError(t, err)
+	var started bool
+	err = quota.CheckAndStartJob(context.TODO(), db, store, *j3, "", func() error {
+		started = true
+		return nil
+	})
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrJobQuotaExceeded))
+	assert.False(t, started)
+
+	// One more job is allowed, only one of two concurrent jobs is booked
+	q.MaxJobs = 4
+	require.NoError(t, quota.Update(context.TODO(), db, &q))
+	j4, err := workflow.LoadNodeJobRun(context.TODO(), db, store, ids[4])
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, j := range []*sdk.WorkflowNodeJobRun{j3, j4} {
+		wg.Add(1)
+		go func(i int, j sdk.WorkflowNodeJobRun) {
+			defer wg.Done()
+			errs[i] = quota.CheckAndStartJob(context.TODO(), db, store, j, "", func() error {
+				_, err := workflow.BookNodeJobRun(context.TODO(), store, j.ID, &sdk.Service{CanonicalService: sdk.CanonicalService{ID: int64(i + 2), Name: "my-hatchery"}})
+				return err
+			})
+		}(i, *j)
+	}
+	wg.Wait()
+
+	var nbStarted, nbExceeded int
+	for _, err := range errs {
+		if err == nil {
+			nbStarted++
+		} else if sdk.ErrorIs(err, sdk.ErrJobQuotaExceeded) {
+			nbExceeded++
+		}
+	}
+	assert.Equal(t, 1, nbStarted)
+	assert.Equal(t, 1, nbExceeded)
+
+	u, err = quota.ComputeUsage(context.TODO(), db, store, q, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), u.Jobs)
+}
@@ -246,6 +246,10 @@ func (c *vcsClient) doJSONRequest(ctx context.Context, method, path string, in i
 			err = sdk.WrapError(sdk.ErrNotFound, "%s", err)
 		case http.StatusForbidden:
 			err = sdk.WrapError(sdk.ErrForbidden, "%s", err)
+		case http.StatusConflict:
+			err = sdk.WrapError(sdk.ErrConflict, "%s", err)
+		case http.StatusNotImplemented:
+			err = sdk.WrapError(sdk.ErrNotImplemented, "%s", err)
 		default:
 			err = sdk.WrapError(sdk.ErrUnknownError, "%s", err)
 		}
@@ -372,6 +376,32 @@ func (c *vcsClient) Branch(ctx context.Context, fullname string, branchName stri
 	return &branch, nil
 }
 
+func (c *vcsClient) CreateBranch(ctx context.Context, fullname, branch, hash string) error {
+	path := fmt.Sprintf("/vcs/%s/repos/%s/branches", c.name, fullname)
+	b := sdk.VCSBranch{DisplayID: branch, LatestCommit: hash}
+	if _, err := c.doJSONRequest(ctx, "POST", path, b, nil); err != nil {
+		return sdk.WrapError(err, "unable to create branch %s on repository %s from %s", branch, fullname, c.name)
+	}
+	return nil
+}
+
+func (c *vcsClient) DeleteBranch(ctx context.Context, fullname, branch string) error {
+	path := fmt.Sprintf("/vcs/%s/repos/%s/branches/?branch=%s", c.name, fullname, url.QueryEscape(branch))
+	if _, err := c.doJSONRequest(ctx, "DELETE", path, nil, nil); err != nil {
+		return sdk.WrapError(err, "unable to delete branch %s on repository %s from %s", branch, fullname, c.name)
+	}
+	return nil
+}
+
+func (c *vcsClient) MergeBranch(ctx context.Context, fullname string, merge sdk.VCSBranchMergeRequest) (sdk.VCSCommit, error) {
+	commit := sdk.VCSCommit{}
+	path := fmt.Sprintf("/vcs/%s/repos/%s/branches/merges", c.name, fullname)
+	if _, err := c.doJSONRequest(ctx, "POST", path, merge, &commit); err != nil {
+		return commit, sdk.WrapError(err, "unable to merge %s in branch %s on repository %s from %s", merge.Head, merge.Branch, fullname, c.name)
+	}
+	return commit, nil
+}
+
 // DefaultBranch get default branch from given repository
 func DefaultBranch(ctx context.Context, c sdk.VCSAuthorizedClient, fullname string) (sdk.VCSBranch, error) {
 	branches, err := c.Branches(ctx, fullname)
@@ -456,6 +486,14 @@ func (c *vcsClient) PullRequestCreate(ctx context.Context, fullname string, pr s
 	return pr, nil
 }
 
+func (c *vcsClient) PullRequestMerge(ctx context.Context, fullname string, id int, merge sdk.VCSPullRequestMergeRequest) error {
+	path := fmt.Sprintf("/vcs/%s/repos/%s/pullrequests/%d/merge", c.name, fullname, id)
+	if _, err := c.doJSONRequest(ctx, "POST", path, merge, nil); err != nil {
+		return sdk.WrapError(err, "unable to merge pullrequest %d on repository %s from %s", id, fullname, c.name)
+	}
+	return nil
+}
+
 func (c *vcsClient) CreateHook(ctx context.Context, fullname string, hook *sdk.VCSHook) error {
 	path := fmt.Sprintf("/vcs/%s/repos/%s/hooks", c.name, fullname)
 	_, err := c.doJSONRequest(ctx, "POST", path, hook, hook)
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// stepLogsPubSubKey returns the name of the channel where new parts of a step log are published.
+func stepLogsPubSubKey(jobID, stepOrder int64) string {
+	return cache.Key("step_logs_pubsub", strconv.FormatInt(jobID, 10), strconv.FormatInt(stepOrder, 10))
+}
+
+// publishStepLogChunk publishes a part of a step log received from a worker for the streaming clients.
+func publishStepLogChunk(ctx context.Context, store cache.Store, chunk sdk.StepLogChunk) {
+	b, err := json.Marshal(chunk)
+	if err != nil {
+		log.Warning(ctx, "publishStepLogChunk> cannot marshal chunk: %v", err)
+		return
+	}
+	if err := store.Publish(ctx, stepLogsPubSubKey(chunk.JobID, chunk.StepOrder), string(b)); err != nil {
+		log.Warning(ctx, "publishStepLogChunk> cannot publish chunk for job %d step %d: %v", chunk.JobID, chunk.StepOrder, err)
+	}
+}
+
+// findNodeRunJobStep returns the job run with given id in a node run and the status of its step, the status is empty if the step has not started.
+func findNodeRunJobStep(nodeRun *sdk.WorkflowNodeRun, runJobID, stepOrder int64) (*sdk.WorkflowNodeJobRun, string) {
+	for i := range nodeRun.Stages {
+		for j := range nodeRun.Stages[i].RunJobs {
+			rj := &nodeRun.Stages[i].RunJobs[j]
+			if rj.ID != runJobID {
+				continue
+			}
+			for _, ss := range rj.Job.StepStatus {
+				if int64(ss.StepOrder) == stepOrder {
+					return rj, ss.Status
+				}
+			}
+			return rj, ""
+		}
+	}
+	return nil, ""
+}
+
+// stepLogStream pushes the parts of a step log to a websocket from an offset.
+type stepLogStream struct {
+	con       *websocket.Conn
+	runJobID  int64
+	stepOrder int64
+	offset    int64
+}
+
+// sendFromStorage sends the part of the step log after the current offset loaded from its storage.
+func (s *stepLogStream) sendFromStorage(ctx context.Context, db func() *gorp.DbMap) error {
+	logs, err := workflow.LoadStepLogs(ctx, db(), s.runJobID, s.stepOrder)
+	if err != nil {
+		return err
+	}
+	if logs == nil || int64(len(logs.Val)) <= s.offset {
+		return nil
+	}
+	return s.send(sdk.StepLogChunk{Offset: s.offset, Val: logs.Val[s.offset:]})
+}
+
+// sendChunk sends the part of a published chunk after the current offset. The log is reloaded
+// from its storage if the chunk starts after the current offset, i.e. a chunk was missed.
+func (s *stepLogStream) sendChunk(ctx context.Context, db func() *gorp.DbMap, chunk sdk.StepLogChunk) error {
+	end := chunk.Offset + int64(len(chunk.Val))
+	switch {
+	case end <= s.offset:
+		return nil
+	case chunk.Offset > s.offset:
+		return s.sendFromStorage(ctx, db)
+	}
+	return s.send(sdk.StepLogChunk{Offset: s.offset, Val: chunk.Val[s.offset-chunk.Offset:]})
+}
+
+func (s *stepLogStream) send(chunk sdk.StepLogChunk) error {
+	chunk.JobID = s.runJobID
+	chunk.StepOrder = s.stepOrder
+	if err := s.con.WriteJSON(chunk); err != nil {
+		return sdk.WithStack(err)
+	}
+	s.offset += int64(len(chunk.Val))
+	return nil
+}
+
+func (api *API) getWorkflowNodeRunJobStepLogStreamHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		nodeRunID, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+		runJobID, err := requestVarInt(r, "runJobId")
+		if err != nil {
+			return err
+		}
+		stepOrder, err := requestVarInt(r, "stepOrder")
+		if err != nil {
+			return err
+		}
+		var offset int64
+		if s := r.FormValue("offset"); s != "" {
+			offset, err = strconv.ParseInt(s, 10, 64)
+			if err != nil || offset < 0 {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid offset %q", s)
+			}
+		}
+
+		loadStep := func() (*sdk.WorkflowNodeJobRun, string, error) {
+			nodeRun, err := workflow.LoadNodeRun(api.mustDB(), projectKey, workflowName, number, nodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+			if err != nil {
+				return nil, "", sdk.WrapError(err, "cannot find nodeRun %d/%d for workflow %s in project %s", nodeRunID, number, workflowName, projectKey)
+			}
+			rj, status := findNodeRunJobStep(nodeRun, runJobID, stepOrder)
+			return rj, status, nil
+		}
+		rj, _, err := loadStep()
+		if err != nil {
+			return err
+		}
+		if rj == nil {
+			return sdk.WrapError(sdk.ErrStepNotFound, "cannot find job %d in nodeRun %d/%d for workflow %s in project %s", runJobID, nodeRunID, number, workflowName, projectKey)
+		}
+
+		// Subscribe before loading the existing log to not miss any chunk
+		pubSub, err := api.Cache.Subscribe(stepLogsPubSubKey(runJobID, stepOrder))
+		if err != nil {
+			return sdk.WrapError(err, "unable to subscribe to step logs")
+		}
+		defer pubSub.Unsubscribe() // nolint
+
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Warning(ctx, "websocket> upgrade: %v", err)
+			return err
+		}
+		defer c.Close()
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// The client does not send messages, reading is used to detect when it leaves
+		sdk.GoRoutine(ctx, fmt.Sprintf("getWorkflowNodeRunJobStepLogStreamHandler-read-%d-%d", runJobID, stepOrder), func(ctx context.Context) {
+			defer cancel()
+			for {
+				if _, _, err := c.ReadMessage(); err != nil {
+					return
+				}
+			}
+		})
+
+		chunks := make(chan sdk.StepLogChunk, 100)
+		sdk.GoRoutine(ctx, fmt.Sprintf("getWorkflowNodeRunJobStepLogStreamHandler-sub-%d-%d", runJobID, stepOrder), func(ctx context.Context) {
+			for ctx.Err() == nil {
+				msg, err := api.Cache.GetMessageFromSubscription(ctx, pubSub)
+				if err != nil {
+					log.Warning(ctx, "getWorkflowNodeRunJobStepLogStreamHandler> cannot get message: %v", err)
+					continue
+				}
+				var chunk sdk.StepLogChunk
+				if err := json.Unmarshal([]byte(msg), &chunk); err != nil {
+					continue
+				}
+				select {
+				case chunks <- chunk:
+				case <-ctx.Done():
+				}
+			}
+		})
+
+		stream := &stepLogStream{con: c, runJobID: runJobID, stepOrder: stepOrder, offset: offset}
+		if err := stream.sendFromStorage(ctx, api.mustDB); err != nil {
+			log.Warning(ctx, "getWorkflowNodeRunJobStepLogStreamHandler> %v", err)
+			return nil
+		}
+
+		tick := time.NewTicker(2 * time.Second)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case chunk := <-chunks:
+				if err := stream.sendChunk(ctx, api.mustDB, chunk); err != nil {
+					log.Warning(ctx, "getWorkflowNodeRunJobStepLogStreamHandler> %v", err)
+					return nil
+				}
+			case <-tick.C:
+				rj, stepStatus, err := loadStep()
+				if err != nil {
+					log.Warning(ctx, "getWorkflowNodeRunJobStepLogStreamHandler> %v", err)
+					return nil
+				}
+				// The stream ends when the step or its job is terminated, a job run replaced by a retry is not in its node run anymore
+				status := stepStatus
+				if rj == nil {
+					status = sdk.StatusStopped
+				} else if !sdk.StatusIsTerminated(stepStatus) && sdk.StatusIsTerminated(rj.Status) {
+					status = rj.Status
+				}
+				if !sdk.StatusIsTerminated(status) {
+					continue
+				}
+				if err := stream.sendFromStorage(ctx, api.mustDB); err != nil {
+					log.Warning(ctx, "getWorkflowNodeRunJobStepLogStreamHandler> %v", err)
+					return nil
+				}
+				if err := c.WriteJSON(sdk.StepLogChunk{JobID: runJobID, StepOrder: stepOrder, Offset: stream.offset, Done: true, Status: status}); err != nil {
+					log.Warning(ctx, "getWorkflowNodeRunJobStepLogStreamHandler> %v", err)
+				}
+				_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return nil
+			}
+		}
+	}
+}
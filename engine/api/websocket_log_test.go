@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_stepLogStreamSendChunk(t *testing.T) {
+	received := make(chan sdk.StepLogChunk, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		stream := &stepLogStream{con: c, runJobID: 1, stepOrder: 2, offset: 5}
+		// already sent
+		require.NoError(t, stream.sendChunk(context.TODO(), nil, sdk.StepLogChunk{Offset: 0, Val: "hello"}))
+		// partially sent
+		require.NoError(t, stream.sendChunk(context.TODO(), nil, sdk.StepLogChunk{Offset: 3, Val: "lo world"}))
+		require.NoError(t, stream.sendChunk(context.TODO(), nil, sdk.StepLogChunk{Offset: 11, Val: "!"}))
+		assert.Equal(t, int64(12), stream.offset)
+		_ = c.Close()
+	}))
+	defer srv.Close()
+
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer c.Close()
+	for {
+		var chunk sdk.StepLogChunk
+		if err := c.ReadJSON(&chunk); err != nil {
+			break
+		}
+		received <- chunk
+	}
+	close(received)
+
+	var chunks []sdk.StepLogChunk
+	for c := range received {
+		chunks = append(chunks, c)
+	}
+	require.Len(t, chunks, 2)
+	assert.Equal(t, sdk.StepLogChunk{JobID: 1, StepOrder: 2, Offset: 5, Val: " world"}, chunks[0])
+	assert.Equal(t, sdk.StepLogChunk{JobID: 1, StepOrder: 2, Offset: 11, Val: "!"}, chunks[1])
+}
+
+func Test_findNodeRunJobStep(t *testing.T) {
+	nodeRun := &sdk.WorkflowNodeRun{
+		Stages: []sdk.Stage{{
+			RunJobs: []sdk.WorkflowNodeJobRun{{
+				ID: 1,
+				Job: sdk.ExecutedJob{
+					StepStatus: []sdk.StepStatus{{StepOrder: 0, Status: sdk.StatusSuccess}, {StepOrder: 1, Status: sdk.StatusBuilding}},
+				},
+			}},
+		}},
+	}
+	rj, status := findNodeRunJobStep(nodeRun, 1, 1)
+	require.NotNil(t, rj)
+	assert.Equal(t, sdk.StatusBuilding, status)
+
+	rj, status = findNodeRunJobStep(nodeRun, 1, 2)
+	require.NotNil(t, rj)
+	assert.Equal(t, "", status)
+
+	rj, _ = findNodeRunJobStep(nodeRun, 2, 0)
+	assert.Nil(t, rj)
+}
@@ -20,6 +20,16 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
+func (api *API) getWorkerVersionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		v := sdk.WorkerVersion{
+			MinVersion:         api.Config.Worker.MinVersion,
+			RecommendedVersion: api.Config.Worker.RecommendedVersion,
+		}
+		return service.WriteJSON(w, v, http.StatusOK)
+	}
+}
+
 func (api *API) postRegisterWorkerHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		// First get the jwt token to checks where this registration is coming from
@@ -33,6 +43,10 @@ func (api *API) postRegisterWorkerHandler() service.Handler {
 			return err
 		}
 
+		if min := api.Config.Worker.MinVersion; min != "" && sdk.VersionIsOlder(registrationForm.Version, min) {
+			return sdk.NewErrorFrom(sdk.ErrWorkerVersionNotSupported, "worker version %s is not supported, the min version is %s: please upgrade the worker binary", registrationForm.Version, min)
+		}
+
 		// Check that the worker can authentify on CDS API
 		workerTokenFromHatchery, err := workerauth.VerifyToken(ctx, api.mustDB(), jwt)
 		if err != nil {
@@ -123,6 +137,12 @@ func (api *API) getWorkersHandler() service.Handler {
 			if err != nil {
 				return err
 			}
+		} else if isWorker(ctx) {
+			wk, err := worker.LoadByConsumerID(ctx, api.mustDB(), getAPIConsumer(ctx).ID)
+			if err != nil {
+				return err
+			}
+			workers = []sdk.Worker{*wk}
 		} else if isMaintainer(ctx) {
 			workers, err = worker.LoadAll(ctx, api.mustDB())
 			if err != nil {
@@ -117,3 +117,21 @@ func SetToBuilding(db gorp.SqlExecutor, workerID string, jobRunID int64) error {
 	_, err := res.RowsAffected()
 	return err
 }
+
+// SetJobRunID assigns a job to an idle worker, it returns an error if the worker is not waiting or has already a job.
+func SetJobRunID(db gorp.SqlExecutor, workerID string, jobRunID int64) error {
+	query := `UPDATE worker SET job_run_id = $1 WHERE id = $2 AND status = $3 AND job_run_id IS NULL`
+
+	res, err := db.Exec(query, jobRunID, workerID, sdk.StatusWaiting)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if n == 0 {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "worker %s is not idle", workerID)
+	}
+	return nil
+}
@@ -44,6 +44,10 @@ func (api *API) postWorkerModelHandler() service.Handler {
 			if !data.Restricted && data.PatternName == "" {
 				return sdk.NewErrorFrom(sdk.ErrWorkerModelNoPattern, "missing model pattern name")
 			}
+			// the pod template can only be given by an admin, even for a restricted model
+			if data.ModelDocker.PodTemplate != "" {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "a pod template can only be set by an administrator")
+			}
 		}
 
 		tx, err := api.mustDB().Begin()
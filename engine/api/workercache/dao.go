@@ -0,0 +1,108 @@
+package workercache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func get(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) (*sdk.CacheEntry, error) {
+	var e dbCacheEntry
+	found, err := gorpmapping.Get(ctx, db, q, &e)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get cache entry")
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	res := sdk.CacheEntry(e)
+	return &res, nil
+}
+
+// LoadByKey returns the last created cache entry with given key.
+func LoadByKey(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, key string) (*sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM worker_cache
+		WHERE project_id = $1 AND integration_name = $2 AND cache_key = $3
+		ORDER BY created DESC LIMIT 1`).Args(projectID, integrationName, key)
+	return get(ctx, db, query)
+}
+
+// LoadLatestByKeyPrefix returns the last created cache entry with a key starting with given prefix.
+func LoadLatestByKeyPrefix(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, prefix string) (*sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM worker_cache
+		WHERE project_id = $1 AND integration_name = $2 AND cache_key LIKE $3
+		ORDER BY created DESC LIMIT 1`).Args(projectID, integrationName, likeEscaper.Replace(prefix)+"%")
+	return get(ctx, db, query)
+}
+
+// LoadAllByIntegration returns all the cache entries of a project in a storage integration, most recently used first.
+func LoadAllByIntegration(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName string) ([]sdk.CacheEntry, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT * FROM worker_cache
+		WHERE project_id = $1 AND integration_name = $2
+		ORDER BY last_access DESC, id DESC`).Args(projectID, integrationName)
+	var es []dbCacheEntry
+	if err := gorpmapping.GetAll(ctx, db, query, &es); err != nil {
+		return nil, sdk.WrapError(err, "cannot load cache entries")
+	}
+	res := make([]sdk.CacheEntry, len(es))
+	for i := range es {
+		res[i] = sdk.CacheEntry(es[i])
+	}
+	return res, nil
+}
+
+// Upsert inserts a cache entry or updates the existing one with the same tag, a pushed cache is considered as accessed.
+func Upsert(db gorp.SqlExecutor, e *sdk.CacheEntry) error {
+	now := time.Now()
+	query := `
+		INSERT INTO worker_cache (project_id, integration_name, tag, cache_key, size, created, last_access)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (project_id, integration_name, tag)
+		DO UPDATE SET cache_key = $4, size = $5, created = $6, last_access = $6
+		RETURNING id`
+	id, err := db.SelectInt(query, e.ProjectID, e.IntegrationName, e.Tag, e.Key, e.Size, now)
+	if err != nil {
+		return sdk.WrapError(err, "cannot save cache entry %s", e.Tag)
+	}
+	e.ID = id
+	e.Created = now
+	e.LastAccess = now
+	return nil
+}
+
+// UpdateLastAccess sets the last access date of a cache entry to now, nothing is done if the entry doesn't exist.
+func UpdateLastAccess(db gorp.SqlExecutor, projectID int64, integrationName, tag string) error {
+	query := "UPDATE worker_cache SET last_access = $4 WHERE project_id = $1 AND integration_name = $2 AND tag = $3"
+	if _, err := db.Exec(query, projectID, integrationName, tag, time.Now()); err != nil {
+		return sdk.WrapError(err, "cannot update cache entry %s", tag)
+	}
+	return nil
+}
+
+// Delete removes a cache entry.
+func Delete(db gorp.SqlExecutor, e sdk.CacheEntry) error {
+	dbE := dbCacheEntry(e)
+	if err := gorpmapping.Delete(db, &dbE); err != nil {
+		return sdk.WrapError(err, "cannot delete cache entry %d", e.ID)
+	}
+	return nil
+}
+
+// DeleteByTag removes the cache entry with given tag, nothing is done if the entry doesn't exist.
+func DeleteByTag(db gorp.SqlExecutor, projectID int64, integrationName, tag string) error {
+	query := "DELETE FROM worker_cache WHERE project_id = $1 AND integration_name = $2 AND tag = $3"
+	if _, err := db.Exec(query, projectID, integrationName, tag); err != nil {
+		return sdk.WrapError(err, "cannot delete cache entry %s", tag)
+	}
+	return nil
+}
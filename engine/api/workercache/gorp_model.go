@@ -0,0 +1,12 @@
+package workercache
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbCacheEntry sdk.CacheEntry
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbCacheEntry{}, "worker_cache", true, "id"))
+}
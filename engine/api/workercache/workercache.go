@@ -0,0 +1,87 @@
+package workercache
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Resolve returns the cache entry matching given key, or else the last created entry that starts with
+// one of the restore keys. Restore keys are checked in the given order.
+func Resolve(ctx context.Context, db gorp.SqlExecutor, projectID int64, integrationName, key string, restoreKeys []string) (*sdk.CacheEntry, error) {
+	if key != "" {
+		e, err := LoadByKey(ctx, db, projectID, integrationName, key)
+		if err == nil {
+			return e, nil
+		}
+		if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return nil, err
+		}
+	}
+	for _, k := range restoreKeys {
+		if k == "" {
+			continue
+		}
+		e, err := LoadLatestByKeyPrefix(ctx, db, projectID, integrationName, k)
+		if err == nil {
+			return e, nil
+		}
+		if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return nil, err
+		}
+	}
+	return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no cache found for key %q", key)
+}
+
+// entriesToEvict returns the entries to remove so the total size of the other ones doesn't exceed maxSize,
+// the most recently used entry is always kept. Given entries must be sorted from the most to the least recently used.
+func entriesToEvict(entries []sdk.CacheEntry, maxSize int64) []sdk.CacheEntry {
+	if maxSize <= 0 {
+		return nil
+	}
+	var total int64
+	for i := range entries {
+		total += entries[i].Size
+		if total > maxSize {
+			if i == 0 {
+				return entries[1:]
+			}
+			return entries[i:]
+		}
+	}
+	return nil
+}
+
+// Evict deletes the least recently used caches of a project in a storage integration until their total size
+// is lower than maxSize. Nothing is done if maxSize is not positive.
+func Evict(ctx context.Context, db gorp.SqlExecutor, driver objectstore.Driver, projectKey string, projectID int64, integrationName string, maxSize int64) error {
+	if maxSize <= 0 {
+		return nil
+	}
+	entries, err := LoadAllByIntegration(ctx, db, projectID, integrationName)
+	if err != nil {
+		return err
+	}
+	for _, e := range entriesToEvict(entries, maxSize) {
+		log.Info(ctx, "workercache.Evict> deleting cache %q of project %s (size: %d, last access: %v)", e.Key, projectKey, e.Size, e.LastAccess)
+		c := sdk.Cache{
+			Name:    "cache.tar",
+			Project: projectKey,
+			Tag:     e.Tag,
+		}
+		// A cache that is already gone from the storage is only removed from the database,
+		// other caches are still evicted if one can't be deleted
+		if err := driver.Delete(ctx, &c); err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			log.Error(ctx, "workercache.Evict> cannot delete cache %s of project %s: %v", e.Tag, projectKey, err)
+			continue
+		}
+		if err := Delete(db, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
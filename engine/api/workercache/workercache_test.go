@@ -0,0 +1,30 @@
+package workercache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_entriesToEvict(t *testing.T) {
+	entries := []sdk.CacheEntry{
+		{ID: 1, Size: 40},
+		{ID: 2, Size: 30},
+		{ID: 3, Size: 20},
+		{ID: 4, Size: 10},
+	}
+
+	assert.Nil(t, entriesToEvict(entries, 0), "no limit")
+	assert.Nil(t, entriesToEvict(entries, 100))
+
+	res := entriesToEvict(entries, 75)
+	assert.Len(t, res, 2)
+	assert.Equal(t, int64(3), res[0].ID)
+	assert.Equal(t, int64(4), res[1].ID)
+
+	res = entriesToEvict(entries, 10)
+	assert.Len(t, res, 3, "the most recently used cache should be kept even if it exceeds the limit")
+	assert.Equal(t, int64(2), res[0].ID)
+}
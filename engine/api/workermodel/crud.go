@@ -149,6 +149,9 @@ func CopyModelTypeData(old, data *sdk.Model) error {
 		return sdk.NewErrorFrom(sdk.ErrWorkerModelNoPattern, "a model script pattern should be given to set the model to not restricted")
 	}
 
+	// the pod template can only be changed by an admin
+	data.ModelDocker.PodTemplate = old.ModelDocker.PodTemplate
+
 	// if model is not restricted and a pattern is not given, reuse old model info
 	if !data.Restricted && data.PatternName == "" {
 		if old.Type != data.Type {
@@ -133,6 +133,7 @@ func TestCopyModelTypeData(t *testing.T) {
 			Envs: map[string]string{
 				"one": "value",
 			},
+			PodTemplate: "spec: {}",
 		},
 	}
 	data := sdk.Model{}
@@ -163,3 +164,27 @@ func TestCopyModelTypeData_OldRestricted(t *testing.T) {
 		PatternName: "my-pattern",
 	}))
 }
+
+func TestCopyModelTypeData_PodTemplate(t *testing.T) {
+	old := sdk.Model{
+		Type: sdk.Docker,
+		ModelDocker: sdk.ModelDocker{
+			PodTemplate: "spec: {}",
+		},
+	}
+
+	data := sdk.Model{
+		Type:        sdk.Docker,
+		PatternName: "my-pattern",
+		ModelDocker: sdk.ModelDocker{
+			PodTemplate: "spec: {hostNetwork: true}",
+		},
+	}
+	assert.NoError(t, workermodel.CopyModelTypeData(&old, &data))
+	assert.Equal(t, "spec: {}", data.ModelDocker.PodTemplate, "the pod template of a not restricted model should not be changed")
+
+	data.Restricted = true
+	data.ModelDocker.PodTemplate = "spec: {hostNetwork: true}"
+	assert.NoError(t, workermodel.CopyModelTypeData(&old, &data))
+	assert.Equal(t, "spec: {}", data.ModelDocker.PodTemplate, "the pod template of a restricted model should not be changed")
+}
@@ -153,8 +153,8 @@ func (api *API) getWorkflowHandler() service.Handler {
 		w1.URLs.APIURL = api.Config.URL.API + api.Router.GetRoute("GET", api.getWorkflowHandler, map[string]string{"key": key, "permWorkflowName": w1.Name})
 		w1.URLs.UIURL = api.Config.URL.UI + "/project/" + key + "/workflow/" + w1.Name
 
-		//We filter project and workflow configuration key, because they are always set on insertHooks
-		w1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		//We filter project and workflow configuration key, because they are always set on insertHooks, and the webhook secret that is kept from the previous hook
+		w1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow, sdk.HookConfigWebHookSecret)
 		return service.WriteJSON(w, w1, http.StatusOK)
 	}
 }
@@ -409,8 +409,8 @@ func (api *API) postWorkflowHandler() service.Handler {
 		wf.Permissions.Writable = true
 		wf.Permissions.Executable = true
 
-		//We filter project and workflow configurtaion key, because they are always set on insertHooks
-		wf.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		//We filter project and workflow configurtaion key, because they are always set on insertHooks, and the webhook secret that is kept from the previous hook
+		wf.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow, sdk.HookConfigWebHookSecret)
 
 		return service.WriteJSON(w, wf, http.StatusCreated)
 	}
@@ -497,8 +497,8 @@ func (api *API) putWorkflowHandler() service.Handler {
 		}
 		wf1.Usage = &usage
 
-		//We filter project and workflow configuration key, because they are always set on insertHooks
-		wf1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow)
+		//We filter project and workflow configuration key, because they are always set on insertHooks, and the webhook secret that is kept from the previous hook
+		wf1.FilterHooksConfig(sdk.HookConfigProject, sdk.HookConfigWorkflow, sdk.HookConfigWebHookSecret)
 		return service.WriteJSON(w, wf1, http.StatusOK)
 	}
 }
@@ -703,7 +703,7 @@ func (api *API) getWorkflowHookHandler() service.Handler {
 			return sdk.WrapError(err, "unable to get hook %s task and executions", uuid)
 		}
 
-		return service.WriteJSON(w, task, http.StatusOK)
+		return service.WriteJSON(w, task.WithoutSecrets(), http.StatusOK)
 	}
 }
 
@@ -50,7 +50,8 @@ workflow_node_run.uuid,
 workflow_node_run.outgoinghook,
 workflow_node_run.hook_execution_timestamp,
 workflow_node_run.execution_id,
-workflow_node_run.callback
+workflow_node_run.callback,
+workflow_node_run.concurrency_group
 `
 
 const nodeRunTestsField string = ", workflow_node_run.tests"
@@ -258,6 +259,7 @@ func fromDBNodeRun(rr NodeRun, opts LoadRunOptions) (*sdk.WorkflowNodeRun, error
 	}
 	r.WorkflowRunID = rr.WorkflowRunID
 	r.ID = rr.ID
+	r.ConcurrencyGroup = rr.ConcurrencyGroup
 	r.WorkflowNodeID = rr.WorkflowNodeID
 	r.WorkflowNodeName = rr.WorkflowNodeName
 	r.Number = rr.Number
@@ -397,6 +399,7 @@ func makeDBNodeRun(n sdk.WorkflowNodeRun) (*NodeRun, error) {
 	nodeRunDB.Start = n.Start
 	nodeRunDB.Done = n.Done
 	nodeRunDB.LastModified = n.LastModified
+	nodeRunDB.ConcurrencyGroup = n.ConcurrencyGroup
 
 	nodeRunDB.VCSServer.Valid = true
 	nodeRunDB.VCSServer.String = n.VCSServer
@@ -286,6 +286,97 @@ func LoadDeadNodeJobRun(ctx context.Context, db gorp.SqlExecutor, store cache.St
 	return deadJobs, nil
 }
 
+// LoadTimedOutNodeJobRunIDs returns the ids of the building NodeJobRuns that exceeded their job timeout
+// and given grace period.
+func LoadTimedOutNodeJobRunIDs(db gorp.SqlExecutor, gracePeriod time.Duration) ([]int64, error) {
+	var ids []int64
+	query := `
+	SELECT id FROM workflow_node_run_job
+	WHERE status = $1
+	AND COALESCE((job->>'timeout')::BIGINT, 0) > 0
+	AND start + ((job->>'timeout')::BIGINT + $2) * INTERVAL '1 second' < NOW()`
+	if _, err := db.Select(&ids, query, sdk.StatusBuilding, int64(gracePeriod.Seconds())); err != nil {
+		return nil, sdk.WrapError(err, "cannot load timed out node job runs")
+	}
+	return ids, nil
+}
+
+// NodeJobRunsUsage is the number of NodeJobRuns using a worker model and the sum of their memory requirements.
+type NodeJobRunsUsage struct {
+	Model  string `db:"model"`
+	Jobs   int64  `db:"jobs"`
+	Memory int64  `db:"memory"`
+}
+
+// CountNodeJobRunsUsageByProjectIDs counts by worker model the building NodeJobRuns of given projects and
+// their waiting NodeJobRuns booked by a hatchery. The NodeJobRun with given id is not counted.
+func CountNodeJobRunsUsageByProjectIDs(ctx context.Context, db gorp.SqlExecutor, store cache.Store, projectIDs []int64, excludedJobID int64) ([]NodeJobRunsUsage, error) {
+	var waitingIDs []int64
+	query := `
+	SELECT id FROM workflow_node_run_job
+	WHERE project_id = ANY(string_to_array($1, ',')::int[])
+	AND status = $2`
+	if _, err := db.Select(&waitingIDs, query, gorpmapping.IDsToQueryString(projectIDs), sdk.StatusWaiting); err != nil {
+		return nil, sdk.WrapError(err, "cannot load waiting node job runs")
+	}
+
+	// Bookings are only stored in cache, they are all read at once
+	keys := make([]string, len(waitingIDs))
+	for i, id := range waitingIDs {
+		keys[i] = keyBookJob(id)
+	}
+	booked, err := store.Exists(keys...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get booked node job runs")
+	}
+	bookedIDs := make([]int64, 0, len(waitingIDs))
+	for i, id := range waitingIDs {
+		if booked[i] {
+			bookedIDs = append(bookedIDs, id)
+		}
+	}
+
+	var usages []NodeJobRunsUsage
+	query = `
+	SELECT COALESCE(model, '') AS "model", COUNT(id) AS "jobs", COALESCE(SUM((
+		SELECT (r->>'value')::bigint FROM jsonb_array_elements(
+			CASE WHEN jsonb_typeof(job->'action'->'requirements') = 'array' THEN job->'action'->'requirements' ELSE '[]'::jsonb END
+		) AS r
+		WHERE r->>'type' = $4 AND r->>'value' ~ '^[0-9]{1,18}$'
+		LIMIT 1
+	)), 0) AS "memory"
+	FROM workflow_node_run_job
+	WHERE project_id = ANY(string_to_array($1, ',')::int[])
+	AND (status = $2 OR id = ANY(string_to_array($3, ',')::bigint[]))
+	AND id <> $5
+	GROUP BY COALESCE(model, '')`
+	if _, err := db.Select(&usages, query, gorpmapping.IDsToQueryString(projectIDs), sdk.StatusBuilding,
+		gorpmapping.IDsToQueryString(bookedIDs), sdk.MemoryRequirement, excludedJobID); err != nil {
+		return nil, sdk.WrapError(err, "cannot count node job runs usage")
+	}
+	return usages, nil
+}
+
+// CountBuildingNodeJobRunsByProject returns the number of building NodeJobRuns for each project.
+func CountBuildingNodeJobRunsByProject(db gorp.SqlExecutor) (map[int64]int64, error) {
+	var rows []struct {
+		ProjectID int64 `db:"project_id"`
+		Count     int64 `db:"count"`
+	}
+	query := `
+	SELECT project_id, COUNT(id) AS "count" FROM workflow_node_run_job
+	WHERE status = $1
+	GROUP BY project_id`
+	if _, err := db.Select(&rows, query, sdk.StatusBuilding); err != nil {
+		return nil, sdk.WrapError(err, "cannot count building node job runs")
+	}
+	res := make(map[int64]int64, len(rows))
+	for _, r := range rows {
+		res[r.ProjectID] = r.Count
+	}
+	return res, nil
+}
+
 //LoadAndLockNodeJobRunWait load for update a NodeJobRun given its ID
 func LoadAndLockNodeJobRunWait(ctx context.Context, db gorp.SqlExecutor, store cache.Store, id int64) (*sdk.WorkflowNodeJobRun, error) {
 	j := JobRun{}
@@ -30,7 +30,8 @@ workflow_run.last_modified,
 workflow_run.status,
 workflow_run.last_sub_num,
 workflow_run.last_execution,
-workflow_run.to_delete
+workflow_run.to_delete,
+workflow_run.concurrency_group
 `
 
 // LoadRunOptions are options for loading a run (node or workflow)
@@ -164,6 +165,8 @@ func (r *Run) PostGet(db gorp.SqlExecutor) error {
 	if err := gorpmapping.JSONNullString(res.W, &w); err != nil {
 		return sdk.WrapError(err, "Unable to unmarshal workflow")
 	}
+	// The secrets of the hooks are not needed by the runs, they should never be sent to users
+	w.FilterHooksConfig(sdk.HookConfigWebHookSecret)
 	r.Workflow = w
 
 	i := []sdk.WorkflowRunInfo{}
@@ -564,7 +567,7 @@ func InsertRunNum(db gorp.SqlExecutor, w *sdk.Workflow, num int64) error {
 }
 
 // CreateRun creates a new workflow run and insert it
-func CreateRun(db *gorp.DbMap, wf *sdk.Workflow, opts *sdk.WorkflowRunPostHandlerOption, ident sdk.Identifiable) (*sdk.WorkflowRun, error) {
+func CreateRun(db gorp.SqlExecutor, wf *sdk.Workflow, opts *sdk.WorkflowRunPostHandlerOption, ident sdk.Identifiable) (*sdk.WorkflowRun, error) {
 	number, err := NextRunNumber(db, wf.ID)
 	if err != nil {
 		return nil, sdk.WrapError(err, "unable to get next run number")
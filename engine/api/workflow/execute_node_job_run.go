@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"path"
+	"strings"
 	"sync"
 	"time"
 
@@ -151,7 +153,7 @@ func UpdateNodeJobRunStatus(ctx context.Context, db gorp.SqlExecutor, store cach
 		job.Start = time.Now()
 		job.Status = status
 
-	case sdk.StatusFail, sdk.StatusSuccess, sdk.StatusDisabled, sdk.StatusSkipped, sdk.StatusStopped:
+	case sdk.StatusFail, sdk.StatusSuccess, sdk.StatusDisabled, sdk.StatusSkipped, sdk.StatusStopped, sdk.StatusTimeout:
 		if currentStatus != sdk.StatusWaiting && currentStatus != sdk.StatusBuilding && status != sdk.StatusDisabled && status != sdk.StatusSkipped {
 			log.Debug("workflow.UpdateNodeJobRunStatus> Status is %s, cannot update %d to %s", currentStatus, job.ID, status)
 			// too late, Nate
@@ -273,6 +275,9 @@ func TakeNodeJobRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 	if err := checkStatusWaiting(ctx, store, jobID, job.Status); err != nil {
 		return nil, report, err
 	}
+	if err := CheckNodeJobRunQueued(*job); err != nil {
+		return nil, report, err
+	}
 
 	job.HatcheryName = hatcheryName
 	job.WorkerName = workerName
@@ -529,6 +534,44 @@ func LoadSecrets(db gorp.SqlExecutor, store cache.Store, nodeRun *sdk.WorkflowNo
 	return secrets, nil
 }
 
+// FilterForkSecrets returns the secrets allowed for a run triggered by a pull request from a fork.
+// A pull request event without the fork flag is considered as coming from a fork.
+// The allowed secrets are configured on the repository hook, none is allowed if the hook is not found.
+func FilterForkSecrets(wr *sdk.WorkflowRun, secrets []sdk.Variable) []sdk.Variable {
+	rootRun := wr.RootRun()
+	if rootRun == nil || rootRun.HookEvent == nil {
+		return secrets
+	}
+	fork := rootRun.HookEvent.Payload["git.pr.fork"]
+	_, isPullRequest := rootRun.HookEvent.Payload["git.pr.id"]
+	if fork == "false" || (!isPullRequest && fork != "true") {
+		return secrets
+	}
+
+	var allowed []string
+	for _, h := range wr.Workflow.WorkflowData.Node.Hooks {
+		if h.UUID != rootRun.HookEvent.WorkflowNodeHookUUID {
+			continue
+		}
+		for _, s := range strings.Split(h.Config[sdk.HookConfigForkSecrets].Value, ";") {
+			if s = strings.TrimSpace(s); s != "" {
+				allowed = append(allowed, s)
+			}
+		}
+	}
+
+	res := make([]sdk.Variable, 0, len(secrets))
+	for _, s := range secrets {
+		for _, pattern := range allowed {
+			if ok, _ := path.Match(pattern, s.Name); ok {
+				res = append(res, s)
+				break
+			}
+		}
+	}
+	return res
+}
+
 //BookNodeJobRun  Book a job for a hatchery
 func BookNodeJobRun(ctx context.Context, store cache.Store, id int64, hatchery *sdk.Service) (*sdk.Service, error) {
 	k := keyBookJob(id)
@@ -567,8 +610,8 @@ func FreeNodeJobRun(ctx context.Context, store cache.Store, id int64) error {
 	return sdk.WrapError(sdk.ErrJobNotBooked, "BookNodeJobRun> job %d already released", id)
 }
 
-//AddLog adds a build log
-func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) error {
+//AddLog adds a build log, it returns the part of the log that was written with its offset or nil if the log was ignored
+func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, maxLogSize int64) (*sdk.StepLogChunk, error) {
 	if job != nil {
 		logs.JobID = job.ID
 		logs.NodeRunID = job.WorkflowNodeRunID
@@ -577,19 +620,38 @@ func AddLog(db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, logs *sdk.Log, max
 	// check if log exists without loading data but with log size
 	exists, size, err := ExistsStepLog(db, logs.JobID, logs.StepOrder)
 	if err != nil {
-		return sdk.WrapError(err, "cannot check if log exists")
+		return nil, sdk.WrapError(err, "cannot check if log exists")
 	}
 
 	// ignore the log if max size already reached
 	if maxReached := truncateLogs(maxLogSize, size, logs); maxReached {
-		return nil
+		return nil, nil
+	}
+
+	chunk := &sdk.StepLogChunk{
+		JobID:     logs.JobID,
+		StepOrder: logs.StepOrder,
+		Offset:    size,
+		Val:       logs.Val,
 	}
 
 	if !exists {
-		return sdk.WrapError(insertLog(db, logs), "cannot insert log")
+		if err := insertLog(db, logs); err != nil {
+			return nil, sdk.WrapError(err, "cannot insert log")
+		}
+		return chunk, nil
+	}
+
+	// new data is appended to the storage where the step log was created
+	_, storage, err := loadStepLogIndex(db, logs.JobID, logs.StepOrder)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load log")
 	}
 
-	return sdk.WrapError(updateLog(db, logs), "cannot update log")
+	if err := updateLog(db, logs, storage); err != nil {
+		return nil, sdk.WrapError(err, "cannot update log")
+	}
+	return chunk, nil
 }
 
 //AddServiceLog adds a service log
@@ -638,7 +700,7 @@ func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob s
 		if step.Status == sdk.StatusNeverBuilt || step.Status == sdk.StatusSkipped || step.Status == sdk.StatusDisabled {
 			continue
 		}
-		l, errL := LoadStepLogs(db, wNodeJob.ID, int64(step.StepOrder))
+		l, storage, errL := loadStepLogIndex(db, wNodeJob.ID, int64(step.StepOrder))
 		if errL != nil {
 			return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while load step logs")
 		}
@@ -647,10 +709,8 @@ func RestartWorkflowNodeJob(ctx context.Context, db gorp.SqlExecutor, wNodeJob s
 		step.Done = time.Time{}
 		if l != nil { // log could be nil here
 			l.Done = nil
-			logbuf := bytes.NewBufferString(l.Val)
-			logbuf.WriteString("\n\n\n-=-=-=-=-=- Worker timeout: job replaced in queue -=-=-=-=-=-\n\n\n")
-			l.Val = logbuf.String()
-			if err := updateLog(db, l); err != nil {
+			l.Val = "\n\n\n-=-=-=-=-=- Worker timeout: job replaced in queue -=-=-=-=-=-\n\n\n"
+			if err := updateLog(db, l, storage); err != nil {
 				return sdk.WrapError(errL, "RestartWorkflowNodeJob> error while update step log")
 			}
 		}
@@ -1,7 +1,10 @@
 package workflow
 
 import (
+	"context"
 	"database/sql"
+	"io"
+	"io/ioutil"
 	"time"
 
 	"github.com/lib/pq"
@@ -15,7 +18,10 @@ import (
 // ExistsStepLog returns the size of step log if exists.
 func ExistsStepLog(db gorp.SqlExecutor, id int64, order int64) (bool, int64, error) {
 	query := `
-    SELECT octet_length(value) as size
+    SELECT octet_length(value) + COALESCE((
+      SELECT SUM(size) FROM workflow_node_run_job_logs_chunk
+      WHERE workflow_node_run_job_logs_chunk.workflow_node_run_job_logs_id = workflow_node_run_job_logs.id
+    ), 0) as size
     FROM workflow_node_run_job_logs
     WHERE workflow_node_run_job_id = $1 AND step_order = $2
   `
@@ -31,24 +37,17 @@ func ExistsStepLog(db gorp.SqlExecutor, id int64, order int64) (bool, int64, err
 	return true, size, nil
 }
 
-//LoadStepLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job) for a specific step_order
-func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
-	log.Debug("LoadStepLogs> workflow_node_run_job_id = %d", id)
-	query := `
-		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value
-		FROM workflow_node_run_job_logs
-		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
+const stepLogIndexFields = "id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, storage"
+
+func scanStepLogIndex(row interface{ Scan(...interface{}) error }) (*sdk.Log, string, error) {
 	logs := &sdk.Log{}
-	var s, m, d pq.NullTime
-	if err := db.QueryRow(query, id, order).Scan(&logs.ID, &logs.JobID, &logs.NodeRunID, &s, &m, &d, &logs.StepOrder, &logs.Val); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
+	var storage string
+	var st, m, d pq.NullTime
+	if err := row.Scan(&logs.ID, &logs.JobID, &logs.NodeRunID, &st, &m, &d, &logs.StepOrder, &storage); err != nil {
+		return nil, "", err
 	}
-
-	if s.Valid {
-		logs.Start = &s.Time
+	if st.Valid {
+		logs.Start = &st.Time
 	}
 	if m.Valid {
 		logs.LastModified = &m.Time
@@ -56,13 +55,73 @@ func LoadStepLogs(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error)
 	if d.Valid {
 		logs.Done = &d.Time
 	}
+	return logs, storage, nil
+}
+
+// loadStepLogIndex loads the index of a step log without its content, it returns the name of the storage of the content.
+func loadStepLogIndex(db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, string, error) {
+	query := `SELECT ` + stepLogIndexFields + ` FROM workflow_node_run_job_logs WHERE workflow_node_run_job_id = $1 AND step_order = $2`
+	logs, storage, err := scanStepLogIndex(db.QueryRow(query, id, order))
+	if err != nil {
+		if sdk.Cause(err) == sql.ErrNoRows {
+			return nil, "", nil
+		}
+		return nil, "", sdk.WithStack(err)
+	}
+	return logs, storage, nil
+}
+
+// StreamStepLogs loads the index of logs (workflow_node_run_job_logs) for a job (workflow_node_run_job) for a specific step_order
+// and returns a reader on its content from the storage where it was written. Returned log value is empty, the reader must be closed.
+func StreamStepLogs(ctx context.Context, db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, io.ReadCloser, error) {
+	logs, storageName, err := loadStepLogIndex(db, id, order)
+	if err != nil || logs == nil {
+		return nil, nil, err
+	}
+	storage, err := getLogStorage(storageName)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := storage.Open(ctx, db, logs)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "cannot open step log %d from storage %s", logs.ID, storageName)
+	}
+	return logs, r, nil
+}
+
+// FlushStepLog writes the data of a step log buffered by its storage, it is called when the step ends.
+func FlushStepLog(db gorp.SqlExecutor, id int64, order int64) error {
+	logs, storageName, err := loadStepLogIndex(db, id, order)
+	if err != nil || logs == nil {
+		return err
+	}
+	storage, err := getLogStorage(storageName)
+	if err != nil {
+		return err
+	}
+	return sdk.WrapError(storage.Flush(db, logs), "cannot flush step log %d to storage %s", logs.ID, storageName)
+}
+
+// LoadStepLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job) for a specific step_order
+func LoadStepLogs(ctx context.Context, db gorp.SqlExecutor, id int64, order int64) (*sdk.Log, error) {
+	log.Debug("LoadStepLogs> workflow_node_run_job_id = %d", id)
+	logs, r, err := StreamStepLogs(ctx, db, id, order)
+	if err != nil || logs == nil {
+		return nil, err
+	}
+	defer r.Close() // nolint
+	btes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot read step log %d", logs.ID)
+	}
+	logs.Val = string(btes)
 	return logs, nil
 }
 
-//LoadLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job)
-func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
+// LoadLogs load logs (workflow_node_run_job_logs) for a job (workflow_node_run_job)
+func LoadLogs(ctx context.Context, db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
 	query := `
-		SELECT id, workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value
+		SELECT ` + stepLogIndexFields + `
 		FROM workflow_node_run_job_logs
 		WHERE workflow_node_run_job_id = $1
 		ORDER BY id`
@@ -70,40 +129,55 @@ func LoadLogs(db gorp.SqlExecutor, id int64) ([]sdk.Log, error) {
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close() // nolint
 	var logs []sdk.Log
+	var storages []string
 	for rows.Next() {
-		l := &sdk.Log{}
-		var s, m, d pq.NullTime
-
-		if err := rows.Scan(&l.ID, &l.JobID, &l.NodeRunID, &s, &m, &d, &l.StepOrder, &l.Val); err != nil {
+		l, storage, err := scanStepLogIndex(rows)
+		if err != nil {
+			rows.Close() // nolint
 			return nil, err
 		}
+		logs = append(logs, *l)
+		storages = append(storages, storage)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, sdk.WithStack(err)
+	}
 
-		if s.Valid {
-			l.Start = &s.Time
+	for i := range logs {
+		storage, err := getLogStorage(storages[i])
+		if err != nil {
+			return nil, err
 		}
-		if m.Valid {
-			l.LastModified = &m.Time
+		r, err := storage.Open(ctx, db, &logs[i])
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot open step log %d from storage %s", logs[i].ID, storages[i])
 		}
-		if d.Valid {
-			l.Done = &d.Time
+		btes, err := ioutil.ReadAll(r)
+		r.Close() // nolint
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot read step log %d", logs[i].ID)
 		}
-
-		logs = append(logs, *l)
+		logs[i].Val = string(btes)
 	}
 	return logs, nil
 }
 
+// insertLog inserts the index of a step log in database and writes its content in the current log storage.
 func insertLog(db gorp.SqlExecutor, logs *sdk.Log) error {
+	storage := currentLogStorage()
 	query := `
-		INSERT INTO workflow_node_run_job_logs (workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO workflow_node_run_job_logs (workflow_node_run_job_id, workflow_node_run_id, start, last_modified, done, step_order, value, storage)
+		VALUES ($1, $2, $3, $4, $5, $6, '', $7)
 		RETURNING ID `
-	return sdk.WithStack(db.QueryRow(query, logs.JobID, logs.NodeRunID, logs.Start, logs.LastModified, logs.Done, logs.StepOrder, logs.Val).Scan(&logs.ID))
+	if err := db.QueryRow(query, logs.JobID, logs.NodeRunID, logs.Start, logs.LastModified, logs.Done, logs.StepOrder, storage.Name()).Scan(&logs.ID); err != nil {
+		return sdk.WithStack(err)
+	}
+	return storage.Append(db, logs, logs.Val)
 }
 
-func updateLog(db gorp.SqlExecutor, logs *sdk.Log) error {
+// updateLog updates the index of a step log and appends its value to the content in given storage.
+func updateLog(db gorp.SqlExecutor, logs *sdk.Log, storageName string) error {
 	now := time.Now()
 	if logs.Start == nil {
 		logs.Start = &now
@@ -115,17 +189,22 @@ func updateLog(db gorp.SqlExecutor, logs *sdk.Log) error {
 		logs.Done = &now
 	}
 
+	storage, err := getLogStorage(storageName)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE workflow_node_run_job_logs set
 			workflow_node_run_id = $3,
 			start = $4,
 			last_modified = $5,
-			done = $6,
-			value = value || $7
-		WHERE workflow_node_run_job_id = $1 AND step_order = $2`
+			done = $6
+		WHERE workflow_node_run_job_id = $1 AND step_order = $2
+		RETURNING id`
 
-	if _, err := db.Exec(query, logs.JobID, logs.StepOrder, logs.NodeRunID, logs.Start, logs.LastModified, logs.Done, logs.Val); err != nil {
+	if err := db.QueryRow(query, logs.JobID, logs.StepOrder, logs.NodeRunID, logs.Start, logs.LastModified, logs.Done).Scan(&logs.ID); err != nil {
 		return sdk.WithStack(err)
 	}
-	return nil
+	return storage.Append(db, logs, logs.Val)
 }
@@ -0,0 +1,345 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+)
+
+// Step logs storages
+const (
+	LogStorageDatabase    = "database"
+	LogStorageObjectStore = "objectstore"
+)
+
+// LogStorage stores the content of step logs. The table workflow_node_run_job_logs is always used as an index
+// of step logs, each row knows the storage where its content was written.
+type LogStorage interface {
+	Name() string
+	// Append writes data at the end of the content of an indexed step log
+	Append(db gorp.SqlExecutor, logs *sdk.Log, data string) error
+	// Flush writes the data buffered by Append for an indexed step log, it is called when the step ends
+	Flush(db gorp.SqlExecutor, logs *sdk.Log) error
+	// Open returns a reader on the content of an indexed step log
+	Open(ctx context.Context, db gorp.SqlExecutor, logs *sdk.Log) (io.ReadCloser, error)
+	// DeleteNodeRunLogs removes the content of all the step logs of a node run, indexes are deleted with the node run
+	DeleteNodeRunLogs(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) error
+}
+
+var (
+	logStoragesMutex      sync.RWMutex
+	logStorages           = map[string]LogStorage{LogStorageDatabase: databaseLogStorage{}}
+	currentLogStorageName = LogStorageDatabase
+)
+
+// SetLogStorage sets the storage used to write new step logs. If an object store driver is given, the logs written in it
+// can still be read after selecting back the database storage.
+func SetLogStorage(name string, driver objectstore.Driver) error {
+	logStoragesMutex.Lock()
+	defer logStoragesMutex.Unlock()
+	if driver != nil {
+		logStorages[LogStorageObjectStore] = NewObjectStoreLogStorage(driver)
+	}
+	if name == "" {
+		name = LogStorageDatabase
+	}
+	if _, ok := logStorages[name]; !ok {
+		return fmt.Errorf("invalid log storage %q", name)
+	}
+	currentLogStorageName = name
+	return nil
+}
+
+func currentLogStorage() LogStorage {
+	logStoragesMutex.RLock()
+	defer logStoragesMutex.RUnlock()
+	return logStorages[currentLogStorageName]
+}
+
+func getLogStorage(name string) (LogStorage, error) {
+	logStoragesMutex.RLock()
+	defer logStoragesMutex.RUnlock()
+	s, ok := logStorages[name]
+	if !ok {
+		return nil, sdk.WithStack(fmt.Errorf("log storage %q is not configured", name))
+	}
+	return s, nil
+}
+
+// DeleteNodeRunLogs removes the content of step logs of a node run from all the configured storages.
+func DeleteNodeRunLogs(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) error {
+	logStoragesMutex.RLock()
+	defer logStoragesMutex.RUnlock()
+	for name, s := range logStorages {
+		if err := s.DeleteNodeRunLogs(ctx, db, nodeRunID); err != nil {
+			return sdk.WrapError(err, "cannot delete logs of node run %d from storage %s", nodeRunID, name)
+		}
+	}
+	return nil
+}
+
+// databaseLogStorage keeps the content of step logs in the index row.
+type databaseLogStorage struct{}
+
+func (databaseLogStorage) Name() string { return LogStorageDatabase }
+
+func (databaseLogStorage) Append(db gorp.SqlExecutor, logs *sdk.Log, data string) error {
+	if _, err := db.Exec("UPDATE workflow_node_run_job_logs SET value = value || $2 WHERE id = $1", logs.ID, data); err != nil {
+		return sdk.WithStack(err)
+	}
+	return nil
+}
+
+func (databaseLogStorage) Flush(db gorp.SqlExecutor, logs *sdk.Log) error { return nil }
+
+func (databaseLogStorage) Open(ctx context.Context, db gorp.SqlExecutor, logs *sdk.Log) (io.ReadCloser, error) {
+	value, err := db.SelectStr("SELECT value FROM workflow_node_run_job_logs WHERE id = $1", logs.ID)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return ioutil.NopCloser(strings.NewReader(value)), nil
+}
+
+func (databaseLogStorage) DeleteNodeRunLogs(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) error {
+	return nil
+}
+
+// logChunkMaxSize is the size of the data buffered in database before being written as a chunk in the object store.
+const logChunkMaxSize = 1024 * 1024
+
+// logChunk is a part of a step log stored in an object store, the data appended to a step log is buffered in database
+// and written as a new chunk when the buffer is full or when the step ends. All the chunks of a node run are stored in the same container.
+type logChunk struct {
+	ID        int64
+	NodeRunID int64
+	JobID     int64
+	StepOrder int64
+}
+
+func (c logChunk) GetName() string {
+	return fmt.Sprintf("%d-%d-%d.log", c.JobID, c.StepOrder, c.ID)
+}
+
+func (c logChunk) GetPath() string {
+	return fmt.Sprintf("logs-%d", c.NodeRunID)
+}
+
+// ObjectStoreLogStorage writes step logs by chunks as objects, the database keeps an index of chunks and the data
+// not written yet in the value of the step log.
+type ObjectStoreLogStorage struct {
+	driver objectstore.Driver
+}
+
+// NewObjectStoreLogStorage returns a log storage on given object store driver.
+func NewObjectStoreLogStorage(driver objectstore.Driver) *ObjectStoreLogStorage {
+	return &ObjectStoreLogStorage{driver: driver}
+}
+
+// Name returns the name of the storage.
+func (s *ObjectStoreLogStorage) Name() string { return LogStorageObjectStore }
+
+// Append buffers data in database, a new chunk is written when the buffer reaches logChunkMaxSize.
+func (s *ObjectStoreLogStorage) Append(db gorp.SqlExecutor, logs *sdk.Log, data string) error {
+	if data == "" {
+		return nil
+	}
+	var size int64
+	if err := db.QueryRow("UPDATE workflow_node_run_job_logs SET value = value || $2 WHERE id = $1 RETURNING octet_length(value)", logs.ID, data).Scan(&size); err != nil {
+		return sdk.WithStack(err)
+	}
+	if size < logChunkMaxSize {
+		return nil
+	}
+	return s.Flush(db, logs)
+}
+
+// Flush writes the data buffered in database as a new chunk. The buffer is emptied and the chunk is indexed in the same query
+// so concurrent calls can't reorder the chunks.
+func (s *ObjectStoreLogStorage) Flush(db gorp.SqlExecutor, logs *sdk.Log) error {
+	query := `
+		WITH buffer AS (
+			SELECT id, value FROM workflow_node_run_job_logs WHERE id = $1 AND value <> '' FOR UPDATE
+		), flushed AS (
+			UPDATE workflow_node_run_job_logs SET value = '' FROM buffer WHERE workflow_node_run_job_logs.id = buffer.id
+		), chunk AS (
+			INSERT INTO workflow_node_run_job_logs_chunk (workflow_node_run_job_logs_id, size)
+			SELECT id, octet_length(value) FROM buffer
+			RETURNING id
+		)
+		SELECT chunk.id, buffer.value FROM chunk, buffer`
+	chunk := logChunk{NodeRunID: logs.NodeRunID, JobID: logs.JobID, StepOrder: logs.StepOrder}
+	var data string
+	if err := db.QueryRow(query, logs.ID).Scan(&chunk.ID, &data); err != nil {
+		if sdk.Cause(err) == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WithStack(err)
+	}
+	if _, err := s.driver.Store(chunk, ioutil.NopCloser(strings.NewReader(data))); err != nil {
+		// the database may not be in a transaction, remove the index of the missing chunk and put back its data in the buffer
+		if _, errD := db.Exec("DELETE FROM workflow_node_run_job_logs_chunk WHERE id = $1", chunk.ID); errD != nil {
+			return sdk.WrapError(errD, "cannot delete chunk %d after store error: %v", chunk.ID, err)
+		}
+		if _, errU := db.Exec("UPDATE workflow_node_run_job_logs SET value = $2 || value WHERE id = $1", logs.ID, data); errU != nil {
+			return sdk.WrapError(errU, "cannot restore data of chunk %d after store error: %v", chunk.ID, err)
+		}
+		return sdk.WrapError(err, "cannot store log chunk %s/%s", chunk.GetPath(), chunk.GetName())
+	}
+	return nil
+}
+
+// Open returns a reader on all the chunks followed by the data buffered in database. Chunks are fetched one by one while reading.
+func (s *ObjectStoreLogStorage) Open(ctx context.Context, db gorp.SqlExecutor, logs *sdk.Log) (io.ReadCloser, error) {
+	var ids []int64
+	if _, err := db.Select(&ids, "SELECT id FROM workflow_node_run_job_logs_chunk WHERE workflow_node_run_job_logs_id = $1 ORDER BY id", logs.ID); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	value, err := db.SelectStr("SELECT value FROM workflow_node_run_job_logs WHERE id = $1", logs.ID)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	chunks := make([]logChunk, len(ids))
+	for i := range ids {
+		chunks[i] = logChunk{ID: ids[i], NodeRunID: logs.NodeRunID, JobID: logs.JobID, StepOrder: logs.StepOrder}
+	}
+	return &logChunksReader{
+		ctx:    ctx,
+		driver: s.driver,
+		chunks: chunks,
+		buffer: value,
+	}, nil
+}
+
+// DeleteNodeRunLogs removes the chunks of the node run then their container.
+func (s *ObjectStoreLogStorage) DeleteNodeRunLogs(ctx context.Context, db gorp.SqlExecutor, nodeRunID int64) error {
+	chunks, err := loadNodeRunLogChunks(db, nodeRunID)
+	if err != nil {
+		return err
+	}
+	return s.deleteChunks(ctx, nodeRunID, chunks)
+}
+
+func (s *ObjectStoreLogStorage) deleteChunks(ctx context.Context, nodeRunID int64, chunks []logChunk) error {
+	for _, c := range chunks {
+		if err := s.driver.Delete(ctx, c); err != nil {
+			return sdk.WrapError(err, "cannot delete log chunk %s/%s", c.GetPath(), c.GetName())
+		}
+	}
+	return s.driver.DeleteContainer(ctx, logChunk{NodeRunID: nodeRunID}.GetPath())
+}
+
+func loadNodeRunLogChunks(db gorp.SqlExecutor, nodeRunID int64) ([]logChunk, error) {
+	query := `
+		SELECT workflow_node_run_job_logs_chunk.id, workflow_node_run_job_logs.workflow_node_run_job_id, workflow_node_run_job_logs.step_order
+		FROM workflow_node_run_job_logs_chunk
+		JOIN workflow_node_run_job_logs ON workflow_node_run_job_logs.id = workflow_node_run_job_logs_chunk.workflow_node_run_job_logs_id
+		WHERE workflow_node_run_job_logs.workflow_node_run_id = $1`
+	rows, err := db.Query(query, nodeRunID)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	var chunks []logChunk
+	for rows.Next() {
+		c := logChunk{NodeRunID: nodeRunID}
+		if err := rows.Scan(&c.ID, &c.JobID, &c.StepOrder); err != nil {
+			rows.Close() // nolint
+			return nil, sdk.WithStack(err)
+		}
+		chunks = append(chunks, c)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return chunks, nil
+}
+
+// logChunksReader reads the chunks of a step log then the data buffered in database.
+type logChunksReader struct {
+	ctx     context.Context
+	driver  objectstore.Driver
+	chunks  []logChunk
+	buffer  string
+	current io.ReadCloser
+}
+
+func (r *logChunksReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.chunks) == 0 {
+				if r.buffer == "" {
+					return 0, io.EOF
+				}
+				r.current = ioutil.NopCloser(strings.NewReader(r.buffer))
+				r.buffer = ""
+				continue
+			}
+			c, err := r.driver.Fetch(r.ctx, r.chunks[0])
+			if err != nil {
+				return 0, sdk.WrapError(err, "cannot fetch log chunk %s/%s", r.chunks[0].GetPath(), r.chunks[0].GetName())
+			}
+			r.current = c
+			r.chunks = r.chunks[1:]
+		}
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close() // nolint
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *logChunksReader) Close() error {
+	r.chunks = nil
+	r.buffer = ""
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
+
+// MoveStepLogToStorage moves the content of a step log kept in database to given storage.
+// The step log is ignored if it is locked or already written in another storage.
+func MoveStepLogToStorage(db gorp.SqlExecutor, id int64, storageName string) error {
+	storage, err := getLogStorage(storageName)
+	if err != nil {
+		return err
+	}
+	if storage.Name() == LogStorageDatabase {
+		return nil
+	}
+
+	query := `
+		SELECT id, workflow_node_run_job_id, workflow_node_run_id, step_order
+		FROM workflow_node_run_job_logs
+		WHERE id = $1 AND storage = $2
+		FOR UPDATE SKIP LOCKED`
+	var logs sdk.Log
+	if err := db.QueryRow(query, id, LogStorageDatabase).Scan(&logs.ID, &logs.JobID, &logs.NodeRunID, &logs.StepOrder); err != nil {
+		if sdk.Cause(err) == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WithStack(err)
+	}
+
+	// the value kept in database becomes the buffer of the storage, it is written as a single chunk
+	if _, err := db.Exec("UPDATE workflow_node_run_job_logs SET storage = $2 WHERE id = $1", logs.ID, storage.Name()); err != nil {
+		return sdk.WithStack(err)
+	}
+	return storage.Flush(db, &logs)
+}
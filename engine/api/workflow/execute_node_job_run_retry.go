@@ -0,0 +1,155 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// RetryNodeJobRun replaces a failed job run by a new attempt if its retry policy allows it for given failure kind.
+// The previous attempt is kept in the job history, its logs are still available with its job run id.
+// It returns false if the job run should not be retried.
+func RetryNodeJobRun(ctx context.Context, db gorp.SqlExecutor, job *sdk.WorkflowNodeJobRun, failureKind string) (bool, *ProcessorReport, error) {
+	var end func()
+	ctx, end = observability.Span(ctx, "workflow.RetryNodeJobRun",
+		observability.Tag(observability.TagWorkflowNodeJobRun, job.ID),
+		observability.Tag("failure_kind", failureKind),
+	)
+	defer end()
+
+	policy := job.Job.RetryPolicy
+	attempt := job.Retry + 1
+	if policy == nil || !policy.ShouldRetry(failureKind, attempt) {
+		return false, nil, nil
+	}
+
+	nodeRun, err := LoadAndLockNodeRunByID(ctx, db, job.WorkflowNodeRunID)
+	if err != nil {
+		return false, nil, err
+	}
+
+	spawnInfos, err := LoadNodeRunJobInfo(ctx, db, job.ID)
+	if err != nil {
+		return false, nil, sdk.WrapError(err, "unable to load spawn infos for job run %d", job.ID)
+	}
+
+	done := job.Done
+	if done.IsZero() {
+		done = time.Now()
+	}
+	status := sdk.StatusFail
+	if failureKind == sdk.JobRetryOnTimeout {
+		status = sdk.StatusTimeout
+	}
+	previous := sdk.ExecutedJobAttempt{
+		Attempt:      attempt,
+		JobRunID:     job.ID,
+		Status:       status,
+		FailureKind:  failureKind,
+		Start:        job.Start,
+		Done:         done,
+		Model:        job.Model,
+		WorkerName:   job.WorkerName,
+		HatcheryName: job.HatcheryName,
+		StepStatus:   job.Job.StepStatus,
+		SpawnInfos:   spawnInfos,
+	}
+
+	backoff := policy.BackoffDuration(attempt + 1)
+	newJob := *job
+	newJob.ID = 0
+	newJob.Status = sdk.StatusWaiting
+	newJob.Retry = attempt
+	// A job run is not visible in the queue before its queued date, this is used to apply the backoff
+	newJob.Queued = time.Now().Add(backoff)
+	newJob.Start = time.Time{}
+	newJob.Done = time.Time{}
+	newJob.Model = ""
+	newJob.WorkerName = ""
+	newJob.HatcheryName = ""
+	newJob.BookedBy = sdk.Service{}
+	newJob.Job.StepStatus = nil
+	newJob.Job.Reason = ""
+	newJob.Job.WorkerName = ""
+	newJob.Job.WorkerID = ""
+	newJob.Job.Attempts = append(append([]sdk.ExecutedJobAttempt{}, job.Job.Attempts...), previous)
+	newJob.SpawnInfos = []sdk.SpawnInfo{{
+		APITime:    time.Now(),
+		RemoteTime: time.Now(),
+		Message: sdk.SpawnMsg{
+			ID:   sdk.MsgSpawnInfoJobRetry.ID,
+			Args: []interface{}{failureKind, attempt + 1, policy.MaxAttempts, backoff.String()},
+		},
+	}}
+
+	if err := insertWorkflowNodeJobRun(db, &newJob); err != nil {
+		return false, nil, sdk.WrapError(err, "unable to insert new attempt for job run %d", job.ID)
+	}
+	if err := AddSpawnInfosNodeJobRun(db, newJob.WorkflowNodeRunID, newJob.ID, newJob.SpawnInfos); err != nil {
+		return false, nil, sdk.WrapError(err, "cannot save spawn info job %d", newJob.ID)
+	}
+
+	// Replace the previous attempt in the node run stages
+	var found bool
+	for i := range nodeRun.Stages {
+		for j := range nodeRun.Stages[i].RunJobs {
+			if nodeRun.Stages[i].RunJobs[j].ID == job.ID {
+				nodeRun.Stages[i].RunJobs[j] = newJob
+				found = true
+			}
+		}
+	}
+	if !found {
+		log.Warning(ctx, "RetryNodeJobRun> job run %d not found in node run %d", job.ID, nodeRun.ID)
+	}
+	if err := UpdateNodeRun(db, nodeRun); err != nil {
+		return false, nil, sdk.WrapError(err, "cannot update node run %d", nodeRun.ID)
+	}
+
+	if _, err := db.Exec("UPDATE worker SET status = $2, job_run_id = NULL where job_run_id = $1", job.ID, sdk.StatusDisabled); err != nil {
+		return false, nil, sdk.WrapError(err, "unable to set workers")
+	}
+	if err := DeleteNodeJobRun(db, job.ID); err != nil {
+		return false, nil, sdk.WrapError(err, "cannot delete job run %d", job.ID)
+	}
+
+	log.Info(ctx, "RetryNodeJobRun> job run %d failed (%s), new attempt %d/%d with job run %d", job.ID, failureKind, attempt+1, policy.MaxAttempts, newJob.ID)
+
+	report := new(ProcessorReport)
+	report.Add(ctx, newJob, *nodeRun)
+	return true, report, nil
+}
+
+// CheckNodeJobRunQueued returns an error if the job run is still delayed by the backoff of its retry policy.
+// The hatcheries receive the job run event as soon as it is inserted, so the backoff is also checked when a job run is booked or taken.
+func CheckNodeJobRunQueued(job sdk.WorkflowNodeJobRun) error {
+	if job.Queued.After(time.Now()) {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "job %d is delayed by its retry policy until %s", job.ID, job.Queued.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// AddSpawnInfoRetryExhausted adds a spawn info on a job run explaining that it failed after all its attempts.
+// Nothing is added if the job retry policy does not handle given failure kind.
+func AddSpawnInfoRetryExhausted(db gorp.SqlExecutor, job sdk.WorkflowNodeJobRun, failureKind string) error {
+	if job.Job.RetryPolicy == nil || !job.Job.RetryPolicy.Retries(failureKind) {
+		return nil
+	}
+	info := sdk.SpawnInfo{
+		APITime:    time.Now(),
+		RemoteTime: time.Now(),
+		Message: sdk.SpawnMsg{
+			ID:   sdk.MsgSpawnInfoJobRetryExhausted.ID,
+			Args: []interface{}{failureKind, job.Retry + 1},
+		},
+	}
+	if err := AddSpawnInfosNodeJobRun(db, job.WorkflowNodeRunID, job.ID, []sdk.SpawnInfo{info}); err != nil {
+		return sdk.WrapError(err, "cannot save spawn info job %d", job.ID)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestFilterForkSecrets(t *testing.T) {
+	secrets := []sdk.Variable{{Name: "cds.proj.token"}, {Name: "cds.app.deploy_key"}, {Name: "git.http.password"}}
+	wr := &sdk.WorkflowRun{
+		Workflow: sdk.Workflow{WorkflowData: sdk.WorkflowData{Node: sdk.Node{
+			ID: 1,
+			Hooks: []sdk.NodeHook{{
+				UUID:   "uuid",
+				Config: sdk.WorkflowNodeHookConfig{sdk.HookConfigForkSecrets: {Value: "cds.app.*; git.http.password"}},
+			}},
+		}}},
+	}
+	withPayload := func(payload map[string]string) *sdk.WorkflowRun {
+		wr.WorkflowNodeRuns = map[int64][]sdk.WorkflowNodeRun{1: {{
+			HookEvent: &sdk.WorkflowNodeRunHookEvent{WorkflowNodeHookUUID: "uuid", Payload: payload},
+		}}}
+		return wr
+	}
+
+	// Push events and pull requests from the repository get all the secrets
+	assert.Len(t, FilterForkSecrets(withPayload(map[string]string{"git.branch": "master"}), secrets), 3)
+	assert.Len(t, FilterForkSecrets(withPayload(map[string]string{"git.pr.id": "42", "git.pr.fork": "false"}), secrets), 3)
+
+	// Pull requests from a fork, or without the fork flag, only get the allowed secrets
+	for _, payload := range []map[string]string{
+		{"git.pr.id": "42", "git.pr.fork": "true"},
+		{"git.pr.id": "42"},
+	} {
+		res := FilterForkSecrets(withPayload(payload), secrets)
+		assert.Len(t, res, 2)
+		assert.Equal(t, "cds.app.deploy_key", res[0].Name)
+		assert.Equal(t, "git.http.password", res[1].Name)
+	}
+
+	// No secret is allowed if the hook is not found
+	wr.Workflow.WorkflowData.Node.Hooks = nil
+	assert.Len(t, FilterForkSecrets(withPayload(map[string]string{"git.pr.id": "42"}), secrets), 0)
+}
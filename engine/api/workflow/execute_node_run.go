@@ -300,6 +300,12 @@ func executeNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 			return nil, sdk.WrapError(err, "unable to delete node %d job runs", nr.ID)
 		}
 
+		r2, err := releaseNodeConcurrencyGroup(ctx, db, store, proj, updatedWorkflowRun.ProjectID, nr)
+		if err != nil {
+			return nil, err
+		}
+		report.Merge(ctx, r2)
+
 		var hasMutex bool
 		var nodeName string
 
@@ -433,120 +439,133 @@ func addJobsToQueue(ctx context.Context, db gorp.SqlExecutor, stage *sdk.Stage,
 
 	skippedOrDisabledJobs := 0
 	failedJobs := 0
+	nbJobRuns := 0
 	//Browse the jobs
-jobLoop:
 	for j := range stage.Jobs {
-		job := &stage.Jobs[j]
+		// A job with a matrix is expanded into one job run per combination of matrix values
+		combinations := stage.Jobs[j].Matrix.Combinations()
+		if len(combinations) == 0 {
+			combinations = []map[string]string{nil}
+		}
 
-		if previousStage != nil {
-			for _, rj := range previousStage.RunJobs {
-				if rj.Job.PipelineActionID == job.PipelineActionID && rj.Status != sdk.StatusFail && sdk.StatusIsTerminated(rj.Status) {
-					stage.RunJobs = append(stage.RunJobs, rj)
-					continue jobLoop
+	matrixLoop:
+		for _, matrixValues := range combinations {
+			job := stage.Jobs[j]
+			job.Action.Name = sdk.JobMatrixName(job.Action.Name, matrixValues)
+			nbJobRuns++
+
+			if previousStage != nil {
+				for _, rj := range previousStage.RunJobs {
+					if rj.Job.PipelineActionID == job.PipelineActionID && rj.Job.Action.Name == job.Action.Name &&
+						rj.Status != sdk.StatusFail && rj.Status != sdk.StatusTimeout && sdk.StatusIsTerminated(rj.Status) {
+						stage.RunJobs = append(stage.RunJobs, rj)
+						continue matrixLoop
+					}
 				}
 			}
-		}
 
-		// errors generated in the loop will be added to job run spawn info
-		spawnErrs := sdk.MultiError{}
+			// errors generated in the loop will be added to job run spawn info
+			spawnErrs := sdk.MultiError{}
 
-		//Process variables for the jobs
-		_, next = observability.Span(ctx, "workflow..getNodeJobRunParameters")
-		jobParams, err := getNodeJobRunParameters(db, *job, nr, stage)
-		next()
-		if err != nil {
-			spawnErrs.Join(*err)
-		}
+			//Process variables for the jobs
+			_, next = observability.Span(ctx, "workflow..getNodeJobRunParameters")
+			jobParams, err := getNodeJobRunParameters(db, job, nr, stage, matrixValues)
+			next()
+			if err != nil {
+				spawnErrs.Join(*err)
+			}
 
-		_, next = observability.Span(ctx, "workflow.processNodeJobRunRequirements")
-		jobRequirements, containsService, wm, err := processNodeJobRunRequirements(ctx, db, *job, nr, sdk.Groups(groups).ToIDs(), integrationPluginBinaries)
-		next()
-		if err != nil {
-			spawnErrs.Join(*err)
-		}
+			_, next = observability.Span(ctx, "workflow.processNodeJobRunRequirements")
+			jobRequirements, containsService, wm, err := processNodeJobRunRequirements(ctx, db, job, jobParams, sdk.Groups(groups).ToIDs(), integrationPluginBinaries)
+			next()
+			if err != nil {
+				spawnErrs.Join(*err)
+			}
 
-		// check that children actions used by job can be used by the project
-		if err := action.CheckChildrenForGroupIDsWithLoop(ctx, db, &job.Action, sdk.Groups(groups).ToIDs()); err != nil {
-			spawnErrs.Append(err)
-		}
+			// check that children actions used by job can be used by the project
+			if err := action.CheckChildrenForGroupIDsWithLoop(ctx, db, &job.Action, sdk.Groups(groups).ToIDs()); err != nil {
+				spawnErrs.Append(err)
+			}
 
-		// add requirements in job parameters, to use them as {{.job.requirement...}} in job
-		_, next = observability.Span(ctx, "workflow.prepareRequirementsToNodeJobRunParameters")
-		jobParams = append(jobParams, prepareRequirementsToNodeJobRunParameters(jobRequirements)...)
-		next()
-
-		//Create the job run
-		wjob := sdk.WorkflowNodeJobRun{
-			ProjectID:                 wr.ProjectID,
-			WorkflowNodeRunID:         nr.ID,
-			Start:                     time.Time{},
-			Queued:                    time.Now(),
-			Status:                    sdk.StatusWaiting,
-			Parameters:                jobParams,
-			ExecGroups:                groups,
-			IntegrationPluginBinaries: integrationPluginBinaries,
-			Job: sdk.ExecutedJob{
-				Job: *job,
-			},
-			Header:          nr.Header,
-			ContainsService: containsService,
-		}
-		if wm != nil {
-			wjob.ModelType = wm.Type
-		}
-		wjob.Job.Job.Action.Requirements = jobRequirements // Set the interpolated requirements on the job run only
-
-		if !stage.Enabled || !wjob.Job.Enabled {
-			wjob.Status = sdk.StatusDisabled
-			skippedOrDisabledJobs++
-		} else if !conditionsOK {
-			wjob.Status = sdk.StatusSkipped
-			skippedOrDisabledJobs++
-		}
+			// add requirements in job parameters, to use them as {{.job.requirement...}} in job
+			_, next = observability.Span(ctx, "workflow.prepareRequirementsToNodeJobRunParameters")
+			jobParams = append(jobParams, prepareRequirementsToNodeJobRunParameters(jobRequirements)...)
+			next()
+
+			//Create the job run
+			wjob := sdk.WorkflowNodeJobRun{
+				ProjectID:                 wr.ProjectID,
+				WorkflowNodeRunID:         nr.ID,
+				Start:                     time.Time{},
+				Queued:                    time.Now(),
+				Status:                    sdk.StatusWaiting,
+				Parameters:                jobParams,
+				ExecGroups:                groups,
+				IntegrationPluginBinaries: integrationPluginBinaries,
+				Job: sdk.ExecutedJob{
+					Job:          job,
+					MatrixValues: matrixValues,
+				},
+				Header:          nr.Header,
+				ContainsService: containsService,
+			}
+			if wm != nil {
+				wjob.ModelType = wm.Type
+			}
+			wjob.Job.Job.Action.Requirements = jobRequirements // Set the interpolated requirements on the job run only
+
+			if !stage.Enabled || !wjob.Job.Enabled {
+				wjob.Status = sdk.StatusDisabled
+				skippedOrDisabledJobs++
+			} else if !conditionsOK {
+				wjob.Status = sdk.StatusSkipped
+				skippedOrDisabledJobs++
+			}
 
-		// If there is any error in the previous operation, mark the job as failed
-		if !spawnErrs.IsEmpty() {
-			failedJobs++
-			wjob.Status = sdk.StatusFail
+			// If there is any error in the previous operation, mark the job as failed
+			if !spawnErrs.IsEmpty() {
+				failedJobs++
+				wjob.Status = sdk.StatusFail
 
-			for _, e := range spawnErrs {
-				msg := sdk.SpawnMsg{
-					ID: sdk.MsgSpawnInfoJobError.ID,
+				for _, e := range spawnErrs {
+					msg := sdk.SpawnMsg{
+						ID: sdk.MsgSpawnInfoJobError.ID,
+					}
+					msg.Args = []interface{}{sdk.Cause(e).Error()}
+					wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
+						APITime:    time.Now(),
+						Message:    msg,
+						RemoteTime: time.Now(),
+					})
 				}
-				msg.Args = []interface{}{sdk.Cause(e).Error()}
-				wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
+			} else {
+				wjob.SpawnInfos = []sdk.SpawnInfo{{
 					APITime:    time.Now(),
-					Message:    msg,
+					Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobInQueue.ID},
 					RemoteTime: time.Now(),
-				})
+				}}
 			}
-		} else {
-			wjob.SpawnInfos = []sdk.SpawnInfo{{
-				APITime:    time.Now(),
-				Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobInQueue.ID},
-				RemoteTime: time.Now(),
-			}}
-		}
 
-		// insert in database
-		_, next = observability.Span(ctx, "workflow.insertWorkflowNodeJobRun")
-		if err := insertWorkflowNodeJobRun(db, &wjob); err != nil {
+			// insert in database
+			_, next = observability.Span(ctx, "workflow.insertWorkflowNodeJobRun")
+			if err := insertWorkflowNodeJobRun(db, &wjob); err != nil {
+				next()
+				return report, sdk.WrapError(err, "unable to insert in table workflow_node_run_job")
+			}
 			next()
-			return report, sdk.WrapError(err, "unable to insert in table workflow_node_run_job")
-		}
-		next()
 
-		if err := AddSpawnInfosNodeJobRun(db, wjob.WorkflowNodeRunID, wjob.ID, PrepareSpawnInfos(wjob.SpawnInfos)); err != nil {
-			return nil, sdk.WrapError(err, "cannot save spawn info job %d", wjob.ID)
-		}
+			if err := AddSpawnInfosNodeJobRun(db, wjob.WorkflowNodeRunID, wjob.ID, PrepareSpawnInfos(wjob.SpawnInfos)); err != nil {
+				return nil, sdk.WrapError(err, "cannot save spawn info job %d", wjob.ID)
+			}
 
-		//Put the job run in database
-		stage.RunJobs = append(stage.RunJobs, wjob)
+			//Put the job run in database
+			stage.RunJobs = append(stage.RunJobs, wjob)
 
-		report.Add(ctx, wjob)
+			report.Add(ctx, wjob)
+		}
 	}
 
-	if skippedOrDisabledJobs == len(stage.Jobs) {
+	if skippedOrDisabledJobs == nbJobRuns {
 		stage.Status = sdk.StatusSkipped
 	}
 
@@ -659,7 +678,7 @@ func syncStage(ctx context.Context, db gorp.SqlExecutor, store cache.Store, stag
 				if finalStatus == sdk.StatusBuilding || finalStatus == sdk.StatusDisabled {
 					finalStatus = sdk.StatusSkipped
 				}
-			case sdk.StatusFail:
+			case sdk.StatusFail, sdk.StatusTimeout:
 				finalStatus = sdk.StatusFail
 				break finalStageLoop
 			case sdk.StatusSuccess:
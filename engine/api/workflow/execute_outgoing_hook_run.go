@@ -68,7 +68,7 @@ loop:
 	}
 
 	oldStatus := wr.Status
-	r1, err := computeAndUpdateWorkflowRunStatus(ctx, db, wr)
+	r1, err := computeAndUpdateWorkflowRunStatus(ctx, db, store, proj, wr)
 	if err != nil {
 		return report, sdk.WrapError(err, "unable to compute workflow run status")
 	}
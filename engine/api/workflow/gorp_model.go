@@ -58,6 +58,7 @@ type NodeRun struct {
 	HookExecutionTimestamp sql.NullInt64  `db:"hook_execution_timestamp"`
 	ExecutionID            sql.NullString `db:"execution_id"`
 	Callback               sql.NullString `db:"callback"`
+	ConcurrencyGroup       string         `db:"concurrency_group"`
 }
 
 // JobRun is a gorp wrapper around sdk.WorkflowNodeJobRun
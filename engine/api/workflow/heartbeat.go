@@ -28,7 +28,26 @@ func manageDeadJob(ctx context.Context, DBFunc func() *gorp.DbMap, store cache.S
 		}
 
 		if deadJob.Status == sdk.StatusBuilding {
-			if deadJob.Retry >= maxRetry {
+			// If the job has a retry policy, it replaces the default restart behavior
+			if deadJob.Job.RetryPolicy != nil {
+				retried, _, err := RetryNodeJobRun(ctx, tx, &deadJob, sdk.JobRetryOnWorkerLost)
+				if err != nil {
+					log.Error(ctx, "manageDeadJob> Cannot retry node run job %d : %v", deadJob.ID, err)
+					_ = tx.Rollback()
+					continue
+				}
+				if retried {
+					if err := tx.Commit(); err != nil {
+						log.Error(ctx, "manageDeadJob> Cannot commit transaction : %v", err)
+					}
+					continue
+				}
+				if err := AddSpawnInfoRetryExhausted(tx, deadJob, sdk.JobRetryOnWorkerLost); err != nil {
+					log.Error(ctx, "manageDeadJob> Cannot save spawn info on node run job %d : %v", deadJob.ID, err)
+				}
+			}
+
+			if deadJob.Job.RetryPolicy != nil || deadJob.Retry >= maxRetry {
 				if _, err := UpdateNodeJobRunStatus(ctx, tx, store, sdk.Project{}, &deadJob, sdk.StatusStopped); err != nil {
 					log.Error(ctx, "manageDeadJob> Cannot update node run job %d : %v", deadJob.ID, err)
 					_ = tx.Rollback()
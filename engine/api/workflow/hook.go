@@ -116,24 +116,43 @@ func hookRegistration(ctx context.Context, db gorp.SqlExecutor, store cache.Stor
 			Configurable: false,
 		}
 
+		var previousHook *sdk.NodeHook
 		if h.UUID == "" && oldHooksByRef != nil {
 			// search previous hook configuration by ref
-			previousHook, has := oldHooksByRef[h.Ref()]
-			if has {
-				h.UUID = previousHook.UUID
-				// If previous hook is the same, we do nothing
-				if h.Equals(previousHook) {
-					continue
-				}
+			if ph, has := oldHooksByRef[h.Ref()]; has {
+				h.UUID = ph.UUID
+				previousHook = &ph
 			}
 		} else if oldHooks != nil {
 			// search previous hook configuration by uuid
-			previousHook, has := oldHooks[h.UUID]
-			// If previous hook is the same, we do nothing
-			if has && h.Equals(*previousHook) {
-				continue
+			previousHook = oldHooks[h.UUID]
+		}
+
+		// The secret of repository webhooks is never sent to users, keep the previous one
+		if previousHook != nil && h.Config[sdk.HookConfigWebHookSecret].Value == "" {
+			if secret, has := previousHook.Config[sdk.HookConfigWebHookSecret]; has {
+				h.Config[sdk.HookConfigWebHookSecret] = secret
+			}
+		}
+
+		// Generate the secret used by the repository manager to sign the payloads of the webhook,
+		// webhooks created without secret get one on their next update
+		if h.HookModelName == sdk.RepositoryWebHookModelName && h.Config[sdk.HookConfigWebHookSecret].Value == "" {
+			secret, err := sdk.GenerateHash()
+			if err != nil {
+				return sdk.WrapError(err, "cannot generate webhook secret")
+			}
+			h.Config[sdk.HookConfigWebHookSecret] = sdk.WorkflowNodeHookConfigValue{
+				Value:        secret,
+				Configurable: false,
 			}
 		}
+
+		// If previous hook is the same, we do nothing
+		if previousHook != nil && h.Equals(*previousHook) {
+			continue
+		}
+
 		// initialize a UUID is there no uuid
 		if h.UUID == "" {
 			h.UUID = sdk.UUID()
@@ -302,16 +321,22 @@ func createVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 		Method:   "POST",
 		URL:      h.Config["webHookURL"].Value,
 		Workflow: true,
+		Secret:   h.Config[sdk.HookConfigWebHookSecret].Value,
 	}
 
 	// Set given event filters if exists, else default values will be set by CreateHook func.
+	var eventFilter []string
 	if c, ok := h.Config[sdk.HookConfigEventFilter]; ok && c.Value != "" {
-		vcsHook.Events = strings.Split(c.Value, ";")
+		eventFilter = strings.Split(c.Value, ";")
+		vcsHook.Events = pullRequestHookEvents(*h, eventFilter, webHookInfo.Events)
 	}
 
 	if err := client.CreateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "Cannot create hook on repository: %+v", vcsHook)
 	}
+	if len(eventFilter) == 0 {
+		eventFilter = vcsHook.Events
+	}
 	observability.Current(ctx, observability.Tag("VCS_ID", vcsHook.ID))
 	h.Config[sdk.HookConfigWebHookID] = sdk.WorkflowNodeHookConfigValue{
 		Value:        vcsHook.ID,
@@ -325,11 +350,40 @@ func createVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 	h.Config[sdk.HookConfigEventFilter] = sdk.WorkflowNodeHookConfigValue{
 		Type:         sdk.HookConfigTypeMultiChoice,
 		Configurable: true,
-		Value:        strings.Join(vcsHook.Events, ";"),
+		Value:        strings.Join(eventFilter, ";"),
 	}
 	return nil
 }
 
+// mergeQueueHookEvent is the event sent by the repository manager when a pull request is reviewed
+const mergeQueueHookEvent = "pull_request_review"
+
+// pullRequestHookEvents returns the events sent by the repository manager to the hook.
+// When the merge refs of the pull requests are built, the push event is added to rebuild them when their base branch moves.
+// When the merge queue is enabled, the review event is added to queue the approved pull requests.
+func pullRequestHookEvents(h sdk.NodeHook, eventFilter []string, availableEvents []string) []string {
+	if len(availableEvents) == 0 {
+		return eventFilter
+	}
+	mergeRef := h.Config[sdk.HookConfigPullRequestRef].Value == sdk.HookPullRequestRefMerge
+	method, _ := sdk.MergeQueueConfig(h.Config)
+	mergeQueue := method != "" && sdk.IsInArray(mergeQueueHookEvent, availableEvents)
+	if !mergeRef && !mergeQueue {
+		return eventFilter
+	}
+
+	events := make([]string, 0, len(eventFilter)+2)
+	events = append(events, eventFilter...)
+	// The first available event is the push event, it is also the default one when there is no filter
+	if (mergeRef || len(eventFilter) == 0) && !sdk.IsInArray(availableEvents[0], events) {
+		events = append(events, availableEvents[0])
+	}
+	if mergeQueue && !sdk.IsInArray(mergeQueueHookEvent, events) {
+		events = append(events, mergeQueueHookEvent)
+	}
+	return events
+}
+
 func updateVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, h *sdk.NodeHook) error {
 	ctx, end := observability.Span(ctx, "workflow.updateVCSConfiguration", observability.Tag("UUID", h.UUID))
 	defer end()
@@ -353,16 +407,22 @@ func updateVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 		Method:   "POST",
 		URL:      h.Config["webHookURL"].Value,
 		Workflow: true,
+		Secret:   h.Config[sdk.HookConfigWebHookSecret].Value,
 	}
 
 	// Set given event filters if exists, else default values will be set by CreateHook func.
+	var eventFilter []string
 	if c, ok := h.Config[sdk.HookConfigEventFilter]; ok && c.Value != "" {
-		vcsHook.Events = strings.Split(c.Value, ";")
+		eventFilter = strings.Split(c.Value, ";")
+		vcsHook.Events = pullRequestHookEvents(*h, eventFilter, webHookInfo.Events)
 	}
 
 	if err := client.UpdateHook(ctx, h.Config["repoFullName"].Value, &vcsHook); err != nil {
 		return sdk.WrapError(err, "Cannot update hook on repository: %+v", vcsHook)
 	}
+	if len(eventFilter) == 0 {
+		eventFilter = vcsHook.Events
+	}
 	h.Config[sdk.HookConfigIcon] = sdk.WorkflowNodeHookConfigValue{
 		Value:        webHookInfo.Icon,
 		Configurable: false,
@@ -371,7 +431,7 @@ func updateVCSConfiguration(ctx context.Context, db gorp.SqlExecutor, store cach
 	h.Config[sdk.HookConfigEventFilter] = sdk.WorkflowNodeHookConfigValue{
 		Type:         sdk.HookConfigTypeMultiChoice,
 		Configurable: true,
-		Value:        strings.Join(vcsHook.Events, ";"),
+		Value:        strings.Join(eventFilter, ";"),
 	}
 	return nil
 }
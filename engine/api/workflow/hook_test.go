@@ -0,0 +1,30 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_pullRequestHookEvents(t *testing.T) {
+	available := []string{"push", "pull_request_review_comment", "pull_request_review", "pull_request"}
+
+	h := sdk.NodeHook{Config: sdk.WorkflowNodeHookConfig{}}
+	assert.Equal(t, []string{"pull_request"}, pullRequestHookEvents(h, []string{"pull_request"}, available))
+	assert.Nil(t, pullRequestHookEvents(h, nil, available))
+
+	// Merge refs need the push event
+	h.Config[sdk.HookConfigPullRequestRef] = sdk.WorkflowNodeHookConfigValue{Value: sdk.HookPullRequestRefMerge}
+	assert.Equal(t, []string{"pull_request", "push"}, pullRequestHookEvents(h, []string{"pull_request"}, available))
+
+	// The merge queue needs the review event
+	h.Config[sdk.HookConfigMergeQueue] = sdk.WorkflowNodeHookConfigValue{Value: sdk.VCSMergeMethodMerge}
+	assert.Equal(t, []string{"pull_request", "push", "pull_request_review"}, pullRequestHookEvents(h, []string{"pull_request"}, available))
+	delete(h.Config, sdk.HookConfigPullRequestRef)
+	assert.Equal(t, []string{"push", "pull_request_review"}, pullRequestHookEvents(h, nil, available))
+
+	// The review event is not available on every repository manager
+	assert.Equal(t, []string{"pull_request"}, pullRequestHookEvents(h, []string{"pull_request"}, []string{"push", "pull_request"}))
+}
@@ -1,6 +1,17 @@
 package workflow
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
+
 	"github.com/ovh/cds/sdk"
 )
 
@@ -10,6 +21,26 @@ const (
 	maxLogMarker      = "... truncated\n"
 )
 
+// Limits of a step logs search
+const (
+	DefaultLogSearchLimit = 100
+	MaxLogSearchLimit     = 1000
+	maxLogSearchLogs      = 1000
+	maxLogSearchLineSize  = 1024
+	maxLogSearchBytes     = 100 * 1024 * 1024 // 100MB
+	logSearchTimeout      = 30 * time.Second
+)
+
+// LogSearchOptions filters the step logs of a log search.
+type LogSearchOptions struct {
+	ProjectKey    string
+	WorkflowNames []string
+	From          time.Time
+	To            time.Time
+	Regexp        *regexp.Regexp
+	Limit         int
+}
+
 func truncateLogs(maxSize, existingSize int64, logs *sdk.Log) bool {
 	if maxSize == 0 {
 		maxSize = DefaultMaxLogSize
@@ -47,3 +78,174 @@ func truncateServiceLogs(maxSize, existingSize int64, logs *sdk.ServiceLog) bool
 
 	return false
 }
+
+// SearchStepLogs returns the lines of the step logs of given workflows that match a regexp, from the most recent step logs.
+// Only the step logs written in the time window are read, with a maximum of maxLogSearchLogs step logs.
+// The search stops when maxLogSearchBytes are read or after logSearchTimeout, the lines found so far are returned.
+func SearchStepLogs(ctx context.Context, db gorp.SqlExecutor, opts LogSearchOptions) ([]sdk.WorkflowLogSearchResult, error) {
+	if opts.Limit <= 0 || opts.Limit > MaxLogSearchLimit {
+		opts.Limit = DefaultLogSearchLimit
+	}
+	results := []sdk.WorkflowLogSearchResult{}
+	if len(opts.WorkflowNames) == 0 {
+		return results, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, logSearchTimeout)
+	defer cancel()
+	db = db.WithContext(ctx)
+
+	query := `
+		SELECT l.id, l.workflow_node_run_job_id, l.workflow_node_run_id, l.start, l.last_modified, l.done, l.step_order, l.storage,
+			workflow.name, workflow_node_run.num, workflow_node_run.sub_num, workflow_node_run.workflow_node_name
+		FROM workflow_node_run_job_logs l
+		JOIN workflow_node_run ON workflow_node_run.id = l.workflow_node_run_id
+		JOIN workflow ON workflow.id = workflow_node_run.workflow_id
+		JOIN project ON project.id = workflow.project_id
+		WHERE project.projectkey = $1 AND workflow.name = ANY($2)
+		AND l.last_modified >= $3 AND l.start <= $4
+		ORDER BY l.id DESC
+		LIMIT $5`
+	rows, err := db.Query(query, opts.ProjectKey, pq.StringArray(opts.WorkflowNames), opts.From, opts.To, maxLogSearchLogs)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	type searchedLog struct {
+		logs    sdk.Log
+		storage string
+		result  sdk.WorkflowLogSearchResult
+	}
+	var searched []searchedLog
+	for rows.Next() {
+		var l searchedLog
+		var st, m, d pq.NullTime
+		if err := rows.Scan(&l.logs.ID, &l.logs.JobID, &l.logs.NodeRunID, &st, &m, &d, &l.logs.StepOrder, &l.storage,
+			&l.result.WorkflowName, &l.result.RunNumber, &l.result.SubNumber, &l.result.NodeName); err != nil {
+			rows.Close() // nolint
+			return nil, sdk.WithStack(err)
+		}
+		if m.Valid {
+			l.result.Date = m.Time
+		}
+		l.result.NodeRunID = l.logs.NodeRunID
+		l.result.JobID = l.logs.JobID
+		l.result.StepOrder = l.logs.StepOrder
+		searched = append(searched, l)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	jobNames := map[int64]map[int64]string{}
+	budget := int64(maxLogSearchBytes)
+	for i := range searched {
+		if budget <= 0 || ctx.Err() != nil {
+			break
+		}
+		l := &searched[i]
+		storage, err := getLogStorage(l.storage)
+		if err != nil {
+			return nil, err
+		}
+		r, err := storage.Open(ctx, db, &l.logs)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return nil, sdk.WrapError(err, "cannot open step log %d from storage %s", l.logs.ID, l.storage)
+		}
+		lr := &io.LimitedReader{R: r, N: budget}
+		lines, err := searchLines(lr, opts.Regexp, opts.Limit-len(results))
+		r.Close() // nolint
+		budget = lr.N
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return nil, sdk.WrapError(err, "cannot read step log %d", l.logs.ID)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		names, ok := jobNames[l.logs.NodeRunID]
+		if !ok {
+			names, err = loadNodeRunJobNames(db, l.logs.NodeRunID)
+			if err != nil {
+				if ctx.Err() != nil {
+					break
+				}
+				return nil, err
+			}
+			jobNames[l.logs.NodeRunID] = names
+		}
+		for _, line := range lines {
+			res := l.result
+			res.JobName = names[l.logs.JobID]
+			res.LineNumber = line.number
+			res.Line = line.value
+			results = append(results, res)
+		}
+		if len(results) >= opts.Limit {
+			break
+		}
+	}
+	return results, nil
+}
+
+type searchedLine struct {
+	number int64
+	value  string
+}
+
+// searchLines returns the lines of a log matching a regexp, at most limit lines.
+func searchLines(r io.Reader, reg *regexp.Regexp, limit int) ([]searchedLine, error) {
+	var lines []searchedLine
+	br := bufio.NewReader(r)
+	var number int64
+	for len(lines) < limit {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			number++
+			line = strings.TrimRight(line, "\r\n")
+			if reg.MatchString(line) {
+				if len(line) > maxLogSearchLineSize {
+					line = line[:maxLogSearchLineSize] + maxLogMarker
+				}
+				lines = append(lines, searchedLine{number: number, value: line})
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, sdk.WithStack(err)
+		}
+	}
+	return lines, nil
+}
+
+// loadNodeRunJobNames returns the names of the job runs of a node run, including the previous attempts of retried jobs.
+func loadNodeRunJobNames(db gorp.SqlExecutor, nodeRunID int64) (map[int64]string, error) {
+	btes, err := db.SelectNullStr("SELECT stages FROM workflow_node_run WHERE id = $1", nodeRunID)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	names := map[int64]string{}
+	if !btes.Valid {
+		return names, nil
+	}
+	var stages []sdk.Stage
+	if err := json.Unmarshal([]byte(btes.String), &stages); err != nil {
+		return nil, sdk.WrapError(err, "cannot unmarshal stages of node run %d", nodeRunID)
+	}
+	for _, s := range stages {
+		for _, rj := range s.RunJobs {
+			names[rj.ID] = rj.Job.Action.Name
+			for _, a := range rj.Job.Attempts {
+				names[a.JobRunID] = rj.Job.Action.Name
+			}
+		}
+	}
+	return names, nil
+}
@@ -1,10 +1,17 @@
 package workflow
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -39,3 +46,67 @@ func Test_truncateStepLogs(t *testing.T) {
 
 	assert.Equal(t, true, truncateServiceLogs(15, 20, logs))
 }
+
+func Test_logChunksReader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-step-logs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	driver, err := objectstore.Init(context.TODO(), objectstore.Config{
+		Kind:    objectstore.Filesystem,
+		Options: objectstore.ConfigOptions{Filesystem: objectstore.ConfigOptionsFilesystem{Basedir: dir}},
+	})
+	require.NoError(t, err)
+
+	chunks := []logChunk{
+		{ID: 1, NodeRunID: 10, JobID: 100, StepOrder: 0},
+		{ID: 2, NodeRunID: 10, JobID: 100, StepOrder: 0},
+		{ID: 3, NodeRunID: 10, JobID: 100, StepOrder: 0},
+	}
+	for i, data := range []string{"first ", "", "second "} {
+		_, err := driver.Store(chunks[i], ioutil.NopCloser(strings.NewReader(data)))
+		require.NoError(t, err)
+	}
+
+	// the chunks are read before the data still buffered in database
+	r := &logChunksReader{
+		ctx:    context.TODO(),
+		driver: driver,
+		chunks: chunks,
+		buffer: "third",
+	}
+	btes, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "first second third", string(btes))
+	require.NoError(t, r.Close())
+
+	// each chunk is deleted before the container
+	s := NewObjectStoreLogStorage(driver)
+	require.NoError(t, s.deleteChunks(context.TODO(), 10, chunks))
+	_, err = os.Stat(dir + "/logs-10")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_SetLogStorage(t *testing.T) {
+	defer SetLogStorage(LogStorageDatabase, nil) // nolint
+
+	assert.Error(t, SetLogStorage("unknown", nil))
+	assert.Error(t, SetLogStorage(LogStorageObjectStore, nil))
+	require.NoError(t, SetLogStorage("", nil))
+	assert.Equal(t, LogStorageDatabase, currentLogStorage().Name())
+}
+
+func Test_searchLines(t *testing.T) {
+	log := "Starting build\nerror: file not found\r\nbuild step 2\nerror: timeout\nend"
+	lines, err := searchLines(strings.NewReader(log), regexp.MustCompile(`^error: (.*)$`), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []searchedLine{{number: 2, value: "error: file not found"}, {number: 4, value: "error: timeout"}}, lines)
+
+	lines, err = searchLines(strings.NewReader(log), regexp.MustCompile(`error`), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []searchedLine{{number: 2, value: "error: file not found"}}, lines)
+
+	lines, err = searchLines(strings.NewReader(log), regexp.MustCompile(`end`), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []searchedLine{{number: 5, value: "end"}}, lines)
+}
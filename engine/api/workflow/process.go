@@ -45,9 +45,12 @@ func setValuesGitInBuildParameters(run *sdk.WorkflowNodeRun, vcsInfos vcsInfos)
 func checkCondition(ctx context.Context, wr *sdk.WorkflowRun, conditions sdk.WorkflowNodeConditions, params []sdk.Parameter) bool {
 	var conditionsOK bool
 	var errc error
-	if conditions.LuaScript == "" {
+	switch {
+	case conditions.Expression != "":
+		conditionsOK, errc = sdk.WorkflowCheckConditionExpression(conditions.Expression, params)
+	case conditions.LuaScript == "":
 		conditionsOK, errc = sdk.WorkflowCheckConditions(conditions.PlainConditions, params)
-	} else {
+	default:
 		luacheck, err := luascript.NewCheck()
 		if err != nil {
 			log.Warning(ctx, "processWorkflowNodeRun> WorkflowCheckConditions error: %s", err)
@@ -65,7 +68,7 @@ func checkCondition(ctx context.Context, wr *sdk.WorkflowRun, conditions sdk.Wor
 		log.Warning(ctx, "processWorkflowNodeRun> WorkflowCheckConditions error: %s", errc)
 		AddWorkflowRunInfo(wr, sdk.SpawnMsg{
 			ID:   sdk.MsgWorkflowError.ID,
-			Args: []interface{}{fmt.Sprintf("Error on LUA Condition: %v", errc)},
+			Args: []interface{}{fmt.Sprintf("Error on Condition: %v", errc)},
 			Type: sdk.MsgWorkflowError.Type,
 		})
 		return false
@@ -68,7 +68,7 @@ func processWorkflowDataRun(ctx context.Context, db gorp.SqlExecutor, store cach
 		}
 		report.Merge(ctx, r1)
 
-		r2, err := computeAndUpdateWorkflowRunStatus(ctx, db, wr)
+		r2, err := computeAndUpdateWorkflowRunStatus(ctx, db, store, proj, wr)
 		if err != nil {
 			return nil, false, sdk.WrapError(err, "unable to compute workflow run status")
 		}
@@ -85,7 +85,7 @@ func processWorkflowDataRun(ctx context.Context, db gorp.SqlExecutor, store cach
 		}
 		report.Merge(ctx, r1)
 
-		r2, err := computeAndUpdateWorkflowRunStatus(ctx, db, wr)
+		r2, err := computeAndUpdateWorkflowRunStatus(ctx, db, store, proj, wr)
 		if err != nil {
 			return nil, false, sdk.WrapError(err, "unable to compute workflow run status")
 		}
@@ -106,7 +106,7 @@ func processWorkflowDataRun(ctx context.Context, db gorp.SqlExecutor, store cach
 	}
 	report.Merge(ctx, r2)
 
-	r1, err := computeAndUpdateWorkflowRunStatus(ctx, db, wr)
+	r1, err := computeAndUpdateWorkflowRunStatus(ctx, db, store, proj, wr)
 	if err != nil {
 		return nil, false, sdk.WrapError(err, "unable to compute workflow run status")
 	}
@@ -115,7 +115,7 @@ func processWorkflowDataRun(ctx context.Context, db gorp.SqlExecutor, store cach
 	return report, true, nil
 }
 
-func computeAndUpdateWorkflowRunStatus(ctx context.Context, db gorp.SqlExecutor, wr *sdk.WorkflowRun) (*ProcessorReport, error) {
+func computeAndUpdateWorkflowRunStatus(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun) (*ProcessorReport, error) {
 	report := new(ProcessorReport)
 	// Recompute status counter, it's mandatory to resync
 	// the map of workflow node runs of the workflow run to get the right statuses
@@ -149,5 +149,11 @@ func computeAndUpdateWorkflowRunStatus(ctx context.Context, db gorp.SqlExecutor,
 	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
 		return report, sdk.WithStack(err)
 	}
+
+	r1, err := ReleaseWorkflowConcurrencyGroup(ctx, db, store, proj, wr)
+	if err != nil {
+		return report, err
+	}
+	report.Merge(ctx, r1)
 	return report, nil
 }
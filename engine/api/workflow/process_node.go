@@ -76,7 +76,7 @@ func processNodeRun(ctx context.Context, db gorp.SqlExecutor, store cache.Store,
 		n := wr.Workflow.WorkflowData.NodeByID(parentNodeRuns[0].WorkflowNodeID)
 		// If fork or JOIN and No run conditions
 		if (n.Type == sdk.NodeTypeJoin || n.Type == sdk.NodeTypeFork) &&
-			(n.Context == nil || (n.Context.Conditions.LuaScript == "" && len(n.Context.Conditions.PlainConditions) == 0)) {
+			(n.Context == nil || n.Context.Conditions.IsEmpty()) {
 			manual = parentNodeRuns[0].Manual
 		}
 	}
@@ -256,7 +256,9 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 	}
 
 	// CONDITION
-	if !checkCondition(ctx, wr, n.Context.Conditions, nr.BuildParameters) {
+	conditionsOK := checkCondition(ctx, wr, n.Context.Conditions, nr.BuildParameters)
+	recordNodeConditions(ctx, wr, n, nr.BuildParameters)
+	if !conditionsOK {
 		log.Debug("Condition failed on processNode %d/%d %+v", wr.ID, n.ID, nr.BuildParameters)
 		return nil, false, nil
 	}
@@ -383,12 +385,22 @@ func processNode(ctx context.Context, db gorp.SqlExecutor, store cache.Store, pr
 		//Mutex is free, continue
 	}
 
+	r1, canRun, err := checkConcurrencyGroups(ctx, db, store, proj, wr, n, nr)
+	if err != nil {
+		return nil, false, err
+	}
+	report.Merge(ctx, r1)
+	if !canRun {
+		// The node run is waiting for the end of the runs of its concurrency groups, conditions are ok
+		return report, true, nil
+	}
+
 	//Execute the node run !
-	r1, err := executeNodeRun(ctx, db, store, proj, nr)
+	r2, err := executeNodeRun(ctx, db, store, proj, nr)
 	if err != nil {
 		return nil, false, sdk.WrapError(err, "unable to execute workflow run")
 	}
-	report.Merge(ctx, r1)
+	report.Merge(ctx, r2)
 	return report, true, nil
 }
 
@@ -129,15 +129,20 @@ func processNodeOutGoingHook(ctx context.Context, db gorp.SqlExecutor, store cac
 		}
 	}
 
-	if !checkCondition(ctx, wr, node.Context.Conditions, hookRun.BuildParameters) {
+	conditionsOK := checkCondition(ctx, wr, node.Context.Conditions, hookRun.BuildParameters)
+	recordNodeConditions(ctx, wr, node, hookRun.BuildParameters)
+	if !conditionsOK {
 		log.Debug("Condition failed on processNodeOutGoingHook %d/%d %+v", wr.ID, node.ID, hookRun.BuildParameters)
 		return report, false, nil
 	}
 
+	// The hook is not executed when the workflow run is processed in plan mode
 	var task sdk.Task
-	if _, _, err := services.NewClient(db, srvs).DoJSONRequest(ctx, "POST", "/task/execute", hookRun, &task); err != nil {
-		log.Warning(ctx, "outgoing hook execution failed: %v", err)
-		hookRun.Status = sdk.StatusFail
+	if getRunPlan(ctx) == nil {
+		if _, _, err := services.NewClient(db, srvs).DoJSONRequest(ctx, "POST", "/task/execute", hookRun, &task); err != nil {
+			log.Warning(ctx, "outgoing hook execution failed: %v", err)
+			hookRun.Status = sdk.StatusFail
+		}
 	}
 
 	if len(task.Executions) > 0 {
@@ -14,14 +14,14 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-// processNodeJobRunRequirements returns requirements list interpolated, and true or false if at least
+// processNodeJobRunRequirements returns requirements list interpolated with given job parameters, and true or false if at least
 // one requirement is of type "Service"
-func processNodeJobRunRequirements(ctx context.Context, db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNodeRun, execsGroupIDs []int64, integrationPluginBinaries []sdk.GRPCPluginBinary) (sdk.RequirementList, bool, *sdk.Model, *sdk.MultiError) {
+func processNodeJobRunRequirements(ctx context.Context, db gorp.SqlExecutor, j sdk.Job, params []sdk.Parameter, execsGroupIDs []int64, integrationPluginBinaries []sdk.GRPCPluginBinary) (sdk.RequirementList, bool, *sdk.Model, *sdk.MultiError) {
 	var requirements sdk.RequirementList
 	var errm sdk.MultiError
 	var containsService bool
 	var model string
-	var tmp = sdk.ParametersToMap(run.BuildParameters)
+	var tmp = sdk.ParametersToMap(params)
 
 	pluginsRequirements := []sdk.Requirement{}
 	for i := range integrationPluginBinaries {
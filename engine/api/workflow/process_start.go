@@ -2,12 +2,17 @@ package workflow
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -129,7 +134,7 @@ func processAllJoins(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 			}
 
 			// If there is no conditions on join, keep default condition ( only continue on success )
-			if j.Context == nil || (len(j.Context.Conditions.PlainConditions) == 0 && j.Context.Conditions.LuaScript == "") {
+			if j.Context == nil || j.Context.Conditions.IsEmpty() {
 				if nodeRun.Status == sdk.StatusFail || nodeRun.Status == sdk.StatusNeverBuilt || nodeRun.Status == sdk.StatusStopped {
 					ok = false
 					break
@@ -156,3 +161,343 @@ func processAllJoins(ctx context.Context, db gorp.SqlExecutor, store cache.Store
 	}
 	return report, nil
 }
+
+// computeConcurrencyGroup interpolates a concurrency group with the build parameters of a node run.
+func computeConcurrencyGroup(c sdk.WorkflowConcurrency, params []sdk.Parameter) (string, error) {
+	group, err := interpolate.Do(c.Group, sdk.ParametersToMap(params))
+	if err != nil {
+		return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "unable to interpolate concurrency group %q: %v", c.Group, err)
+	}
+	// The group is stored in a VARCHAR(256), it is truncated on a rune boundary
+	if utf8.RuneCountInString(group) > 256 {
+		group = string([]rune(group)[:256])
+	}
+	return group, nil
+}
+
+// checkConcurrencyGroups resolves the workflow and node concurrency groups of a new node run.
+// Runs in progress in the same groups are cancelled if the group allows it, else it returns
+// false and the node run has to wait for them to end.
+func checkConcurrencyGroups(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun, n *sdk.Node, nr *sdk.WorkflowNodeRun) (*ProcessorReport, bool, error) {
+	var end func()
+	ctx, end = observability.Span(ctx, "workflow.checkConcurrencyGroups")
+	defer end()
+
+	report := new(ProcessorReport)
+	var mustWait bool
+	// Runs in progress are not cancelled when the workflow run is processed in plan mode
+	isPlan := getRunPlan(ctx) != nil
+
+	// The workflow concurrency group is resolved when the run starts from its root node
+	if wr.Workflow.Concurrency != nil && n.ID == wr.Workflow.WorkflowData.Node.ID && nr.SubNumber == 0 {
+		group, err := computeConcurrencyGroup(*wr.Workflow.Concurrency, nr.BuildParameters)
+		if err != nil {
+			return nil, false, err
+		}
+		wr.ConcurrencyGroup = group
+		if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+			return nil, false, sdk.WrapError(err, "unable to update workflow run")
+		}
+
+		if wr.Workflow.Concurrency.CancelInProgress {
+			if !isPlan {
+				r1, err := cancelWorkflowRunsInConcurrencyGroup(ctx, db, wr)
+				if err != nil {
+					return nil, false, err
+				}
+				report.Merge(ctx, r1)
+			}
+		} else {
+			// Waiting or building runs started before this one are in progress or waiting for their turn
+			query := `select count(1)
+			from workflow_run
+			where workflow_run.project_id = $1
+			and workflow_run.concurrency_group = $2
+			and workflow_run.id < $3
+			and workflow_run.status in ($4, $5)`
+			nb, err := db.SelectInt(query, wr.ProjectID, group, wr.ID, sdk.StatusWaiting, sdk.StatusBuilding)
+			if err != nil {
+				return nil, false, sdk.WrapError(err, "unable to check workflow concurrency group")
+			}
+			mustWait = nb > 0
+		}
+	}
+
+	if n.Context != nil && n.Context.Concurrency != nil {
+		group, err := computeConcurrencyGroup(*n.Context.Concurrency, nr.BuildParameters)
+		if err != nil {
+			return nil, false, err
+		}
+		nr.ConcurrencyGroup = group
+		if err := UpdateNodeRun(db, nr); err != nil {
+			return nil, false, sdk.WrapError(err, "unable to update node run %d", nr.ID)
+		}
+
+		if n.Context.Concurrency.CancelInProgress {
+			if !isPlan {
+				r1, err := cancelNodeRunsInConcurrencyGroup(ctx, db, store, proj, wr, nr)
+				if err != nil {
+					return nil, false, err
+				}
+				report.Merge(ctx, r1)
+			}
+		} else if !mustWait {
+			// Same check as for mutex: a previous node run waiting in the group or another one building
+			query := `select count(1)
+			from workflow_node_run
+			join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+			where workflow_run.project_id = $1
+			and workflow_node_run.concurrency_group = $2
+			and (
+				(workflow_node_run.id < $3 and workflow_node_run.status = $4)
+				or
+				(workflow_node_run.id <> $3 and workflow_node_run.status = $5)
+			)`
+			nb, err := db.SelectInt(query, wr.ProjectID, group, nr.ID, sdk.StatusWaiting, sdk.StatusBuilding)
+			if err != nil {
+				return nil, false, sdk.WrapError(err, "unable to check node concurrency group")
+			}
+			mustWait = nb > 0
+		}
+	}
+
+	if mustWait {
+		group := nr.ConcurrencyGroup
+		if group == "" {
+			group = wr.ConcurrencyGroup
+		}
+		log.Debug("Noderun %s processed but not executed because of concurrency group %s", n.Name, group)
+		AddWorkflowRunInfo(wr, sdk.SpawnMsg{
+			ID:   sdk.MsgWorkflowConcurrencyWaiting.ID,
+			Args: []interface{}{n.Name, group},
+			Type: sdk.MsgWorkflowConcurrencyWaiting.Type,
+		})
+		if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+			return nil, false, sdk.WrapError(err, "unable to update workflow run")
+		}
+	}
+
+	return report, !mustWait, nil
+}
+
+// cancelWorkflowRunsInConcurrencyGroup stops the runs in progress started before given run in its concurrency group.
+func cancelWorkflowRunsInConcurrencyGroup(ctx context.Context, db gorp.SqlExecutor, wr *sdk.WorkflowRun) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	var ids []int64
+	query := `select workflow_run.id
+	from workflow_run
+	where workflow_run.project_id = $1
+	and workflow_run.concurrency_group = $2
+	and workflow_run.id < $3
+	and workflow_run.status in ($4, $5)
+	order by workflow_run.id`
+	if _, err := db.Select(&ids, query, wr.ProjectID, wr.ConcurrencyGroup, wr.ID, sdk.StatusWaiting, sdk.StatusBuilding); err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow runs in concurrency group %s", wr.ConcurrencyGroup)
+	}
+
+	for _, id := range ids {
+		run, err := LoadRunByID(db, id, LoadRunOptions{})
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to load workflow run %d", id)
+		}
+		for _, nodeRuns := range run.WorkflowNodeRuns {
+			for i := range nodeRuns {
+				if nodeRuns[i].SubNumber != run.LastSubNumber || sdk.StatusIsTerminated(nodeRuns[i].Status) {
+					continue
+				}
+				r1, err := cancelNodeRunInConcurrencyGroup(ctx, db, run, nodeRuns[i].ID, wr, wr.ConcurrencyGroup)
+				if err != nil {
+					return nil, err
+				}
+				report.Merge(ctx, r1)
+			}
+		}
+		run.Status = sdk.StatusStopped
+		if err := UpdateWorkflowRun(ctx, db, run); err != nil {
+			return nil, sdk.WrapError(err, "unable to update workflow run %d", run.ID)
+		}
+		report.Add(ctx, *run)
+	}
+	return report, nil
+}
+
+// cancelNodeRunsInConcurrencyGroup stops the node runs in progress started before given node run in its concurrency group.
+// Node runs of the same workflow run are not cancelled.
+func cancelNodeRunsInConcurrencyGroup(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	var nodeRuns []struct {
+		ID            int64 `db:"id"`
+		WorkflowRunID int64 `db:"workflow_run_id"`
+	}
+	query := `select workflow_node_run.id, workflow_node_run.workflow_run_id
+	from workflow_node_run
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	where workflow_run.project_id = $1
+	and workflow_node_run.concurrency_group = $2
+	and workflow_node_run.id < $3
+	and workflow_node_run.workflow_run_id <> $4
+	and workflow_node_run.status in ($5, $6)
+	order by workflow_node_run.id`
+	if _, err := db.Select(&nodeRuns, query, wr.ProjectID, nr.ConcurrencyGroup, nr.ID, wr.ID, sdk.StatusWaiting, sdk.StatusBuilding); err != nil {
+		return nil, sdk.WrapError(err, "unable to load node runs in concurrency group %s", nr.ConcurrencyGroup)
+	}
+
+	for _, nodeRun := range nodeRuns {
+		run, err := LoadRunByID(db, nodeRun.WorkflowRunID, LoadRunOptions{})
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to load workflow run %d", nodeRun.WorkflowRunID)
+		}
+		r1, err := cancelNodeRunInConcurrencyGroup(ctx, db, run, nodeRun.ID, wr, nr.ConcurrencyGroup)
+		if err != nil {
+			return nil, err
+		}
+		report.Merge(ctx, r1)
+
+		r2, err := computeAndUpdateWorkflowRunStatus(ctx, db, store, proj, run)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to compute workflow run %d status", run.ID)
+		}
+		report.Merge(ctx, r2)
+		report.Add(ctx, *run)
+	}
+	return report, nil
+}
+
+// cancelNodeRunInConcurrencyGroup stops a node run replaced by a newer run of its concurrency group.
+// Workers building its jobs are disabled, they will stop as soon as they see that their job has been removed.
+func cancelNodeRunInConcurrencyGroup(ctx context.Context, db gorp.SqlExecutor, run *sdk.WorkflowRun, nodeRunID int64, by *sdk.WorkflowRun, group string) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	nodeRun, err := LoadAndLockNodeRunByID(ctx, db, nodeRunID)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrLocked) {
+			log.Warning(ctx, "cancelNodeRunInConcurrencyGroup> node run %d is locked, it will not be cancelled", nodeRunID)
+			return report, nil
+		}
+		return nil, err
+	}
+
+	msg := sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowConcurrencyCancel.ID,
+		Args: []interface{}{nodeRun.WorkflowNodeName, fmt.Sprintf("%s #%d", by.Workflow.Name, by.Number), group},
+		Type: sdk.MsgWorkflowConcurrencyCancel.Type,
+	}
+	AddWorkflowRunInfo(run, msg)
+
+	// Stages status are computed with spawn infos loaded from the job runs, they have to be stopped before removing the job runs
+	stopWorkflowNodeRunStages(ctx, db, nodeRun)
+	info := sdk.SpawnInfo{APITime: time.Now(), RemoteTime: time.Now(), Message: msg}
+	for i := range nodeRun.Stages {
+		for j := range nodeRun.Stages[i].RunJobs {
+			runJob := &nodeRun.Stages[i].RunJobs[j]
+			if runJob.Status == sdk.StatusStopped {
+				runJob.SpawnInfos = append(runJob.SpawnInfos, info)
+			}
+		}
+	}
+
+	if _, err := db.Exec(`UPDATE worker SET status = $2, job_run_id = NULL
+	WHERE job_run_id IN (SELECT id FROM workflow_node_run_job WHERE workflow_node_run_id = $1)`, nodeRun.ID, sdk.StatusDisabled); err != nil {
+		return nil, sdk.WrapError(err, "unable to disable workers of node run %d", nodeRun.ID)
+	}
+	if err := DeleteNodeJobRuns(db, nodeRun.ID); err != nil {
+		return nil, sdk.WrapError(err, "unable to delete node %d job runs", nodeRun.ID)
+	}
+
+	nodeRun.Status = sdk.StatusStopped
+	nodeRun.Done = time.Now()
+	if err := UpdateNodeRun(db, nodeRun); err != nil {
+		return nil, sdk.WrapError(err, "unable to update node run %d", nodeRun.ID)
+	}
+	log.Info(ctx, "cancelNodeRunInConcurrencyGroup> node run %d cancelled by workflow run %d (group %s)", nodeRun.ID, by.ID, group)
+
+	report.Add(ctx, *nodeRun)
+	return report, nil
+}
+
+// ReleaseWorkflowConcurrencyGroup starts the oldest workflow run waiting in the concurrency group of given run once it is over.
+func ReleaseWorkflowConcurrencyGroup(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun) (*ProcessorReport, error) {
+	// The waiting runs are not started when the workflow run is processed in plan mode
+	if wr.ConcurrencyGroup == "" || !sdk.StatusIsTerminated(wr.Status) || getRunPlan(ctx) != nil {
+		return nil, nil
+	}
+
+	// A waiting run only has its root node run, that has not been executed yet
+	query := `select workflow_node_run.id
+	from workflow_node_run
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	where workflow_run.project_id = $1
+	and workflow_run.concurrency_group = $2
+	and workflow_run.id <> $3
+	and workflow_run.status in ($4, $5)
+	and workflow_node_run.sub_num = 0
+	and workflow_node_run.status = $4
+	and not exists (select 1 from workflow_node_run_job where workflow_node_run_job.workflow_node_run_id = workflow_node_run.id)
+	order by workflow_run.id asc
+	limit 1`
+	waitingRunID, err := db.SelectInt(query, wr.ProjectID, wr.ConcurrencyGroup, wr.ID, sdk.StatusWaiting, sdk.StatusBuilding)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "unable to load waiting node run in concurrency group %s", wr.ConcurrencyGroup)
+	}
+	if waitingRunID == 0 {
+		return nil, nil
+	}
+	return releaseConcurrencyGroup(ctx, db, store, proj, waitingRunID, wr.ConcurrencyGroup)
+}
+
+// releaseNodeConcurrencyGroup starts the oldest node run waiting in the concurrency group of given node run once it is over.
+func releaseNodeConcurrencyGroup(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, projectID int64, nr *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	if nr.ConcurrencyGroup == "" || !sdk.StatusIsTerminated(nr.Status) || getRunPlan(ctx) != nil {
+		return nil, nil
+	}
+
+	query := `select workflow_node_run.id
+	from workflow_node_run
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	where workflow_run.project_id = $1
+	and workflow_node_run.concurrency_group = $2
+	and workflow_node_run.id <> $3
+	and workflow_node_run.status = $4
+	and not exists (select 1 from workflow_node_run_job where workflow_node_run_job.workflow_node_run_id = workflow_node_run.id)
+	order by workflow_node_run.id asc
+	limit 1`
+	waitingRunID, err := db.SelectInt(query, projectID, nr.ConcurrencyGroup, nr.ID, sdk.StatusWaiting)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, sdk.WrapError(err, "unable to load waiting node run in concurrency group %s", nr.ConcurrencyGroup)
+	}
+	if waitingRunID == 0 {
+		return nil, nil
+	}
+	return releaseConcurrencyGroup(ctx, db, store, proj, waitingRunID, nr.ConcurrencyGroup)
+}
+
+func releaseConcurrencyGroup(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, waitingRunID int64, group string) (*ProcessorReport, error) {
+	_, next := observability.Span(ctx, "workflow.releaseConcurrencyGroup")
+	defer next()
+
+	waitingRun, err := LoadNodeRunByID(db, waitingRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load node run %d", waitingRunID)
+	}
+	workflowRun, err := LoadRunByID(db, waitingRun.WorkflowRunID, LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to load workflow run %d", waitingRun.WorkflowRunID)
+	}
+	AddWorkflowRunInfo(workflowRun, sdk.SpawnMsg{
+		ID:   sdk.MsgWorkflowConcurrencyRelease.ID,
+		Args: []interface{}{waitingRun.WorkflowNodeName, group},
+		Type: sdk.MsgWorkflowConcurrencyRelease.Type,
+	})
+	if err := UpdateWorkflowRun(ctx, db, workflowRun); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run %d after concurrency group release", workflowRun.ID)
+	}
+
+	log.Debug("workflow.releaseConcurrencyGroup> process the node run %d because concurrency group %s has been released", waitingRun.ID, group)
+	report, err := executeNodeRun(ctx, db, store, proj, waitingRun)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to execute node run %d", waitingRun.ID)
+	}
+	return report, nil
+}
@@ -2,14 +2,20 @@ package workflow_test
 
 import (
 	"context"
+	"github.com/go-gorp/gorp"
 	"github.com/ovh/cds/engine/api/authentication"
 	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
 	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestProcessJoinDefaultCondition(t *testing.T) {
@@ -141,3 +147,197 @@ func TestProcessJoinCustomCondition(t *testing.T) {
 	require.Equal(t, 1, len(wrUpdated.WorkflowNodeRuns))
 	require.Equal(t, sdk.StatusSuccess, wrUpdated.WorkflowNodeRuns[wr.Workflow.WorkflowData.Node.ID][0].Status)
 }
+
+// insertConcurrencyWorkflow inserts a workflow with one job in the given concurrency group.
+func insertConcurrencyWorkflow(t *testing.T, db gorp.SqlExecutor, store cache.Store, proj *sdk.Project, concurrency sdk.WorkflowConcurrency) (*sdk.Project, *sdk.Workflow) {
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip-" + sdk.RandomString(5),
+	}
+	require.NoError(t, pipeline.InsertPipeline(db, &pip))
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	require.NoError(t, pipeline.InsertStage(db, s))
+	j := &sdk.Job{
+		Enabled: true,
+		Action: sdk.Action{
+			Enabled: true,
+		},
+	}
+	require.NoError(t, pipeline.InsertJob(db, j, s.ID, &pip))
+
+	proj, err := project.LoadByID(db, proj.ID, project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments, project.LoadOptions.WithGroups)
+	require.NoError(t, err)
+
+	w := sdk.Workflow{
+		Name:        "wf-" + sdk.RandomString(5),
+		ProjectID:   proj.ID,
+		ProjectKey:  proj.Key,
+		Concurrency: &concurrency,
+		WorkflowData: sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "node1",
+				Ref:  "node1",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID: pip.ID,
+				},
+			},
+		},
+	}
+	require.NoError(t, workflow.Insert(context.TODO(), db, store, *proj, &w))
+
+	w1, err := workflow.Load(context.TODO(), db, store, *proj, w.Name, workflow.LoadOptions{DeepPipeline: true})
+	require.NoError(t, err)
+	return proj, w1
+}
+
+// startConcurrencyRun starts a manual run of the workflow and returns it with its node runs.
+func startConcurrencyRun(t *testing.T, db *gorp.DbMap, store cache.Store, proj *sdk.Project, w *sdk.Workflow, u *sdk.AuthentifiedUser) *sdk.WorkflowRun {
+	consumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+
+	wr, err := workflow.CreateRun(db, w, nil, u)
+	require.NoError(t, err)
+	wr.Workflow = *w
+	_, err = workflow.StartWorkflowRun(context.TODO(), db, store, *proj, wr, &sdk.WorkflowRunPostHandlerOption{
+		Manual: &sdk.WorkflowNodeRunManual{Username: u.Username},
+	}, consumer, nil)
+	require.NoError(t, err)
+
+	return loadConcurrencyRun(t, db, wr.ID)
+}
+
+func loadConcurrencyRun(t *testing.T, db gorp.SqlExecutor, id int64) *sdk.WorkflowRun {
+	wr, err := workflow.LoadRunByID(db, id, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	return wr
+}
+
+// countNodeJobRuns returns the number of jobs of the root node run of a workflow run.
+func countNodeJobRuns(t *testing.T, db gorp.SqlExecutor, wr *sdk.WorkflowRun) int64 {
+	require.Len(t, wr.WorkflowNodeRuns[wr.Workflow.WorkflowData.Node.ID], 1)
+	nb, err := db.SelectInt("SELECT COUNT(1) FROM workflow_node_run_job WHERE workflow_node_run_id = $1", wr.WorkflowNodeRuns[wr.Workflow.WorkflowData.Node.ID][0].ID)
+	require.NoError(t, err)
+	return nb
+}
+
+func hasRunInfo(wr *sdk.WorkflowRun, msgID string) bool {
+	for _, info := range wr.Infos {
+		if info.Message.ID == msgID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConcurrencyGroupQueueAndRelease(t *testing.T) {
+	db, cache, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	u, _ := assets.InsertAdminUser(t, db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	proj, w := insertConcurrencyWorkflow(t, db, cache, proj, sdk.WorkflowConcurrency{Group: "deploy-{{.cds.workflow}}"})
+
+	// The first run is executed
+	wr1 := startConcurrencyRun(t, db, cache, proj, w, u)
+	assert.Equal(t, "deploy-"+w.Name, wr1.ConcurrencyGroup)
+	assert.Equal(t, int64(1), countNodeJobRuns(t, db, wr1))
+
+	// The second run waits for the end of the first one
+	wr2 := startConcurrencyRun(t, db, cache, proj, w, u)
+	assert.Equal(t, "deploy-"+w.Name, wr2.ConcurrencyGroup)
+	assert.Equal(t, int64(0), countNodeJobRuns(t, db, wr2))
+	assert.True(t, hasRunInfo(wr2, sdk.MsgWorkflowConcurrencyWaiting.ID))
+
+	// The group is not released while the first run is in progress
+	_, err := workflow.ReleaseWorkflowConcurrencyGroup(context.TODO(), db, cache, *proj, wr1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), countNodeJobRuns(t, db, loadConcurrencyRun(t, db, wr2.ID)))
+
+	// Once the first run is over, the second one is executed
+	wr1.Status = sdk.StatusSuccess
+	require.NoError(t, workflow.UpdateWorkflowRun(context.TODO(), db, wr1))
+	_, err = workflow.ReleaseWorkflowConcurrencyGroup(context.TODO(), db, cache, *proj, wr1)
+	require.NoError(t, err)
+
+	wr2 = loadConcurrencyRun(t, db, wr2.ID)
+	assert.Equal(t, int64(1), countNodeJobRuns(t, db, wr2))
+	assert.True(t, hasRunInfo(wr2, sdk.MsgWorkflowConcurrencyRelease.ID))
+}
+
+func TestConcurrencyGroupCancelInProgress(t *testing.T) {
+	db, cache, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	u, _ := assets.InsertAdminUser(t, db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	proj, w := insertConcurrencyWorkflow(t, db, cache, proj, sdk.WorkflowConcurrency{Group: "deploy", CancelInProgress: true})
+
+	wr1 := startConcurrencyRun(t, db, cache, proj, w, u)
+	require.Equal(t, int64(1), countNodeJobRuns(t, db, wr1))
+
+	// The second run cancels the first one and is executed right away
+	wr2 := startConcurrencyRun(t, db, cache, proj, w, u)
+	assert.Equal(t, int64(1), countNodeJobRuns(t, db, wr2))
+
+	wr1 = loadConcurrencyRun(t, db, wr1.ID)
+	assert.Equal(t, sdk.StatusStopped, wr1.Status)
+	assert.Equal(t, sdk.StatusStopped, wr1.WorkflowNodeRuns[w.WorkflowData.Node.ID][0].Status)
+	assert.Equal(t, int64(0), countNodeJobRuns(t, db, wr1))
+	assert.True(t, hasRunInfo(wr1, sdk.MsgWorkflowConcurrencyCancel.ID))
+}
+
+func TestPlanWorkflowRunWithConcurrencyGroup(t *testing.T) {
+	db, cache, end := test.SetupPG(t, bootstrap.InitiliazeDB)
+	defer end()
+	u, _ := assets.InsertAdminUser(t, db)
+	consumer, _ := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	proj, w := insertConcurrencyWorkflow(t, db, cache, proj, sdk.WorkflowConcurrency{Group: "deploy", CancelInProgress: true})
+
+	wr1 := startConcurrencyRun(t, db, cache, proj, w, u)
+	require.Equal(t, int64(1), countNodeJobRuns(t, db, wr1))
+
+	// The running run is locked by another transaction while the plan is computed
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback() // nolint
+	_, err = tx.Exec("SELECT id FROM workflow_run WHERE id = $1 FOR UPDATE", wr1.ID)
+	require.NoError(t, err)
+	_, err = tx.Exec("SELECT id FROM workflow_node_run WHERE workflow_run_id = $1 FOR UPDATE", wr1.ID)
+	require.NoError(t, err)
+
+	type planResult struct {
+		plan *sdk.WorkflowRunPlan
+		err  error
+	}
+	chanPlan := make(chan planResult, 1)
+	go func() {
+		plan, err := workflow.PlanWorkflowRun(context.TODO(), db, cache, *proj, w, nil, &sdk.WorkflowRunPostHandlerOption{
+			Manual: &sdk.WorkflowNodeRunManual{Username: u.Username},
+		}, consumer)
+		chanPlan <- planResult{plan: plan, err: err}
+	}()
+
+	select {
+	case res := <-chanPlan:
+		require.NoError(t, res.err)
+		require.Len(t, res.plan.Nodes, 1)
+		assert.True(t, res.plan.Nodes[0].Triggered)
+	case <-time.After(10 * time.Second):
+		require.NoError(t, tx.Rollback())
+		t.Fatal("the plan is blocked by the locks of the running workflow run")
+	}
+	require.NoError(t, tx.Rollback())
+
+	// The running run was not cancelled
+	wr1 = loadConcurrencyRun(t, db, wr1.ID)
+	assert.NotEqual(t, sdk.StatusStopped, wr1.Status)
+	assert.NotEqual(t, sdk.StatusStopped, wr1.WorkflowNodeRuns[w.WorkflowData.Node.ID][0].Status)
+	assert.Equal(t, int64(1), countNodeJobRuns(t, db, wr1))
+	assert.False(t, hasRunInfo(wr1, sdk.MsgWorkflowConcurrencyCancel.ID))
+}
@@ -1,7 +1,9 @@
 package workflow
 
 import (
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/stretchr/testify/assert"
@@ -55,3 +57,24 @@ func TestGetWorkflowRunStatus(t *testing.T) {
 		assert.Equal(t, tc.status, status)
 	}
 }
+
+func TestComputeConcurrencyGroup(t *testing.T) {
+	params := []sdk.Parameter{
+		{Name: "git.branch", Type: sdk.StringParameter, Value: "feat/concurrency"},
+		{Name: "cds.workflow", Type: sdk.StringParameter, Value: "myworkflow"},
+	}
+
+	group, err := computeConcurrencyGroup(sdk.WorkflowConcurrency{Group: "{{.cds.workflow}}-{{.git.branch}}"}, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "myworkflow-feat/concurrency", group)
+
+	group, err = computeConcurrencyGroup(sdk.WorkflowConcurrency{Group: "deploy"}, params)
+	assert.NoError(t, err)
+	assert.Equal(t, "deploy", group)
+
+	// Long groups are truncated on a rune boundary
+	group, err = computeConcurrencyGroup(sdk.WorkflowConcurrency{Group: "d" + strings.Repeat("é", 300)}, params)
+	assert.NoError(t, err)
+	assert.True(t, utf8.ValidString(group))
+	assert.Equal(t, 256, utf8.RuneCountInString(group))
+}
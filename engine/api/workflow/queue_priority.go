@@ -0,0 +1,45 @@
+package workflow
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// JobPriorityConfig contains the weights used to compute the priority of the jobs in queue.
+type JobPriorityConfig struct {
+	ManualRun            int64
+	ReleaseBranch        int64
+	ReleaseBranchPattern *regexp.Regexp
+	AgingPeriod          time.Duration
+	BuildingJobPenalty   int64
+}
+
+// ComputeJobPriority returns the priority of a waiting job, the higher the sooner a hatchery should start it.
+// Manual runs and runs on a release branch get a bonus, the priority grows with the time spent in queue and
+// each building job of the same project lowers it so the workers are shared between projects.
+func ComputeJobPriority(cfg JobPriorityConfig, j sdk.WorkflowNodeJobRun, buildingJobsInProject int64, now time.Time) int64 {
+	var priority int64
+	if sdk.ParameterValue(j.Parameters, "cds.manual") == "true" {
+		priority += cfg.ManualRun
+	}
+	if cfg.ReleaseBranchPattern != nil {
+		if branch := sdk.ParameterValue(j.Parameters, "git.branch"); branch != "" && cfg.ReleaseBranchPattern.MatchString(branch) {
+			priority += cfg.ReleaseBranch
+		}
+	}
+	if cfg.AgingPeriod > 0 && !j.Queued.IsZero() && now.After(j.Queued) {
+		priority += int64(now.Sub(j.Queued) / cfg.AgingPeriod)
+	}
+	priority -= buildingJobsInProject * cfg.BuildingJobPenalty
+	return priority
+}
+
+// ComputeJobPriorities sets the priority of all given jobs.
+func ComputeJobPriorities(cfg JobPriorityConfig, jobs []sdk.WorkflowNodeJobRun, buildingJobsByProject map[int64]int64) {
+	now := time.Now()
+	for i := range jobs {
+		jobs[i].Priority = ComputeJobPriority(cfg, jobs[i], buildingJobsByProject[jobs[i].ProjectID], now)
+	}
+}
@@ -0,0 +1,41 @@
+package workflow_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestComputeJobPriority(t *testing.T) {
+	cfg := workflow.JobPriorityConfig{
+		ManualRun:            10,
+		ReleaseBranch:        5,
+		ReleaseBranchPattern: regexp.MustCompile(`^(master|release/.*)$`),
+		AgingPeriod:          5 * time.Minute,
+		BuildingJobPenalty:   1,
+	}
+	now := time.Now()
+
+	job := func(manual bool, branch string, queued time.Time) sdk.WorkflowNodeJobRun {
+		j := sdk.WorkflowNodeJobRun{Queued: queued}
+		if manual {
+			j.Parameters = append(j.Parameters, sdk.Parameter{Name: "cds.manual", Value: "true"})
+		}
+		if branch != "" {
+			j.Parameters = append(j.Parameters, sdk.Parameter{Name: "git.branch", Value: branch})
+		}
+		return j
+	}
+
+	assert.Equal(t, int64(0), workflow.ComputeJobPriority(cfg, job(false, "feat/foo", now), 0, now))
+	assert.Equal(t, int64(10), workflow.ComputeJobPriority(cfg, job(true, "feat/foo", now), 0, now))
+	assert.Equal(t, int64(15), workflow.ComputeJobPriority(cfg, job(true, "release/1.0", now), 0, now))
+	assert.Equal(t, int64(5), workflow.ComputeJobPriority(cfg, job(false, "master", now), 0, now))
+	assert.Equal(t, int64(3), workflow.ComputeJobPriority(cfg, job(false, "feat/foo", now.Add(-16*time.Minute)), 0, now), "one point by aging period in queue")
+	assert.Equal(t, int64(-20), workflow.ComputeJobPriority(cfg, job(false, "feat/foo", now), 20, now), "one point lost by building job in the project")
+}
@@ -190,6 +190,38 @@ func pollRepositoryOperation(c context.Context, db gorp.SqlExecutor, store cache
 	}
 }
 
+// LoadChangedFiles returns the files changed by the given commits of the application repository
+func LoadChangedFiles(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, app sdk.Application, cloneURL, branch, head string, commits []string) ([]string, error) {
+	ctx, end := observability.Span(ctx, "workflow.LoadChangedFiles")
+	defer end()
+
+	ope := sdk.Operation{
+		VCSServer:          app.VCSServer,
+		RepoFullName:       app.RepositoryFullname,
+		URL:                cloneURL,
+		RepositoryStrategy: app.RepositoryStrategy,
+		Setup: sdk.OperationSetup{
+			Checkout: sdk.OperationCheckout{
+				Branch: branch,
+				Commit: head,
+			},
+		},
+		LoadChanges: sdk.OperationLoadChanges{
+			Commits: commits,
+		},
+	}
+
+	if err := operation.PostRepositoryOperation(ctx, db, proj, &ope, nil); err != nil {
+		return nil, sdk.WrapError(err, "unable to post repository operation")
+	}
+
+	if err := pollRepositoryOperation(ctx, db, store, &ope); err != nil {
+		return nil, sdk.WrapError(err, "cannot load changed files")
+	}
+
+	return ope.LoadChanges.Results, nil
+}
+
 func createOperationRequest(w sdk.Workflow, opts sdk.WorkflowRunPostHandlerOption) (sdk.Operation, error) {
 	ope := sdk.Operation{}
 	if w.WorkflowData.Node.Context.ApplicationID == 0 {
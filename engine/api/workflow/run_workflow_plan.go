@@ -0,0 +1,254 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/observability"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/luascript"
+)
+
+type contextKey string
+
+const contextRunPlan contextKey = "workflow-run-plan"
+
+// runPlan collects what can't be read from the node runs while a workflow run is processed in plan mode.
+type runPlan struct {
+	workflowRunID    int64
+	previousNodeRuns map[int64]struct{}
+	conditions       map[int64]nodeConditionsResult
+}
+
+type nodeConditionsResult struct {
+	params     []sdk.Parameter
+	conditions []sdk.WorkflowRunPlanCondition
+	blocking   string
+}
+
+func getRunPlan(ctx context.Context) *runPlan {
+	p, _ := ctx.Value(contextRunPlan).(*runPlan)
+	return p
+}
+
+// recordNodeConditions saves the result of each run condition of a node when the workflow run is processed in plan mode.
+func recordNodeConditions(ctx context.Context, wr *sdk.WorkflowRun, n *sdk.Node, params []sdk.Parameter) {
+	p := getRunPlan(ctx)
+	if p == nil || p.workflowRunID != wr.ID {
+		return
+	}
+	var res nodeConditionsResult
+	res.params = params
+	if n.Context != nil {
+		res.conditions = checkEachCondition(n.Context.Conditions, params)
+	}
+	for _, c := range res.conditions {
+		if !c.Result {
+			res.blocking = c.Condition
+			break
+		}
+	}
+	p.conditions[n.ID] = res
+}
+
+// checkEachCondition returns the result of each plain condition, or of the expression or lua script.
+func checkEachCondition(conditions sdk.WorkflowNodeConditions, params []sdk.Parameter) []sdk.WorkflowRunPlanCondition {
+	newResult := func(condition string, ok bool, err error) sdk.WorkflowRunPlanCondition {
+		c := sdk.WorkflowRunPlanCondition{Condition: condition, Result: ok && err == nil}
+		if err != nil {
+			c.Error = err.Error()
+		}
+		return c
+	}
+
+	switch {
+	case conditions.Expression != "":
+		ok, err := sdk.WorkflowCheckConditionExpression(conditions.Expression, params)
+		return []sdk.WorkflowRunPlanCondition{newResult(conditions.Expression, ok, err)}
+	case conditions.LuaScript != "":
+		luacheck, err := luascript.NewCheck()
+		if err != nil {
+			return []sdk.WorkflowRunPlanCondition{newResult(conditions.LuaScript, false, err)}
+		}
+		luacheck.SetVariables(sdk.ParametersToMap(params))
+		err = luacheck.Perform(conditions.LuaScript)
+		return []sdk.WorkflowRunPlanCondition{newResult(conditions.LuaScript, luacheck.Result, err)}
+	}
+
+	res := make([]sdk.WorkflowRunPlanCondition, 0, len(conditions.PlainConditions))
+	for _, c := range conditions.PlainConditions {
+		ok, err := sdk.WorkflowCheckConditions([]sdk.WorkflowNodeCondition{c}, params)
+		res = append(res, newResult(fmt.Sprintf("%s %s %s", c.Variable, c.Operator, c.Value), ok, err))
+	}
+	return res
+}
+
+// PlanWorkflowRun processes a workflow run request like StartWorkflowRun but in a transaction that is always rolled back.
+// Node runs that would be added are ended as if all their jobs succeeded, until no more node can be triggered.
+// The as code workflows are not reimported from their repository.
+func PlanWorkflowRun(ctx context.Context, db *gorp.DbMap, store cache.Store, proj sdk.Project, wf *sdk.Workflow, lastRun *sdk.WorkflowRun,
+	opts *sdk.WorkflowRunPostHandlerOption, u *sdk.AuthConsumer) (*sdk.WorkflowRunPlan, error) {
+	ctx, end := observability.Span(ctx, "workflow.PlanWorkflowRun")
+	defer end()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot start transaction")
+	}
+	defer tx.Rollback() // nolint
+
+	wr := lastRun
+	if wr == nil {
+		wr, err = CreateRun(tx, wf, opts, u)
+		if err != nil {
+			return nil, err
+		}
+		wr.Workflow = *wf
+	}
+	wr.Status = sdk.StatusWaiting
+
+	p := &runPlan{
+		workflowRunID:    wr.ID,
+		previousNodeRuns: make(map[int64]struct{}),
+		conditions:       make(map[int64]nodeConditionsResult),
+	}
+	for _, nrs := range wr.WorkflowNodeRuns {
+		for _, nr := range nrs {
+			p.previousNodeRuns[nr.ID] = struct{}{}
+		}
+	}
+	nbInfos := len(wr.Infos)
+	ctx = context.WithValue(ctx, contextRunPlan, p)
+
+	if _, err := startWorkflowRun(ctx, tx, store, proj, wr, opts, u, nil); err != nil && !sdk.ErrorIs(err, sdk.ErrConditionsNotOk) {
+		return nil, err
+	}
+
+	nodes := wr.Workflow.WorkflowData.Array()
+	for i := 0; i <= len(nodes); i++ {
+		wr, err = LoadRunByID(tx, wr.ID, LoadRunOptions{})
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to load workflow run")
+		}
+		nb, err := p.endNodeRuns(ctx, tx, wr)
+		if err != nil {
+			return nil, err
+		}
+		if nb == 0 {
+			break
+		}
+		if _, _, err := processWorkflowDataRun(ctx, tx, store, proj, wr, nil, nil, nil); err != nil {
+			return nil, sdk.WrapError(err, "unable to process workflow run")
+		}
+	}
+
+	res := &sdk.WorkflowRunPlan{
+		WorkflowName: wf.Name,
+		Number:       wr.Number,
+	}
+	if len(wr.Infos) > nbInfos {
+		res.Infos = wr.Infos[nbInfos:]
+	}
+	for _, n := range nodes {
+		res.Nodes = append(res.Nodes, p.planNode(wr, n))
+	}
+	return res, nil
+}
+
+// endNodeRuns adds the jobs of all the stages of the node runs of the plan that are not terminated,
+// then ends them as if the jobs succeeded. It returns the number of ended node runs.
+func (p *runPlan) endNodeRuns(ctx context.Context, db gorp.SqlExecutor, wr *sdk.WorkflowRun) (int, error) {
+	var nb int
+	for id := range wr.WorkflowNodeRuns {
+		for i := range wr.WorkflowNodeRuns[id] {
+			nr := &wr.WorkflowNodeRuns[id][i]
+			if _, ok := p.previousNodeRuns[nr.ID]; ok || sdk.StatusIsTerminated(nr.Status) {
+				continue
+			}
+
+			var counter statusCounter
+			for j := range nr.Stages {
+				stage := &nr.Stages[j]
+				if len(stage.RunJobs) == 0 && len(stage.Jobs) > 0 && (stage.Status == "" || stage.Status == sdk.StatusWaiting) {
+					if _, err := addJobsToQueue(ctx, db, stage, wr, nr, nil); err != nil {
+						return 0, err
+					}
+				}
+				switch stage.Status {
+				case "", sdk.StatusWaiting, sdk.StatusBuilding:
+					stage.Status = sdk.StatusSuccess
+				}
+				computeRunStatus(stage.Status, &counter)
+				if stage.Status == sdk.StatusFail {
+					break
+				}
+			}
+
+			nr.Status = sdk.StatusSuccess
+			if len(nr.Stages) > 0 {
+				nr.Status = getRunStatus(counter)
+			}
+			nr.Done = time.Now()
+			if err := UpdateNodeRun(db, nr); err != nil {
+				return 0, sdk.WrapError(err, "unable to update node run %d", nr.ID)
+			}
+			nb++
+		}
+	}
+	return nb, nil
+}
+
+func (p *runPlan) planNode(wr *sdk.WorkflowRun, n *sdk.Node) sdk.WorkflowRunPlanNode {
+	res := sdk.WorkflowRunPlanNode{
+		NodeID:   n.ID,
+		NodeName: n.Name,
+		NodeType: n.Type,
+	}
+	if c, ok := p.conditions[n.ID]; ok {
+		res.Conditions = c.conditions
+		res.BlockingCondition = c.blocking
+		res.BuildParameters = c.params
+	}
+
+	var nr *sdk.WorkflowNodeRun
+	for i := range wr.WorkflowNodeRuns[n.ID] {
+		if _, ok := p.previousNodeRuns[wr.WorkflowNodeRuns[n.ID][i].ID]; !ok {
+			nr = &wr.WorkflowNodeRuns[n.ID][i]
+			break
+		}
+	}
+	if nr == nil {
+		return res
+	}
+
+	res.Triggered = true
+	res.BlockingCondition = ""
+	res.Status = nr.Status
+	res.BuildParameters = nr.BuildParameters
+	for _, s := range nr.Stages {
+		for _, rj := range s.RunJobs {
+			job := sdk.WorkflowRunPlanJob{
+				Stage:        s.Name,
+				Name:         rj.Job.Action.Name,
+				Status:       rj.Status,
+				Requirements: rj.Job.Action.Requirements,
+			}
+			for _, r := range rj.Job.Action.Requirements {
+				if r.Type == sdk.ModelRequirement {
+					job.Model = r.Value
+				}
+			}
+			for _, info := range rj.SpawnInfos {
+				if info.Message.ID == sdk.MsgSpawnInfoJobError.ID && len(info.Message.Args) > 0 {
+					job.Errors = append(job.Errors, fmt.Sprintf("%v", info.Message.Args[0]))
+				}
+			}
+			res.Jobs = append(res.Jobs, job)
+		}
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkEachCondition(t *testing.T) {
+	params := []sdk.Parameter{
+		{Name: "git.branch", Type: sdk.StringParameter, Value: "feat/plan"},
+		{Name: "cds.status", Type: sdk.StringParameter, Value: sdk.StatusSuccess},
+	}
+
+	res := checkEachCondition(sdk.WorkflowNodeConditions{
+		PlainConditions: []sdk.WorkflowNodeCondition{
+			{Variable: "cds.status", Operator: sdk.WorkflowConditionsOperatorEquals, Value: sdk.StatusSuccess},
+			{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "master"},
+		},
+	}, params)
+	require.Len(t, res, 2)
+	assert.Equal(t, sdk.WorkflowRunPlanCondition{Condition: "cds.status eq Success", Result: true}, res[0])
+	assert.Equal(t, sdk.WorkflowRunPlanCondition{Condition: "git.branch eq master", Result: false}, res[1])
+
+	res = checkEachCondition(sdk.WorkflowNodeConditions{LuaScript: `return git_branch == "feat/plan"`}, params)
+	require.Len(t, res, 1)
+	assert.True(t, res[0].Result)
+
+	assert.Empty(t, checkEachCondition(sdk.WorkflowNodeConditions{}, params))
+}
+
+func Test_recordNodeConditions(t *testing.T) {
+	wr := &sdk.WorkflowRun{ID: 1}
+	n := &sdk.Node{ID: 2, Context: &sdk.NodeContext{Conditions: sdk.WorkflowNodeConditions{
+		PlainConditions: []sdk.WorkflowNodeCondition{{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "master"}},
+	}}}
+	params := []sdk.Parameter{{Name: "git.branch", Type: sdk.StringParameter, Value: "dev"}}
+
+	// Nothing is recorded outside of plan mode
+	recordNodeConditions(context.TODO(), wr, n, params)
+
+	p := &runPlan{workflowRunID: 1, conditions: make(map[int64]nodeConditionsResult)}
+	ctx := context.WithValue(context.TODO(), contextRunPlan, p)
+	recordNodeConditions(ctx, &sdk.WorkflowRun{ID: 3}, n, params)
+	assert.Empty(t, p.conditions)
+
+	recordNodeConditions(ctx, wr, n, params)
+	require.Contains(t, p.conditions, int64(2))
+	assert.Equal(t, "git.branch eq master", p.conditions[2].blocking)
+
+	res := p.planNode(wr, n)
+	assert.False(t, res.Triggered)
+	assert.Equal(t, "git.branch eq master", res.BlockingCondition)
+	assert.Equal(t, params, res.BuildParameters)
+}
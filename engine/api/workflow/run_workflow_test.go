@@ -633,16 +633,18 @@ queueRun:
 		assert.Len(t, secrets, 1)
 
 		//TestAddLog
-		assert.NoError(t, workflow.AddLog(db, j, &sdk.Log{
+		_, err = workflow.AddLog(db, j, &sdk.Log{
 			Val: "This is a log",
-		}, workflow.DefaultMaxLogSize))
+		}, workflow.DefaultMaxLogSize)
+		assert.NoError(t, err)
 		if t.Failed() {
 			tx.Rollback()
 			t.FailNow()
 		}
-		assert.NoError(t, workflow.AddLog(db, j, &sdk.Log{
+		_, err = workflow.AddLog(db, j, &sdk.Log{
 			Val: "This is another log",
-		}, workflow.DefaultMaxLogSize))
+		}, workflow.DefaultMaxLogSize)
+		assert.NoError(t, err)
 		if t.Failed() {
 			tx.Rollback()
 			t.FailNow()
@@ -686,7 +688,7 @@ queueRun:
 			t.Fatalf("unable to retrieve job run in the workflow run")
 		}
 
-		logs, err := workflow.LoadLogs(db, takenJob.ID)
+		logs, err := workflow.LoadLogs(context.TODO(), db, takenJob.ID)
 		assert.NoError(t, err)
 		if t.Failed() {
 			tx.Rollback()
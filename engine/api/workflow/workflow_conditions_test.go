@@ -0,0 +1,43 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkConditionExpressions(t *testing.T) {
+	w := &sdk.Workflow{
+		WorkflowData: sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "root",
+				Context: &sdk.NodeContext{
+					Conditions: sdk.WorkflowNodeConditions{Expression: `git.branch == "master"`},
+				},
+			},
+		},
+	}
+	assert.NoError(t, checkConditionExpressions(w))
+
+	w.WorkflowData.Node.Context.Conditions.Expression = `git.branch ==`
+	assert.Error(t, checkConditionExpressions(w))
+
+	// An expression replaces the checks, they can't be set together
+	w.WorkflowData.Node.Context.Conditions = sdk.WorkflowNodeConditions{
+		Expression:      `git.branch == "master"`,
+		PlainConditions: []sdk.WorkflowNodeCondition{{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "master"}},
+	}
+	assert.Error(t, checkConditionExpressions(w))
+
+	w.WorkflowData.Node.Context.Conditions = sdk.WorkflowNodeConditions{}
+	w.WorkflowData.Node.Hooks = []sdk.NodeHook{{
+		HookModelName: sdk.RepositoryWebHookModelName,
+		Conditions: sdk.WorkflowNodeConditions{
+			Expression: `git.branch == "master"`,
+			LuaScript:  `return true`,
+		},
+	}}
+	assert.Error(t, checkConditionExpressions(w))
+}
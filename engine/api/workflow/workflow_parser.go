@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/go-gorp/gorp"
@@ -9,6 +10,7 @@ import (
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/condition"
 	"github.com/ovh/cds/sdk/exportentities"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -39,6 +41,10 @@ func Parse(ctx context.Context, proj sdk.Project, ew exportentities.Workflow) (*
 	w.ProjectID = proj.ID
 	w.ProjectKey = proj.Key
 
+	if err := checkConditionExpressions(w); err != nil {
+		return nil, err
+	}
+
 	// Get permission from project if needed
 	if len(w.Groups) == 0 {
 		w.Groups = make([]sdk.GroupPermission, 0, len(proj.ProjectGroups))
@@ -50,6 +56,35 @@ func Parse(ctx context.Context, proj sdk.Project, ew exportentities.Workflow) (*
 	return w, nil
 }
 
+// checkConditionExpressions validates the syntax of all condition expressions on nodes and hooks.
+// An expression replaces the checks and the lua script, so they can't be set together.
+func checkConditionExpressions(w *sdk.Workflow) error {
+	for _, n := range w.WorkflowData.Array() {
+		if n.Context != nil {
+			if err := checkConditionExpression(n.Context.Conditions); err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "invalid condition on node %s: %v", n.Name, err)
+			}
+		}
+		for _, h := range n.Hooks {
+			if err := checkConditionExpression(h.Conditions); err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "invalid condition on hook %s of node %s: %v", h.HookModelName, n.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func checkConditionExpression(c sdk.WorkflowNodeConditions) error {
+	if c.Expression == "" {
+		return nil
+	}
+	if len(c.PlainConditions) > 0 || c.LuaScript != "" {
+		return fmt.Errorf("an expression can't be used with checks or a lua script")
+	}
+	_, err := condition.Parse(c.Expression)
+	return err
+}
+
 // ParseAndImport parse an exportentities.workflow and insert or update the workflow in database
 func ParseAndImport(ctx context.Context, db gorp.SqlExecutor, store cache.Store, proj sdk.Project, oldW *sdk.Workflow, ew exportentities.Workflow, u sdk.Identifiable, opts ImportOptions) (*sdk.Workflow, []sdk.Message, error) {
 	ctx, end := observability.Span(ctx, "workflow.ParseAndImport")
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// default and max time windows of a step logs search
+const (
+	defaultLogSearchWindow = 24 * time.Hour
+	maxLogSearchWindow     = 31 * 24 * time.Hour
+)
+
+// getLogSearchOptions reads the regexp, time window and limit of a step logs search from query params.
+func getLogSearchOptions(r *http.Request) (workflow.LogSearchOptions, error) {
+	var opts workflow.LogSearchOptions
+
+	regex := r.FormValue("regex")
+	if regex == "" {
+		return opts, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing regex")
+	}
+	reg, err := regexp.Compile(regex)
+	if err != nil {
+		return opts, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid regex %q: %v", regex, err)
+	}
+	opts.Regexp = reg
+
+	opts.To = time.Now()
+	if s := r.FormValue("to"); s != "" {
+		opts.To, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return opts, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid date %q, expected format is RFC3339", s)
+		}
+	}
+	opts.From = opts.To.Add(-defaultLogSearchWindow)
+	if s := r.FormValue("from"); s != "" {
+		opts.From, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return opts, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid date %q, expected format is RFC3339", s)
+		}
+	}
+	if !opts.From.Before(opts.To) {
+		return opts, sdk.NewErrorFrom(sdk.ErrWrongRequest, "from date must be before to date")
+	}
+	if opts.To.Sub(opts.From) > maxLogSearchWindow {
+		return opts, sdk.NewErrorFrom(sdk.ErrWrongRequest, "time window cannot exceed %s", maxLogSearchWindow)
+	}
+
+	if s := r.FormValue("limit"); s != "" {
+		opts.Limit, err = strconv.Atoi(s)
+		if err != nil || opts.Limit <= 0 || opts.Limit > workflow.MaxLogSearchLimit {
+			return opts, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid limit %q, max value is %d", s, workflow.MaxLogSearchLimit)
+		}
+	}
+
+	return opts, nil
+}
+
+func (api *API) getSearchWorkflowLogsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		opts, err := getLogSearchOptions(r)
+		if err != nil {
+			return err
+		}
+		opts.ProjectKey = vars["key"]
+		opts.WorkflowNames = []string{vars["permWorkflowName"]}
+
+		results, err := workflow.SearchStepLogs(ctx, api.mustDB(), opts)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, results, http.StatusOK)
+	}
+}
+
+func (api *API) getSearchProjectLogsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		opts, err := getLogSearchOptions(r)
+		if err != nil {
+			return err
+		}
+		opts.ProjectKey = key
+
+		ws, err := workflow.LoadAll(api.mustDB(), key)
+		if err != nil {
+			return err
+		}
+		names := ws.Names()
+
+		// Only search in the logs of the workflows that the consumer can read
+		if !isAdmin(ctx) && !isMaintainer(ctx) {
+			perms, err := permission.LoadWorkflowMaxLevelPermission(ctx, api.mustDB(), key, names, getAPIConsumer(ctx).GetGroupIDs())
+			if err != nil {
+				return err
+			}
+			readable := make([]string, 0, len(names))
+			for _, name := range names {
+				if perms.Level(name) >= sdk.PermissionRead {
+					readable = append(readable, name)
+				}
+			}
+			names = readable
+		}
+		opts.WorkflowNames = names
+
+		results, err := workflow.SearchStepLogs(ctx, api.mustDB(), opts)
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, results, http.StatusOK)
+	}
+}
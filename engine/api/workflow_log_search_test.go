@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_getLogSearchOptions(t *testing.T) {
+	r := httptest.NewRequest("GET", "/project/KEY/logs/search?regex=err.*&limit=10", nil)
+	opts, err := getLogSearchOptions(r)
+	require.NoError(t, err)
+	assert.Equal(t, "err.*", opts.Regexp.String())
+	assert.Equal(t, 10, opts.Limit)
+	assert.Equal(t, defaultLogSearchWindow, opts.To.Sub(opts.From))
+
+	r = httptest.NewRequest("GET", "/project/KEY/logs/search?regex=err&from=2020-01-01T00:00:00Z&to=2020-01-02T00:00:00Z", nil)
+	opts, err = getLogSearchOptions(r)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), opts.From)
+	assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), opts.To)
+
+	for _, q := range []string{
+		"",
+		"regex=(",
+		"regex=err&from=2020-01-02T00:00:00Z&to=2020-01-01T00:00:00Z",
+		"regex=err&from=2020-01-01T00:00:00Z&to=2020-03-01T00:00:00Z",
+		"regex=err&from=yesterday",
+		"regex=err&limit=100000",
+	} {
+		_, err := getLogSearchOptions(httptest.NewRequest("GET", "/project/KEY/logs/search?"+q, nil))
+		assert.Error(t, err, q)
+	}
+}
+
+func Test_getSearchProjectLogsHandler_WithoutReadPermission(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+
+	ctx := testRunWorkflow(t, api, router)
+	testGetWorkflowJobAsHatchery(t, api, router, &ctx)
+
+	now := time.Now()
+	_, err := workflow.AddLog(db, ctx.job, &sdk.Log{
+		StepOrder:    0,
+		Start:        &now,
+		LastModified: &now,
+		Val:          "error: something went wrong\n",
+	}, workflow.DefaultMaxLogSize)
+	require.NoError(t, err)
+
+	// This group can read the project but was not given any permission on the workflow
+	g := &sdk.Group{Name: sdk.RandomString(10)}
+	_, jwtLambda := assets.InsertLambdaUser(t, db, g)
+	require.NoError(t, group.InsertLinkGroupProject(context.TODO(), db, &group.LinkGroupProject{
+		GroupID:   g.ID,
+		ProjectID: ctx.project.ID,
+		Role:      sdk.PermissionRead,
+	}))
+
+	uri := router.GetRoute("GET", api.getSearchProjectLogsHandler, map[string]string{
+		"permProjectKey": ctx.project.Key,
+	})
+	require.NotEmpty(t, uri)
+
+	var results []sdk.WorkflowLogSearchResult
+	req := assets.NewJWTAuthentifiedRequest(t, jwtLambda, "GET", uri+"?regex=error", nil)
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 200, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+	assert.Empty(t, results)
+
+	req = assets.NewAuthentifiedRequest(t, ctx.user, ctx.password, "GET", uri+"?regex=error", nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 200, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, ctx.workflow.Name, results[0].WorkflowName)
+	assert.Equal(t, "error: something went wrong", results[0].Line)
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
 	"github.com/ovh/venom"
 	"github.com/sguiheux/go-coverage"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/observability"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/quota"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/worker"
@@ -97,9 +99,13 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 		}
 
 		pbji := &sdk.WorkflowNodeJobRunData{}
-		report, err := takeJob(ctx, api.mustDB, api.Cache, p, id, workerModelName, pbji, wk, hatcheryName)
-		if err != nil {
+		var report *workflow.ProcessorReport
+		if err := api.checkJobQuotas(ctx, *pbj, workerModelName, func() error {
+			var err error
+			report, err = takeJob(ctx, api.mustDB, api.Cache, p, id, workerModelName, pbji, wk, hatcheryName)
 			return sdk.WrapError(err, "cannot takeJob nodeJobRunID:%d", id)
+		}); err != nil {
+			return err
 		}
 
 		workflow.ResyncNodeRunsWithCommits(ctx, api.mustDB(), api.Cache, *p, report)
@@ -184,6 +190,9 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 	wnjri.Secrets = append(wnjri.Secrets, secretsKeys...)
 	wnjri.NodeJobRun.Parameters = append(wnjri.NodeJobRun.Parameters, params...)
 
+	// Pull requests from forks only get the secrets allowed by their hook
+	wnjri.Secrets = workflow.FilterForkSecrets(workflowRun, wnjri.Secrets)
+
 	if err := tx.Commit(); err != nil {
 		return nil, sdk.WithStack(err)
 	}
@@ -207,14 +216,108 @@ func (api *API) postBookWorkflowJobHandler() service.Handler {
 			return err
 		}
 
-		if _, err := workflow.BookNodeJobRun(ctx, api.Cache, id, s); err != nil {
+		job, err := workflow.LoadNodeJobRun(ctx, api.mustDB(), api.Cache, id)
+		if err != nil {
+			return err
+		}
+		if err := workflow.CheckNodeJobRunQueued(*job); err != nil {
+			return err
+		}
+		if err := api.checkJobQuotas(ctx, *job, "", func() error {
+			_, err := workflow.BookNodeJobRun(ctx, api.Cache, id, s)
 			return sdk.WrapError(err, "job already booked")
+		}); err != nil {
+			return err
 		}
 
 		return service.WriteJSON(w, nil, http.StatusOK)
 	}
 }
 
+// postAssignWorkflowJobHandler books a job for a hatchery and assigns it to one of its idle workers,
+// the worker will then take the job.
+func (api *API) postAssignWorkflowJobHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permJobID")
+		if err != nil {
+			return err
+		}
+
+		if ok := isHatchery(ctx); !ok {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		s, err := services.LoadByID(ctx, api.mustDB(), getAPIConsumer(ctx).Service.ID)
+		if err != nil {
+			return err
+		}
+
+		wk, err := worker.LoadByID(ctx, api.mustDB(), mux.Vars(r)["workerID"])
+		if err != nil {
+			return err
+		}
+		if wk.HatcheryID != s.ID {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "worker %s was not spawned by hatchery %s", wk.Name, s.Name)
+		}
+		if wk.Status != sdk.StatusWaiting || wk.JobRunID != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "worker %s is not idle", wk.Name)
+		}
+
+		job, err := workflow.LoadNodeJobRun(ctx, api.mustDB(), api.Cache, id)
+		if err != nil {
+			return err
+		}
+		if err := workflow.CheckNodeJobRunQueued(*job); err != nil {
+			return err
+		}
+		if err := api.checkJobQuotas(ctx, *job, "", func() error {
+			_, err := workflow.BookNodeJobRun(ctx, api.Cache, id, s)
+			return sdk.WrapError(err, "job already booked")
+		}); err != nil {
+			return err
+		}
+
+		if err := worker.SetJobRunID(api.mustDB(), wk.ID, id); err != nil {
+			if errF := workflow.FreeNodeJobRun(ctx, api.Cache, id); errF != nil {
+				log.Error(ctx, "cannot release job %d: %v", id, errF)
+			}
+			return err
+		}
+
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// checkJobQuotas calls start to book or take given job if no quota prevents to start it. Else an error is returned
+// and the reason is added to the job's spawn infos once until it changes.
+func (api *API) checkJobQuotas(ctx context.Context, job sdk.WorkflowNodeJobRun, workerModel string, start func() error) error {
+	err := quota.CheckAndStartJob(ctx, api.mustDB(), api.Cache, job, workerModel, start)
+	if err == nil || !sdk.ErrorIs(err, sdk.ErrJobQuotaExceeded) {
+		return err
+	}
+
+	reason := sdk.ExtractHTTPError(err, "").From
+	k := cache.Key("quota", "job", strconv.FormatInt(job.ID, 10))
+	var lastReason string
+	if find, errC := api.Cache.Get(k, &lastReason); errC != nil {
+		log.Error(ctx, "cannot get from cache %s: %v", k, errC)
+	} else if find && lastReason == reason {
+		return err
+	}
+	if errC := api.Cache.SetWithTTL(k, reason, 600); errC != nil {
+		log.Error(ctx, "cannot SetWithTTL: %s: %v", k, errC)
+	}
+
+	infos := []sdk.SpawnInfo{{
+		RemoteTime: getRemoteTime(ctx),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobQuotaExceeded.ID, Args: []interface{}{reason}},
+	}}
+	if errS := workflow.AddSpawnInfosNodeJobRun(api.mustDB(), job.WorkflowNodeRunID, job.ID, infos); errS != nil {
+		log.Error(ctx, "cannot add spawn info on job %d: %v", job.ID, errS)
+	}
+	return err
+}
+
 func (api *API) deleteBookWorkflowJobHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, err := requestVarInt(r, "permJobID")
@@ -331,10 +434,46 @@ func (api *API) postSpawnInfosWorkflowJobHandler() service.Handler {
 			return err
 		}
 
+		// Jobs with a retry policy are retried or failed when the hatchery cannot spawn a worker
+		var spawnError bool
+		for _, info := range s {
+			if info.Message.ID == sdk.MsgSpawnInfoHatcheryErrorSpawn.ID {
+				spawnError = true
+				break
+			}
+		}
+		if !spawnError || jobRun.Job.RetryPolicy == nil || !jobRun.Job.RetryPolicy.Retries(sdk.JobRetryOnSpawnError) || jobRun.Status != sdk.StatusWaiting {
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+			return nil
+		}
+
+		proj, err := project.LoadProjectByNodeJobRunID(ctx, tx, api.Cache, id, project.LoadOptions.WithVariables)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project from job %d", id)
+		}
+
+		retried, report, err := workflow.RetryNodeJobRun(ctx, tx, jobRun, sdk.JobRetryOnSpawnError)
+		if err != nil {
+			return err
+		}
+		if !retried {
+			if err := workflow.AddSpawnInfoRetryExhausted(tx, *jobRun, sdk.JobRetryOnSpawnError); err != nil {
+				return err
+			}
+			report, err = workflow.UpdateNodeJobRunStatus(ctx, tx, api.Cache, *proj, jobRun, sdk.StatusFail)
+			if err != nil {
+				return sdk.WrapError(err, "cannot update NodeJobRun %d status", jobRun.ID)
+			}
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WithStack(err)
 		}
 
+		go WorkflowSendEvent(context.Background(), api.mustDB(), api.Cache, *proj, report)
+
 		return nil
 	}
 }
@@ -500,6 +639,26 @@ func postJobResult(ctx context.Context, dbFunc func(context.Context) *gorp.DbMap
 	newDBFunc := func() *gorp.DbMap {
 		return dbFunc(context.Background())
 	}
+	if (res.Status == sdk.StatusFail || res.Status == sdk.StatusTimeout) && job.Job.RetryPolicy != nil {
+		failureKind := sdk.JobRetryOnFailure
+		if res.Status == sdk.StatusTimeout {
+			failureKind = sdk.JobRetryOnTimeout
+		}
+		retried, report, err := workflow.RetryNodeJobRun(ctx, tx, job, failureKind)
+		if err != nil {
+			return nil, err
+		}
+		if retried {
+			if err := tx.Commit(); err != nil {
+				return nil, sdk.WrapError(err, "cannot commit tx")
+			}
+			return report, nil
+		}
+		if err := workflow.AddSpawnInfoRetryExhausted(tx, *job, failureKind); err != nil {
+			return nil, err
+		}
+	}
+
 	report, err := workflow.UpdateNodeJobRunStatus(ctx, tx, store, *proj, job, res.Status)
 	if err != nil {
 		return nil, sdk.WrapError(err, "cannot update NodeJobRun %d status", job.ID)
@@ -552,9 +711,13 @@ func (api *API) postWorkflowJobLogsHandler() service.Handler {
 
 		log.Debug("postWorkflowJobLogsHandler> Logs: %+v", logs)
 
-		if err := workflow.AddLog(api.mustDB(), pbJob, &logs, api.Config.Log.StepMaxSize); err != nil {
+		chunk, err := workflow.AddLog(api.mustDB(), pbJob, &logs, api.Config.Log.StepMaxSize)
+		if err != nil {
 			return err
 		}
+		if chunk != nil {
+			publishStepLogChunk(ctx, api.Cache, *chunk)
+		}
 
 		return nil
 	}
@@ -574,6 +737,7 @@ func (api *API) postWorkflowJobServiceLogsHandler() service.AsynchronousHandler
 
 		globalErr := &sdk.MultiError{}
 		errorOccured := false
+		maskers := make(map[int64]*sdk.SecretMasker)
 		for _, log := range logs {
 			nodeRunJob, errJob := workflow.LoadNodeJobRun(ctx, db, api.Cache, log.WorkflowNodeJobRunID)
 			if errJob != nil {
@@ -591,6 +755,20 @@ func (api *API) postWorkflowJobServiceLogsHandler() service.AsynchronousHandler
 				continue
 			}
 
+			// Services are not run by the worker, their logs must be masked here
+			masker, ok := maskers[nodeRunJob.ID]
+			if !ok {
+				var errM error
+				masker, errM = loadNodeJobRunSecretMasker(ctx, db, api.Cache, nodeRunJob)
+				if errM != nil {
+					errorOccured = true
+					globalErr.Append(fmt.Errorf("postWorkflowJobServiceLogsHandler> %v", errM))
+					continue
+				}
+				maskers[nodeRunJob.ID] = masker
+			}
+			log.Val = masker.Mask(log.Val)
+
 			if err := workflow.AddServiceLog(db, nodeRunJob, &log, api.Config.Log.ServiceMaxSize); err != nil {
 				errorOccured = true
 				globalErr.Append(fmt.Errorf("postWorkflowJobServiceLogsHandler> %v", err))
@@ -606,6 +784,46 @@ func (api *API) postWorkflowJobServiceLogsHandler() service.AsynchronousHandler
 	}
 }
 
+// loadNodeJobRunSecretMasker returns a masker for the secrets and the password parameters of a job run.
+func loadNodeJobRunSecretMasker(ctx context.Context, db gorp.SqlExecutor, store cache.Store, job *sdk.WorkflowNodeJobRun) (*sdk.SecretMasker, error) {
+	proj, err := project.LoadProjectByNodeJobRunID(ctx, db, store, job.ID, project.LoadOptions.WithClearKeys)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load project by nodeJobRunID: %d", job.ID)
+	}
+	nodeRun, err := workflow.LoadNodeRunByID(db, job.WorkflowNodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load node run %d", job.WorkflowNodeRunID)
+	}
+	workflowRun, err := workflow.LoadRunByID(db, nodeRun.WorkflowRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load workflow run %d", nodeRun.WorkflowRunID)
+	}
+	pv, err := project.LoadAllVariablesWithDecrytion(db, proj.ID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load project variables")
+	}
+	secrets, err := workflow.LoadSecrets(db, store, nodeRun, workflowRun, pv)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load secrets")
+	}
+	_, secretsKeys, err := workflow.LoadNodeJobRunKeys(ctx, db, proj, workflowRun, nodeRun)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load keys")
+	}
+	secrets = append(secrets, secretsKeys...)
+
+	values := make([]string, 0, len(secrets))
+	for _, s := range secrets {
+		values = append(values, s.Value)
+	}
+	for _, p := range job.Parameters {
+		if p.Type == sdk.SecretVariable {
+			values = append(values, p.Value)
+		}
+	}
+	return sdk.NewSecretMasker(values...), nil
+}
+
 func (api *API) postWorkflowJobStepStatusHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if isWorker := isWorker(ctx); !isWorker {
@@ -681,6 +899,13 @@ func (api *API) postWorkflowJobStepStatusHandler() service.Handler {
 			return sdk.WithStack(err)
 		}
 
+		// the logs buffered by the storage are written when the step ends
+		if sdk.StatusIsTerminated(step.Status) {
+			if err := workflow.FlushStepLog(api.mustDB(), nodeJobRun.ID, int64(step.StepOrder)); err != nil {
+				log.Error(ctx, "postWorkflowJobStepStatusHandler> unable to flush step log: %v", err)
+			}
+		}
+
 		if nodeRun.ID == 0 {
 			nodeRunP, err := workflow.LoadNodeRunByID(api.mustDB(), nodeJobRun.WorkflowNodeRunID, workflow.LoadRunOptions{DisableDetailledNodeRun: true})
 			if err != nil {
@@ -787,6 +1012,12 @@ func (api *API) getWorkflowJobQueueHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to load queue")
 		}
 
+		buildingJobs, err := workflow.CountBuildingNodeJobRunsByProject(api.mustDB())
+		if err != nil {
+			return err
+		}
+		workflow.ComputeJobPriorities(api.jobPriority, jobs, buildingJobs)
+
 		return service.WriteJSON(w, jobs, http.StatusOK)
 	}
 }
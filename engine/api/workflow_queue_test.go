@@ -539,6 +539,38 @@ func Test_postBookWorkflowJobHandler(t *testing.T) {
 	require.Equal(t, 200, rec.Code)
 }
 
+func Test_postBookWorkflowJobHandlerRetryBackoff(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+	ctx := testRunWorkflow(t, api, router)
+	testGetWorkflowJobAsHatchery(t, api, router, &ctx)
+	assert.NotNil(t, ctx.job)
+	testRegisterHatchery(t, api, router, &ctx)
+
+	// The job run is delayed by the backoff of its retry policy
+	_, err := db.Exec("UPDATE workflow_node_run_job SET queued = $2 WHERE id = $1", ctx.job.ID, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	uri := router.GetRoute("POST", api.postBookWorkflowJobHandler, map[string]string{
+		"permJobID": fmt.Sprintf("%d", ctx.job.ID),
+	})
+	test.NotEmpty(t, uri)
+
+	req := assets.NewJWTAuthentifiedRequest(t, ctx.hatcheryToken, "POST", uri, nil)
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 403, rec.Code)
+
+	// Once the backoff is over, the job run can be booked
+	_, err = db.Exec("UPDATE workflow_node_run_job SET queued = $2 WHERE id = $1", ctx.job.ID, time.Now())
+	require.NoError(t, err)
+
+	req = assets.NewJWTAuthentifiedRequest(t, ctx.hatcheryToken, "POST", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 200, rec.Code)
+}
+
 func Test_postWorkflowJobResultHandler(t *testing.T) {
 	api, _, router, end := newTestAPI(t)
 	defer end()
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// jobTimeoutGracePeriod is the delay given to a worker to send its result after the job timeout.
+const jobTimeoutGracePeriod = 2 * time.Minute
+
+// manageTimedOutJobs checks periodically the building jobs that exceeded their timeout.
+func (api *API) manageTimedOutJobs(ctx context.Context) {
+	tick := time.NewTicker(30 * time.Second)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "Exiting manageTimedOutJobs: %v", ctx.Err())
+				return
+			}
+		case <-tick.C:
+			ids, err := workflow.LoadTimedOutNodeJobRunIDs(api.mustDB(), jobTimeoutGracePeriod)
+			if err != nil {
+				log.Warning(ctx, "manageTimedOutJobs> %v", err)
+				continue
+			}
+			for _, id := range ids {
+				if err := api.timeoutNodeJobRun(ctx, id); err != nil {
+					log.Error(ctx, "manageTimedOutJobs> unable to timeout node job run %d: %v", id, err)
+				}
+			}
+		}
+	}
+}
+
+// timeoutNodeJobRun fails a job run with the timeout status if the worker did not send its result
+// before the job deadline. The job run is retried if its retry policy allows it.
+func (api *API) timeoutNodeJobRun(ctx context.Context, id int64) error {
+	proj, err := project.LoadProjectByNodeJobRunID(ctx, api.mustDB(), api.Cache, id, project.LoadOptions.WithVariables)
+	if err != nil {
+		return sdk.WrapError(err, "cannot load project from job %d", id)
+	}
+
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	job, err := workflow.LoadAndLockNodeJobRunSkipLocked(ctx, tx, api.Cache, id)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrLocked) {
+			// the job is being updated, i.e. the worker is sending its result
+			return nil
+		}
+		return err
+	}
+	if job.Status != sdk.StatusBuilding {
+		return nil
+	}
+
+	info := sdk.SpawnInfo{
+		RemoteTime: time.Now(),
+		Message: sdk.SpawnMsg{
+			ID:   sdk.MsgSpawnInfoJobTimeout.ID,
+			Args: []interface{}{(time.Duration(job.Job.Timeout) * time.Second).String()},
+		},
+	}
+	if err := workflow.AddSpawnInfosNodeJobRun(tx, job.WorkflowNodeRunID, job.ID, workflow.PrepareSpawnInfos([]sdk.SpawnInfo{info})); err != nil {
+		return sdk.WrapError(err, "cannot save spawn info job %d", job.ID)
+	}
+
+	retried, report, err := workflow.RetryNodeJobRun(ctx, tx, job, sdk.JobRetryOnTimeout)
+	if err != nil {
+		return err
+	}
+	if !retried {
+		if err := workflow.AddSpawnInfoRetryExhausted(tx, *job, sdk.JobRetryOnTimeout); err != nil {
+			return err
+		}
+		// The worker is still bound to the job, it should not take another one
+		if _, err := tx.Exec("UPDATE worker SET status = $2, job_run_id = NULL where job_run_id = $1", job.ID, sdk.StatusDisabled); err != nil {
+			return sdk.WrapError(err, "unable to set workers")
+		}
+		report, err = workflow.UpdateNodeJobRunStatus(ctx, tx, api.Cache, *proj, job, sdk.StatusTimeout)
+		if err != nil {
+			return sdk.WrapError(err, "cannot update NodeJobRun %d status", job.ID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	log.Info(ctx, "timeoutNodeJobRun> job run %d timed out after %ds", job.ID, job.Job.Timeout)
+
+	go WorkflowSendEvent(context.Background(), api.mustDB(), api.Cache, *proj, report)
+
+	return nil
+}
@@ -481,6 +481,12 @@ func stopWorkflowRun(ctx context.Context, dbFunc func() *gorp.DbMap, store cache
 	}
 	report.Add(ctx, *run)
 
+	r1, err := workflow.ReleaseWorkflowConcurrencyGroup(ctx, tx, store, *p, run)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to release concurrency group of workflow run %d", run.ID)
+	}
+	report.Merge(ctx, r1)
+
 	if err := tx.Commit(); err != nil {
 		return nil, sdk.WithStack(err)
 	}
@@ -837,9 +843,12 @@ func (api *API) postWorkflowRunHandler() service.Handler {
 
 			var errc error
 			var conditionsOK bool
-			if conditions.LuaScript == "" {
+			switch {
+			case conditions.Expression != "":
+				conditionsOK, errc = sdk.WorkflowCheckConditionExpression(conditions.Expression, params)
+			case conditions.LuaScript == "":
 				conditionsOK, errc = sdk.WorkflowCheckConditions(conditions.PlainConditions, params)
-			} else {
+			default:
 				luacheck, err := luascript.NewCheck()
 				if err != nil {
 					return sdk.WrapError(err, "cannot check lua script")
@@ -921,6 +930,64 @@ func (api *API) postWorkflowRunHandler() service.Handler {
 	}
 }
 
+// postWorkflowRunPlanHandler processes a workflow run request without persisting anything and returns
+// the nodes that would be triggered with their jobs, or the conditions that blocked them.
+func (api *API) postWorkflowRunPlanHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		p, err := project.Load(api.mustDB(), key,
+			project.LoadOptions.WithVariables,
+			project.LoadOptions.WithFeatures(api.Cache),
+			project.LoadOptions.WithIntegrations,
+			project.LoadOptions.WithApplicationVariables,
+			project.LoadOptions.WithApplicationWithDeploymentStrategies,
+			project.LoadOptions.WithEnvironments,
+			project.LoadOptions.WithPipelines,
+		)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project")
+		}
+
+		opts := &sdk.WorkflowRunPostHandlerOption{}
+		if err := service.UnmarshalBody(r, opts); err != nil {
+			return err
+		}
+		if opts.Manual != nil && opts.Manual.Resync {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot resync workflow in plan mode")
+		}
+
+		var lastRun *sdk.WorkflowRun
+		var wf *sdk.Workflow
+		if opts.Number != nil {
+			lastRun, err = workflow.LoadRun(ctx, api.mustDB(), key, name, *opts.Number, workflow.LoadRunOptions{})
+			if err != nil {
+				return sdk.WrapError(err, "unable to load workflow run")
+			}
+			wf = &lastRun.Workflow
+			wf.Name = name
+		} else {
+			wf, err = workflow.Load(ctx, api.mustDB(), api.Cache, *p, name, workflow.LoadOptions{
+				DeepPipeline:     true,
+				Base64Keys:       true,
+				WithIntegrations: true,
+			})
+			if err != nil {
+				return sdk.WrapError(err, "unable to load workflow %s", name)
+			}
+		}
+
+		plan, err := workflow.PlanWorkflowRun(ctx, api.mustDB(), api.Cache, *p, wf, lastRun, opts, getAPIConsumer(ctx))
+		if err != nil {
+			return err
+		}
+		plan.Translate(r.Header.Get("Accept-Language"))
+		return service.WriteJSON(w, plan, http.StatusOK)
+	}
+}
+
 func (api *API) initWorkflowRun(ctx context.Context, projKey string, wf *sdk.Workflow, wfRun *sdk.WorkflowRun, opts *sdk.WorkflowRunPostHandlerOption, u *sdk.AuthConsumer) {
 	var asCodeInfosMsg []sdk.Message
 	report := new(workflow.ProcessorReport)
@@ -1301,31 +1368,15 @@ func (api *API) getWorkflowNodeRunJobStepHandler() service.Handler {
 			return sdk.WrapError(errNR, "cannot find nodeRun %d/%d for workflow %s in project %s", nodeRunID, number, workflowName, projectKey)
 		}
 
-		var stepStatus string
 		// Find job/step in nodeRun
-	stageLoop:
-		for _, s := range nodeRun.Stages {
-			for _, rj := range s.RunJobs {
-				if rj.ID != runJobID {
-					continue
-				}
-				ss := rj.Job.StepStatus
-				for _, sss := range ss {
-					if int64(sss.StepOrder) == stepOrder {
-						stepStatus = sss.Status
-						break
-					}
-				}
-				break stageLoop
-			}
-		}
+		_, stepStatus := findNodeRunJobStep(nodeRun, runJobID, stepOrder)
 
 		if stepStatus == "" {
 			return sdk.WrapError(sdk.ErrStepNotFound, "cannot find step %d on job %d in nodeRun %d/%d for workflow %s in project %s",
 				stepOrder, runJobID, nodeRunID, number, workflowName, projectKey)
 		}
 
-		logs, errL := workflow.LoadStepLogs(api.mustDB(), runJobID, stepOrder)
+		logs, errL := workflow.LoadStepLogs(ctx, api.mustDB(), runJobID, stepOrder)
 		if errL != nil {
 			return sdk.WrapError(errL, "cannot load log for runJob %d on step %d", runJobID, stepOrder)
 		}
@@ -680,6 +680,74 @@ func Test_getWorkflowRunHandler(t *testing.T) {
 	assert.Equal(t, int64(9), wr.Number)
 }
 
+func Test_getWorkflowRunHandlerWithoutHookSecret(t *testing.T) {
+	api, db, router, end := newTestAPI(t)
+	defer end()
+	u, pass := assets.InsertAdminUser(t, db)
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key)
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+	}
+	require.NoError(t, pipeline.InsertPipeline(db, &pip))
+
+	w := sdk.Workflow{
+		Name:       "test_1",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "root",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID: pip.ID,
+				},
+			},
+		},
+	}
+	proj2, err := project.Load(db, proj.Key, project.LoadOptions.WithPipelines, project.LoadOptions.WithGroups, project.LoadOptions.WithIntegrations)
+	require.NoError(t, err)
+	require.NoError(t, workflow.Insert(context.TODO(), db, api.Cache, *proj2, &w))
+	w1, err := workflow.Load(context.TODO(), db, api.Cache, *proj, "test_1", workflow.LoadOptions{})
+	require.NoError(t, err)
+
+	// The workflow of the run contains a repository webhook with its secret
+	wr, err := workflow.CreateRun(db, w1, nil, u)
+	require.NoError(t, err)
+	secret := sdk.RandomString(20)
+	wr.Workflow = *w1
+	wr.Workflow.WorkflowData.Node.Hooks = []sdk.NodeHook{{
+		UUID:          sdk.UUID(),
+		HookModelName: sdk.RepositoryWebHookModelName,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigWebHookSecret: {Value: secret},
+		},
+	}}
+	require.NoError(t, workflow.UpdateWorkflowRun(context.TODO(), db, wr))
+
+	vars := map[string]string{
+		"key":              proj.Key,
+		"permWorkflowName": w1.Name,
+		"number":           fmt.Sprintf("%d", wr.Number),
+	}
+	for _, uri := range []string{
+		router.GetRoute("GET", api.getWorkflowRunHandler, vars),
+		router.GetRoute("GET", api.getWorkflowRunsHandler, vars),
+		router.GetRoute("GET", api.getLatestWorkflowRunHandler, vars),
+	} {
+		require.NotEmpty(t, uri)
+		req := assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil)
+		rec := httptest.NewRecorder()
+		router.Mux.ServeHTTP(rec, req)
+		require.Equal(t, 200, rec.Code)
+		assert.Contains(t, rec.Body.String(), wr.Workflow.WorkflowData.Node.Hooks[0].UUID, uri)
+		assert.NotContains(t, rec.Body.String(), secret, uri)
+	}
+}
+
 func Test_getWorkflowNodeRunHandler(t *testing.T) {
 	api, db, router, end := newTestAPI(t)
 	defer end()
@@ -2174,16 +2242,18 @@ func initGetWorkflowNodeRunJobTest(t *testing.T, api *API, db *gorp.DbMap) (*sdk
 	require.NoError(t, errUJ)
 
 	// Add log
-	require.NoError(t, workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
+	_, err = workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
 		StepOrder: 1,
 		Val:       "1234567890",
-	}, 15))
+	}, 15)
+	require.NoError(t, err)
 
 	// Add truncated log
-	require.NoError(t, workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
+	_, err = workflow.AddLog(api.mustDB(), jobRun, &sdk.Log{
 		StepOrder: 1,
 		Val:       "1234567890",
-	}, 15))
+	}, 15)
+	require.NoError(t, err)
 
 	// Add service log
 	require.NoError(t, workflow.AddServiceLog(api.mustDB(), jobRun, &sdk.ServiceLog{
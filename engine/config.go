@@ -24,6 +24,7 @@ import (
 	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
+	"github.com/ovh/cds/engine/hatchery/nomad"
 	"github.com/ovh/cds/engine/hatchery/openstack"
 	"github.com/ovh/cds/engine/hatchery/swarm"
 	"github.com/ovh/cds/engine/hatchery/vsphere"
@@ -49,7 +50,7 @@ func configBootstrap(args []string) Configuration {
 	if len(args) == 0 {
 		args = []string{
 			"api", "ui", "migrate", "hooks", "vcs", "repositories", "elasticsearch",
-			"hatchery:local", "hatchery:kubernetes", "hatchery:marathon", "hatchery:openstack", "hatchery:swarm", "hatchery:vsphere",
+			"hatchery:local", "hatchery:kubernetes", "hatchery:marathon", "hatchery:nomad", "hatchery:openstack", "hatchery:swarm", "hatchery:vsphere",
 		}
 	}
 
@@ -93,6 +94,10 @@ func configBootstrap(args []string) Configuration {
 			conf.Hatchery.Kubernetes = &kubernetes.HatcheryConfiguration{}
 			defaults.SetDefaults(conf.Hatchery.Kubernetes)
 			conf.Hatchery.Kubernetes.Name = "cds-hatchery-kubernetes-" + namesgenerator.GetRandomNameCDS(0)
+		case "hatchery:nomad":
+			conf.Hatchery.Nomad = &nomad.HatcheryConfiguration{}
+			defaults.SetDefaults(conf.Hatchery.Nomad)
+			conf.Hatchery.Nomad.Name = "cds-hatchery-nomad-" + namesgenerator.GetRandomNameCDS(0)
 		case "hatchery:marathon":
 			conf.Hatchery.Marathon = &marathon.HatcheryConfiguration{}
 			defaults.SetDefaults(conf.Hatchery.Marathon)
@@ -129,6 +134,8 @@ func configBootstrap(args []string) Configuration {
 			defaults.SetDefaults(&bitbucketcloud)
 			var gitlab vcs.GitlabServerConfiguration
 			defaults.SetDefaults(&gitlab)
+			var gitea vcs.GiteaServerConfiguration
+			defaults.SetDefaults(&gitea)
 			var gerrit vcs.GerritServerConfiguration
 			defaults.SetDefaults(&gerrit)
 			conf.VCS.Servers = map[string]vcs.ServerConfiguration{
@@ -136,6 +143,7 @@ func configBootstrap(args []string) Configuration {
 				"bitbucket":      vcs.ServerConfiguration{URL: "https://mybitbucket.com", Bitbucket: &bitbucket},
 				"bitbucketcloud": vcs.ServerConfiguration{BitbucketCloud: &bitbucketcloud},
 				"gitlab":         vcs.ServerConfiguration{URL: "https://gitlab.com", Gitlab: &gitlab},
+				"gitea":          vcs.ServerConfiguration{URL: "https://gitea.example.com", Gitea: &gitea},
 				"gerrit":         vcs.ServerConfiguration{URL: "http://localhost:8080", Gerrit: &gerrit},
 			}
 			conf.VCS.Name = "cds-vcs-" + namesgenerator.GetRandomNameCDS(0)
@@ -443,6 +451,33 @@ func configSetStartupData(conf *Configuration) (string, error) {
 			privateKeyPEM, _ := jws.ExportPrivateKey(privateKey)
 			h.Marathon.RSAPrivateKey = string(privateKeyPEM)
 		}
+		if h.Nomad != nil {
+			var cfg = api.StartupConfigService{
+				ID:          sdk.UUID(),
+				Name:        "hatchery:nomad",
+				Description: "Autogenerated configuration for nomad hatchery",
+				ServiceType: services.TypeHatchery,
+			}
+
+			var c = sdk.AuthConsumer{
+				ID:          cfg.ID,
+				Name:        cfg.Name,
+				Description: cfg.Description,
+				Type:        sdk.ConsumerBuiltin,
+				Data:        map[string]string{},
+				IssuedAt:    iat,
+			}
+
+			conf.Hatchery.Nomad.API.Token, err = builtin.NewSigninConsumerToken(&c)
+			if err != nil {
+				return "", err
+			}
+
+			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
+			privateKey, _ := jws.NewRandomRSAKey()
+			privateKeyPEM, _ := jws.ExportPrivateKey(privateKey)
+			h.Nomad.RSAPrivateKey = string(privateKeyPEM)
+		}
 		if h.Kubernetes != nil {
 			var cfg = api.StartupConfigService{
 				ID:          sdk.UUID(),
@@ -721,6 +756,24 @@ func getInitTokenFromExistingConfiguration(conf Configuration) (string, error) {
 
 			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
 		}
+		if h.Nomad != nil {
+			consumerID, iat, err := builtin.CheckSigninConsumerToken(h.Nomad.API.Token)
+			if err != nil {
+				return "", fmt.Errorf("cannot parse hatchery:nomad signin token: %v", err)
+			}
+			if iat < globalIAT {
+				globalIAT = iat
+			}
+
+			var cfg = api.StartupConfigService{
+				ID:          consumerID,
+				Name:        "hatchery:nomad",
+				Description: "Autogenerated configuration for nomad hatchery",
+				ServiceType: services.TypeHatchery,
+			}
+
+			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
+		}
 		if h.Kubernetes != nil {
 			consumerID, iat, err := builtin.CheckSigninConsumerToken(h.Kubernetes.API.Token)
 			if err != nil {
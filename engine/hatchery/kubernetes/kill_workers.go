@@ -21,6 +21,10 @@ func (h *HatcheryKubernetes) killAwolWorkers(ctx context.Context) error {
 	for _, pod := range pods.Items {
 		toDelete := false
 		for _, container := range pod.Status.ContainerStatuses {
+			// Services are sidecars of the worker container, the pod is deleted only when the worker exits
+			if containerServiceNameRegexp.MatchString(container.Name) {
+				continue
+			}
 			if (container.State.Terminated != nil && (container.State.Terminated.Reason == "Completed" || container.State.Terminated.Reason == "Error")) ||
 				(container.State.Waiting != nil && container.State.Waiting.Reason == "ErrImagePull") {
 				toDelete = true
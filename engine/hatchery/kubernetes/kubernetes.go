@@ -218,25 +218,25 @@ func (h *HatcheryKubernetes) WorkerModelsEnabled() ([]sdk.Model, error) {
 }
 
 // CanSpawn return wether or not hatchery can spawn model.
-// requirements are not supported
+// Hostname requirement and volume requirements that can't be mapped on a pod volume are not supported
 func (h *HatcheryKubernetes) CanSpawn(ctx context.Context, model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
-	// Service and Hostname requirement are not supported
 	for _, r := range requirements {
-		if r.Type == sdk.ServiceRequirement {
-			log.Debug("CanSpawn> Job %d has a service requirement. Kubernetes can't spawn a worker for this job", jobID)
-			return false
-		} else if r.Type == sdk.HostnameRequirement {
+		if r.Type == sdk.HostnameRequirement {
 			log.Debug("CanSpawn> Job %d has a hostname requirement. Kubernetes can't spawn a worker for this job", jobID)
 			return false
 		}
 	}
+	if _, _, err := computeVolumes(requirements); err != nil {
+		log.Debug("CanSpawn> Job %d has an unsupported volume requirement. Kubernetes can't spawn a worker for this job: %v", jobID, err)
+		return false
+	}
 	return true
 }
 
 // SpawnWorker starts a new worker process
 func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery.SpawnArguments) error {
-	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly {
-		return sdk.WithStack(fmt.Errorf("no job ID and no register"))
+	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly && !spawnArgs.Warm {
+		return sdk.WithStack(fmt.Errorf("no job ID, no register and not a warm worker"))
 	}
 
 	label := "execution"
@@ -358,6 +358,13 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		},
 	}
 
+	volumes, mounts, err := computeVolumes(spawnArgs.Requirements)
+	if err != nil {
+		return sdk.WrapError(err, "cannot compute volumes for worker %s", spawnArgs.WorkerName)
+	}
+	podSchema.Spec.Volumes = volumes
+	podSchema.Spec.Containers[0].VolumeMounts = mounts
+
 	var services []sdk.Requirement
 	for _, req := range spawnArgs.Requirements {
 		if req.Type == sdk.ServiceRequirement {
@@ -422,7 +429,16 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		podSchema.Spec.HostAliases[0].Hostnames[i+1] = strings.ToLower(serv.Name)
 	}
 
-	_, err := h.k8sClient.CoreV1().Pods(h.Config.Namespace).Create(&podSchema)
+	// Merge the pod template after services so it can't remove them
+	if spawnArgs.Model.ModelDocker.PodTemplate != "" {
+		podTemplate, err := parsePodTemplate(spawnArgs.Model.ModelDocker.PodTemplate)
+		if err != nil {
+			return sdk.WrapError(err, "cannot parse pod template of model %s", spawnArgs.Model.Name)
+		}
+		mergePodTemplate(&podSchema, podTemplate)
+	}
+
+	_, err = h.k8sClient.CoreV1().Pods(h.Config.Namespace).Create(&podSchema)
 
 	log.Debug("hatchery> kubernetes> SpawnWorker> %s > Pod created", spawnArgs.WorkerName)
 
@@ -120,3 +120,67 @@ func TestHatcheryKubernetes_Status(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, gock.IsDone())
 }
+
+func TestHatcheryKubernetes_SpawnWorkerWithPodTemplate(t *testing.T) {
+	defer gock.Off()
+	h := NewHatcheryKubernetesTest(t)
+
+	m := &sdk.Model{
+		Name: "model1",
+		Group: &sdk.Group{
+			Name: "group",
+		},
+		ModelDocker: sdk.ModelDocker{
+			Image: "golang",
+			PodTemplate: `
+spec:
+  nodeSelector:
+    pool: ci
+  containers:
+  - name: worker
+    resources:
+      limits:
+        cpu: "2"
+`,
+		},
+	}
+
+	podResponse := v1.Pod{}
+	gock.New("http://lolcat.kube").Post("/api/v1/namespaces/hachibi/pods").Reply(http.StatusOK).JSON(podResponse)
+
+	var checkRequest gock.ObserverFunc = func(request *http.Request, mock gock.Mock) {
+		if request.Body == nil {
+			return
+		}
+		bodyContent, err := ioutil.ReadAll(request.Body)
+		assert.NoError(t, err)
+		var podRequest v1.Pod
+		require.NoError(t, json.Unmarshal(bodyContent, &podRequest))
+
+		require.Equal(t, "ci", podRequest.Spec.NodeSelector["pool"])
+		require.Equal(t, 1, len(podRequest.Spec.Containers))
+		require.Equal(t, "k8s-toto", podRequest.Spec.Containers[0].Name)
+		require.Equal(t, "golang", podRequest.Spec.Containers[0].Image)
+		require.Equal(t, "2", podRequest.Spec.Containers[0].Resources.Limits.Cpu().String())
+		require.Equal(t, 1, len(podRequest.Spec.Volumes))
+		require.Equal(t, "cache", podRequest.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		require.Equal(t, 1, len(podRequest.Spec.Containers[0].VolumeMounts))
+		require.Equal(t, "/cache", podRequest.Spec.Containers[0].VolumeMounts[0].MountPath)
+	}
+	gock.Observe(checkRequest)
+
+	err := h.SpawnWorker(context.TODO(), hatchery.SpawnArguments{
+		JobID:      666,
+		Model:      m,
+		WorkerName: "k8s-toto",
+		Requirements: []sdk.Requirement{
+			{
+				Name:  "cache",
+				Type:  sdk.VolumeRequirement,
+				Value: "type=volume,source=cache,destination=/cache",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, gock.IsDone())
+}
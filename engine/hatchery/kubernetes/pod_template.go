@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// podTemplateWorkerContainer is the name of the container in a pod template that will be merged with the worker container.
+const podTemplateWorkerContainer = "worker"
+
+// parsePodTemplate reads a pod template given in YAML or JSON, templates that give access to the node are rejected.
+func parsePodTemplate(tmpl string) (*apiv1.Pod, error) {
+	var pod apiv1.Pod
+	if err := yaml.Unmarshal([]byte(tmpl), &pod); err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pod template: %v", err)
+	}
+	if err := checkPodTemplate(&pod); err != nil {
+		return nil, err
+	}
+	return &pod, nil
+}
+
+// checkPodTemplate rejects the privileged containers, the host namespaces and volumes and the service accounts,
+// they would allow a job to take over the node or the cluster.
+func checkPodTemplate(tmpl *apiv1.Pod) error {
+	spec := tmpl.Spec
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pod template: host namespaces are not allowed")
+	}
+	if spec.ServiceAccountName != "" || spec.DeprecatedServiceAccount != "" {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pod template: service accounts are not allowed")
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pod template: hostPath volume %s is not allowed", v.Name)
+		}
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		sc := c.SecurityContext
+		if sc == nil {
+			continue
+		}
+		if (sc.Privileged != nil && *sc.Privileged) || (sc.AllowPrivilegeEscalation != nil && *sc.AllowPrivilegeEscalation) {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pod template: privileged container %s is not allowed", c.Name)
+		}
+		if sc.Capabilities != nil && len(sc.Capabilities.Add) > 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pod template: added capabilities of container %s are not allowed", c.Name)
+		}
+	}
+	return nil
+}
+
+// mergePodTemplate merges the pod template of a worker model into the pod built by the hatchery.
+// Identity values set by the hatchery (name, namespace, labels, image, command, env...) always take precedence
+// over the template ones. The template container named "worker" (or else the first one) is merged with
+// the worker container, its resources override the computed ones. Other template containers are ignored.
+func mergePodTemplate(pod *apiv1.Pod, tmpl *apiv1.Pod) {
+	for k, v := range tmpl.Labels {
+		if _, ok := pod.Labels[k]; !ok {
+			pod.Labels[k] = v
+		}
+	}
+	if len(tmpl.Annotations) > 0 && pod.Annotations == nil {
+		pod.Annotations = make(map[string]string, len(tmpl.Annotations))
+	}
+	for k, v := range tmpl.Annotations {
+		if _, ok := pod.Annotations[k]; !ok {
+			pod.Annotations[k] = v
+		}
+	}
+
+	if len(tmpl.Spec.NodeSelector) > 0 && pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = make(map[string]string, len(tmpl.Spec.NodeSelector))
+	}
+	for k, v := range tmpl.Spec.NodeSelector {
+		pod.Spec.NodeSelector[k] = v
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, tmpl.Spec.Tolerations...)
+	pod.Spec.Volumes = append(pod.Spec.Volumes, tmpl.Spec.Volumes...)
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, tmpl.Spec.InitContainers...)
+	pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, tmpl.Spec.ImagePullSecrets...)
+	if tmpl.Spec.Affinity != nil {
+		pod.Spec.Affinity = tmpl.Spec.Affinity
+	}
+	if tmpl.Spec.SecurityContext != nil {
+		pod.Spec.SecurityContext = tmpl.Spec.SecurityContext
+	}
+	if tmpl.Spec.PriorityClassName != "" {
+		pod.Spec.PriorityClassName = tmpl.Spec.PriorityClassName
+	}
+	if tmpl.Spec.ServiceAccountName != "" {
+		pod.Spec.ServiceAccountName = tmpl.Spec.ServiceAccountName
+	}
+
+	if len(tmpl.Spec.Containers) == 0 || len(pod.Spec.Containers) == 0 {
+		return
+	}
+	tmplContainer := tmpl.Spec.Containers[0]
+	for _, c := range tmpl.Spec.Containers {
+		if c.Name == podTemplateWorkerContainer {
+			tmplContainer = c
+			break
+		}
+	}
+	mergeContainerTemplate(&pod.Spec.Containers[0], tmplContainer)
+}
+
+func mergeContainerTemplate(c *apiv1.Container, tmpl apiv1.Container) {
+	if c.Resources.Requests == nil && len(tmpl.Resources.Requests) > 0 {
+		c.Resources.Requests = apiv1.ResourceList{}
+	}
+	for k, v := range tmpl.Resources.Requests {
+		c.Resources.Requests[k] = v
+	}
+	if c.Resources.Limits == nil && len(tmpl.Resources.Limits) > 0 {
+		c.Resources.Limits = apiv1.ResourceList{}
+	}
+	for k, v := range tmpl.Resources.Limits {
+		c.Resources.Limits[k] = v
+	}
+	if tmpl.SecurityContext != nil {
+		c.SecurityContext = tmpl.SecurityContext
+	}
+	if tmpl.ImagePullPolicy != "" {
+		c.ImagePullPolicy = tmpl.ImagePullPolicy
+	}
+	c.VolumeMounts = append(c.VolumeMounts, tmpl.VolumeMounts...)
+	c.EnvFrom = append(c.EnvFrom, tmpl.EnvFrom...)
+
+	existingEnvs := make(map[string]struct{}, len(c.Env))
+	for _, e := range c.Env {
+		existingEnvs[e.Name] = struct{}{}
+	}
+	for _, e := range tmpl.Env {
+		if _, ok := existingEnvs[e.Name]; !ok {
+			c.Env = append(c.Env, e)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_mergePodTemplate(t *testing.T) {
+	tmpl, err := parsePodTemplate(`
+metadata:
+  labels:
+    team: build
+    CDS_WORKER: overridden
+  annotations:
+    sidecar.istio.io/inject: "false"
+spec:
+  nodeSelector:
+    pool: ci
+  tolerations:
+  - key: dedicated
+    operator: Equal
+    value: ci
+    effect: NoSchedule
+  securityContext:
+    runAsUser: 1000
+  volumes:
+  - name: docker-config
+    secret:
+      secretName: docker-config
+  initContainers:
+  - name: init
+    image: busybox
+  containers:
+  - name: other
+    image: ignored
+  - name: worker
+    image: ignored
+    resources:
+      limits:
+        cpu: "2"
+        memory: 4Gi
+    volumeMounts:
+    - name: docker-config
+      mountPath: /root/.docker
+    env:
+    - name: CDS_NAME
+      value: overridden
+    - name: GOPROXY
+      value: https://proxy.golang.org
+`)
+	require.NoError(t, err)
+
+	pod := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "worker-1",
+			Labels: map[string]string{LABEL_WORKER: "execution"},
+		},
+		Spec: apiv1.PodSpec{
+			RestartPolicy: apiv1.RestartPolicyNever,
+			Containers: []apiv1.Container{{
+				Name:  "worker-1",
+				Image: "golang",
+				Env:   []apiv1.EnvVar{{Name: "CDS_NAME", Value: "worker-1"}},
+			}},
+		},
+	}
+	mergePodTemplate(&pod, tmpl)
+
+	require.Equal(t, "execution", pod.Labels[LABEL_WORKER])
+	require.Equal(t, "build", pod.Labels["team"])
+	require.Equal(t, "false", pod.Annotations["sidecar.istio.io/inject"])
+	require.Equal(t, "ci", pod.Spec.NodeSelector["pool"])
+	require.Len(t, pod.Spec.Tolerations, 1)
+	require.Equal(t, int64(1000), *pod.Spec.SecurityContext.RunAsUser)
+	require.Len(t, pod.Spec.Volumes, 1)
+	require.Len(t, pod.Spec.InitContainers, 1)
+	require.Equal(t, apiv1.RestartPolicyNever, pod.Spec.RestartPolicy)
+
+	require.Len(t, pod.Spec.Containers, 1)
+	c := pod.Spec.Containers[0]
+	require.Equal(t, "worker-1", c.Name)
+	require.Equal(t, "golang", c.Image)
+	require.Equal(t, "2", c.Resources.Limits.Cpu().String())
+	require.Equal(t, "4Gi", c.Resources.Limits.Memory().String())
+	require.Len(t, c.VolumeMounts, 1)
+	require.Equal(t, []apiv1.EnvVar{
+		{Name: "CDS_NAME", Value: "worker-1"},
+		{Name: "GOPROXY", Value: "https://proxy.golang.org"},
+	}, c.Env)
+
+	_, err = parsePodTemplate("spec: [")
+	require.Error(t, err)
+}
+
+func Test_parsePodTemplateRejectsHostAccess(t *testing.T) {
+	for _, tmpl := range []string{
+		`spec: {hostNetwork: true}`,
+		`spec: {hostPID: true}`,
+		`spec: {serviceAccountName: admin}`,
+		`
+spec:
+  volumes:
+  - name: root
+    hostPath:
+      path: /
+  containers:
+  - name: worker
+    volumeMounts:
+    - name: root
+      mountPath: /host`,
+		`
+spec:
+  containers:
+  - name: worker
+    securityContext:
+      privileged: true`,
+		`
+spec:
+  initContainers:
+  - name: init
+    image: busybox
+    securityContext:
+      capabilities:
+        add: ["SYS_ADMIN"]`,
+	} {
+		_, err := parsePodTemplate(tmpl)
+		require.Error(t, err, tmpl)
+	}
+
+	_, err := parsePodTemplate(`
+spec:
+  containers:
+  - name: worker
+    securityContext:
+      privileged: false
+      runAsUser: 1000`)
+	require.NoError(t, err)
+}
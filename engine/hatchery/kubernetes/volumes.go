@@ -0,0 +1,81 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Supported types of volume requirement, the value of the requirement looks like a docker --mount flag.
+// - type=volume,source=my-claim,destination=/cache[,readonly] mounts an existing persistent volume claim.
+// - type=emptydir,destination=/tmp/build mounts an empty directory that lives as long as the pod.
+// - type=tmpfs,destination=/tmp/build mounts an empty directory backed by memory.
+const (
+	volumeTypePVC      = "volume"
+	volumeTypeEmptyDir = "emptydir"
+	volumeTypeTmpfs    = "tmpfs"
+)
+
+// computeVolumes returns the pod volumes and the worker container mounts for given volume requirements.
+func computeVolumes(requirements []sdk.Requirement) ([]apiv1.Volume, []apiv1.VolumeMount, error) {
+	var volumes []apiv1.Volume
+	var mounts []apiv1.VolumeMount
+	for _, r := range requirements {
+		if r.Type != sdk.VolumeRequirement {
+			continue
+		}
+		name := fmt.Sprintf("cds-volume-%d", len(volumes))
+		v, m, err := parseVolumeRequirement(name, r.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+		volumes = append(volumes, v)
+		mounts = append(mounts, m)
+	}
+	return volumes, mounts, nil
+}
+
+func parseVolumeRequirement(name, value string) (apiv1.Volume, apiv1.VolumeMount, error) {
+	var mtype, source, destination string
+	var readonly bool
+	// only the first field is used, like for the --mount flag on swarm hatchery
+	opt := strings.Fields(value)
+	if len(opt) == 0 {
+		return apiv1.Volume{}, apiv1.VolumeMount{}, fmt.Errorf("empty volume requirement")
+	}
+	for _, o := range strings.Split(opt[0], ",") {
+		switch {
+		case strings.HasPrefix(o, "type="):
+			mtype = strings.TrimPrefix(o, "type=")
+		case strings.HasPrefix(o, "source="):
+			source = strings.TrimPrefix(o, "source=")
+		case strings.HasPrefix(o, "destination="):
+			destination = strings.TrimPrefix(o, "destination=")
+		case o == "readonly":
+			readonly = true
+		}
+	}
+	if destination == "" {
+		return apiv1.Volume{}, apiv1.VolumeMount{}, fmt.Errorf("invalid volume requirement %q: missing destination", value)
+	}
+
+	v := apiv1.Volume{Name: name}
+	switch mtype {
+	case volumeTypePVC:
+		if source == "" {
+			return apiv1.Volume{}, apiv1.VolumeMount{}, fmt.Errorf("invalid volume requirement %q: missing source", value)
+		}
+		v.PersistentVolumeClaim = &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: source, ReadOnly: readonly}
+	case volumeTypeEmptyDir:
+		v.EmptyDir = &apiv1.EmptyDirVolumeSource{}
+	case volumeTypeTmpfs:
+		v.EmptyDir = &apiv1.EmptyDirVolumeSource{Medium: apiv1.StorageMediumMemory}
+	default:
+		return apiv1.Volume{}, apiv1.VolumeMount{}, fmt.Errorf("invalid volume requirement %q: type %q is not supported by kubernetes hatchery", value, mtype)
+	}
+
+	return v, apiv1.VolumeMount{Name: name, MountPath: destination, ReadOnly: readonly}, nil
+}
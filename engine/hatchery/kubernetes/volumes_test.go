@@ -0,0 +1,39 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_computeVolumes(t *testing.T) {
+	volumes, mounts, err := computeVolumes([]sdk.Requirement{
+		{Type: sdk.MemoryRequirement, Value: "4096"},
+		{Type: sdk.VolumeRequirement, Value: "type=volume,source=go-cache,destination=/go/pkg/mod,readonly"},
+		{Type: sdk.VolumeRequirement, Value: "type=emptydir,destination=/tmp/build"},
+		{Type: sdk.VolumeRequirement, Value: "type=tmpfs,destination=/tmp/ram"},
+	})
+	require.NoError(t, err)
+	require.Len(t, volumes, 3)
+	require.Len(t, mounts, 3)
+
+	require.Equal(t, "cds-volume-0", volumes[0].Name)
+	require.Equal(t, "go-cache", volumes[0].PersistentVolumeClaim.ClaimName)
+	require.True(t, volumes[0].PersistentVolumeClaim.ReadOnly)
+	require.Equal(t, apiv1.VolumeMount{Name: "cds-volume-0", MountPath: "/go/pkg/mod", ReadOnly: true}, mounts[0])
+
+	require.NotNil(t, volumes[1].EmptyDir)
+	require.Equal(t, apiv1.StorageMediumDefault, volumes[1].EmptyDir.Medium)
+	require.Equal(t, "/tmp/build", mounts[1].MountPath)
+
+	require.Equal(t, apiv1.StorageMediumMemory, volumes[2].EmptyDir.Medium)
+
+	_, _, err = computeVolumes([]sdk.Requirement{{Type: sdk.VolumeRequirement, Value: "type=bind,source=/hostDir,destination=/dirInJob"}})
+	require.Error(t, err, "host directories can't be mounted")
+
+	_, _, err = computeVolumes([]sdk.Requirement{{Type: sdk.VolumeRequirement, Value: "type=volume,destination=/dirInJob"}})
+	require.Error(t, err, "a claim name is needed")
+}
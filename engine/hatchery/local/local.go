@@ -220,7 +220,7 @@ func (h *HatcheryLocal) CanSpawn(ctx context.Context, _ *sdk.Model, jobID int64,
 	}
 
 	for _, r := range requirements {
-		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
+		if (r.Type == sdk.ServiceRequirement && !h.Config.Provision.LocalServices) || r.Type == sdk.MemoryRequirement {
 			log.Debug("CanSpawn false service or memory")
 			return false
 		}
@@ -210,8 +210,8 @@ func (h *HatcheryMarathon) SpawnWorker(ctx context.Context, spawnArgs hatchery.S
 		log.Debug("spawnWorker> spawning worker %s (%s)", spawnArgs.Model.Name, spawnArgs.Model.ModelDocker.Image)
 	}
 
-	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly {
-		return sdk.WithStack(fmt.Errorf("no job ID and no register"))
+	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly && !spawnArgs.Warm {
+		return sdk.WithStack(fmt.Errorf("no job ID, no register and not a warm worker"))
 	}
 
 	// Estimate needed memory, we will set 110% of required memory
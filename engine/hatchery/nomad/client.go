@@ -0,0 +1,208 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+// nomadJob is a job of the Nomad HTTP API, only the fields used by the hatchery are declared.
+// See https://www.nomadproject.io/api-docs/json-jobs
+type nomadJob struct {
+	ID          string            `json:"ID"`
+	Name        string            `json:"Name"`
+	Type        string            `json:"Type"`
+	Namespace   string            `json:"Namespace,omitempty"`
+	Region      string            `json:"Region,omitempty"`
+	Datacenters []string          `json:"Datacenters"`
+	Meta        map[string]string `json:"Meta,omitempty"`
+	TaskGroups  []nomadTaskGroup  `json:"TaskGroups"`
+	Status      string            `json:"Status,omitempty"`
+}
+
+type nomadTaskGroup struct {
+	Name             string                 `json:"Name"`
+	Count            int                    `json:"Count"`
+	Networks         []nomadNetwork         `json:"Networks,omitempty"`
+	RestartPolicy    *nomadRestartPolicy    `json:"RestartPolicy,omitempty"`
+	ReschedulePolicy *nomadReschedulePolicy `json:"ReschedulePolicy,omitempty"`
+	Tasks            []nomadTask            `json:"Tasks"`
+}
+
+type nomadNetwork struct {
+	Mode string `json:"Mode"`
+}
+
+type nomadRestartPolicy struct {
+	Attempts int    `json:"Attempts"`
+	Mode     string `json:"Mode"`
+}
+
+type nomadReschedulePolicy struct {
+	Attempts  int  `json:"Attempts"`
+	Unlimited bool `json:"Unlimited"`
+}
+
+type nomadTask struct {
+	Name      string                 `json:"Name"`
+	Driver    string                 `json:"Driver"`
+	Leader    bool                   `json:"Leader,omitempty"`
+	Config    map[string]interface{} `json:"Config"`
+	Env       map[string]string      `json:"Env,omitempty"`
+	Resources nomadResources         `json:"Resources"`
+}
+
+type nomadResources struct {
+	CPU      int   `json:"CPU,omitempty"`
+	MemoryMB int64 `json:"MemoryMB"`
+}
+
+// nomadJobListStub is a job returned by the job list route. The meta are only returned by Nomad >= 1.6.
+type nomadJobListStub struct {
+	ID         string            `json:"ID"`
+	Name       string            `json:"Name"`
+	Status     string            `json:"Status"`
+	SubmitTime int64             `json:"SubmitTime"`
+	Meta       map[string]string `json:"Meta,omitempty"`
+}
+
+// nomadAllocation is an allocation returned by the job allocations route.
+type nomadAllocation struct {
+	ID           string                    `json:"ID"`
+	JobID        string                    `json:"JobID"`
+	ClientStatus string                    `json:"ClientStatus"`
+	TaskStates   map[string]nomadTaskState `json:"TaskStates"`
+}
+
+type nomadTaskState struct {
+	State  string `json:"State"`
+	Failed bool   `json:"Failed"`
+}
+
+const (
+	nomadJobStatusDead         = "dead"
+	nomadJobStatusPending      = "pending"
+	nomadAllocStatusFailed     = "failed"
+	nomadAllocStatusComplete   = "complete"
+	nomadAllocStatusLost       = "lost"
+	nomadTaskStateDead         = "dead"
+	nomadJobTypeBatch          = "batch"
+	nomadDriverDocker          = "docker"
+	nomadNetworkModeBridge     = "bridge"
+	nomadRestartPolicyModeFail = "fail"
+)
+
+// nomadClient is a minimal client of the Nomad HTTP API.
+type nomadClient struct {
+	address    string
+	token      string
+	namespace  string
+	region     string
+	httpClient *http.Client
+}
+
+func newNomadClient(cfg HatcheryConfiguration) *nomadClient {
+	return &nomadClient{
+		address:    strings.TrimSuffix(cfg.NomadAddress, "/"),
+		token:      cfg.NomadToken,
+		namespace:  cfg.Namespace,
+		region:     cfg.Region,
+		httpClient: cdsclient.NewHTTPClient(30*time.Second, cfg.InsecureSkipVerifyTLS),
+	}
+}
+
+func (c *nomadClient) do(ctx context.Context, method, path string, query url.Values, in, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.namespace != "" {
+		query.Set("namespace", c.namespace)
+	}
+	if c.region != "" {
+		query.Set("region", c.region)
+	}
+
+	var body io.Reader
+	if in != nil {
+		btes, err := json.Marshal(in)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		body = bytes.NewReader(btes)
+	}
+
+	req, err := http.NewRequest(method, c.address+path+"?"+query.Encode(), body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("X-Nomad-Token", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "nomad request %s %s failed", method, path)
+	}
+	defer resp.Body.Close() // nolint
+
+	btes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if resp.StatusCode >= 300 {
+		return sdk.WithStack(fmt.Errorf("nomad request %s %s failed: HTTP %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(btes))))
+	}
+	if out != nil {
+		if err := json.Unmarshal(btes, out); err != nil {
+			return sdk.WrapError(err, "cannot unmarshal nomad response of %s %s", method, path)
+		}
+	}
+	return nil
+}
+
+func (c *nomadClient) registerJob(ctx context.Context, job nomadJob) error {
+	return c.do(ctx, http.MethodPost, "/v1/jobs", nil, struct {
+		Job nomadJob `json:"Job"`
+	}{Job: job}, nil)
+}
+
+func (c *nomadClient) listJobs(ctx context.Context, prefix string) ([]nomadJobListStub, error) {
+	var jobs []nomadJobListStub
+	if err := c.do(ctx, http.MethodGet, "/v1/jobs", url.Values{"prefix": {prefix}, "meta": {"true"}}, nil, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (c *nomadClient) getJob(ctx context.Context, id string) (*nomadJob, error) {
+	var job nomadJob
+	if err := c.do(ctx, http.MethodGet, "/v1/job/"+url.PathEscape(id), nil, nil, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (c *nomadClient) jobAllocations(ctx context.Context, id string) ([]nomadAllocation, error) {
+	var allocs []nomadAllocation
+	if err := c.do(ctx, http.MethodGet, "/v1/job/"+url.PathEscape(id)+"/allocations", nil, nil, &allocs); err != nil {
+		return nil, err
+	}
+	return allocs, nil
+}
+
+// deregisterJob stops a job and purges it, so its allocations are garbage collected by Nomad.
+func (c *nomadClient) deregisterJob(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/job/"+url.PathEscape(id), url.Values{"purge": {"true"}}, nil, nil)
+}
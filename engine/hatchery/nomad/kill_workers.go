@@ -0,0 +1,89 @@
+package nomad
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// killAwolWorkers purges the nomad jobs of the workers that exited, the jobs whose allocations are all terminated,
+// for example because the image of the worker model can't be pulled, and the jobs still pending without allocation
+// after the spawn timeout, for example because no client can run them.
+func (h *HatcheryNomad) killAwolWorkers(ctx context.Context) error {
+	jobs, err := h.nomadClient.listJobs(ctx, h.jobIDPrefix())
+	if err != nil {
+		return err
+	}
+
+	spawnTimeout := time.Duration(h.Config.WorkerSpawnTimeout) * time.Second
+	var globalErr error
+	for _, j := range jobs {
+		toDelete := j.Status == nomadJobStatusDead
+		if !toDelete {
+			allocs, err := h.nomadClient.jobAllocations(ctx, j.ID)
+			if err != nil {
+				log.Error(ctx, "hatchery:nomad> killAwolWorkers> Cannot get allocations of job %s (%s)", j.ID, err)
+				continue
+			}
+			if len(allocs) == 0 {
+				toDelete = j.Status == nomadJobStatusPending && spawnTimeout > 0 && j.SubmitTime > 0 &&
+					time.Since(time.Unix(0, j.SubmitTime)) > spawnTimeout
+				if toDelete {
+					log.Warning(ctx, "hatchery:nomad> killAwolWorkers> Job %s is pending without allocation since %s", j.ID, time.Unix(0, j.SubmitTime))
+				}
+			} else {
+				toDelete = true
+				for _, a := range allocs {
+					if !allocationTerminated(a) {
+						toDelete = false
+						break
+					}
+				}
+			}
+		}
+		if !toDelete {
+			continue
+		}
+
+		// If its a worker "register", check registration before deleting it
+		workerName := strings.TrimPrefix(j.ID, h.jobIDPrefix())
+		if strings.HasPrefix(workerName, "register-") {
+			meta, err := h.jobMeta(ctx, j)
+			if err != nil {
+				log.Error(ctx, "hatchery:nomad> killAwolWorkers> Cannot get job %s (%s)", j.ID, err)
+			} else if modelPath := meta[META_MODEL_PATH]; modelPath != "" {
+				if err := hatchery.CheckWorkerModelRegister(h, modelPath); err != nil {
+					var spawnErr = sdk.SpawnErrorForm{
+						Error: err.Error(),
+					}
+					tuple := strings.SplitN(modelPath, "/", 2)
+					if err := h.CDSClient().WorkerModelSpawnError(tuple[0], tuple[1], spawnErr); err != nil {
+						log.Error(ctx, "killAndRemove> error on call client.WorkerModelSpawnError on worker model %s for register: %s", modelPath, err)
+					}
+				}
+			}
+		}
+
+		if err := h.nomadClient.deregisterJob(ctx, j.ID); err != nil {
+			globalErr = err
+			log.Error(ctx, "hatchery:nomad> killAwolWorkers> Cannot deregister job %s (%s)", j.ID, err)
+		}
+	}
+	return globalErr
+}
+
+// allocationTerminated returns true if the allocation is terminated or if its worker task is dead.
+func allocationTerminated(a nomadAllocation) bool {
+	switch a.ClientStatus {
+	case nomadAllocStatusFailed, nomadAllocStatusComplete, nomadAllocStatusLost:
+		return true
+	}
+	if s, ok := a.TaskStates[workerTaskName]; ok && s.State == nomadTaskStateDead {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package nomad
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestHatcheryNomad_KillAwolWorkers(t *testing.T) {
+	defer gock.Off()
+	h := NewHatcheryNomadTest(t)
+	h.Config.WorkerSpawnTimeout = 600
+
+	jobs := []nomadJobListStub{
+		{ID: "cds-kyubi-w1", Status: "dead"},
+		{ID: "cds-kyubi-w2", Status: "running"},
+		{ID: "cds-kyubi-w3", Status: "running"},
+		{ID: "cds-kyubi-w4", Status: "pending", SubmitTime: time.Now().Add(-time.Minute).UnixNano()},
+		{ID: "cds-kyubi-w5", Status: "pending", SubmitTime: time.Now().Add(-time.Hour).UnixNano()},
+	}
+	gock.New("http://lolcat.nomad").Get("/v1/jobs").Reply(http.StatusOK).JSON(jobs)
+
+	// the worker task of w2 exited, w3 is running, w4 is not allocated yet and w5 can't be allocated
+	gock.New("http://lolcat.nomad").Get("/v1/job/cds-kyubi-w2/allocations").Reply(http.StatusOK).JSON([]nomadAllocation{
+		{ID: "a2", JobID: "cds-kyubi-w2", ClientStatus: "running", TaskStates: map[string]nomadTaskState{
+			"worker":     {State: "dead"},
+			"service-pg": {State: "running"},
+		}},
+	})
+	gock.New("http://lolcat.nomad").Get("/v1/job/cds-kyubi-w3/allocations").Reply(http.StatusOK).JSON([]nomadAllocation{
+		{ID: "a3", JobID: "cds-kyubi-w3", ClientStatus: "running", TaskStates: map[string]nomadTaskState{
+			"worker": {State: "running"},
+		}},
+	})
+	gock.New("http://lolcat.nomad").Get("/v1/job/cds-kyubi-w4/allocations").Reply(http.StatusOK).JSON([]nomadAllocation{})
+	gock.New("http://lolcat.nomad").Get("/v1/job/cds-kyubi-w5/allocations").Reply(http.StatusOK).JSON([]nomadAllocation{})
+
+	gock.New("http://lolcat.nomad").Delete("/v1/job/cds-kyubi-w1").MatchParam("purge", "true").Reply(http.StatusOK).JSON(nil)
+	gock.New("http://lolcat.nomad").Delete("/v1/job/cds-kyubi-w2").MatchParam("purge", "true").Reply(http.StatusOK).JSON(nil)
+	gock.New("http://lolcat.nomad").Delete("/v1/job/cds-kyubi-w5").MatchParam("purge", "true").Reply(http.StatusOK).JSON(nil)
+
+	err := h.killAwolWorkers(context.TODO())
+	require.NoError(t, err)
+	require.True(t, gock.IsDone())
+}
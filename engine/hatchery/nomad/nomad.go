@@ -0,0 +1,452 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// New instanciates a new hatchery nomad
+func New() *HatcheryNomad {
+	s := new(HatcheryNomad)
+	s.Router = &api.Router{
+		Mux: mux.NewRouter(),
+	}
+	return s
+}
+
+// InitHatchery starts the routines of the nomad hatchery
+func (h *HatcheryNomad) InitHatchery(ctx context.Context) error {
+	sdk.GoRoutine(context.Background(), "hatchery nomad routines", func(ctx context.Context) {
+		h.routines(ctx)
+	})
+	return nil
+}
+
+func (h *HatcheryNomad) Init(config interface{}) (cdsclient.ServiceConfig, error) {
+	var cfg cdsclient.ServiceConfig
+	sConfig, ok := config.(HatcheryConfiguration)
+	if !ok {
+		return cfg, sdk.WithStack(fmt.Errorf("invalid nomad hatchery configuration"))
+	}
+
+	cfg.Host = sConfig.API.HTTP.URL
+	cfg.Token = sConfig.API.Token
+	cfg.InsecureSkipVerifyTLS = sConfig.API.HTTP.Insecure
+	cfg.RequestSecondsTimeout = sConfig.API.RequestTimeout
+	return cfg, nil
+}
+
+// ApplyConfiguration apply an object of type HatcheryConfiguration after checking it
+func (h *HatcheryNomad) ApplyConfiguration(cfg interface{}) error {
+	if err := h.CheckConfiguration(cfg); err != nil {
+		return err
+	}
+
+	var ok bool
+	h.Config, ok = cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	h.nomadClient = newNomadClient(h.Config)
+
+	h.Common.Common.ServiceName = h.Config.Name
+	h.Common.Common.ServiceType = services.TypeHatchery
+	h.HTTPURL = h.Config.URL
+	h.MaxHeartbeatFailures = h.Config.API.MaxHeartbeatFailures
+	var err error
+	h.Common.Common.PrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(h.Config.RSAPrivateKey))
+	if err != nil {
+		return fmt.Errorf("unable to parse RSA private Key: %v", err)
+	}
+
+	return nil
+}
+
+// Status returns sdk.MonitoringStatus, implements interface service.Service
+func (h *HatcheryNomad) Status(ctx context.Context) sdk.MonitoringStatus {
+	m := h.CommonMonitoring()
+	m.Lines = append(m.Lines, sdk.MonitoringStatusLine{Component: "Workers", Value: fmt.Sprintf("%d/%d", len(h.WorkersStarted(ctx)), h.Config.Provision.MaxWorker), Status: sdk.MonitoringStatusOK})
+
+	return m
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (h *HatcheryNomad) CheckConfiguration(cfg interface{}) error {
+	hconfig, ok := cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid hatchery nomad configuration")
+	}
+
+	if err := hconfig.Check(); err != nil {
+		return fmt.Errorf("Invalid hatchery nomad configuration: %v", err)
+	}
+
+	if hconfig.NomadAddress == "" {
+		return fmt.Errorf("please enter a valid nomad address")
+	}
+
+	return nil
+}
+
+// Serve start the hatchery server
+func (h *HatcheryNomad) Serve(ctx context.Context) error {
+	return h.CommonServe(ctx, h)
+}
+
+//Configuration returns Hatchery CommonConfiguration
+func (h *HatcheryNomad) Configuration() service.HatcheryCommonConfiguration {
+	return h.Config.HatcheryCommonConfiguration
+}
+
+// ModelType returns type of hatchery
+func (*HatcheryNomad) ModelType() string {
+	return sdk.Docker
+}
+
+// WorkerModelsEnabled returns Worker model enabled
+func (h *HatcheryNomad) WorkerModelsEnabled() ([]sdk.Model, error) {
+	return h.CDSClient().WorkerModelsEnabled()
+}
+
+// CanSpawn return wether or not hatchery can spawn model.
+// Hostname requirement and volume requirement are not supported
+func (h *HatcheryNomad) CanSpawn(ctx context.Context, model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	for _, r := range requirements {
+		if r.Type == sdk.HostnameRequirement || r.Type == sdk.VolumeRequirement {
+			log.Debug("CanSpawn> Job %d has a %s requirement. Nomad can't spawn a worker for this job", jobID, r.Type)
+			return false
+		}
+	}
+	return true
+}
+
+// jobIDPrefix is the prefix of the ID of the nomad jobs of the hatchery, followed by the name of the worker.
+func (h *HatcheryNomad) jobIDPrefix() string {
+	return "cds-" + h.Config.Name + "-"
+}
+
+// SpawnWorker submits a nomad batch job for a new worker
+func (h *HatcheryNomad) SpawnWorker(ctx context.Context, spawnArgs hatchery.SpawnArguments) error {
+	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly && !spawnArgs.Warm {
+		return sdk.WithStack(fmt.Errorf("no job ID, no register and not a warm worker"))
+	}
+
+	job, err := h.computeJob(spawnArgs)
+	if err != nil {
+		return err
+	}
+
+	if err := h.nomadClient.registerJob(ctx, *job); err != nil {
+		return sdk.WrapError(err, "cannot submit nomad job for worker %s", spawnArgs.WorkerName)
+	}
+
+	log.Debug("hatchery> nomad> SpawnWorker> %s > Job %s submitted", spawnArgs.WorkerName, job.ID)
+	return nil
+}
+
+// computeJob returns the nomad job of a worker: a task group with the worker task and a task for each service requirement.
+func (h *HatcheryNomad) computeJob(spawnArgs hatchery.SpawnArguments) (*nomadJob, error) {
+	label := "execution"
+	if spawnArgs.RegisterOnly {
+		label = "register"
+	}
+
+	memory := int64(h.Config.DefaultMemory)
+	for _, r := range spawnArgs.Requirements {
+		if r.Type == sdk.MemoryRequirement {
+			var err error
+			memory, err = strconv.ParseInt(r.Value, 10, 64)
+			if err != nil {
+				return nil, sdk.WrapError(err, "unable to parse memory requirement %s", r.Value)
+			}
+		}
+	}
+
+	udataParam := sdk.WorkerArgs{
+		API:               h.Configuration().API.HTTP.URL,
+		Token:             spawnArgs.WorkerToken,
+		HTTPInsecure:      h.Config.API.HTTP.Insecure,
+		Name:              spawnArgs.WorkerName,
+		Model:             spawnArgs.Model.Group.Name + "/" + spawnArgs.Model.Name,
+		HatcheryName:      h.Name(),
+		TTL:               h.Config.WorkerTTL,
+		GraylogHost:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Host,
+		GraylogPort:       h.Configuration().Provision.WorkerLogsOptions.Graylog.Port,
+		GraylogExtraKey:   h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraKey,
+		GraylogExtraValue: h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue,
+	}
+	udataParam.WorkflowJobID = spawnArgs.JobID
+
+	tmpl, err := template.New("cmd").Parse(spawnArgs.Model.ModelDocker.Cmd)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, udataParam); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	cmd := buffer.String()
+	if spawnArgs.RegisterOnly {
+		cmd += " register"
+		memory = hatchery.MemoryRegisterContainer
+	}
+
+	envs := map[string]string{}
+	envs["CDS_FORCE_EXIT"] = "1"
+	envs["CDS_MODEL_MEMORY"] = fmt.Sprintf("%d", memory)
+	envs["CDS_API"] = udataParam.API
+	envs["CDS_TOKEN"] = udataParam.Token
+	envs["CDS_NAME"] = udataParam.Name
+	envs["CDS_MODEL_PATH"] = udataParam.Model
+	envs["CDS_HATCHERY_NAME"] = udataParam.HatcheryName
+	envs["CDS_FROM_WORKER_IMAGE"] = fmt.Sprintf("%v", udataParam.FromWorkerImage)
+	envs["CDS_INSECURE"] = fmt.Sprintf("%v", udataParam.HTTPInsecure)
+	if spawnArgs.JobID > 0 {
+		envs["CDS_BOOKED_WORKFLOW_JOB_ID"] = fmt.Sprintf("%d", spawnArgs.JobID)
+	}
+
+	envTemplated, err := sdk.TemplateEnvs(udataParam, spawnArgs.Model.ModelDocker.Envs)
+	if err != nil {
+		return nil, err
+	}
+	for envName, envValue := range envTemplated {
+		envs[envName] = envValue
+	}
+
+	// the command of the worker is run by the shell of the model, example: sh -c "<cmd>"
+	workerConfig := map[string]interface{}{
+		"image": spawnArgs.Model.ModelDocker.Image,
+		"args":  []string{cmd},
+	}
+	if shell := strings.Fields(spawnArgs.Model.ModelDocker.Shell); len(shell) > 0 {
+		workerConfig["command"] = shell[0]
+		workerConfig["args"] = append(shell[1:], cmd)
+	}
+	if spawnArgs.Model.ModelDocker.Private {
+		workerConfig["auth"] = []map[string]string{{
+			"username":       spawnArgs.Model.ModelDocker.Username,
+			"password":       spawnArgs.Model.ModelDocker.Password,
+			"server_address": spawnArgs.Model.ModelDocker.Registry,
+		}}
+	}
+
+	group := nomadTaskGroup{
+		Name:             workerTaskName,
+		Count:            1,
+		RestartPolicy:    &nomadRestartPolicy{Attempts: 0, Mode: nomadRestartPolicyModeFail},
+		ReschedulePolicy: &nomadReschedulePolicy{Attempts: 0, Unlimited: false},
+		Tasks: []nomadTask{{
+			Name:      workerTaskName,
+			Driver:    nomadDriverDocker,
+			Leader:    true,
+			Config:    workerConfig,
+			Env:       envs,
+			Resources: nomadResources{MemoryMB: memory},
+		}},
+	}
+
+	// Services are tasks of the worker task group, they share its network namespace and they are stopped when the worker exits
+	var extraHosts []string
+	for _, r := range spawnArgs.Requirements {
+		if r.Type != sdk.ServiceRequirement {
+			continue
+		}
+		//name= <alias> => the name of the host put in /etc/hosts of the worker
+		//value= "postgres:latest env_1=blabla env_2=blabla" => we can add env variables in requirement name
+		img, envm := hatchery.ParseRequirementModel(r.Value)
+
+		serviceMemory := int64(1024)
+		if sm, ok := envm["CDS_SERVICE_MEMORY"]; ok {
+			if m, err := strconv.ParseInt(sm, 10, 64); err == nil && m > 4 {
+				serviceMemory = m
+			}
+			delete(envm, "CDS_SERVICE_MEMORY")
+		}
+
+		serviceConfig := map[string]interface{}{"image": img}
+		if sa, ok := envm["CDS_SERVICE_ARGS"]; ok {
+			serviceConfig["args"] = hatchery.ParseArgs(sa)
+			delete(envm, "CDS_SERVICE_ARGS")
+		}
+
+		group.Tasks = append(group.Tasks, nomadTask{
+			Name:      serviceTaskPrefix + strings.ToLower(r.Name),
+			Driver:    nomadDriverDocker,
+			Config:    serviceConfig,
+			Env:       envm,
+			Resources: nomadResources{MemoryMB: serviceMemory},
+		})
+		extraHosts = append(extraHosts, strings.ToLower(r.Name)+":127.0.0.1")
+	}
+	if len(extraHosts) > 0 {
+		group.Networks = []nomadNetwork{{Mode: nomadNetworkModeBridge}}
+		group.Tasks[0].Config["extra_hosts"] = append([]string{"worker:127.0.0.1"}, extraHosts...)
+	}
+
+	datacenters := h.Config.Datacenters
+	if len(datacenters) == 0 {
+		datacenters = []string{"dc1"}
+	}
+
+	job := nomadJob{
+		ID:          h.jobIDPrefix() + spawnArgs.WorkerName,
+		Name:        spawnArgs.WorkerName,
+		Type:        nomadJobTypeBatch,
+		Namespace:   h.Config.Namespace,
+		Region:      h.Config.Region,
+		Datacenters: datacenters,
+		Meta: map[string]string{
+			META_HATCHERY_NAME: h.Configuration().Name,
+			META_WORKER:        label,
+			META_WORKER_MODEL:  strings.ToLower(spawnArgs.Model.Name),
+			META_MODEL_PATH:    udataParam.Model,
+		},
+		TaskGroups: []nomadTaskGroup{group},
+	}
+	if spawnArgs.JobID > 0 {
+		job.Meta[META_JOB_ID] = strconv.FormatInt(spawnArgs.JobID, 10)
+	}
+
+	return &job, nil
+}
+
+// WorkersStarted returns the names of the workers started but
+// not necessarily register on CDS yet
+func (h *HatcheryNomad) WorkersStarted(ctx context.Context) []string {
+	jobs, err := h.nomadClient.listJobs(ctx, h.jobIDPrefix())
+	if err != nil {
+		log.Warning(ctx, "WorkersStarted> unable to list nomad jobs: %v", err)
+		return nil
+	}
+	workerNames := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		if j.Status == nomadJobStatusDead {
+			continue
+		}
+		workerNames = append(workerNames, strings.TrimPrefix(j.ID, h.jobIDPrefix()))
+	}
+	return workerNames
+}
+
+// WorkersStartedByModel returns the number of instances of given model started but
+// not necessarily register on CDS yet
+func (h *HatcheryNomad) WorkersStartedByModel(ctx context.Context, model *sdk.Model) int {
+	jobs, err := h.nomadClient.listJobs(ctx, h.jobIDPrefix())
+	if err != nil {
+		log.Error(ctx, "WorkersStartedByModel> Cannot get list of workers started (%s)", err)
+		return 0
+	}
+	h.forgetJobsMeta(jobs)
+	workersLen := 0
+	for _, j := range jobs {
+		if j.Status == nomadJobStatusDead {
+			continue
+		}
+		meta, err := h.jobMeta(ctx, j)
+		if err != nil {
+			log.Error(ctx, "WorkersStartedByModel> Cannot get nomad job %s (%s)", j.ID, err)
+			continue
+		}
+		if meta[META_WORKER_MODEL] == strings.ToLower(model.Name) {
+			workersLen++
+		}
+	}
+	return workersLen
+}
+
+// jobMeta returns the meta of a job from the job list, or from the job that is then cached
+// if the version of Nomad doesn't return them in the list.
+func (h *HatcheryNomad) jobMeta(ctx context.Context, j nomadJobListStub) (map[string]string, error) {
+	if j.Meta != nil {
+		return j.Meta, nil
+	}
+
+	h.jobsMeta.Lock()
+	meta, ok := h.jobsMeta.byID[j.ID]
+	h.jobsMeta.Unlock()
+	if ok {
+		return meta, nil
+	}
+
+	job, err := h.nomadClient.getJob(ctx, j.ID)
+	if err != nil {
+		return nil, err
+	}
+	meta = job.Meta
+	if meta == nil {
+		meta = map[string]string{}
+	}
+
+	h.jobsMeta.Lock()
+	if h.jobsMeta.byID == nil {
+		h.jobsMeta.byID = make(map[string]map[string]string)
+	}
+	h.jobsMeta.byID[j.ID] = meta
+	h.jobsMeta.Unlock()
+	return meta, nil
+}
+
+// forgetJobsMeta removes from the cache the meta of the jobs that are not in given list anymore.
+func (h *HatcheryNomad) forgetJobsMeta(jobs []nomadJobListStub) {
+	h.jobsMeta.Lock()
+	defer h.jobsMeta.Unlock()
+	if len(h.jobsMeta.byID) == 0 {
+		return
+	}
+	ids := make(map[string]struct{}, len(jobs))
+	for _, j := range jobs {
+		ids[j.ID] = struct{}{}
+	}
+	for id := range h.jobsMeta.byID {
+		if _, ok := ids[id]; !ok {
+			delete(h.jobsMeta.byID, id)
+		}
+	}
+}
+
+// NeedRegistration return true if worker model need regsitration
+func (h *HatcheryNomad) NeedRegistration(ctx context.Context, m *sdk.Model) bool {
+	if m.NeedRegistration || m.LastRegistration.Unix() < m.UserLastModified.Unix() {
+		return true
+	}
+	return false
+}
+
+func (h *HatcheryNomad) routines(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sdk.GoRoutine(ctx, "killAwolWorker", func(ctx context.Context) {
+				if err := h.killAwolWorkers(ctx); err != nil {
+					log.Error(ctx, "hatchery> nomad> cannot kill awol workers: %v", err)
+				}
+			})
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "Hatchery> Nomad> Exiting routines")
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+var _ hatchery.InterfaceWithModels = new(HatcheryNomad)
+
+func TestHatcheryNomad_WorkersStarted(t *testing.T) {
+	defer gock.Off()
+	h := NewHatcheryNomadTest(t)
+
+	jobs := []nomadJobListStub{
+		{ID: "cds-kyubi-w1", Status: "running"},
+		{ID: "cds-kyubi-w2", Status: "pending"},
+		{ID: "cds-kyubi-w3", Status: "dead"},
+	}
+	gock.New("http://lolcat.nomad").Get("/v1/jobs").
+		MatchParam("prefix", "cds-kyubi-").
+		MatchParam("namespace", "hachibi").
+		MatchHeader("X-Nomad-Token", "secret").
+		Reply(http.StatusOK).JSON(jobs)
+
+	ws := h.WorkersStarted(context.TODO())
+	require.Equal(t, []string{"w1", "w2"}, ws)
+	require.True(t, gock.IsDone())
+}
+
+func TestHatcheryNomad_WorkersStartedByModel(t *testing.T) {
+	defer gock.Off()
+	h := NewHatcheryNomadTest(t)
+	m := &sdk.Model{Name: "Model1"}
+
+	// The meta are returned by the job list
+	gock.New("http://lolcat.nomad").Get("/v1/jobs").MatchParam("meta", "true").Reply(http.StatusOK).JSON([]nomadJobListStub{
+		{ID: "cds-kyubi-w1", Status: "running", Meta: map[string]string{META_WORKER_MODEL: "model1"}},
+		{ID: "cds-kyubi-w2", Status: "pending", Meta: map[string]string{META_WORKER_MODEL: "model2"}},
+		{ID: "cds-kyubi-w3", Status: "dead", Meta: map[string]string{META_WORKER_MODEL: "model1"}},
+	})
+	assert.Equal(t, 1, h.WorkersStartedByModel(context.TODO(), m))
+	require.True(t, gock.IsDone())
+
+	// The meta are not returned by older versions of Nomad, each job is only loaded once
+	jobs := []nomadJobListStub{
+		{ID: "cds-kyubi-w1", Status: "running"},
+		{ID: "cds-kyubi-w2", Status: "running"},
+	}
+	gock.New("http://lolcat.nomad").Get("/v1/jobs").Times(2).Reply(http.StatusOK).JSON(jobs)
+	gock.New("http://lolcat.nomad").Get("/v1/job/cds-kyubi-w1").Reply(http.StatusOK).JSON(nomadJob{ID: "cds-kyubi-w1", Meta: map[string]string{META_WORKER_MODEL: "model1"}})
+	gock.New("http://lolcat.nomad").Get("/v1/job/cds-kyubi-w2").Reply(http.StatusOK).JSON(nomadJob{ID: "cds-kyubi-w2", Meta: map[string]string{META_WORKER_MODEL: "model1"}})
+	assert.Equal(t, 2, h.WorkersStartedByModel(context.TODO(), m))
+	assert.Equal(t, 2, h.WorkersStartedByModel(context.TODO(), m))
+	require.True(t, gock.IsDone())
+
+	// The meta of the removed jobs are forgotten
+	gock.New("http://lolcat.nomad").Get("/v1/jobs").Reply(http.StatusOK).JSON(jobs[:1])
+	assert.Equal(t, 1, h.WorkersStartedByModel(context.TODO(), m))
+	require.True(t, gock.IsDone())
+	assert.Len(t, h.jobsMeta.byID, 1)
+}
+
+func TestHatcheryNomad_SpawnWorker(t *testing.T) {
+	defer gock.Off()
+	h := NewHatcheryNomadTest(t)
+	h.Config.DefaultMemory = 1024
+
+	m := &sdk.Model{
+		Name:  "model1",
+		Group: &sdk.Group{Name: "group"},
+		ModelDocker: sdk.ModelDocker{
+			Image: "worker:latest",
+			Shell: "sh -c",
+			Cmd:   "worker --api={{.API}}",
+		},
+	}
+
+	var job nomadJob
+	var checkRequest gock.ObserverFunc = func(request *http.Request, mock gock.Mock) {
+		if request.Body == nil {
+			return
+		}
+		bodyContent, err := ioutil.ReadAll(request.Body)
+		require.NoError(t, err)
+		var body struct {
+			Job nomadJob `json:"Job"`
+		}
+		require.NoError(t, json.Unmarshal(bodyContent, &body))
+		job = body.Job
+	}
+	gock.Observe(checkRequest)
+	gock.New("http://lolcat.nomad").Post("/v1/jobs").Reply(http.StatusOK).JSON(map[string]string{"EvalID": "1"})
+
+	err := h.SpawnWorker(context.TODO(), hatchery.SpawnArguments{
+		JobID:      666,
+		Model:      m,
+		WorkerName: "my-worker",
+		Requirements: []sdk.Requirement{
+			{Type: sdk.MemoryRequirement, Value: "4096"},
+			{ID: 1, Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.5 POSTGRES_PASSWORD=pg CDS_SERVICE_MEMORY=512"},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, gock.IsDone())
+
+	assert.Equal(t, "cds-kyubi-my-worker", job.ID)
+	assert.Equal(t, "batch", job.Type)
+	assert.Equal(t, []string{"dc1"}, job.Datacenters)
+	assert.Equal(t, "model1", job.Meta[META_WORKER_MODEL])
+	assert.Equal(t, "666", job.Meta[META_JOB_ID])
+
+	require.Len(t, job.TaskGroups, 1)
+	group := job.TaskGroups[0]
+	assert.Equal(t, []nomadNetwork{{Mode: "bridge"}}, group.Networks)
+	require.Len(t, group.Tasks, 2)
+
+	worker := group.Tasks[0]
+	assert.True(t, worker.Leader)
+	assert.Equal(t, int64(4096), worker.Resources.MemoryMB)
+	assert.Equal(t, "worker:latest", worker.Config["image"])
+	assert.Equal(t, "sh", worker.Config["command"])
+	assert.Equal(t, []interface{}{"-c", "worker --api="}, worker.Config["args"])
+	assert.Equal(t, []interface{}{"worker:127.0.0.1", "pg:127.0.0.1"}, worker.Config["extra_hosts"])
+	assert.Equal(t, "666", worker.Env["CDS_BOOKED_WORKFLOW_JOB_ID"])
+
+	service := group.Tasks[1]
+	assert.Equal(t, "service-pg", service.Name)
+	assert.Equal(t, "postgres:9.5", service.Config["image"])
+	assert.Equal(t, int64(512), service.Resources.MemoryMB)
+	assert.Equal(t, map[string]string{"POSTGRES_PASSWORD": "pg"}, service.Env)
+}
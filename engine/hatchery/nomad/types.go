@@ -0,0 +1,57 @@
+package nomad
+
+import (
+	"sync"
+
+	"github.com/ovh/cds/engine/service"
+
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+)
+
+const (
+	META_HATCHERY_NAME = "CDS_HATCHERY_NAME"
+	META_WORKER        = "CDS_WORKER"
+	META_WORKER_MODEL  = "CDS_WORKER_MODEL"
+	META_MODEL_PATH    = "CDS_MODEL_PATH"
+	META_JOB_ID        = "CDS_JOB_ID"
+
+	// workerTaskName is the name of the task of the worker, services are the other tasks of the task group.
+	workerTaskName = "worker"
+	// serviceTaskPrefix prefixes the name of the tasks of the services, followed by the name of the service requirement.
+	serviceTaskPrefix = "service-"
+)
+
+// HatcheryConfiguration is the configuration for nomad hatchery
+type HatcheryConfiguration struct {
+	service.HatcheryCommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration" json:"commonConfiguration"`
+	// WorkerTTL Worker TTL (minutes)
+	WorkerTTL int `mapstructure:"workerTTL" toml:"workerTTL" default:"10" commented:"false" comment:"Worker TTL (minutes)" json:"workerTTL"`
+	// WorkerSpawnTimeout is the time after which a worker job still pending without allocation is purged (seconds)
+	WorkerSpawnTimeout int `mapstructure:"workerSpawnTimeout" toml:"workerSpawnTimeout" default:"600" commented:"false" comment:"Time after which a worker job still pending without allocation is purged (seconds)" json:"workerSpawnTimeout"`
+	// DefaultMemory Worker default memory
+	DefaultMemory int `mapstructure:"defaultMemory" toml:"defaultMemory" default:"1024" commented:"false" comment:"Worker default memory in Mo" json:"defaultMemory"`
+	// NomadAddress is the address of the nomad HTTP API
+	NomadAddress string `mapstructure:"nomadAddress" toml:"nomadAddress" default:"http://127.0.0.1:4646" commented:"false" comment:"Address of the Nomad HTTP API" json:"nomadAddress"`
+	// NomadToken is the ACL token used to submit the jobs
+	NomadToken string `mapstructure:"nomadToken" toml:"nomadToken" default:"" commented:"true" comment:"Nomad ACL token, needs the submit-job and read-job capabilities on the namespace" json:"-"`
+	// Namespace is the nomad namespace in which workers are spawned
+	Namespace string `mapstructure:"namespace" toml:"namespace" default:"default" commented:"false" comment:"Nomad namespace in which workers are spawned" json:"namespace"`
+	// Region is the nomad region in which workers are spawned
+	Region string `mapstructure:"region" toml:"region" default:"" commented:"true" comment:"Nomad region in which workers are spawned (default: region of the agent)" json:"region"`
+	// Datacenters are the nomad datacenters in which workers are spawned
+	Datacenters []string `mapstructure:"datacenters" toml:"datacenters" commented:"true" comment:"Nomad datacenters in which workers are spawned (default: [\"dc1\"])" json:"datacenters"`
+	// InsecureSkipVerifyTLS skips the verification of the certificate of the nomad API
+	InsecureSkipVerifyTLS bool `mapstructure:"insecureSkipVerifyTLS" toml:"insecureSkipVerifyTLS" default:"false" commented:"true" comment:"Skip the verification of the TLS certificate of the Nomad API" json:"insecureSkipVerifyTLS"`
+}
+
+// HatcheryNomad implements HatcheryMode interface for nomad usage
+type HatcheryNomad struct {
+	hatcheryCommon.Common
+	Config      HatcheryConfiguration
+	nomadClient *nomadClient
+	// jobsMeta caches the meta of the nomad jobs when they are not returned by the job list route
+	jobsMeta struct {
+		sync.Mutex
+		byID map[string]map[string]string
+	}
+}
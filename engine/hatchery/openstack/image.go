@@ -0,0 +1,183 @@
+package openstack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Images of worker models are built from the server spawned to register the model: the server boots on the base
+// image of the model, runs the pre command, the provisioning script and the worker commands, then shuts down.
+// A snapshot of the server is created and tagged with the version of the model, that is the date of the last
+// modification of the model by a user. Workers are spawned from the snapshot of the current version of their model
+// as soon as it is active, and the snapshots of previous versions are deleted when no server uses them anymore.
+
+const (
+	imageMetadataModelName    = "worker_model_name"
+	imageMetadataModelVersion = "worker_model_last_modified"
+	imageMetadataCreatedBy    = "created_by"
+)
+
+// modelVersion returns the version of the model used to tag its images.
+func modelVersion(m sdk.Model) string {
+	return fmt.Sprintf("%d", m.UserLastModified.Unix())
+}
+
+// modelImageName returns the name of the image built for the given version of a worker model.
+func modelImageName(modelName, version string) string {
+	return "cds_image_" + modelName + "_" + version
+}
+
+func (h *HatcheryOpenstack) imagesCreatedBy() string {
+	return "cdsHatchery_" + h.Name()
+}
+
+// modelImage returns the image built for the current version of the given model, if any.
+func modelImage(imgs []images.Image, m sdk.Model) (images.Image, bool) {
+	version := modelVersion(m)
+	for _, img := range imgs {
+		if imageMetadata(img, imageMetadataModelName) == m.Name && imageMetadata(img, imageMetadataModelVersion) == version {
+			return img, true
+		}
+	}
+	return images.Image{}, false
+}
+
+func imageMetadata(img images.Image, key string) string {
+	v, ok := img.Metadata[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// createModelImage creates a snapshot of the given registration server and tags it with the version of the worker model.
+// Images of previous versions of the model are garbage collected once the new image is active.
+func (h *HatcheryOpenstack) createModelImage(ctx context.Context, workerModelName, workerModelNameLastModified, serverID, model, flavor string) {
+	for _, img := range h.getImages(ctx) {
+		if imageMetadata(img, imageMetadataModelName) == workerModelName && imageMetadata(img, imageMetadataModelVersion) == workerModelNameLastModified {
+			// no need to recreate an image
+			return
+		}
+	}
+
+	log.Info(ctx, "createModelImage> create image before deleting server")
+	imageID, err := servers.CreateImage(h.openstackClient, serverID, servers.CreateImageOpts{
+		Name: modelImageName(workerModelName, workerModelNameLastModified),
+		Metadata: map[string]string{
+			imageMetadataModelName:    workerModelName,
+			"model":                   model,
+			"flavor":                  flavor,
+			imageMetadataCreatedBy:    h.imagesCreatedBy(),
+			imageMetadataModelVersion: workerModelNameLastModified,
+		},
+	}).ExtractImageID()
+	if err != nil {
+		log.Error(ctx, "createModelImage> error on create image for worker model %s: %s", workerModelName, err)
+		return
+	}
+
+	log.Info(ctx, "createModelImage> image %s created for worker model %s - waiting %ds for saving created img...", imageID, workerModelName, h.Config.CreateImageTimeout)
+
+	startTime := time.Now().Unix()
+	var newImageIsActive bool
+	for time.Now().Unix()-startTime < int64(h.Config.CreateImageTimeout) {
+		newImage, err := images.Get(h.openstackClient, imageID).Extract()
+		if err != nil {
+			log.Error(ctx, "createModelImage> error on get new image %s for worker model %s: %s", imageID, workerModelName, err)
+		} else if newImage.Status == "ACTIVE" {
+			// new image is created, end wait
+			log.Info(ctx, "createModelImage> image %s created for worker model %s is active", imageID, workerModelName)
+			newImageIsActive = true
+			break
+		}
+		time.Sleep(15 * time.Second)
+	}
+
+	if !newImageIsActive {
+		log.Info(ctx, "createModelImage> timeout while creating new image. Deleting new image for %s with ID %s", workerModelName, imageID)
+		if err := images.Delete(h.openstackClient, imageID).ExtractErr(); err != nil {
+			log.Error(ctx, "createModelImage> error while deleting new image %s", imageID)
+		}
+		return
+	}
+
+	// the model rolls over to the new image as soon as it is in the cache of images
+	h.resetImagesCache()
+	h.killOldImages(ctx)
+}
+
+// killOldImages deletes the images created by the hatchery for previous versions of worker models,
+// except the last ones kept by configuration and the ones used by a server.
+func (h *HatcheryOpenstack) killOldImages(ctx context.Context) {
+	used := map[string]struct{}{}
+	for _, s := range h.getServers(ctx) {
+		if id, ok := s.Image["id"].(string); ok {
+			used[id] = struct{}{}
+		}
+	}
+
+	toDelete := oldImages(h.getImages(ctx), used, h.imagesCreatedBy(), h.Config.KeepOldImages)
+	for _, img := range toDelete {
+		log.Info(ctx, "killOldImages> deleting image %s of worker model %s version %s", img.ID, imageMetadata(img, imageMetadataModelName), imageMetadata(img, imageMetadataModelVersion))
+		if err := images.Delete(h.openstackClient, img.ID).ExtractErr(); err != nil {
+			log.Error(ctx, "killOldImages> error while deleting old image %s: %v", img.ID, err)
+		}
+	}
+	if len(toDelete) > 0 {
+		h.resetImagesCache()
+	}
+}
+
+// oldImages returns the images created by the given hatchery that can be garbage collected: for each worker model,
+// the image of the last version and the keep images of previous versions are kept, as well as the used images.
+func oldImages(imgs []images.Image, used map[string]struct{}, createdBy string, keep int) []images.Image {
+	if keep < 0 {
+		keep = 0
+	}
+
+	var modelNames []string
+	imagesByModel := map[string][]images.Image{}
+	for _, img := range imgs {
+		if imageMetadata(img, imageMetadataCreatedBy) != createdBy {
+			continue
+		}
+		name := imageMetadata(img, imageMetadataModelName)
+		if name == "" {
+			continue
+		}
+		if _, ok := imagesByModel[name]; !ok {
+			modelNames = append(modelNames, name)
+		}
+		imagesByModel[name] = append(imagesByModel[name], img)
+	}
+
+	var res []images.Image
+	for _, name := range modelNames {
+		modelImages := imagesByModel[name]
+		// sort images from the last version to the oldest one
+		sort.SliceStable(modelImages, func(i, j int) bool {
+			vi, _ := strconv.ParseInt(imageMetadata(modelImages[i], imageMetadataModelVersion), 10, 64)
+			vj, _ := strconv.ParseInt(imageMetadata(modelImages[j], imageMetadataModelVersion), 10, 64)
+			return vi > vj
+		})
+		for i, img := range modelImages {
+			if i <= keep {
+				continue
+			}
+			if _, ok := used[img.ID]; ok {
+				continue
+			}
+			res = append(res, img)
+		}
+	}
+	return res
+}
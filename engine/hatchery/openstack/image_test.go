@@ -0,0 +1,65 @@
+package openstack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/images"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestModelImage(t *testing.T) {
+	m := sdk.Model{Name: "my-model", UserLastModified: time.Unix(200, 0)}
+	imgs := []images.Image{
+		{ID: "1", Metadata: map[string]interface{}{"worker_model_name": "my-model", "worker_model_last_modified": "100"}},
+		{ID: "2", Metadata: map[string]interface{}{"worker_model_name": "other-model", "worker_model_last_modified": "200"}},
+		{ID: "3", Metadata: map[string]interface{}{"worker_model_name": "my-model", "worker_model_last_modified": "200"}},
+		{ID: "4", Name: "Debian 10"},
+	}
+
+	img, ok := modelImage(imgs, m)
+	require.True(t, ok)
+	require.Equal(t, "3", img.ID)
+
+	m.UserLastModified = time.Unix(300, 0)
+	_, ok = modelImage(imgs, m)
+	require.False(t, ok, "the model must not use an image of a previous version")
+}
+
+func TestOldImages(t *testing.T) {
+	img := func(id, model, version, createdBy string) images.Image {
+		return images.Image{ID: id, Metadata: map[string]interface{}{
+			"worker_model_name":          model,
+			"worker_model_last_modified": version,
+			"created_by":                 createdBy,
+		}}
+	}
+	imgs := []images.Image{
+		img("a1", "model-a", "100", "cdsHatchery_my-hatchery"),
+		img("a3", "model-a", "300", "cdsHatchery_my-hatchery"),
+		img("a2", "model-a", "200", "cdsHatchery_my-hatchery"),
+		img("a4", "model-a", "400", "cdsHatchery_my-hatchery"),
+		img("b1", "model-b", "100", "cdsHatchery_my-hatchery"),
+		img("c1", "model-c", "100", "cdsHatchery_another-hatchery"),
+		img("c2", "model-c", "200", "cdsHatchery_another-hatchery"),
+		{ID: "base", Name: "Debian 10"},
+	}
+
+	// keep the last image of each model only
+	res := oldImages(imgs, nil, "cdsHatchery_my-hatchery", 0)
+	var ids []string
+	for _, i := range res {
+		ids = append(ids, i.ID)
+	}
+	require.Equal(t, []string{"a3", "a2", "a1"}, ids)
+
+	// keep the image of the previous version, and the images used by servers
+	res = oldImages(imgs, map[string]struct{}{"a1": {}}, "cdsHatchery_my-hatchery", 1)
+	ids = nil
+	for _, i := range res {
+		ids = append(ids, i.ID)
+	}
+	require.Equal(t, []string{"a2"}, ids)
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/ovh/cds/engine/service"
 
-	"github.com/gophercloud/gophercloud/openstack/compute/v2/images"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gorilla/mux"
 
@@ -167,6 +166,9 @@ func (h *HatcheryOpenstack) CanSpawn(ctx context.Context, model *sdk.Model, jobI
 	}
 
 	for _, r := range requirements {
+		if r.Type == sdk.ServiceRequirement && h.Config.Provision.LocalServices {
+			continue
+		}
 		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement || r.Type == sdk.HostnameRequirement {
 			return false
 		}
@@ -179,6 +181,7 @@ func (h *HatcheryOpenstack) main(ctx context.Context) {
 	killAwolServersTick := time.NewTicker(30 * time.Second).C
 	killErrorServersTick := time.NewTicker(60 * time.Second).C
 	killDisabledWorkersTick := time.NewTicker(60 * time.Second).C
+	killOldImagesTick := time.NewTicker(10 * time.Minute).C
 
 	for {
 		select {
@@ -190,6 +193,10 @@ func (h *HatcheryOpenstack) main(ctx context.Context) {
 			h.killErrorServers(ctx)
 		case <-killDisabledWorkersTick:
 			h.killDisabledWorkers()
+		case <-killOldImagesTick:
+			if !h.Config.DisableCreateImage {
+				h.killOldImages(ctx)
+			}
 		}
 	}
 }
@@ -283,7 +290,7 @@ func (h *HatcheryOpenstack) killAwolServers(ctx context.Context) {
 			// check if we need to create a new openstack image from it
 			// by comparing userDateLastModified from worker model
 			if !h.Config.DisableCreateImage && s.Status == "SHUTOFF" && registerOnly == "true" {
-				h.killAwolServersComputeImage(ctx, workerModelName, workerModelNameLastModified, s.ID, model, flavor)
+				h.createModelImage(ctx, workerModelName, workerModelNameLastModified, s.ID, model, flavor)
 			}
 
 			log.Debug("killAwolServers> Deleting server %s status: %s last update: %s registerOnly:%s toDeleteKilled:%t inWorkersList:%t", s.Name, s.Status, time.Since(s.Updated), registerOnly, toDeleteKilled, inWorkersList)
@@ -303,68 +310,6 @@ func (h *HatcheryOpenstack) killAwolServers(ctx context.Context) {
 	log.Debug("killAwolServers> workersAlive: %+v", workersAlive)
 }
 
-func (h *HatcheryOpenstack) killAwolServersComputeImage(ctx context.Context, workerModelName, workerModelNameLastModified, serverID, model, flavor string) {
-	oldImagesID := []string{}
-	for _, img := range h.getImages(ctx) {
-		if w := img.Metadata["worker_model_name"]; w == workerModelName {
-			oldImagesID = append(oldImagesID, img.ID)
-			if d, ok := img.Metadata["worker_model_last_modified"]; ok && d.(string) == workerModelNameLastModified {
-				// no need to recreate an image
-				return
-			}
-		}
-	}
-
-	log.Info(ctx, "killAwolServersComputeImage> create image before deleting server")
-	imageID, err := servers.CreateImage(h.openstackClient, serverID, servers.CreateImageOpts{
-		Name: "cds_image_" + workerModelName,
-		Metadata: map[string]string{
-			"worker_model_name":          workerModelName,
-			"model":                      model,
-			"flavor":                     flavor,
-			"created_by":                 "cdsHatchery_" + h.Name(),
-			"worker_model_last_modified": workerModelNameLastModified,
-		},
-	}).ExtractImageID()
-	if err != nil {
-		log.Error(ctx, "killAwolServersComputeImage> error on create image for worker model %s: %s", workerModelName, err)
-	} else {
-		log.Info(ctx, "killAwolServersComputeImage> image %s created for worker model %s - waiting %ds for saving created img...", imageID, workerModelName, h.Config.CreateImageTimeout)
-
-		startTime := time.Now().Unix()
-		var newImageIsActive bool
-		for time.Now().Unix()-startTime < int64(h.Config.CreateImageTimeout) {
-			newImage, err := images.Get(h.openstackClient, imageID).Extract()
-			if err != nil {
-				log.Error(ctx, "killAwolServersComputeImage> error on get new image %s for worker model %s: %s", imageID, workerModelName, err)
-			}
-			if newImage.Status == "ACTIVE" {
-				// new image is created, end wait
-				log.Info(ctx, "killAwolServersComputeImage> image %s created for worker model %s is active", imageID, workerModelName)
-				newImageIsActive = true
-				break
-			}
-			time.Sleep(15 * time.Second)
-		}
-
-		if !newImageIsActive {
-			log.Info(ctx, "killAwolServersComputeImage> timeout while creating new image. Deleting new image for %s with ID %s", workerModelName, imageID)
-			if err := images.Delete(h.openstackClient, imageID).ExtractErr(); err != nil {
-				log.Error(ctx, "killAwolServersComputeImage> error while deleting new image %s", imageID)
-			}
-		}
-
-		for _, oldImageID := range oldImagesID {
-			log.Info(ctx, "killAwolServersComputeImage> deleting old image for %s with ID %s", workerModelName, oldImageID)
-			if err := images.Delete(h.openstackClient, oldImageID).ExtractErr(); err != nil {
-				log.Error(ctx, "killAwolServersComputeImage> error while deleting old image %s", oldImageID)
-			}
-		}
-
-		h.resetImagesCache()
-	}
-}
-
 func (h *HatcheryOpenstack) killErrorServers(ctx context.Context) {
 	for _, s := range h.getServers(ctx) {
 		//Remove server without IP Address
@@ -66,3 +66,22 @@ func TestHatcheryOpenstack_CanSpawn(t *testing.T) {
 	canSpawn = h.CanSpawn(context.TODO(), m, 1, nil)
 	require.True(t, canSpawn)
 }
+
+func TestUserData(t *testing.T) {
+	m := sdk.ModelVirtualMachine{
+		PreCmd:    "export CDS_API={{.API}}",
+		Provision: "docker run --format '{{.ID}}' {{ not a template",
+		Cmd:       "./worker --name={{.Name}}",
+		PostCmd:   "sudo shutdown -h now",
+	}
+	params := sdk.WorkerArgs{API: "http://cds.api", Name: "my-worker"}
+
+	udata, err := userData(m, true, params)
+	require.NoError(t, err)
+	require.Equal(t, "export CDS_API=http://cds.api\ndocker run --format '{{.ID}}' {{ not a template\n./worker --name=my-worker\nsudo shutdown -h now", string(udata))
+
+	// the provisioning script is not run on the image of the model
+	udata, err = userData(m, false, params)
+	require.NoError(t, err)
+	require.Equal(t, "export CDS_API=http://cds.api\n./worker --name=my-worker\nsudo shutdown -h now", string(udata))
+}
@@ -25,8 +25,8 @@ func (h *HatcheryOpenstack) SpawnWorker(ctx context.Context, spawnArgs hatchery.
 		log.Debug("spawnWorker> spawning worker %s model:%s", spawnArgs.WorkerName, spawnArgs.Model.Name)
 	}
 
-	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly {
-		return sdk.WithStack(fmt.Errorf("no job ID and no register"))
+	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly && !spawnArgs.Warm {
+		return sdk.WithStack(fmt.Errorf("no job ID, no register and not a warm worker"))
 	}
 
 	if len(h.getServers(ctx)) == h.Configuration().Provision.MaxWorker {
@@ -51,14 +51,9 @@ func (h *HatcheryOpenstack) SpawnWorker(ctx context.Context, spawnArgs hatchery.
 		start := time.Now()
 		imgs := h.getImages(ctx)
 		log.Debug("spawnWorker> call images.List on openstack took %fs, nbImages:%d", time.Since(start).Seconds(), len(imgs))
-		for _, img := range imgs {
-			workerModelName := img.Metadata["worker_model_name"]
-			workerModelLastModified := img.Metadata["worker_model_last_modified"]
-			if workerModelName == spawnArgs.Model.Name && fmt.Sprintf("%s", workerModelLastModified) == fmt.Sprintf("%d", spawnArgs.Model.UserLastModified.Unix()) {
-				withExistingImage = true
-				imageID = img.ID
-				break
-			}
+		if img, ok := modelImage(imgs, *spawnArgs.Model); ok {
+			withExistingImage = true
+			imageID = img.ID
 		}
 	}
 
@@ -66,12 +61,6 @@ func (h *HatcheryOpenstack) SpawnWorker(ctx context.Context, spawnArgs hatchery.
 		spawnArgs.Model.ModelVirtualMachine.Cmd += " register"
 	}
 
-	udata := spawnArgs.Model.ModelVirtualMachine.PreCmd + "\n" + spawnArgs.Model.ModelVirtualMachine.Cmd + "\n" + spawnArgs.Model.ModelVirtualMachine.PostCmd
-
-	tmpl, errt := template.New("udata").Parse(udata)
-	if errt != nil {
-		return errt
-	}
 	udataParam := sdk.WorkerArgs{
 		API:               h.Configuration().API.HTTP.URL,
 		Name:              spawnArgs.WorkerName,
@@ -88,13 +77,14 @@ func (h *HatcheryOpenstack) SpawnWorker(ctx context.Context, spawnArgs hatchery.
 
 	udataParam.WorkflowJobID = spawnArgs.JobID
 
-	var buffer bytes.Buffer
-	if err := tmpl.Execute(&buffer, udataParam); err != nil {
+	// the provisioning script is already in the image of the model, if it was built
+	udata, err := userData(spawnArgs.Model.ModelVirtualMachine, !withExistingImage, udataParam)
+	if err != nil {
 		return err
 	}
 
 	// Encode again
-	udata64 := base64.StdEncoding.EncodeToString(buffer.Bytes())
+	udata64 := base64.StdEncoding.EncodeToString(udata)
 
 	// Create openstack vm
 	meta := map[string]string{
@@ -105,7 +95,7 @@ func (h *HatcheryOpenstack) SpawnWorker(ctx context.Context, spawnArgs hatchery.
 		"model":                      spawnArgs.Model.ModelVirtualMachine.Image,
 		"worker_model_path":          spawnArgs.Model.Group.Name + "/" + spawnArgs.Model.Name,
 		"worker_model_name":          spawnArgs.Model.Name,
-		"worker_model_last_modified": fmt.Sprintf("%d", spawnArgs.Model.UserLastModified.Unix()),
+		"worker_model_last_modified": modelVersion(*spawnArgs.Model),
 	}
 
 	maxTries := 3
@@ -144,3 +134,27 @@ func (h *HatcheryOpenstack) SpawnWorker(ctx context.Context, spawnArgs hatchery.
 	}
 	return nil
 }
+
+// userData returns the script run by the server: the commands of the model are templates executed with the worker
+// arguments, the provisioning script is added as is between the pre command and the worker command.
+func userData(m sdk.ModelVirtualMachine, withProvision bool, params sdk.WorkerArgs) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := executeUserData(&buffer, m.PreCmd+"\n", params); err != nil {
+		return nil, err
+	}
+	if withProvision && m.Provision != "" {
+		buffer.WriteString(m.Provision + "\n")
+	}
+	if err := executeUserData(&buffer, m.Cmd+"\n"+m.PostCmd, params); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func executeUserData(buffer *bytes.Buffer, udata string, params sdk.WorkerArgs) error {
+	tmpl, err := template.New("udata").Parse(udata)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	return sdk.WithStack(tmpl.Execute(buffer, params))
+}
@@ -47,6 +47,9 @@ type HatcheryConfiguration struct {
 
 	// CreateImageTimeout max wait for create an openstack image (in seconds)
 	CreateImageTimeout int `mapstructure:"createImageTimeout" toml:"createImageTimeout" default:"180" commented:"false" comment:"max wait for create an openstack image (in seconds)" json:"createImageTimeout"`
+
+	// KeepOldImages number of images of previous versions of a worker model kept when a new image is created
+	KeepOldImages int `mapstructure:"keepOldImages" toml:"keepOldImages" default:"1" commented:"false" comment:"number of images of previous versions of a worker model kept when a new image is created. Older images are deleted when no server uses them" json:"keepOldImages"`
 }
 
 // HatcheryOpenstack spawns instances of worker model with type 'ISO'
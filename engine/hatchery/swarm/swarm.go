@@ -51,10 +51,15 @@ func (h *HatcherySwarm) InitHatchery(ctx context.Context) error {
 		}
 		ctxDocker, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
-		if _, errPing := d.Ping(ctxDocker); errPing != nil {
+		ping, errPing := d.Ping(ctxDocker)
+		if errPing != nil {
 			log.Error(ctx, "hatchery> swarm> unable to ping docker host:%s", errPing)
 			return errPing
 		}
+		// Use the API version of the engine if it's older than the client one (ie. Podman), unless DOCKER_API_VERSION is set
+		if os.Getenv("DOCKER_API_VERSION") == "" {
+			d.NegotiateAPIVersionPing(ping)
+		}
 		h.dockerClients["default"] = &dockerClient{
 			Client:        *d,
 			MaxContainers: h.Config.MaxContainers,
@@ -146,6 +151,9 @@ func (h *HatcherySwarm) InitHatchery(ctx context.Context) error {
 			var opts = []func(*docker.Client) error{docker.WithHost(cfg.Host), docker.WithVersion(cfg.APIVersion), docker.WithHTTPClient(httpClient)}
 			if strings.HasPrefix(cfg.Host, "unix:///") {
 				opts = []func(*docker.Client) error{docker.WithHost(cfg.Host)}
+				if cfg.APIVersion != "" {
+					opts = append(opts, docker.WithVersion(cfg.APIVersion))
+				}
 			}
 
 			d, errc := docker.NewClientWithOpts(opts...)
@@ -155,11 +163,16 @@ func (h *HatcherySwarm) InitHatchery(ctx context.Context) error {
 			}
 			ctxDocker, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer cancel()
-			if _, errPing := d.Ping(ctxDocker); errPing != nil {
+			ping, errPing := d.Ping(ctxDocker)
+			if errPing != nil {
 				log.Error(ctx, "hatchery> swarm> unable to ping docker host:%s", errPing)
 				continue
 			}
-			log.Info(ctx, "hatchery> swarm> connected to %s (%s)", hostName, cfg.Host)
+			// An explicit API version is never overridden
+			if cfg.APIVersion == "" {
+				d.NegotiateAPIVersionPing(ping)
+			}
+			log.Info(ctx, "hatchery> swarm> connected to %s (%s) with API version %s", hostName, cfg.Host, d.ClientVersion())
 
 			h.dockerClients[hostName] = &dockerClient{
 				Client:        *d,
@@ -185,8 +198,8 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 	ctx, end := observability.Span(ctx, "swarm.SpawnWorker")
 	defer end()
 
-	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly {
-		return sdk.WithStack(fmt.Errorf("unable to spawn worker, no Job ID and no Register."))
+	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly && !spawnArgs.Warm {
+		return sdk.WithStack(fmt.Errorf("unable to spawn worker, no Job ID, no Register and not a warm worker."))
 	}
 
 	observability.Current(ctx, observability.Tag(observability.TagWorker, spawnArgs.WorkerName))
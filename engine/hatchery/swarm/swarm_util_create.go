@@ -34,6 +34,7 @@ func (h *HatcherySwarm) createNetwork(ctx context.Context, dockerClient *dockerC
 		},
 		Labels: map[string]string{
 			"worker_net": name,
+			"hatchery":   h.Config.Name,
 		},
 	})
 	return err
@@ -78,16 +79,7 @@ func (h *HatcherySwarm) createAndStartContainer(ctx context.Context, dockerClien
 		config.Entrypoint = cArgs.entryPoint
 	}
 
-	hostConfig := &container.HostConfig{
-		PortBindings: cArgs.dockerOpts.ports,
-		Privileged:   cArgs.dockerOpts.privileged,
-		Mounts:       cArgs.dockerOpts.mounts,
-		ExtraHosts:   cArgs.dockerOpts.extraHosts,
-	}
-	hostConfig.Resources = container.Resources{
-		Memory:     cArgs.memory * 1024 * 1024, //from MB to B
-		MemorySwap: -1,
-	}
+	hostConfig := h.computeHostConfig(cArgs)
 
 	networkingConfig := &network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{},
@@ -165,6 +157,29 @@ checkImage:
 	return nil
 }
 
+// computeHostConfig returns the host config of a container, its cgroup limits are computed from the memory
+// of the container and the isolation settings of the hatchery.
+func (h *HatcherySwarm) computeHostConfig(cArgs containerArgs) *container.HostConfig {
+	hostConfig := &container.HostConfig{
+		PortBindings: cArgs.dockerOpts.ports,
+		Privileged:   cArgs.dockerOpts.privileged,
+		Mounts:       cArgs.dockerOpts.mounts,
+		ExtraHosts:   cArgs.dockerOpts.extraHosts,
+		UsernsMode:   container.UsernsMode(h.Config.UsernsMode),
+	}
+
+	memory := cArgs.memory * 1024 * 1024 //from MB to B
+	hostConfig.Resources = container.Resources{
+		Memory:     memory,
+		MemorySwap: -1,
+		PidsLimit:  h.Config.PidsLimit,
+	}
+	if h.Config.DisableMemorySwap {
+		hostConfig.Resources.MemorySwap = memory
+	}
+	return hostConfig
+}
+
 var regexPort = regexp.MustCompile("^--port=(.*):(.*)$")
 
 type dockerOpts struct {
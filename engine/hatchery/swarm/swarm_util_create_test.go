@@ -256,3 +256,22 @@ func getContainer(dockerClient *dockerClient, containers []types.Container, name
 
 	return nil, nil
 }
+
+func TestHatcherySwarm_computeHostConfig(t *testing.T) {
+	h := &HatcherySwarm{}
+	hostConfig := h.computeHostConfig(containerArgs{memory: 512})
+	require.Equal(t, int64(512*1024*1024), hostConfig.Memory)
+	require.Equal(t, int64(-1), hostConfig.MemorySwap)
+	require.Equal(t, int64(0), hostConfig.PidsLimit)
+	require.Equal(t, "", string(hostConfig.UsernsMode))
+
+	h.Config.UsernsMode = "auto"
+	h.Config.PidsLimit = 1024
+	h.Config.DisableMemorySwap = true
+	hostConfig = h.computeHostConfig(containerArgs{memory: 4096, dockerOpts: dockerOpts{privileged: true}})
+	require.Equal(t, int64(4096*1024*1024), hostConfig.Memory)
+	require.Equal(t, hostConfig.Memory, hostConfig.MemorySwap, "swap should be disabled")
+	require.Equal(t, int64(1024), hostConfig.PidsLimit)
+	require.Equal(t, "auto", string(hostConfig.UsernsMode))
+	require.True(t, hostConfig.Privileged)
+}
@@ -3,10 +3,12 @@ package swarm
 import (
 	"fmt"
 	"io/ioutil"
+	"regexp"
 	"strings"
 	"time"
 
 	types "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
 	context "golang.org/x/net/context"
 
 	"github.com/ovh/cds/sdk"
@@ -19,6 +21,34 @@ const (
 	docker0 = "docker0"
 )
 
+// anonymous volumes are named with a random 64 hex chars ID by Docker and Podman
+var regexAnonymousVolume = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// isWorkerNetwork checks that a network was created by this hatchery for the services of a worker
+func (h *HatcherySwarm) isWorkerNetwork(n types.NetworkResource) bool {
+	if n.Driver != bridge || n.Name == docker0 || n.Name == bridge {
+		return false
+	}
+	if _, ok := n.Labels["worker_net"]; !ok {
+		return false
+	}
+	// networks created before the hatchery label was set are considered as owned by any hatchery
+	if hatch, ok := n.Labels["hatchery"]; ok && hatch != h.Config.Name {
+		return false
+	}
+	return true
+}
+
+func anonymousVolumes(mounts []types.MountPoint) []string {
+	var res []string
+	for _, m := range mounts {
+		if m.Type == mount.TypeVolume && regexAnonymousVolume.MatchString(m.Name) {
+			res = append(res, m.Name)
+		}
+	}
+	return res
+}
+
 func (h *HatcherySwarm) killAndRemove(ctx context.Context, dockerClient *dockerClient, ID string) error {
 	ctxList, cancelList := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancelList()
@@ -94,18 +124,16 @@ func (h *HatcherySwarm) killAndRemove(ctx context.Context, dockerClient *dockerC
 			continue
 		}
 
-		//If it's the default docker bridge... skip
-		if network.Driver != bridge || network.Name == docker0 || network.Name == bridge {
+		//If it's the default bridge or a network that was not created for the worker... skip
+		if !h.isWorkerNetwork(network) {
 			continue
 		}
 
 		// If we succeed to get the network, kill and remove all the container on the network
-		if netname, ok := network.Labels["worker_net"]; ok {
-			log.Debug("hatchery> swarm> killAndRemove> Remove network %s", netname)
-			for id := range network.Containers {
-				if err := h.killAndRemoveContainer(ctx, dockerClient, id); err != nil {
-					log.Error(ctx, "hatchery> swarm> killAndRemove> unable to kill and remove container %s on %s err:%s", id[:12], dockerClient.name, err)
-				}
+		log.Debug("hatchery> swarm> killAndRemove> Remove network %s", network.Labels["worker_net"])
+		for id := range network.Containers {
+			if err := h.killAndRemoveContainer(ctx, dockerClient, id); err != nil {
+				log.Error(ctx, "hatchery> swarm> killAndRemove> unable to kill and remove container %s on %s err:%s", id[:12], dockerClient.name, err)
 			}
 		}
 
@@ -122,6 +150,15 @@ func (h *HatcherySwarm) killAndRemove(ctx context.Context, dockerClient *dockerC
 
 func (h *HatcherySwarm) killAndRemoveContainer(ctx context.Context, dockerClient *dockerClient, ID string) error {
 	log.Debug("hatchery> swarm> killAndRemove> remove container %s on %s", ID, dockerClient.name)
+
+	// Get the anonymous volumes of the container to remove them if the engine keeps them with the container
+	var volumes []string
+	ctxInspect, cancelInspect := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancelInspect()
+	if c, err := dockerClient.ContainerInspect(ctxInspect, ID); err == nil {
+		volumes = anonymousVolumes(c.Mounts)
+	}
+
 	ctxDocker, cancelList := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancelList()
 	if err := dockerClient.ContainerKill(ctxDocker, ID, "SIGKILL"); err != nil {
@@ -139,6 +176,15 @@ func (h *HatcherySwarm) killAndRemoveContainer(ctx context.Context, dockerClient
 		}
 	}
 
+	for _, v := range volumes {
+		ctxVolume, cancelVolume := context.WithTimeout(context.Background(), 10*time.Second)
+		err := dockerClient.VolumeRemove(ctxVolume, v, true)
+		cancelVolume()
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "no such volume") {
+			log.Error(ctx, "Unable to remove volume %s from %s: %v", v, dockerClient.name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -162,11 +208,7 @@ func (h *HatcherySwarm) killAwolNetworks(ctx context.Context) error {
 				continue
 			}
 
-			if n.Driver != bridge || n.Name == docker0 || n.Name == bridge {
-				continue
-			}
-
-			if _, ok := n.Labels["worker_net"]; !ok {
+			if !h.isWorkerNetwork(n) {
 				continue
 			}
 
@@ -3,8 +3,12 @@ package swarm
 import (
 	"testing"
 
-	"github.com/ovh/cds/engine/api/test"
+	types "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
 	context "golang.org/x/net/context"
+
+	"github.com/ovh/cds/engine/api/test"
 )
 
 func TestHatcherySwarm_killAwolNetworks(t *testing.T) {
@@ -12,3 +16,24 @@ func TestHatcherySwarm_killAwolNetworks(t *testing.T) {
 	err := h.killAwolNetworks(context.Background())
 	test.NoError(t, err)
 }
+
+func TestHatcherySwarm_isWorkerNetwork(t *testing.T) {
+	h := &HatcherySwarm{}
+	h.Config.Name = "swarmy"
+
+	assert.False(t, h.isWorkerNetwork(types.NetworkResource{Name: bridge, Driver: bridge}))
+	assert.False(t, h.isWorkerNetwork(types.NetworkResource{Name: "podman", Driver: bridge}), "default Podman network should be kept")
+	assert.False(t, h.isWorkerNetwork(types.NetworkResource{Name: "w1-net", Driver: bridge, Labels: map[string]string{"worker_net": "w1-net", "hatchery": "another"}}))
+	assert.True(t, h.isWorkerNetwork(types.NetworkResource{Name: "w1-net", Driver: bridge, Labels: map[string]string{"worker_net": "w1-net", "hatchery": "swarmy"}}))
+	assert.True(t, h.isWorkerNetwork(types.NetworkResource{Name: "w2-net", Driver: bridge, Labels: map[string]string{"worker_net": "w2-net"}}))
+}
+
+func Test_anonymousVolumes(t *testing.T) {
+	anonymous := "4f1c2a9b8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a"
+	vs := anonymousVolumes([]types.MountPoint{
+		{Type: mount.TypeBind, Source: "/tmp"},
+		{Type: mount.TypeVolume, Name: "m2-cache"},
+		{Type: mount.TypeVolume, Name: anonymous},
+	})
+	assert.Equal(t, []string{anonymous}, vs)
+}
@@ -29,6 +29,15 @@ type HatcheryConfiguration struct {
 	// NetworkEnableIPv6 if true: set ipv6 to true
 	NetworkEnableIPv6 bool `mapstructure:"networkEnableIPv6" toml:"networkEnableIPv6" default:"false" commented:"false" comment:"if true: hatchery creates private network between services with ipv6 enabled" json:"networkEnableIPv6"`
 
+	// UsernsMode user namespace of the containers
+	UsernsMode string `mapstructure:"usernsMode" toml:"usernsMode" default:"" commented:"true" comment:"User namespace mode of the workers and services containers. With Podman, \"auto\" runs each container in its own user namespace. With Docker, let it empty to use the userns-remap setting of the daemon or use \"host\" to disable it" json:"usernsMode,omitempty"`
+
+	// PidsLimit max number of processes in a container
+	PidsLimit int64 `mapstructure:"pidsLimit" toml:"pidsLimit" default:"0" commented:"true" comment:"Max number of processes in each container, 0 means no limit" json:"pidsLimit"`
+
+	// DisableMemorySwap if true, the memory limit of a container also applies to memory+swap
+	DisableMemorySwap bool `mapstructure:"disableMemorySwap" toml:"disableMemorySwap" default:"false" commented:"true" comment:"if true: containers can't use swap, their memory is strictly limited to the memory requirement of the job" json:"disableMemorySwap"`
+
 	DockerEngines map[string]DockerEngineConfiguration `mapstructure:"dockerEngines" toml:"dockerEngines" comment:"List of Docker Engines" json:"dockerEngines,omitempty"`
 }
 
@@ -53,6 +62,6 @@ type DockerEngineConfiguration struct {
 	TLSCAPEM              string `mapstructure:"TLSCAPEM" toml:"TLSCAPEM" comment:"content of your ca.pem" json:"-"`
 	TLSCERTPEM            string `mapstructure:"TLSCERTPEM" toml:"TLSCERTPEM" comment:"content of your cert.pem" json:"-"`
 	TLSKEYPEM             string `mapstructure:"TLSKEYPEM" toml:"TLSKEYPEM" comment:"content of your key.pem" json:"-"`
-	APIVersion            string `mapstructure:"APIVersion" toml:"APIVersion" comment:"DOCKER_API_VERSION" json:"APIVersion"` // DOCKER_API_VERSION
+	APIVersion            string `mapstructure:"APIVersion" toml:"APIVersion" comment:"DOCKER_API_VERSION, negotiated with the engine if empty (useful with Podman)" json:"APIVersion"` // DOCKER_API_VERSION
 	MaxContainers         int    `mapstructure:"maxContainers" toml:"maxContainers" default:"10" commented:"false" comment:"Max Containers on Host managed by this Hatchery" json:"maxContainers"`
 }
@@ -28,19 +28,39 @@ type annotation struct {
 	Created                 time.Time `json:"created"`
 }
 
+// modelBuildTimeout is the time after which the VM building the template of a worker model is considered lost.
+const modelBuildTimeout = time.Hour
+
+// modelVersion returns the version of the model used to tag its template.
+func modelVersion(m sdk.Model) string {
+	return fmt.Sprintf("%d", m.UserLastModified.Unix())
+}
+
 // SpawnWorker creates a new vm instance
 func (h *HatcheryVSphere) SpawnWorker(ctx context.Context, spawnArgs hatchery.SpawnArguments) error {
-	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly {
-		return sdk.WithStack(fmt.Errorf("no job ID and no register"))
+	if spawnArgs.JobID == 0 && !spawnArgs.RegisterOnly && !spawnArgs.Warm {
+		return sdk.WithStack(fmt.Errorf("no job ID, no register and not a warm worker"))
 	}
 
 	var vm *object.VirtualMachine
 	var errV error
-	_, errM := h.getModelByName(ctx, spawnArgs.Model.Name)
+	modelVM, errM := h.getModelByName(ctx, spawnArgs.Model.Name)
+
+	// The template is built again for a new version of the model when it is registered
+	var outdated bool
+	if errM == nil && spawnArgs.RegisterOnly {
+		var annot annotation
+		outdated = modelVM.Config == nil || json.Unmarshal([]byte(modelVM.Config.Annotation), &annot) != nil ||
+			annot.WorkerModelLastModified != modelVersion(*spawnArgs.Model)
+	}
 
-	if errM != nil || spawnArgs.Model.NeedRegistration {
+	if errM != nil || spawnArgs.Model.NeedRegistration || outdated {
 		// Generate worker model vm
 		vm, errV = h.createVMModel(*spawnArgs.Model)
+		// the registration must not succeed with the template of a previous version
+		if errV != nil && outdated {
+			return sdk.WrapError(errV, "cannot build the template of model %s", spawnArgs.Model.Name)
+		}
 	}
 
 	if vm == nil || errV != nil {
@@ -54,7 +74,7 @@ func (h *HatcheryVSphere) SpawnWorker(ctx context.Context, spawnArgs hatchery.Sp
 		HatcheryName:            h.Name(),
 		WorkerName:              spawnArgs.WorkerName,
 		RegisterOnly:            spawnArgs.RegisterOnly,
-		WorkerModelLastModified: fmt.Sprintf("%d", spawnArgs.Model.UserLastModified.Unix()),
+		WorkerModelLastModified: modelVersion(*spawnArgs.Model),
 		WorkerModelName:         spawnArgs.ModelName(),
 		Created:                 time.Now(),
 	}
@@ -91,7 +111,7 @@ func (h *HatcheryVSphere) createVMModel(model sdk.Model) (*object.VirtualMachine
 
 	annot := annotation{
 		HatcheryName:            h.Name(),
-		WorkerModelLastModified: fmt.Sprintf("%d", model.UserLastModified.Unix()),
+		WorkerModelLastModified: modelVersion(model),
 		WorkerModelName:         model.Name,
 		Model:                   true,
 		Created:                 time.Now(),
@@ -118,19 +138,22 @@ func (h *HatcheryVSphere) createVMModel(model sdk.Model) (*object.VirtualMachine
 		return vm, sdk.WrapError(errW, "createVMModel> cannot get an ip")
 	}
 
-	if _, errS := h.launchClientOp(vm, model.ModelVirtualMachine.PreCmd+"; \n"+model.ModelVirtualMachine.Cmd+"; \n"+model.ModelVirtualMachine.PostCmd, nil); errS != nil {
-		log.Warning(ctx, "createVMModel> cannot start program %s", errS)
-		annot := annotation{ToDelete: true}
-		if annotStr, err := json.Marshal(annot); err == nil {
-			vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{
-				Annotation: string(annotStr),
-			})
-		}
+	script := model.ModelVirtualMachine.PreCmd + "; \n"
+	if model.ModelVirtualMachine.Provision != "" {
+		script += model.ModelVirtualMachine.Provision + "; \n"
+	}
+	script += model.ModelVirtualMachine.Cmd + "; \n" + model.ModelVirtualMachine.PostCmd
+
+	// A failed build doesn't replace the template of the previous version, it is deleted by killAwolServers
+	if _, errS := h.launchClientOp(vm, script, nil); errS != nil {
+		h.markToDelete(ctx, vm)
+		return nil, sdk.WrapError(errS, "createVMModel> cannot start program")
 	}
 
 	ctxTo, cancel := context.WithTimeout(ctx, 4*time.Minute)
 	defer cancel()
 	if err := vm.WaitForPowerState(ctxTo, types.VirtualMachinePowerStatePoweredOff); err != nil {
+		h.markToDelete(ctx, vm)
 		return nil, sdk.WrapError(err, "cannot wait for power state result")
 	}
 	log.Info(ctx, "createVMModel> model %s is build", model.Name)
@@ -158,6 +181,18 @@ func (h *HatcheryVSphere) createVMModel(model sdk.Model) (*object.VirtualMachine
 	return vm, nil
 }
 
+// markToDelete tags a vm to be deleted by killAwolServers.
+func (h *HatcheryVSphere) markToDelete(ctx context.Context, vm *object.VirtualMachine) {
+	annot := annotation{ToDelete: true}
+	annotStr, err := json.Marshal(annot)
+	if err != nil {
+		return
+	}
+	if _, err := vm.Reconfigure(ctx, types.VirtualMachineConfigSpec{Annotation: string(annotStr)}); err != nil {
+		log.Error(ctx, "markToDelete> cannot tag vm %s to delete: %v", vm.Name(), err)
+	}
+}
+
 // launchScriptWorker launch a script on the worker
 func (h *HatcheryVSphere) launchScriptWorker(name string, jobID int64, token string, model sdk.Model, registerOnly bool, vmInfo types.ManagedObjectReference) error {
 	ctx := context.Background()
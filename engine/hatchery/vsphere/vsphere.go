@@ -110,6 +110,9 @@ func (h *HatcheryVSphere) CheckConfiguration(cfg interface{}) error {
 // requirements are not supported
 func (h *HatcheryVSphere) CanSpawn(ctx context.Context, model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
 	for _, r := range requirements {
+		if r.Type == sdk.ServiceRequirement && h.Config.Provision.LocalServices {
+			continue
+		}
 		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement || r.Type == sdk.HostnameRequirement {
 			return false
 		}
@@ -139,7 +142,7 @@ func (h *HatcheryVSphere) NeedRegistration(ctx context.Context, m *sdk.Model) bo
 		return true
 	}
 
-	return !annot.ToDelete && (m.NeedRegistration || fmt.Sprintf("%d", m.UserLastModified.Unix()) != annot.WorkerModelLastModified)
+	return !annot.ToDelete && (m.NeedRegistration || modelVersion(*m) != annot.WorkerModelLastModified)
 }
 
 // WorkerModelsEnabled returns Worker model enabled
@@ -259,10 +262,17 @@ func (h *HatcheryVSphere) killAwolServers() {
 			continue
 		}
 
-		if annot.ToDelete || (s.Summary.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn && (!annot.Model || annot.RegisterOnly)) {
+		if annot.ToDelete || (s.Summary.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn && (!annot.Model || annot.RegisterOnly)) ||
+			isLostModelBuild(s.Name, annot, time.Now()) {
 			if err := h.deleteServer(s); err != nil {
 				log.Warning(context.Background(), "killAwolServers> cannot delete server %s", s.Name)
 			}
 		}
 	}
 }
+
+// isLostModelBuild returns true for a vm building the template of a worker model that was left by a stopped hatchery:
+// the template is renamed with the name of the model at the end of the build.
+func isLostModelBuild(name string, annot annotation, now time.Time) bool {
+	return annot.Model && name != annot.WorkerModelName && now.Sub(annot.Created) > modelBuildTimeout
+}
@@ -0,0 +1,22 @@
+package vsphere
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLostModelBuild(t *testing.T) {
+	now := time.Now()
+	template := annotation{WorkerModelName: "my-model", Model: true, Created: now.Add(-48 * time.Hour)}
+	assert.False(t, isLostModelBuild("my-model", template, now), "the template of a model is kept")
+
+	build := annotation{WorkerModelName: "my-model", Model: true, Created: now.Add(-10 * time.Minute)}
+	assert.False(t, isLostModelBuild("my-model-tmp", build, now), "a template being built is kept")
+	build.Created = now.Add(-2 * time.Hour)
+	assert.True(t, isLostModelBuild("my-model-tmp", build, now))
+
+	worker := annotation{WorkerModelName: "my-model", Created: now.Add(-2 * time.Hour)}
+	assert.False(t, isLostModelBuild("my-worker", worker, now))
+}
@@ -26,6 +26,14 @@ func (s *Service) generatePayloadFromBitbucketCloudRequest(ctx context.Context,
 	getVariableFromBitbucketCloudRepository(payload, request.Repository)
 	getPayloadStringVariable(ctx, payload, request)
 
+	if request.PullRequest != nil {
+		if !getPayloadFromPullRequest(ctx, t.Config, payload, toVCSPullRequestFromBitbucketCloud(*request.PullRequest)) {
+			return payloads, nil
+		}
+		payload[PR_STATE] = request.PullRequest.State
+		return append(payloads, payload), nil
+	}
+
 	for _, pushChange := range request.Push.Changes {
 		if pushChange.Closed {
 			if pushChange.Old.Type == "branch" {
@@ -92,3 +100,39 @@ func getVariableFromBitbucketCloudAuthor(payload map[string]interface{}, actor *
 	payload[CDS_TRIGGERED_BY_USERNAME] = actor.Username
 	payload[CDS_TRIGGERED_BY_FULLNAME] = actor.DisplayName
 }
+
+func toVCSPullRequestFromBitbucketCloud(pr BitbucketCloudPullRequest) sdk.VCSPullRequest {
+	res := sdk.VCSPullRequest{
+		ID:     pr.ID,
+		URL:    pr.Links.HTML.Href,
+		Title:  pr.Title,
+		Merged: pr.State == "MERGED",
+		Closed: pr.State == "DECLINED" || pr.State == "SUPERSEDED",
+		Head:   toVCSPushEventFromBitbucketCloud(pr.Source),
+		Base:   toVCSPushEventFromBitbucketCloud(pr.Destination),
+	}
+	if pr.Author != nil {
+		res.User = sdk.VCSAuthor{
+			Name:        pr.Author.Username,
+			DisplayName: pr.Author.DisplayName,
+		}
+	}
+	return res
+}
+
+func toVCSPushEventFromBitbucketCloud(ref BitbucketCloudPullRequestRef) sdk.VCSPushEvent {
+	e := sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           ref.Branch.Name,
+			DisplayID:    ref.Branch.Name,
+			LatestCommit: ref.Commit.Hash,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: ref.Commit.Hash,
+		},
+	}
+	if ref.Repository != nil {
+		e.Repo = ref.Repository.FullName
+	}
+	return e
+}
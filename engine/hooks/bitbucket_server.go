@@ -22,7 +22,12 @@ func (s *Service) generatePayloadFromBitbucketServerRequest(ctx context.Context,
 
 	payload[GIT_EVENT] = request.EventKey
 	getVariableFromBitbucketServerAuthor(payload, request.Actor)
-	getVariableFromBitbucketServerPullRequest(payload, request.PullRequest)
+	if request.PullRequest != nil {
+		if !getPayloadFromPullRequest(ctx, t.Config, payload, toVCSPullRequestFromBitbucketServer(*request.PullRequest)) {
+			return payloads, nil
+		}
+		payload[PR_STATE] = request.PullRequest.State
+	}
 	getVariableFromBitbucketServerParticipant(payload, request.Participant)
 	getPayloadStringVariable(ctx, payload, request)
 	getPayloadFromBitbucketServerPRComment(payload, request.Comment)
@@ -94,13 +99,6 @@ func getVariableFromBitbucketServerRepository(payload map[string]interface{}, re
 	payload[GIT_REPOSITORY_DEST] = fmt.Sprintf("%s/%s", repo.Project.Key, repo.Slug)
 }
 
-func getVariableFromBitbucketServerSrcRepository(payload map[string]interface{}, repo *sdk.BitbucketServerRepository) {
-	if repo == nil {
-		return
-	}
-	payload[GIT_REPOSITORY] = fmt.Sprintf("%s/%s", repo.Project.Key, repo.Slug)
-}
-
 func getVariableFromBitbucketServerAuthor(payload map[string]interface{}, actor *sdk.BitbucketServerActor) {
 	if actor == nil {
 		return
@@ -112,25 +110,42 @@ func getVariableFromBitbucketServerAuthor(payload map[string]interface{}, actor
 	payload[CDS_TRIGGERED_BY_EMAIL] = actor.EmailAddress
 }
 
-func getVariableFromBitbucketServerPullRequest(payload map[string]interface{}, pr *sdk.BitbucketServerPullRequest) {
-	if pr == nil {
-		return
-	}
-	payload[PR_ID] = pr.ID
-	payload[PR_STATE] = pr.State
-	payload[PR_TITLE] = pr.Title
-	payload[GIT_BRANCH] = pr.FromRef.DisplayID
-	payload[GIT_HASH] = pr.FromRef.LatestCommit
-	payload[GIT_BRANCH_DEST] = pr.ToRef.DisplayID
-	payload[GIT_HASH_DEST] = pr.ToRef.LatestCommit
-	hashShort := pr.FromRef.LatestCommit
-	if len(hashShort) >= 7 {
-		hashShort = hashShort[:7]
+// toVCSPullRequestFromBitbucketServer converts a bitbucket pull request, bitbucket computes the merge commit of the pull requests without conflict
+func toVCSPullRequestFromBitbucketServer(pr sdk.BitbucketServerPullRequest) sdk.VCSPullRequest {
+	res := sdk.VCSPullRequest{
+		ID:       pr.ID,
+		Title:    pr.Title,
+		Merged:   pr.State == "MERGED",
+		Closed:   pr.Closed,
+		Head:     toVCSPushEventFromBitbucketServer(pr.FromRef),
+		Base:     toVCSPushEventFromBitbucketServer(pr.ToRef),
+		MergeRef: fmt.Sprintf("refs/pull-requests/%d/merge", pr.ID),
+	}
+	if len(pr.Links.Self) > 0 {
+		res.URL = pr.Links.Self[0].Href
+	}
+	if pr.Author != nil {
+		res.User = sdk.VCSAuthor{
+			Name:        pr.Author.User.Name,
+			DisplayName: pr.Author.User.DisplayName,
+			Email:       pr.Author.User.EmailAddress,
+		}
 	}
-	payload[GIT_HASH_SHORT] = hashShort
+	return res
+}
 
-	getVariableFromBitbucketServerRepository(payload, &pr.ToRef.Repository)
-	getVariableFromBitbucketServerSrcRepository(payload, &pr.FromRef.Repository)
+func toVCSPushEventFromBitbucketServer(ref sdk.BitbucketServerRef) sdk.VCSPushEvent {
+	return sdk.VCSPushEvent{
+		Repo: fmt.Sprintf("%s/%s", ref.Repository.Project.Key, ref.Repository.Slug),
+		Branch: sdk.VCSBranch{
+			ID:           ref.ID,
+			DisplayID:    ref.DisplayID,
+			LatestCommit: ref.LatestCommit,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: ref.LatestCommit,
+		},
+	}
 }
 
 func getPayloadFromBitbucketServerPRComment(payload map[string]interface{}, comment *sdk.BitbucketServerComment) {
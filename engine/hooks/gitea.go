@@ -0,0 +1,157 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (s *Service) generatePayloadFromGiteaRequest(ctx context.Context, t *sdk.TaskExecution, event string) (map[string]interface{}, error) {
+	projectKey := t.Config["project"].Value
+	workflowName := t.Config["workflow"].Value
+
+	var request GiteaEvent
+	if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read gitea request: %s", string(t.WebHook.RequestBody))
+	}
+
+	// Branch deletion: gitea sends a delete event and a push event with 0000000000000000000000000000000000000000 as git hash
+	if (event == "delete" && request.RefType == "branch") || request.After == "0000000000000000000000000000000000000000" {
+		err := s.enqueueBranchDeletion(projectKey, workflowName, strings.TrimPrefix(request.Ref, "refs/heads/"))
+		return nil, sdk.WrapError(err, "cannot enqueue branch deletion")
+	}
+
+	payload := make(map[string]interface{})
+	payload[GIT_EVENT] = event
+
+	// the ref of create and delete events is the short name of the branch or of the tag
+	ref := request.Ref
+	switch request.RefType {
+	case "branch":
+		ref = "refs/heads/" + ref
+	case "tag":
+		ref = "refs/tags/" + ref
+	}
+	if ref != "" {
+		if !strings.HasPrefix(ref, "refs/tags/") {
+			branch := strings.TrimPrefix(ref, "refs/heads/")
+			payload[GIT_BRANCH] = branch
+			if err := s.stopBranchDeletionTask(ctx, branch); err != nil {
+				log.Error(ctx, "cannot stop branch deletion task for branch %s : %v", branch, err)
+			}
+		} else {
+			payload[GIT_TAG] = strings.TrimPrefix(ref, "refs/tags/")
+		}
+	}
+
+	hash := request.After
+	if hash == "" {
+		hash = request.Sha
+	}
+	if request.Before != "" {
+		payload[GIT_HASH_BEFORE] = request.Before
+	}
+	if hash != "" {
+		payload[GIT_HASH] = hash
+		hashShort := hash
+		if len(hashShort) >= 7 {
+			hashShort = hashShort[:7]
+		}
+		payload[GIT_HASH_SHORT] = hashShort
+	}
+
+	getPayloadFromGiteaRepository(payload, request.Repository)
+	getPayloadFromGiteaSender(payload, request.Sender)
+	getPayloadFromGiteaCommit(payload, request.HeadCommit)
+	if request.PullRequest != nil {
+		if !getPayloadFromPullRequest(ctx, t.Config, payload, toVCSPullRequestFromGitea(*request.PullRequest)) {
+			return nil, nil
+		}
+		payload[PR_STATE] = request.PullRequest.State
+	}
+
+	for i := range request.Commits {
+		request.Commits[i].Added = nil
+		request.Commits[i].Removed = nil
+		request.Commits[i].Modified = nil
+	}
+	getPayloadStringVariable(ctx, payload, request)
+
+	return payload, nil
+}
+
+func getPayloadFromGiteaRepository(payload map[string]interface{}, repo *GiteaRepository) {
+	if repo == nil {
+		return
+	}
+	payload[GIT_REPOSITORY] = repo.FullName
+}
+
+func getPayloadFromGiteaSender(payload map[string]interface{}, sender *GiteaUser) {
+	if sender == nil {
+		return
+	}
+	payload[GIT_AUTHOR] = sender.Login
+	payload[GIT_AUTHOR_EMAIL] = sender.Email
+	payload[CDS_TRIGGERED_BY_USERNAME] = sender.Login
+	payload[CDS_TRIGGERED_BY_FULLNAME] = sender.FullName
+	payload[CDS_TRIGGERED_BY_EMAIL] = sender.Email
+}
+
+func getPayloadFromGiteaCommit(payload map[string]interface{}, commit *GiteaCommit) {
+	if commit == nil {
+		return
+	}
+	payload[GIT_MESSAGE] = commit.Message
+	if commit.Author.Username != "" {
+		payload[GIT_AUTHOR] = commit.Author.Username
+	}
+	if commit.Author.Email != "" {
+		payload[GIT_AUTHOR_EMAIL] = commit.Author.Email
+	}
+}
+
+// toVCSPullRequestFromGitea converts a gitea pull request, gitea does not compute merge refs
+func toVCSPullRequestFromGitea(pr GiteaPullRequest) sdk.VCSPullRequest {
+	res := sdk.VCSPullRequest{
+		ID:     pr.Number,
+		URL:    pr.HTMLURL,
+		Title:  pr.Title,
+		Merged: pr.Merged,
+		Closed: pr.State == "closed",
+		Head:   toVCSPushEventFromGitea(pr.Head),
+		Base:   toVCSPushEventFromGitea(pr.Base),
+	}
+	if pr.User != nil {
+		res.User = sdk.VCSAuthor{
+			Name:        pr.User.Login,
+			DisplayName: pr.User.FullName,
+			Email:       pr.User.Email,
+		}
+	}
+	for _, l := range pr.Labels {
+		res.Labels = append(res.Labels, l.Name)
+	}
+	return res
+}
+
+func toVCSPushEventFromGitea(b GiteaPRBranchInfo) sdk.VCSPushEvent {
+	e := sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           b.Ref,
+			DisplayID:    b.Ref,
+			LatestCommit: b.Sha,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: b.Sha,
+		},
+	}
+	if b.Repository != nil {
+		e.Repo = b.Repository.FullName
+		e.CloneURL = b.Repository.CloneURL
+	}
+	return e
+}
@@ -3,6 +3,7 @@ package hooks
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/ovh/cds/sdk"
@@ -13,6 +14,10 @@ func (s *Service) generatePayloadFromGithubRequest(ctx context.Context, t *sdk.T
 	projectKey := t.Config["project"].Value
 	workflowName := t.Config["workflow"].Value
 
+	if event == "pull_request" {
+		return generatePayloadFromGithubPullRequest(ctx, t, event)
+	}
+
 	var request GithubWebHookEvent
 	if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil {
 		return nil, sdk.WrapError(err, "unable ro read github request: %s", string(t.WebHook.RequestBody))
@@ -66,6 +71,68 @@ func (s *Service) generatePayloadFromGithubRequest(ctx context.Context, t *sdk.T
 	return payload, nil
 }
 
+func generatePayloadFromGithubPullRequest(ctx context.Context, t *sdk.TaskExecution, event string) (map[string]interface{}, error) {
+	var request GithubPullRequestEvent
+	if err := json.Unmarshal(t.WebHook.RequestBody, &request); err != nil {
+		return nil, sdk.WrapError(err, "unable ro read github request: %s", string(t.WebHook.RequestBody))
+	}
+
+	payload := make(map[string]interface{})
+	payload[GIT_EVENT] = event
+	payload[GIT_AUTHOR] = request.Sender.Login
+	payload[CDS_TRIGGERED_BY_USERNAME] = request.Sender.Login
+
+	pr := toVCSPullRequestFromGithub(request.PullRequest)
+	if !getPayloadFromPullRequest(ctx, t.Config, payload, pr) {
+		return nil, nil
+	}
+	payload[PR_STATE] = request.PullRequest.State
+	payload[GIT_MESSAGE] = request.PullRequest.Title
+	getPayloadStringVariable(ctx, payload, request)
+
+	return payload, nil
+}
+
+func toVCSPullRequestFromGithub(pr GithubPullRequest) sdk.VCSPullRequest {
+	res := sdk.VCSPullRequest{
+		ID:     pr.Number,
+		URL:    pr.HTMLURL,
+		Title:  pr.Title,
+		Merged: pr.Merged,
+		Closed: pr.State == "closed",
+		User: sdk.VCSAuthor{
+			Name:   pr.User.Login,
+			Avatar: pr.User.AvatarURL,
+		},
+		Head: toVCSPushEventFromGithub(pr.Head),
+		Base: toVCSPushEventFromGithub(pr.Base),
+		// Github computes the merge commit of the pull requests without conflict
+		MergeRef: fmt.Sprintf("refs/pull/%d/merge", pr.Number),
+	}
+	for _, l := range pr.Labels {
+		res.Labels = append(res.Labels, l.Name)
+	}
+	return res
+}
+
+func toVCSPushEventFromGithub(ref GithubPullRequestRef) sdk.VCSPushEvent {
+	e := sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           ref.Ref,
+			DisplayID:    ref.Ref,
+			LatestCommit: ref.Sha,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: ref.Sha,
+		},
+	}
+	if ref.Repo != nil {
+		e.Repo = ref.Repo.FullName
+		e.CloneURL = ref.Repo.CloneURL
+	}
+	return e
+}
+
 func getPayloadFromRepository(payload map[string]interface{}, repo *GithubRepository) {
 	if repo == nil {
 		return
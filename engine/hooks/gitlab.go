@@ -59,6 +59,13 @@ func (s *Service) generatePayloadFromGitlabRequest(ctx context.Context, t *sdk.T
 
 	getPayloadFromGitlabProject(payload, request.Project)
 	getPayloadFromGitlabCommit(payload, request.Commits)
+	getPayloadFromGitlabUser(payload, request.User)
+	if request.ObjectAttributes != nil {
+		if !getPayloadFromPullRequest(ctx, t.Config, payload, toVCSPullRequestFromGitlab(request)) {
+			return nil, nil
+		}
+		payload[PR_STATE] = request.ObjectAttributes.State
+	}
 	getPayloadStringVariable(ctx, payload, request)
 
 	return payload, nil
@@ -77,3 +84,56 @@ func getPayloadFromGitlabProject(payload map[string]interface{}, project *Gitlab
 	}
 	payload[GIT_REPOSITORY] = project.PathWithNamespace
 }
+
+func getPayloadFromGitlabUser(payload map[string]interface{}, user *GitlabUser) {
+	if user == nil {
+		return
+	}
+	payload[GIT_AUTHOR] = user.Username
+	payload[GIT_AUTHOR_EMAIL] = user.Email
+	payload[CDS_TRIGGERED_BY_USERNAME] = user.Username
+	payload[CDS_TRIGGERED_BY_FULLNAME] = user.Name
+	payload[CDS_TRIGGERED_BY_EMAIL] = user.Email
+}
+
+// toVCSPullRequestFromGitlab converts a gitlab merge request event, gitlab only sends the user who triggered the event
+// and does not send the hash of the target branch
+func toVCSPullRequestFromGitlab(request GitlabEvent) sdk.VCSPullRequest {
+	mr := request.ObjectAttributes
+	res := sdk.VCSPullRequest{
+		ID:     mr.IID,
+		URL:    mr.URL,
+		Title:  mr.Title,
+		Merged: mr.State == "merged",
+		Closed: mr.State == "closed",
+		Head: sdk.VCSPushEvent{
+			Branch: sdk.VCSBranch{ID: mr.SourceBranch, DisplayID: mr.SourceBranch},
+		},
+		Base: sdk.VCSPushEvent{
+			Branch: sdk.VCSBranch{ID: mr.TargetBranch, DisplayID: mr.TargetBranch},
+		},
+	}
+	if mr.Source != nil {
+		res.Head.Repo = mr.Source.PathWithNamespace
+		res.Head.CloneURL = mr.Source.GitHTTPURL
+	}
+	if mr.Target != nil {
+		res.Base.Repo = mr.Target.PathWithNamespace
+		res.Base.CloneURL = mr.Target.GitHTTPURL
+	}
+	if mr.LastCommit != nil {
+		res.Head.Commit = sdk.VCSCommit{Hash: mr.LastCommit.ID, Message: mr.LastCommit.Message}
+		res.Head.Branch.LatestCommit = mr.LastCommit.ID
+	}
+	if request.User != nil {
+		res.User = sdk.VCSAuthor{
+			Name:        request.User.Username,
+			DisplayName: request.User.Name,
+			Email:       request.User.Email,
+		}
+	}
+	for _, l := range request.Labels {
+		res.Labels = append(res.Labels, l.Title)
+	}
+	return res
+}
@@ -37,7 +37,7 @@ func (s *Service) webhookHandler() service.Handler {
 		//Check method
 		confValue := webHook.Config[sdk.WebHookModelConfigMethod]
 		if r.Method != confValue.Value {
-			return sdk.WrapError(sdk.ErrMethodNotAllowed, "Unsupported method %s : %v", r.Method, webHook.Config)
+			return sdk.WrapError(sdk.ErrMethodNotAllowed, "Unsupported method %s", r.Method)
 		}
 
 		//Read the body
@@ -46,12 +46,19 @@ func (s *Service) webhookHandler() service.Handler {
 			return sdk.WrapError(err, "Unable to read request")
 		}
 
-		//Prepare a web hook execution
+		//Check that the payload of a repository webhook has been signed by the repository manager
+		if webHook.Type == TypeRepoManagerWebHook {
+			if err := s.checkRepositoryWebHookSignature(ctx, webHook, r, req); err != nil {
+				return err
+			}
+		}
+
+		//Prepare a web hook execution, the secrets are not needed to process it
 		exec := &sdk.TaskExecution{
 			Timestamp: time.Now().UnixNano(),
 			Type:      webHook.Type,
 			UUID:      webHook.UUID,
-			Config:    webHook.Config,
+			Config:    webHook.Config.WithoutSecrets(),
 			Status:    TaskExecutionScheduled,
 			WebHook: &sdk.WebHookExecution{
 				RequestBody:   req,
@@ -158,7 +165,7 @@ func (s *Service) postAndExecuteTaskHandler() service.Handler {
 			return sdk.WrapError(err, "Hooks> postAndExecuteTaskHandler> unable to add Task")
 		}
 		t.Executions = []sdk.TaskExecution{e}
-		return service.WriteJSON(w, t, http.StatusOK)
+		return service.WriteJSON(w, t.WithoutSecrets(), http.StatusOK)
 	}
 }
 
@@ -218,6 +225,10 @@ func (s *Service) getTasksHandler() service.Handler {
 			}
 		}
 
+		for i := range tasks {
+			tasks[i] = tasks[i].WithoutSecrets()
+		}
+
 		return service.WriteJSON(w, tasks, http.StatusOK)
 	}
 }
@@ -275,7 +286,7 @@ func (s *Service) getTaskHandler() service.Handler {
 
 		t.Executions = execs
 
-		return service.WriteJSON(w, t, http.StatusOK)
+		return service.WriteJSON(w, t.WithoutSecrets(), http.StatusOK)
 	}
 }
 
@@ -323,7 +334,7 @@ func (s *Service) getTaskExecutionsHandler() service.Handler {
 			return t.Executions[i].Timestamp > t.Executions[j].Timestamp
 		})
 
-		return service.WriteJSON(w, t, http.StatusOK)
+		return service.WriteJSON(w, t.WithoutSecrets(), http.StatusOK)
 	}
 }
 
@@ -357,10 +368,10 @@ func (s *Service) deleteAllTaskExecutionsHandler() service.Handler {
 
 		//Start the task
 		if _, err := s.startTask(ctx, t); err != nil {
-			return sdk.WrapError(err, "Unable start task %+v", t)
+			return sdk.WrapError(err, "Unable start task %s", t.UUID)
 		}
 
-		return service.WriteJSON(w, t, http.StatusOK)
+		return service.WriteJSON(w, t.WithoutSecrets(), http.StatusOK)
 	}
 }
 
@@ -615,7 +626,7 @@ func (s *Service) getTaskExecutionHandler() service.Handler {
 
 		for _, e := range execs {
 			if strconv.FormatInt(e.Timestamp, 10) == timestamp {
-				return service.WriteJSON(w, e, http.StatusOK)
+				return service.WriteJSON(w, e.WithoutSecrets(), http.StatusOK)
 			}
 		}
 
@@ -0,0 +1,61 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// mergeQueueTrustedAssociations are the associations with the repository of the reviewers whose approval
+// adds a pull request in the merge queue.
+var mergeQueueTrustedAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR"}
+
+// executeMergeQueueApproval adds a pull request in the merge queue of the hook when it is approved by a trusted reviewer.
+// It returns false if the merge queue is disabled or if the webhook is not a pull request review.
+func (s *Service) executeMergeQueueApproval(ctx context.Context, t *sdk.TaskExecution) (bool, error) {
+	if method, _ := sdk.MergeQueueConfig(t.Config); method == "" {
+		return false, nil
+	}
+	// Only Github reviews are supported
+	v, ok := t.WebHook.RequestHeader[GithubHeader]
+	if !ok || len(v) == 0 || v[0] != "pull_request_review" {
+		return false, nil
+	}
+	if _, ok := t.WebHook.RequestHeader[GiteaHeader]; ok {
+		return false, nil
+	}
+
+	var event GithubPullRequestReviewEvent
+	if err := json.Unmarshal(t.WebHook.RequestBody, &event); err != nil {
+		return true, sdk.WrapError(err, "unable to read github pull request review")
+	}
+	if event.Action != "submitted" || !strings.EqualFold(event.Review.State, "approved") {
+		return true, nil
+	}
+	if !sdk.IsInArray(strings.ToUpper(event.Review.AuthorAssociation), mergeQueueTrustedAssociations) {
+		log.Info(ctx, "pull request #%d approved by %s (%s) who can't add it to the merge queue of hook %s", event.PullRequest.Number, event.Review.User.Login, event.Review.AuthorAssociation, t.UUID)
+		return true, nil
+	}
+
+	log.Info(ctx, "pull request #%d approved by %s, adding it to the merge queue of hook %s", event.PullRequest.Number, event.Review.User.Login, t.UUID)
+	if err := s.Client.HookMergeQueueAdd(t.UUID, event.PullRequest.Number); err != nil {
+		return true, sdk.WrapError(err, "unable to add pull request #%d in the merge queue", event.PullRequest.Number)
+	}
+	return true, nil
+}
+
+// skipMergeQueueCandidates removes the payloads of the pushes on the branches of the merge queue candidates
+func skipMergeQueueCandidates(ctx context.Context, payloads []map[string]interface{}) []map[string]interface{} {
+	res := make([]map[string]interface{}, 0, len(payloads))
+	for _, payload := range payloads {
+		if branch, _ := payload[GIT_BRANCH].(string); sdk.IsMergeQueueCandidateBranch(branch) {
+			log.Info(ctx, "push on merge queue candidate branch %s skipped", branch)
+			continue
+		}
+		res = append(res, payload)
+	}
+	return res
+}
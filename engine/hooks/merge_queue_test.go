@@ -0,0 +1,123 @@
+package hooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient/mock_cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func Test_executeRepositoryWebHookMergeQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_cdsclient.NewMockInterface(ctrl)
+	s := Service{}
+	s.Client = client
+
+	task := &sdk.TaskExecution{
+		UUID: "uuid",
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: {Value: "pull_request"},
+			sdk.HookConfigMergeQueue:  {Value: sdk.VCSMergeMethodSquash},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody:   []byte(githubPullRequestReviewEvent),
+			RequestHeader: map[string][]string{GithubHeader: {"pull_request_review"}},
+		},
+	}
+
+	// The approved pull request is queued, the workflow is not triggered
+	client.EXPECT().HookMergeQueueAdd("uuid", 7).Return(nil)
+	hs, err := s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	assert.Len(t, hs, 0)
+
+	// Other reviews are ignored
+	task.WebHook.RequestBody = []byte(`{"action": "submitted", "review": {"state": "commented", "author_association": "MEMBER"}, "pull_request": {"number": 7}}`)
+	hs, err = s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	assert.Len(t, hs, 0)
+
+	// Approvals of reviewers who don't have write access to the repository are ignored
+	for _, association := range []string{"CONTRIBUTOR", "FIRST_TIME_CONTRIBUTOR", "NONE", ""} {
+		task.WebHook.RequestBody = []byte(`{"action": "submitted", "review": {"state": "approved", "author_association": "` + association + `"}, "pull_request": {"number": 7}}`)
+		hs, err = s.executeRepositoryWebHook(context.TODO(), task)
+		require.NoError(t, err)
+		assert.Len(t, hs, 0)
+	}
+}
+
+func Test_doWebHookExecutionMergeQueueCandidate(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		WebHook: &sdk.WebHookExecution{
+			RequestBody:   []byte(strings.Replace(githubPushEvent, "refs/heads/my-branch", "refs/heads/"+sdk.MergeQueueCandidateBranch("master", 7), 1)),
+			RequestHeader: map[string][]string{GithubHeader: {"push"}},
+		},
+	}
+
+	// The candidate is started by the merge queue, the push on its branch doesn't trigger the workflow
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	require.NoError(t, err)
+	assert.Len(t, hs, 0)
+}
+
+func Test_skipMergeQueueCandidates(t *testing.T) {
+	payloads := []map[string]interface{}{
+		{GIT_BRANCH: "master"},
+		{GIT_BRANCH: sdk.MergeQueueCandidateBranch("master", 7)},
+		{GIT_TAG: "v1.0.0"},
+	}
+	res := skipMergeQueueCandidates(context.TODO(), payloads)
+	require.Len(t, res, 2)
+	assert.Equal(t, "master", res[0][GIT_BRANCH])
+	assert.Equal(t, "v1.0.0", res[1][GIT_TAG])
+}
+
+var githubPullRequestReviewEvent = `{
+  "action": "submitted",
+  "review": {
+    "id": 237895671,
+    "user": {
+      "login": "Codertocat",
+      "id": 21031067
+    },
+    "body": null,
+    "commit_id": "ecc4b0b5c8e5f4c0b2c4b5d5c7b0b9d0f1a2b3c4",
+    "state": "APPROVED",
+    "author_association": "COLLABORATOR"
+  },
+  "pull_request": {
+    "number": 7,
+    "state": "open",
+    "title": "Update the README with new information",
+    "user": {
+      "login": "octocat",
+      "id": 21031067
+    },
+    "head": {
+      "ref": "new-topic",
+      "sha": "ecc4b0b5c8e5f4c0b2c4b5d5c7b0b9d0f1a2b3c4"
+    },
+    "base": {
+      "ref": "master",
+      "sha": "f95f852bd8fca8fcc58a9a2d6c842781e32a215e"
+    }
+  },
+  "sender": {
+    "login": "Codertocat",
+    "id": 21031067
+  }
+}`
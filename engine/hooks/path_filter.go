@@ -0,0 +1,176 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// pathFilter selects the events of a repository hook from the files they change
+type pathFilter struct {
+	include []string
+	exclude []string
+}
+
+func newPathFilter(config sdk.WorkflowNodeHookConfig) pathFilter {
+	return pathFilter{
+		include: splitPathFilter(config[sdk.HookConfigPathFilterInclude].Value),
+		exclude: splitPathFilter(config[sdk.HookConfigPathFilterExclude].Value),
+	}
+}
+
+func splitPathFilter(value string) []string {
+	var globs []string
+	for _, g := range strings.Split(value, ";") {
+		g = strings.TrimPrefix(strings.TrimSpace(g), "/")
+		if g != "" {
+			globs = append(globs, g)
+		}
+	}
+	return globs
+}
+
+func (f pathFilter) isEmpty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// match returns true if at least one of the files is included and not excluded
+func (f pathFilter) match(files []string) bool {
+	for _, file := range files {
+		if len(f.include) > 0 && !matchAnyPath(f.include, file) {
+			continue
+		}
+		if matchAnyPath(f.exclude, file) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func matchAnyPath(globs []string, file string) bool {
+	for _, g := range globs {
+		if matchPath(g, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath reports whether the file matches the glob. In addition to the path.Match syntax,
+// '**' matches any number of directories and a glob ending with '/' matches all the files of a directory.
+func matchPath(glob, file string) bool {
+	if strings.HasSuffix(glob, "/") {
+		glob += "**"
+	}
+	return matchPathSegments(strings.Split(glob, "/"), strings.Split(file, "/"))
+}
+
+func matchPathSegments(globs, names []string) bool {
+	for len(globs) > 0 {
+		if globs[0] == "**" {
+			if len(globs) == 1 {
+				return true
+			}
+			for i := range names {
+				if matchPathSegments(globs[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if ok, err := path.Match(globs[0], names[0]); err != nil || !ok {
+			return false
+		}
+		globs, names = globs[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// maxPushEventCommits is the number of commits after which the commits of a push event may be truncated by github
+const maxPushEventCommits = 20
+
+// pushEventFiles is the list of the files changed by the commits of github, gitea and gitlab push events
+type pushEventFiles struct {
+	Commits []struct {
+		Added    []string `json:"added"`
+		Removed  []string `json:"removed"`
+		Modified []string `json:"modified"`
+	} `json:"commits"`
+	// TotalCommitsCount is the number of commits of a gitlab push event, the payload contains at most 20 commits
+	TotalCommitsCount int `json:"total_commits_count"`
+	// TotalCommits is the number of commits of a gitea push event
+	TotalCommits int `json:"total_commits"`
+}
+
+// truncated returns true if the push event may not list all its commits
+func (e pushEventFiles) truncated() bool {
+	return len(e.Commits) >= maxPushEventCommits || e.TotalCommitsCount > len(e.Commits) || e.TotalCommits > len(e.Commits)
+}
+
+// getChangedFilesFromWebHook returns the files changed by a push event if the repository manager sends them all
+func getChangedFilesFromWebHook(header string, whe *sdk.WebHookExecution) ([]string, bool) {
+	switch header {
+	case GithubHeader, GiteaHeader:
+		if whe.RequestHeader[header][0] != "push" {
+			return nil, false
+		}
+	case GitlabHeader:
+		if whe.RequestHeader[header][0] != string(gitlab.EventTypePush) {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+
+	var event pushEventFiles
+	if err := json.Unmarshal(whe.RequestBody, &event); err != nil || len(event.Commits) == 0 || event.truncated() {
+		return nil, false
+	}
+	var files []string
+	for _, c := range event.Commits {
+		files = append(files, c.Added...)
+		files = append(files, c.Removed...)
+		files = append(files, c.Modified...)
+	}
+	return files, true
+}
+
+func isZeroHash(hash string) bool {
+	return strings.Trim(hash, "0") == ""
+}
+
+// matchPathFilter checks the files changed between the base and the head commits against the path filter.
+// When the payload does not list the changed files, they are loaded from the repository.
+func (s *Service) matchPathFilter(ctx context.Context, hookUUID string, filter pathFilter, files []string, hasFiles bool, branch, base, head string) (bool, error) {
+	if !hasFiles {
+		// Without branch or head commit (ie. tag or deletion), changes cannot be computed
+		if branch == "" || isZeroHash(head) {
+			log.Info(ctx, "hook %s: unable to compute changed files, path filter is ignored", hookUUID)
+			return true, nil
+		}
+		if isZeroHash(base) {
+			base = ""
+		}
+		var err error
+		files, err = s.Client.HookChangedFiles(hookUUID, branch, base, head)
+		if err != nil {
+			return false, sdk.WrapError(err, "cannot load files changed between %s and %s", base, head)
+		}
+	}
+
+	if !filter.match(files) {
+		log.Info(ctx, "hook %s: no changed file between %s and %s matches the path filter", hookUUID, base, head)
+		return false, nil
+	}
+	return true, nil
+}
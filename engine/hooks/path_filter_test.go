@@ -0,0 +1,150 @@
+package hooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient/mock_cdsclient"
+)
+
+func Test_matchPath(t *testing.T) {
+	tests := []struct {
+		glob  string
+		file  string
+		match bool
+	}{
+		{glob: "README.md", file: "README.md", match: true},
+		{glob: "*.md", file: "README.md", match: true},
+		{glob: "*.md", file: "docs/README.md"},
+		{glob: "**/*.md", file: "docs/README.md", match: true},
+		{glob: "**/*.md", file: "README.md", match: true},
+		{glob: "api/**", file: "api/main.go", match: true},
+		{glob: "api/**", file: "api/v2/main.go", match: true},
+		{glob: "api/**", file: "ui/main.go"},
+		{glob: "api/", file: "api/v2/main.go", match: true},
+		{glob: "api/**/test/*.go", file: "api/test/main.go", match: true},
+		{glob: "api/**/test/*.go", file: "api/v2/internal/test/main.go", match: true},
+		{glob: "api/**/test/*.go", file: "api/v2/internal/test/data/main.go"},
+		{glob: "api/*", file: "api/v2/main.go"},
+		{glob: "api/[", file: "api/["},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, matchPath(tt.glob, tt.file), "glob %s on file %s", tt.glob, tt.file)
+	}
+}
+
+func Test_pathFilter(t *testing.T) {
+	f := newPathFilter(sdk.WorkflowNodeHookConfig{})
+	assert.True(t, f.isEmpty())
+
+	f = newPathFilter(sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigPathFilterInclude: {Value: "api/ ; /lib/**"},
+		sdk.HookConfigPathFilterExclude: {Value: "**/*.md;"},
+	})
+	assert.False(t, f.isEmpty())
+	assert.Equal(t, []string{"api/", "lib/**"}, f.include)
+	assert.Equal(t, []string{"**/*.md"}, f.exclude)
+
+	assert.True(t, f.match([]string{"ui/index.html", "api/main.go"}))
+	assert.True(t, f.match([]string{"api/README.md", "lib/lib.go"}))
+	assert.False(t, f.match([]string{"ui/index.html"}))
+	assert.False(t, f.match([]string{"api/README.md"}))
+	assert.False(t, f.match(nil))
+
+	f = newPathFilter(sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigPathFilterExclude: {Value: "docs/**"},
+	})
+	assert.True(t, f.match([]string{"docs/index.md", "main.go"}))
+	assert.False(t, f.match([]string{"docs/index.md"}))
+}
+
+func Test_getChangedFilesFromWebHook(t *testing.T) {
+	files, ok := getChangedFilesFromWebHook(GithubHeader, &sdk.WebHookExecution{
+		RequestBody:   []byte(githubPushEvent),
+		RequestHeader: map[string][]string{GithubHeader: {"push"}},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"README.md"}, files)
+
+	files, ok = getChangedFilesFromWebHook(GiteaHeader, &sdk.WebHookExecution{
+		RequestBody:   []byte(giteaPushEvent),
+		RequestHeader: map[string][]string{GiteaHeader: {"push"}, GithubHeader: {"push"}},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"login.html"}, files)
+
+	// The tag event has no commit
+	_, ok = getChangedFilesFromWebHook(GithubHeader, &sdk.WebHookExecution{
+		RequestBody:   []byte(githubTagEvent),
+		RequestHeader: map[string][]string{GithubHeader: {"create"}},
+	})
+	assert.False(t, ok)
+
+	// The commits of a large push are truncated, the changed files are loaded from the repository
+	_, ok = getChangedFilesFromWebHook(GitlabHeader, &sdk.WebHookExecution{
+		RequestBody:   []byte(`{"total_commits_count": 25, "commits": [{"added": ["api/main.go"]}]}`),
+		RequestHeader: map[string][]string{GitlabHeader: {"Push Hook"}},
+	})
+	assert.False(t, ok)
+	commits := make([]string, 20)
+	for i := range commits {
+		commits[i] = `{"modified": ["README.md"]}`
+	}
+	_, ok = getChangedFilesFromWebHook(GithubHeader, &sdk.WebHookExecution{
+		RequestBody:   []byte(`{"commits": [` + strings.Join(commits, ",") + `]}`),
+		RequestHeader: map[string][]string{GithubHeader: {"push"}},
+	})
+	assert.False(t, ok)
+	files, ok = getChangedFilesFromWebHook(GitlabHeader, &sdk.WebHookExecution{
+		RequestBody:   []byte(`{"total_commits_count": 1, "commits": [{"added": ["api/main.go"]}]}`),
+		RequestHeader: map[string][]string{GitlabHeader: {"Push Hook"}},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"api/main.go"}, files)
+
+	_, ok = getChangedFilesFromWebHook(BitbucketHeader, &sdk.WebHookExecution{
+		RequestBody:   []byte(`{}`),
+		RequestHeader: map[string][]string{BitbucketHeader: {"repo:refs_changed"}},
+	})
+	assert.False(t, ok)
+}
+
+func Test_matchPathFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_cdsclient.NewMockInterface(ctrl)
+	s := Service{}
+	s.Client = client
+
+	f := newPathFilter(sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigPathFilterInclude: {Value: "api/**"},
+	})
+
+	// Changed files from the payload
+	match, err := s.matchPathFilter(context.TODO(), "uuid", f, []string{"api/main.go"}, true, "master", "123", "456")
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	// Changed files loaded from the repository
+	client.EXPECT().HookChangedFiles("uuid", "master", "123", "456").Return([]string{"ui/index.html"}, nil)
+	match, err = s.matchPathFilter(context.TODO(), "uuid", f, nil, false, "master", "123", "456")
+	require.NoError(t, err)
+	assert.False(t, match)
+
+	// New branch
+	client.EXPECT().HookChangedFiles("uuid", "feat/api", "", "456").Return([]string{"api/main.go"}, nil)
+	match, err = s.matchPathFilter(context.TODO(), "uuid", f, nil, false, "feat/api", "0000000000000000000000000000000000000000", "456")
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	// Changed files of a tag cannot be computed
+	match, err = s.matchPathFilter(context.TODO(), "uuid", f, nil, false, "", "", "456")
+	require.NoError(t, err)
+	assert.True(t, match)
+}
@@ -43,6 +43,23 @@ func fillPayload(ctx context.Context, pushEvent sdk.VCSPushEvent) map[string]str
 	return payload
 }
 
+func toStringPayload(payload map[string]interface{}) map[string]string {
+	res := make(map[string]string, len(payload))
+	for k, v := range payload {
+		res[k] = fmt.Sprint(v)
+	}
+	return res
+}
+
+// mergePollerPayload returns a new payload with the payload of the hook and the payload of an event
+func mergePollerPayload(hookPayload, eventPayload map[string]string) map[string]string {
+	payload := make(map[string]string, len(hookPayload)+len(eventPayload))
+	for k, v := range hookPayload {
+		payload[k] = v
+	}
+	return sdk.ParametersMapMerge(payload, eventPayload)
+}
+
 func (s *Service) doPollerTaskExecution(ctx context.Context, task *sdk.Task, taskExec *sdk.TaskExecution) ([]sdk.WorkflowNodeRunHookEvent, error) {
 	log.Debug("Hooks> Processing polling task %s:%d", taskExec.UUID, taskExec.Timestamp)
 
@@ -92,26 +109,73 @@ func (s *Service) doPollerTaskExecution(ctx context.Context, task *sdk.Task, tas
 		payloadValues["payload"] = string(payload.Value)
 	}
 
+	// Polled events do not list the changed files, they are computed from the hash of the branch before the pushes
+	filter := newPathFilter(taskExec.Config)
+
 	var hookEvents []sdk.WorkflowNodeRunHookEvent
 	if len(events.PushEvents) > 0 || len(events.PullRequestEvents) > 0 {
-		i := 0
-		hookEvents = make([]sdk.WorkflowNodeRunHookEvent, len(events.PushEvents)+len(events.PullRequestEvents))
+		hookEvents = make([]sdk.WorkflowNodeRunHookEvent, 0, len(events.PushEvents)+len(events.PullRequestEvents))
 		for _, pushEvent := range events.PushEvents {
+			// The candidates of the merge queue are started by the API
+			if sdk.IsMergeQueueCandidateBranch(pushEvent.Branch.DisplayID) {
+				continue
+			}
+			if !filter.isEmpty() {
+				match, err := s.matchPathFilter(ctx, task.UUID, filter, nil, false, pushEvent.Branch.DisplayID, pushEvent.Before, pushEvent.Commit.Hash)
+				if err != nil {
+					return nil, err
+				}
+				if !match {
+					continue
+				}
+			}
 			payload := fillPayload(ctx, pushEvent)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
+			hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
 				WorkflowNodeHookUUID: task.UUID,
-				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+				Payload:              mergePollerPayload(payloadValues, payload),
+			})
+		}
+
+		// When the merge refs are built, the pull requests are built again when their base branch moves
+		if newPullRequestPolicy(taskExec.Config).mergeRef {
+			for _, pushEvent := range events.PushEvents {
+				if strings.HasPrefix(pushEvent.Branch.DisplayID, "refs/tags/") || sdk.IsMergeQueueCandidateBranch(pushEvent.Branch.DisplayID) {
+					continue
+				}
+				branch := strings.TrimPrefix(pushEvent.Branch.DisplayID, "refs/heads/")
+				prPayloads, err := s.getPayloadsFromPullRequestBase(ctx, taskExec, "push", branch)
+				if err != nil {
+					return nil, err
+				}
+				for _, prPayload := range prPayloads {
+					hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
+						WorkflowNodeHookUUID: task.UUID,
+						Payload:              mergePollerPayload(payloadValues, toStringPayload(prPayload)),
+					})
+				}
 			}
-			i++
 		}
 
 		for _, pullRequestEvent := range events.PullRequestEvents {
+			if !filter.isEmpty() {
+				match, err := s.matchPathFilter(ctx, task.UUID, filter, nil, false, pullRequestEvent.Head.Branch.DisplayID, pullRequestEvent.Base.Commit.Hash, pullRequestEvent.Head.Commit.Hash)
+				if err != nil {
+					return nil, err
+				}
+				if !match {
+					continue
+				}
+			}
 			payload := fillPayload(ctx, pullRequestEvent.Head)
-			hookEvents[i] = sdk.WorkflowNodeRunHookEvent{
-				WorkflowNodeHookUUID: task.UUID,
-				Payload:              sdk.ParametersMapMerge(payloadValues, payload),
+			prPayload := make(map[string]interface{})
+			if !getPayloadFromPullRequest(ctx, taskExec.Config, prPayload, pullRequestEvent.PullRequest()) {
+				continue
 			}
-			i++
+			payload = sdk.ParametersMapMerge(payload, toStringPayload(prPayload))
+			hookEvents = append(hookEvents, sdk.WorkflowNodeRunHookEvent{
+				WorkflowNodeHookUUID: task.UUID,
+				Payload:              mergePollerPayload(payloadValues, payload),
+			})
 		}
 	}
 
@@ -0,0 +1,111 @@
+package hooks
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// pullRequestPolicy is the pull request configuration of a repository hook
+type pullRequestPolicy struct {
+	mergeRef  bool
+	denyForks bool
+}
+
+func newPullRequestPolicy(config sdk.WorkflowNodeHookConfig) pullRequestPolicy {
+	return pullRequestPolicy{
+		mergeRef:  config[sdk.HookConfigPullRequestRef].Value == sdk.HookPullRequestRefMerge,
+		denyForks: config[sdk.HookConfigPullRequestForks].Value == sdk.HookPullRequestForkDeny,
+	}
+}
+
+// getPayloadFromPullRequest sets the variables of the pull request in the payload.
+// It returns false if the hook does not allow the pull request to trigger the workflow.
+func getPayloadFromPullRequest(ctx context.Context, config sdk.WorkflowNodeHookConfig, payload map[string]interface{}, pr sdk.VCSPullRequest) bool {
+	policy := newPullRequestPolicy(config)
+	if policy.denyForks && pr.IsFork() {
+		log.Info(ctx, "pull request %d from fork %s is not allowed to trigger the workflow", pr.ID, pr.Head.Repo)
+		return false
+	}
+
+	payload[PR_ID] = pr.ID
+	if pr.Title != "" {
+		payload[PR_TITLE] = pr.Title
+	}
+	author := pr.User.Name
+	if author == "" {
+		author = pr.User.DisplayName
+	}
+	payload[PR_AUTHOR] = author
+	payload[PR_LABELS] = strings.Join(pr.Labels, ",")
+	payload[PR_FORK] = pr.IsFork()
+
+	payload[PR_HEAD_BRANCH] = pr.Head.Branch.DisplayID
+	payload[PR_HEAD_HASH] = pr.Head.Commit.Hash
+	payload[PR_HEAD_REPOSITORY] = pr.Head.Repo
+	payload[PR_BASE_BRANCH] = pr.Base.Branch.DisplayID
+	payload[PR_BASE_HASH] = pr.Base.Commit.Hash
+	payload[PR_BASE_REPOSITORY] = pr.Base.Repo
+
+	payload[GIT_BRANCH] = pr.Head.Branch.DisplayID
+	payload[GIT_HASH] = pr.Head.Commit.Hash
+	hashShort := pr.Head.Commit.Hash
+	if len(hashShort) >= 7 {
+		hashShort = hashShort[:7]
+	}
+	payload[GIT_HASH_SHORT] = hashShort
+	payload[GIT_BRANCH_DEST] = pr.Base.Branch.DisplayID
+	payload[GIT_HASH_DEST] = pr.Base.Commit.Hash
+	if pr.Head.Repo != "" {
+		payload[GIT_REPOSITORY] = pr.Head.Repo
+	}
+	if pr.Base.Repo != "" {
+		payload[GIT_REPOSITORY_DEST] = pr.Base.Repo
+	}
+
+	// The merge ref is computed by the repository manager on the base repository, it does not exist anymore once the pull request is closed
+	if policy.mergeRef && pr.MergeRef != "" && !pr.Closed && !pr.Merged {
+		payload[PR_MERGE_REF] = pr.MergeRef
+		if pr.Base.Repo != "" {
+			payload[GIT_REPOSITORY] = pr.Base.Repo
+		}
+	}
+	return true
+}
+
+// getPayloadsFromPullRequestBase returns the payloads of the opened pull requests targeting the branch.
+// When the merge refs are built, the pull requests are built again each time their base branch moves.
+func (s *Service) getPayloadsFromPullRequestBase(ctx context.Context, t *sdk.TaskExecution, event, branch string) ([]map[string]interface{}, error) {
+	prs, err := s.Client.HookPullRequests(t.UUID, branch)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load pull requests with base branch %s", branch)
+	}
+
+	payloads := make([]map[string]interface{}, 0, len(prs))
+	for _, pr := range prs {
+		payload := make(map[string]interface{})
+		payload[GIT_EVENT] = event
+		if !getPayloadFromPullRequest(ctx, t.Config, payload, pr) {
+			continue
+		}
+		payload[GIT_AUTHOR] = payload[PR_AUTHOR]
+		payload[GIT_AUTHOR_EMAIL] = pr.User.Email
+		payload[CDS_TRIGGERED_BY_USERNAME] = payload[PR_AUTHOR]
+		payload[CDS_TRIGGERED_BY_FULLNAME] = pr.User.DisplayName
+		payload[CDS_TRIGGERED_BY_EMAIL] = pr.User.Email
+		getPayloadStringVariable(ctx, payload, pr)
+		payloads = append(payloads, payload)
+	}
+	return payloads, nil
+}
+
+// isPullRequestBaseUpdate returns true if the payload is a push on a branch which may be the base of pull requests
+func isPullRequestBaseUpdate(payload map[string]interface{}) bool {
+	if _, ok := payload[PR_ID]; ok {
+		return false
+	}
+	branch, _ := payload[GIT_BRANCH].(string)
+	return branch != ""
+}
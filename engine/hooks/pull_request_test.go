@@ -0,0 +1,339 @@
+package hooks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient/mock_cdsclient"
+)
+
+func testPullRequest(headRepo string) sdk.VCSPullRequest {
+	return sdk.VCSPullRequest{
+		ID:    42,
+		Title: "Add login page",
+		User:  sdk.VCSAuthor{Name: "john"},
+		Head: sdk.VCSPushEvent{
+			Repo:   headRepo,
+			Branch: sdk.VCSBranch{DisplayID: "feat/login"},
+			Commit: sdk.VCSCommit{Hash: "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887"},
+		},
+		Base: sdk.VCSPushEvent{
+			Repo:   "ovh/cds",
+			Branch: sdk.VCSBranch{DisplayID: "master"},
+			Commit: sdk.VCSCommit{Hash: "4ee3cf5a8e4e3d6eac6ac0c4b3dc4e3d4a3f2e1d"},
+		},
+		Labels:   []string{"bug", "ui"},
+		MergeRef: "refs/pull/42/merge",
+	}
+}
+
+func Test_getPayloadFromPullRequest(t *testing.T) {
+	// Head mode
+	payload := map[string]interface{}{}
+	require.True(t, getPayloadFromPullRequest(context.TODO(), sdk.WorkflowNodeHookConfig{}, payload, testPullRequest("ovh/cds")))
+	assert.Equal(t, 42, payload[PR_ID])
+	assert.Equal(t, "john", payload[PR_AUTHOR])
+	assert.Equal(t, "bug,ui", payload[PR_LABELS])
+	assert.Equal(t, false, payload[PR_FORK])
+	assert.Equal(t, "feat/login", payload[GIT_BRANCH])
+	assert.Equal(t, "9c3b0c3", payload[GIT_HASH_SHORT])
+	assert.Equal(t, "master", payload[PR_BASE_BRANCH])
+	assert.Equal(t, "4ee3cf5a8e4e3d6eac6ac0c4b3dc4e3d4a3f2e1d", payload[GIT_HASH_DEST])
+	_, hasMergeRef := payload[PR_MERGE_REF]
+	assert.False(t, hasMergeRef)
+
+	// Merge mode on a fork
+	config := sdk.WorkflowNodeHookConfig{
+		sdk.HookConfigPullRequestRef: {Value: sdk.HookPullRequestRefMerge},
+	}
+	payload = map[string]interface{}{}
+	require.True(t, getPayloadFromPullRequest(context.TODO(), config, payload, testPullRequest("john/cds")))
+	assert.Equal(t, true, payload[PR_FORK])
+	assert.Equal(t, "refs/pull/42/merge", payload[PR_MERGE_REF])
+	assert.Equal(t, "john/cds", payload[PR_HEAD_REPOSITORY])
+	assert.Equal(t, "ovh/cds", payload[GIT_REPOSITORY])
+
+	// Closed pull requests have no merge ref
+	pr := testPullRequest("ovh/cds")
+	pr.Merged = true
+	payload = map[string]interface{}{}
+	require.True(t, getPayloadFromPullRequest(context.TODO(), config, payload, pr))
+	_, hasMergeRef = payload[PR_MERGE_REF]
+	assert.False(t, hasMergeRef)
+
+	// Forks denied
+	config[sdk.HookConfigPullRequestForks] = sdk.WorkflowNodeHookConfigValue{Value: sdk.HookPullRequestForkDeny}
+	assert.False(t, getPayloadFromPullRequest(context.TODO(), config, map[string]interface{}{}, testPullRequest("john/cds")))
+	assert.True(t, getPayloadFromPullRequest(context.TODO(), config, map[string]interface{}{}, testPullRequest("ovh/cds")))
+}
+
+func Test_getPayloadsFromPullRequestBase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock_cdsclient.NewMockInterface(ctrl)
+	s := Service{}
+	s.Client = client
+
+	task := &sdk.TaskExecution{
+		UUID: "uuid",
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigPullRequestRef:   {Value: sdk.HookPullRequestRefMerge},
+			sdk.HookConfigPullRequestForks: {Value: sdk.HookPullRequestForkDeny},
+		},
+	}
+
+	client.EXPECT().HookPullRequests("uuid", "master").Return([]sdk.VCSPullRequest{testPullRequest("ovh/cds"), testPullRequest("john/cds")}, nil)
+	payloads, err := s.getPayloadsFromPullRequestBase(context.TODO(), task, "push", "master")
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "push", payloads[0][GIT_EVENT])
+	assert.Equal(t, 42, payloads[0][PR_ID])
+	assert.Equal(t, "refs/pull/42/merge", payloads[0][PR_MERGE_REF])
+	assert.Equal(t, "john", payloads[0][CDS_TRIGGERED_BY_USERNAME])
+}
+
+func Test_executeRepositoryWebHookGithubPullRequest(t *testing.T) {
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: "uuid",
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter:    {Value: "pull_request"},
+			sdk.HookConfigPullRequestRef: {Value: sdk.HookPullRequestRefMerge},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody:   []byte(githubPullRequestEvent),
+			RequestHeader: map[string][]string{GithubHeader: {"pull_request"}},
+		},
+	}
+
+	hs, err := s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	require.Len(t, hs, 1)
+	assert.Equal(t, "pull_request", hs[0].Payload[GIT_EVENT])
+	assert.Equal(t, "7", hs[0].Payload[PR_ID])
+	assert.Equal(t, "open", hs[0].Payload[PR_STATE])
+	assert.Equal(t, "octocat", hs[0].Payload[PR_AUTHOR])
+	assert.Equal(t, "enhancement", hs[0].Payload[PR_LABELS])
+	assert.Equal(t, "true", hs[0].Payload[PR_FORK])
+	assert.Equal(t, "new-topic", hs[0].Payload[GIT_BRANCH])
+	assert.Equal(t, "master", hs[0].Payload[GIT_BRANCH_DEST])
+	assert.Equal(t, "ecc4b0b5c8e5f4c0b2c4b5d5c7b0b9d0f1a2b3c4", hs[0].Payload[GIT_HASH])
+	assert.Equal(t, "refs/pull/7/merge", hs[0].Payload[PR_MERGE_REF])
+	assert.Equal(t, "Codertocat/Hello-World", hs[0].Payload[GIT_REPOSITORY])
+	assert.Equal(t, "octocat/Hello-World", hs[0].Payload[PR_HEAD_REPOSITORY])
+
+	// Forks denied
+	task.Config[sdk.HookConfigPullRequestForks] = sdk.WorkflowNodeHookConfigValue{Value: sdk.HookPullRequestForkDeny}
+	hs, err = s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	assert.Len(t, hs, 0)
+}
+
+func Test_executeRepositoryWebHookGitlabMergeRequest(t *testing.T) {
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: "uuid",
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: {Value: "Merge Request Hook"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody:   []byte(gitlabMergeRequestEvent),
+			RequestHeader: map[string][]string{GitlabHeader: {"Merge Request Hook"}},
+		},
+	}
+
+	hs, err := s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	require.Len(t, hs, 1)
+	assert.Equal(t, "Merge Request Hook", hs[0].Payload[GIT_EVENT])
+	assert.Equal(t, "1", hs[0].Payload[PR_ID])
+	assert.Equal(t, "opened", hs[0].Payload[PR_STATE])
+	assert.Equal(t, "jsmith", hs[0].Payload[PR_AUTHOR])
+	assert.Equal(t, "API", hs[0].Payload[PR_LABELS])
+	assert.Equal(t, "true", hs[0].Payload[PR_FORK])
+	assert.Equal(t, "ms-viewport", hs[0].Payload[GIT_BRANCH])
+	assert.Equal(t, "master", hs[0].Payload[GIT_BRANCH_DEST])
+	assert.Equal(t, "da1560886d4f094c3e6c9ef40349f7d38b5d27d7", hs[0].Payload[GIT_HASH])
+	assert.Equal(t, "jsmith/awesome_project", hs[0].Payload[PR_HEAD_REPOSITORY])
+	assert.Equal(t, "gitlabhq/gitlab-test", hs[0].Payload[GIT_REPOSITORY_DEST])
+
+	// A merge request without source project is considered as coming from a fork
+	task.WebHook.RequestBody = []byte(strings.Replace(gitlabMergeRequestEvent, `"path_with_namespace": "jsmith/awesome_project"`, `"path_with_namespace": ""`, 1))
+	hs, err = s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	require.Len(t, hs, 1)
+	assert.Equal(t, "true", hs[0].Payload[PR_FORK])
+
+	// Forks denied
+	task.Config[sdk.HookConfigPullRequestForks] = sdk.WorkflowNodeHookConfigValue{Value: sdk.HookPullRequestForkDeny}
+	hs, err = s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	assert.Len(t, hs, 0)
+
+	// Merge requests from the repository are allowed
+	task.WebHook.RequestBody = []byte(strings.Replace(gitlabMergeRequestEvent, `"path_with_namespace": "jsmith/awesome_project"`, `"path_with_namespace": "gitlabhq/gitlab-test"`, 1))
+	hs, err = s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	require.Len(t, hs, 1)
+	assert.Equal(t, "false", hs[0].Payload[PR_FORK])
+}
+
+func Test_executeRepositoryWebHookBitbucketCloudPullRequest(t *testing.T) {
+	s := Service{}
+	task := &sdk.TaskExecution{
+		UUID: "uuid",
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: {Value: "pullrequest:created"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody:   []byte(bitbucketCloudPullRequestEvent),
+			RequestHeader: map[string][]string{BitbucketHeader: {"pullrequest:created"}},
+		},
+	}
+
+	hs, err := s.executeRepositoryWebHook(context.TODO(), task)
+	require.NoError(t, err)
+	require.Len(t, hs, 1)
+	assert.Equal(t, "pullrequest:created", hs[0].Payload[GIT_EVENT])
+	assert.Equal(t, "3", hs[0].Payload[PR_ID])
+	assert.Equal(t, "OPEN", hs[0].Payload[PR_STATE])
+	assert.Equal(t, "true", hs[0].Payload[PR_FORK])
+	assert.Equal(t, "feat/login", hs[0].Payload[GIT_BRANCH])
+	assert.Equal(t, "master", hs[0].Payload[GIT_BRANCH_DEST])
+	assert.Equal(t, "77d120bd9980", hs[0].Payload[GIT_HASH])
+	assert.Equal(t, "john/testhook", hs[0].Payload[PR_HEAD_REPOSITORY])
+	assert.Equal(t, "repo1/testhook", hs[0].Payload[GIT_REPOSITORY_DEST])
+}
+
+var gitlabMergeRequestEvent = `{
+  "object_kind": "merge_request",
+  "user": {
+    "name": "John Smith",
+    "username": "jsmith",
+    "email": "john@example.com"
+  },
+  "project": {
+    "name": "Gitlab Test",
+    "path_with_namespace": "gitlabhq/gitlab-test"
+  },
+  "object_attributes": {
+    "iid": 1,
+    "title": "MS-Viewport",
+    "url": "http://example.com/diaspora/merge_requests/1",
+    "state": "opened",
+    "action": "open",
+    "source_branch": "ms-viewport",
+    "target_branch": "master",
+    "source": {
+      "name": "Awesome Project",
+      "path_with_namespace": "jsmith/awesome_project",
+      "git_http_url": "http://example.com/jsmith/awesome_project.git"
+    },
+    "target": {
+      "name": "Gitlab Test",
+      "path_with_namespace": "gitlabhq/gitlab-test",
+      "git_http_url": "http://example.com/gitlabhq/gitlab-test.git"
+    },
+    "last_commit": {
+      "id": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+      "message": "fixed readme",
+      "timestamp": "2012-01-03T23:36:29+02:00"
+    }
+  },
+  "labels": [
+    {
+      "title": "API"
+    }
+  ]
+}`
+
+var bitbucketCloudPullRequestEvent = `{
+  "actor": {
+    "username": "john",
+    "display_name": "John"
+  },
+  "repository": {
+    "full_name": "repo1/testhook"
+  },
+  "pullrequest": {
+    "id": 3,
+    "title": "Add login page",
+    "state": "OPEN",
+    "author": {
+      "username": "john",
+      "display_name": "John"
+    },
+    "source": {
+      "branch": {"name": "feat/login"},
+      "commit": {"hash": "77d120bd9980"},
+      "repository": {"full_name": "john/testhook"}
+    },
+    "destination": {
+      "branch": {"name": "master"},
+      "commit": {"hash": "1b2c3d4e5f60"},
+      "repository": {"full_name": "repo1/testhook"}
+    },
+    "links": {
+      "html": {"href": "https://bitbucket.org/repo1/testhook/pull-requests/3"}
+    }
+  }
+}`
+
+var githubPullRequestEvent = `{
+  "action": "opened",
+  "number": 7,
+  "pull_request": {
+    "number": 7,
+    "state": "open",
+    "title": "Update the README",
+    "html_url": "https://github.com/Codertocat/Hello-World/pull/7",
+    "merged": false,
+    "user": {
+      "login": "octocat",
+      "avatar_url": "https://avatars.githubusercontent.com/u/583231"
+    },
+    "labels": [
+      {
+        "id": 208045946,
+        "name": "enhancement",
+        "color": "a2eeef"
+      }
+    ],
+    "head": {
+      "label": "octocat:new-topic",
+      "ref": "new-topic",
+      "sha": "ecc4b0b5c8e5f4c0b2c4b5d5c7b0b9d0f1a2b3c4",
+      "repo": {
+        "full_name": "octocat/Hello-World",
+        "clone_url": "https://github.com/octocat/Hello-World.git",
+        "pushed_at": "2019-05-15T15:20:33Z"
+      }
+    },
+    "base": {
+      "label": "Codertocat:master",
+      "ref": "master",
+      "sha": "f95f852bd8fca8fcc58a9a2d6c842781e32a215e",
+      "repo": {
+        "full_name": "Codertocat/Hello-World",
+        "clone_url": "https://github.com/Codertocat/Hello-World.git",
+        "pushed_at": "2019-05-15T15:20:33Z"
+      }
+    }
+  },
+  "repository": {
+    "full_name": "Codertocat/Hello-World",
+    "pushed_at": "2019-05-15T15:20:33Z"
+  },
+  "sender": {
+    "login": "octocat"
+  }
+}`
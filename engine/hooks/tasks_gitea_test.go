@@ -0,0 +1,170 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func Test_getRepositoryHeaderGitea(t *testing.T) {
+	// gitea sends both the gitea and the github headers
+	whe := &sdk.WebHookExecution{
+		RequestHeader: map[string][]string{
+			GiteaHeader:  {"push"},
+			GithubHeader: {"push"},
+		},
+	}
+	assert.Equal(t, GiteaHeader, getRepositoryHeader(whe, nil))
+	assert.Equal(t, GiteaHeader, getRepositoryHeader(whe, []string{"push", "pull_request"}))
+	assert.Equal(t, "", getRepositoryHeader(whe, []string{"pull_request"}))
+
+	whe.RequestHeader[GiteaHeader] = []string{"pull_request"}
+	whe.RequestHeader[GithubHeader] = []string{"pull_request"}
+	assert.Equal(t, "", getRepositoryHeader(whe, nil))
+	assert.Equal(t, GiteaHeader, getRepositoryHeader(whe, []string{"pull_request"}))
+}
+
+func Test_doWebHookExecutionGitea(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(giteaPushEvent),
+			RequestHeader: map[string][]string{
+				GiteaHeader:  {"push"},
+				GithubHeader: {"push"},
+			},
+			RequestURL: "",
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "master", hs[0].Payload["git.branch"])
+	assert.Equal(t, "jdoe", hs[0].Payload["git.author"])
+	assert.Equal(t, "add login page\n", hs[0].Payload["git.message"])
+	assert.Equal(t, "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887", hs[0].Payload["git.hash"])
+	assert.Equal(t, "cds/demo", hs[0].Payload["git.repository"])
+}
+
+func Test_doWebHookExecutionGiteaPullRequest(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(giteaPullRequestEvent),
+			RequestHeader: map[string][]string{
+				GiteaHeader:  {"pull_request"},
+				GithubHeader: {"pull_request"},
+			},
+			RequestURL: "",
+		},
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: sdk.WorkflowNodeHookConfigValue{
+				Value: "pull_request",
+			},
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "3", hs[0].Payload["git.pr.id"])
+	assert.Equal(t, "Add login page", hs[0].Payload["git.pr.title"])
+	assert.Equal(t, "feat/login", hs[0].Payload["git.branch"])
+	assert.Equal(t, "master", hs[0].Payload["git.branch.dest"])
+	assert.Equal(t, "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887", hs[0].Payload["git.hash"])
+	assert.Equal(t, "jdoe", hs[0].Payload["git.author"])
+}
+
+var giteaPushEvent = `
+{
+  "ref": "refs/heads/master",
+  "before": "4f1c2e3d4b5a69788796a5b4c3d2e1f0a1b2c3d4",
+  "after": "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887",
+  "compare_url": "https://gitea.example.com/cds/demo/compare/4f1c2e3d4b5a69788796a5b4c3d2e1f0a1b2c3d4...9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887",
+  "commits": [
+    {
+      "id": "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887",
+      "message": "add login page\n",
+      "url": "https://gitea.example.com/cds/demo/commit/9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887",
+      "author": {"name": "John Doe", "email": "john.doe@example.com", "username": "jdoe"},
+      "committer": {"name": "John Doe", "email": "john.doe@example.com", "username": "jdoe"},
+      "verification": null,
+      "timestamp": "2020-05-12T10:22:04+02:00",
+      "added": ["login.html"],
+      "removed": [],
+      "modified": []
+    }
+  ],
+  "head_commit": {
+    "id": "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887",
+    "message": "add login page\n",
+    "url": "https://gitea.example.com/cds/demo/commit/9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887",
+    "author": {"name": "John Doe", "email": "john.doe@example.com", "username": "jdoe"},
+    "committer": {"name": "John Doe", "email": "john.doe@example.com", "username": "jdoe"},
+    "verification": null,
+    "timestamp": "2020-05-12T10:22:04+02:00",
+    "added": ["login.html"],
+    "removed": [],
+    "modified": []
+  },
+  "repository": {
+    "id": 1,
+    "owner": {"id": 1, "login": "cds", "full_name": "", "email": "cds@example.com", "username": "cds"},
+    "name": "demo",
+    "full_name": "cds/demo",
+    "html_url": "https://gitea.example.com/cds/demo",
+    "clone_url": "https://gitea.example.com/cds/demo.git",
+    "ssh_url": "git@gitea.example.com:cds/demo.git",
+    "default_branch": "master"
+  },
+  "pusher": {"id": 2, "login": "jdoe", "full_name": "John Doe", "email": "john.doe@example.com", "username": "jdoe"},
+  "sender": {"id": 2, "login": "jdoe", "full_name": "John Doe", "email": "john.doe@example.com", "username": "jdoe"}
+}
+`
+
+var giteaPullRequestEvent = `
+{
+  "action": "opened",
+  "number": 3,
+  "pull_request": {
+    "id": 12,
+    "number": 3,
+    "user": {"id": 2, "login": "jdoe", "full_name": "John Doe", "email": "john.doe@example.com", "username": "jdoe"},
+    "title": "Add login page",
+    "body": "",
+    "state": "open",
+    "html_url": "https://gitea.example.com/cds/demo/pulls/3",
+    "merged": false,
+    "head": {
+      "label": "feat/login",
+      "ref": "feat/login",
+      "sha": "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887",
+      "repo_id": 1,
+      "repo": {"id": 1, "name": "demo", "full_name": "cds/demo", "html_url": "https://gitea.example.com/cds/demo", "clone_url": "https://gitea.example.com/cds/demo.git", "ssh_url": "git@gitea.example.com:cds/demo.git", "default_branch": "master"}
+    },
+    "base": {
+      "label": "master",
+      "ref": "master",
+      "sha": "4f1c2e3d4b5a69788796a5b4c3d2e1f0a1b2c3d4",
+      "repo_id": 1,
+      "repo": {"id": 1, "name": "demo", "full_name": "cds/demo", "html_url": "https://gitea.example.com/cds/demo", "clone_url": "https://gitea.example.com/cds/demo.git", "ssh_url": "git@gitea.example.com:cds/demo.git", "default_branch": "master"}
+    }
+  },
+  "repository": {"id": 1, "name": "demo", "full_name": "cds/demo", "html_url": "https://gitea.example.com/cds/demo", "clone_url": "https://gitea.example.com/cds/demo.git", "ssh_url": "git@gitea.example.com:cds/demo.git", "default_branch": "master"},
+  "sender": {"id": 2, "login": "jdoe", "full_name": "John Doe", "email": "john.doe@example.com", "username": "jdoe"}
+}
+`
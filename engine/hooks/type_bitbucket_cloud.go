@@ -7,7 +7,28 @@ type BitbucketCloudWebHookEvent struct {
 	Push struct {
 		Changes []BitbucketCloudChange `json:"changes,omitempty"`
 	} `json:"push"`
-	Actor      *BitbucketCloudActor      `json:"actor,omitempty"`
+	Actor       *BitbucketCloudActor       `json:"actor,omitempty"`
+	Repository  *BitbucketCloudRepository  `json:"repository,omitempty"`
+	PullRequest *BitbucketCloudPullRequest `json:"pullrequest,omitempty"`
+}
+
+type BitbucketCloudPullRequest struct {
+	ID          int                          `json:"id"`
+	Title       string                       `json:"title"`
+	State       string                       `json:"state"`
+	Author      *BitbucketCloudActor         `json:"author,omitempty"`
+	Source      BitbucketCloudPullRequestRef `json:"source"`
+	Destination BitbucketCloudPullRequestRef `json:"destination"`
+	Links       BitbucketCloudLink           `json:"links"`
+}
+
+type BitbucketCloudPullRequestRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
 	Repository *BitbucketCloudRepository `json:"repository,omitempty"`
 }
 
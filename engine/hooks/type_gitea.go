@@ -0,0 +1,83 @@
+package hooks
+
+import "time"
+
+// GiteaEvent represents the payload sent by gitea (or forgejo) on a repository webhook
+// It covers the push, create, delete and pull_request events
+type GiteaEvent struct {
+	Ref         string            `json:"ref"`
+	RefType     string            `json:"ref_type,omitempty"`
+	Before      string            `json:"before,omitempty"`
+	After       string            `json:"after,omitempty"`
+	Sha         string            `json:"sha,omitempty"`
+	CompareURL  string            `json:"compare_url,omitempty"`
+	Commits     []GiteaCommit     `json:"commits,omitempty"`
+	HeadCommit  *GiteaCommit      `json:"head_commit,omitempty"`
+	Action      string            `json:"action,omitempty"`
+	Number      int               `json:"number,omitempty"`
+	PullRequest *GiteaPullRequest `json:"pull_request,omitempty"`
+	Repository  *GiteaRepository  `json:"repository"`
+	Pusher      *GiteaUser        `json:"pusher,omitempty"`
+	Sender      *GiteaUser        `json:"sender"`
+}
+
+type GiteaCommit struct {
+	ID        string          `json:"id"`
+	Message   string          `json:"message"`
+	URL       string          `json:"url"`
+	Author    GiteaCommitUser `json:"author"`
+	Committer GiteaCommitUser `json:"committer"`
+	Timestamp time.Time       `json:"timestamp"`
+	Added     []string        `json:"added,omitempty"`
+	Removed   []string        `json:"removed,omitempty"`
+	Modified  []string        `json:"modified,omitempty"`
+}
+
+type GiteaCommitUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+type GiteaUser struct {
+	ID       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+type GiteaRepository struct {
+	ID            int64      `json:"id"`
+	Owner         *GiteaUser `json:"owner"`
+	Name          string     `json:"name"`
+	FullName      string     `json:"full_name"`
+	HTMLURL       string     `json:"html_url"`
+	CloneURL      string     `json:"clone_url"`
+	SSHURL        string     `json:"ssh_url"`
+	DefaultBranch string     `json:"default_branch"`
+}
+
+type GiteaPullRequest struct {
+	ID      int64             `json:"id"`
+	Number  int               `json:"number"`
+	User    *GiteaUser        `json:"user"`
+	Title   string            `json:"title"`
+	State   string            `json:"state"`
+	HTMLURL string            `json:"html_url"`
+	Merged  bool              `json:"merged"`
+	Labels  []GiteaLabel      `json:"labels"`
+	Head    GiteaPRBranchInfo `json:"head"`
+	Base    GiteaPRBranchInfo `json:"base"`
+}
+
+type GiteaLabel struct {
+	Name string `json:"name"`
+}
+
+type GiteaPRBranchInfo struct {
+	Label      string           `json:"label"`
+	Ref        string           `json:"ref"`
+	Sha        string           `json:"sha"`
+	Repository *GiteaRepository `json:"repo"`
+}
@@ -22,6 +22,56 @@ type GithubWebHookEvent struct {
 	Sender     GithubSender      `json:"sender"`
 }
 
+// GithubPullRequestEvent represents payload send by github on a pull request event
+type GithubPullRequestEvent struct {
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest GithubPullRequest `json:"pull_request"`
+	Sender      GithubSender      `json:"sender"`
+}
+
+// GithubPullRequestReviewEvent is sent when a review is submitted, edited or dismissed on a pull request
+type GithubPullRequestReviewEvent struct {
+	Action      string            `json:"action"`
+	Review      GithubReview      `json:"review"`
+	PullRequest GithubPullRequest `json:"pull_request"`
+	Sender      GithubSender      `json:"sender"`
+}
+
+type GithubReview struct {
+	ID                int64        `json:"id"`
+	State             string       `json:"state"`
+	CommitID          string       `json:"commit_id"`
+	User              GithubSender `json:"user"`
+	AuthorAssociation string       `json:"author_association"`
+}
+
+type GithubPullRequest struct {
+	Number  int                  `json:"number"`
+	State   string               `json:"state"`
+	Title   string               `json:"title"`
+	HTMLURL string               `json:"html_url"`
+	Merged  bool                 `json:"merged"`
+	User    GithubSender         `json:"user"`
+	Labels  []GithubLabel        `json:"labels"`
+	Head    GithubPullRequestRef `json:"head"`
+	Base    GithubPullRequestRef `json:"base"`
+}
+
+type GithubPullRequestRef struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	Sha   string `json:"sha"`
+	Repo  *struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+	} `json:"repo"`
+}
+
+type GithubLabel struct {
+	Name string `json:"name"`
+}
+
 type GithubSender struct {
 	Login             string `json:"login"`
 	ID                int    `json:"id"`
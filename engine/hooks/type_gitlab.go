@@ -22,6 +22,33 @@ type GitlabEvent struct {
 	Repository        *GitlabRepository `json:"repository"`
 	Commits           []GitlabCommit    `json:"commits"`
 	TotalCommitsCount int               `json:"total_commits_count"`
+	// Merge request events
+	User             *GitlabUser         `json:"user"`
+	ObjectAttributes *GitlabMergeRequest `json:"object_attributes"`
+	Labels           []GitlabLabel       `json:"labels"`
+}
+
+type GitlabUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type GitlabLabel struct {
+	Title string `json:"title"`
+}
+
+type GitlabMergeRequest struct {
+	IID          int            `json:"iid"`
+	Title        string         `json:"title"`
+	URL          string         `json:"url"`
+	State        string         `json:"state"`
+	Action       string         `json:"action"`
+	SourceBranch string         `json:"source_branch"`
+	TargetBranch string         `json:"target_branch"`
+	Source       *GitlabProject `json:"source"`
+	Target       *GitlabProject `json:"target"`
+	LastCommit   *GitlabCommit  `json:"last_commit"`
 }
 
 type GitlabCommit struct {
@@ -12,6 +12,7 @@ const (
 	TaskExecutionDoing     = "DOING"
 	TaskExecutionDone      = "DONE"
 	TaskExecutionScheduled = "SCHEDULED"
+	TaskExecutionRejected  = "REJECTED"
 )
 
 // Service is the stuct representing a hooks µService
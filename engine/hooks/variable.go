@@ -8,6 +8,17 @@ const (
 	PR_PREVIOUS_BRANCH = "git.pr.previous.branch"
 	PR_PREVIOUS_HASH   = "git.pr.previous.hash"
 	PR_PREVIOUS_STATE  = "git.pr.previous.state"
+	PR_AUTHOR          = "git.pr.author"
+	PR_LABELS          = "git.pr.labels"
+	PR_FORK            = "git.pr.fork"
+	PR_MERGE_REF       = "git.pr.merge.ref"
+
+	PR_BASE_BRANCH     = "git.pr.base.branch"
+	PR_BASE_HASH       = "git.pr.base.hash"
+	PR_BASE_REPOSITORY = "git.pr.base.repository"
+	PR_HEAD_BRANCH     = "git.pr.head.branch"
+	PR_HEAD_HASH       = "git.pr.head.hash"
+	PR_HEAD_REPOSITORY = "git.pr.head.repository"
 
 	PR_REVIEWER        = "git.pr.reviewer"
 	PR_REVIEWER_EMAIL  = "git.pr.reviewer.email"
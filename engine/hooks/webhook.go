@@ -30,20 +30,36 @@ func (s *Service) doWebHookExecution(ctx context.Context, e *sdk.TaskExecution)
 }
 
 func getRepositoryHeader(whe *sdk.WebHookExecution, events []string) string {
-	if v, ok := whe.RequestHeader[GithubHeader]; ok && ((len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events)) {
+	// Gitea also sends the github header, it has to be checked first
+	if v, ok := whe.RequestHeader[GiteaHeader]; ok {
+		if (len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events) {
+			return GiteaHeader
+		}
+		return ""
+	} else if v, ok := whe.RequestHeader[GithubHeader]; ok && ((len(events) == 0 && v[0] == "push") || sdk.IsInArray(v[0], events)) {
 		return GithubHeader
 	} else if v, ok := whe.RequestHeader[GitlabHeader]; ok && ((len(events) == 0 && (v[0] == string(gitlab.EventTypePush) || v[0] == string(gitlab.EventTypeTagPush))) || sdk.IsInArray(v[0], events)) {
 		return GitlabHeader
-	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && ((len(events) == 0 && v[0] == "repo:refs_changed") || sdk.IsInArray(v[0], events)) {
-		return BitbucketHeader
-	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && ((len(events) == 0 && v[0] == "repo:push") || sdk.IsInArray(v[0], events)) {
+	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && isBitbucketCloudEvent(v[0]) && ((len(events) == 0 && v[0] == "repo:push") || sdk.IsInArray(v[0], events)) {
 		// We return a fake header to make a difference between server and cloud version
 		return BitbucketCloudHeader
+	} else if v, ok := whe.RequestHeader[BitbucketHeader]; ok && ((len(events) == 0 && v[0] == "repo:refs_changed") || sdk.IsInArray(v[0], events)) {
+		return BitbucketHeader
 	}
 	return ""
 }
 
+// isBitbucketCloudEvent returns true for the events only sent by bitbucket cloud, both versions use the same header
+func isBitbucketCloudEvent(event string) bool {
+	return event == "repo:push" || strings.HasPrefix(event, "pullrequest:")
+}
+
 func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecution) ([]sdk.WorkflowNodeRunHookEvent, error) {
+	// Approved pull requests go to the merge queue instead of triggering the workflow
+	if approval, err := s.executeMergeQueueApproval(ctx, t); approval || err != nil {
+		return nil, err
+	}
+
 	// Prepare a struct to send to CDS API
 	payloads := []map[string]interface{}{}
 
@@ -52,7 +68,16 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 		events = strings.Split(t.Config[sdk.HookConfigEventFilter].Value, ";")
 	}
 
-	switch getRepositoryHeader(t.WebHook, events) {
+	policy := newPullRequestPolicy(t.Config)
+	header := getRepositoryHeader(t.WebHook, events)
+	// When the merge refs of the pull requests are built, the push events are also received to know when their base moves
+	var baseUpdateOnly bool
+	if header == "" && policy.mergeRef {
+		header = getRepositoryHeader(t.WebHook, nil)
+		baseUpdateOnly = header != ""
+	}
+
+	switch header {
 	case GithubHeader:
 		headerValue := t.WebHook.RequestHeader[GithubHeader][0]
 		payload, err := s.generatePayloadFromGithubRequest(ctx, t, headerValue)
@@ -62,6 +87,15 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 		if payload != nil {
 			payloads = append(payloads, payload)
 		}
+	case GiteaHeader:
+		headerValue := t.WebHook.RequestHeader[GiteaHeader][0]
+		payload, err := s.generatePayloadFromGiteaRequest(ctx, t, headerValue)
+		if err != nil {
+			return nil, err
+		}
+		if payload != nil {
+			payloads = append(payloads, payload)
+		}
 	case GitlabHeader:
 		headerValue := t.WebHook.RequestHeader[GitlabHeader][0]
 		payload, err := s.generatePayloadFromGitlabRequest(ctx, t, headerValue)
@@ -90,8 +124,54 @@ func (s *Service) executeRepositoryWebHook(ctx context.Context, t *sdk.TaskExecu
 		return nil, fmt.Errorf("Repository manager not found. Cannot read request body")
 	}
 
+	// The candidates of the merge queue are started by the API, the pushes on their branches must not trigger the workflow again
+	payloads = skipMergeQueueCandidates(ctx, payloads)
+
+	if policy.mergeRef {
+		var basePayloads []map[string]interface{}
+		for _, payload := range payloads {
+			if !isPullRequestBaseUpdate(payload) {
+				continue
+			}
+			event, _ := payload[GIT_EVENT].(string)
+			prPayloads, err := s.getPayloadsFromPullRequestBase(ctx, t, event, payload[GIT_BRANCH].(string))
+			if err != nil {
+				return nil, err
+			}
+			basePayloads = append(basePayloads, prPayloads...)
+		}
+		if baseUpdateOnly {
+			payloads = nil
+		}
+		payloads = append(payloads, basePayloads...)
+	}
+
+	filter := newPathFilter(t.Config)
+	files, hasFiles := getChangedFilesFromWebHook(header, t.WebHook)
+
 	hs := make([]sdk.WorkflowNodeRunHookEvent, 0, len(payloads))
 	for _, payload := range payloads {
+		if !filter.isEmpty() {
+			// The files of the push event are not the files changed by the pull requests rebuilt on their base update
+			payloadFiles, payloadHasFiles := files, hasFiles
+			if _, ok := payload[PR_ID]; ok {
+				payloadFiles, payloadHasFiles = nil, false
+			}
+			branch, _ := payload[GIT_BRANCH].(string)
+			head, _ := payload[GIT_HASH].(string)
+			base, _ := payload[GIT_HASH_BEFORE].(string)
+			if dest, ok := payload[GIT_HASH_DEST].(string); ok && base == "" {
+				base = dest
+			}
+			match, err := s.matchPathFilter(ctx, t.UUID, filter, payloadFiles, payloadHasFiles, branch, base, head)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+		}
+
 		h := sdk.WorkflowNodeRunHookEvent{
 			WorkflowNodeHookUUID: t.UUID,
 		}
@@ -0,0 +1,103 @@
+package hooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Headers used by the repository managers to sign the payloads of webhooks
+const (
+	GithubSignatureHeader    = "X-Hub-Signature-256"
+	BitbucketSignatureHeader = "X-Hub-Signature"
+	GitlabTokenHeader        = "X-Gitlab-Token"
+	GiteaSignatureHeader     = "X-Gitea-Signature"
+)
+
+// checkRepositoryWebHookSignature checks the payload of a repository webhook with the secret of the hook, a hook
+// without secret rejects all the requests. Rejected requests are recorded as task executions that are never processed.
+func (s *Service) checkRepositoryWebHookSignature(ctx context.Context, t *sdk.Task, r *http.Request, body []byte) error {
+	var err error
+	if secret := t.Config[sdk.HookConfigWebHookSecret].Value; secret == "" {
+		err = fmt.Errorf("no secret configured for the hook")
+	} else {
+		err = verifyRepositoryWebHookSignature(r.Header, body, secret)
+	}
+	if err == nil {
+		return nil
+	}
+
+	log.Warning(ctx, "checkRepositoryWebHookSignature> request on repository webhook %s from %s rejected: %v", t.UUID, r.RemoteAddr, err)
+	now := time.Now().UnixNano()
+	exec := &sdk.TaskExecution{
+		Timestamp:           now,
+		ProcessingTimestamp: now,
+		Type:                t.Type,
+		UUID:                t.UUID,
+		Config:              t.Config.WithoutSecrets(),
+		Status:              TaskExecutionRejected,
+		LastError:           err.Error(),
+		NbErrors:            1,
+		WebHook: &sdk.WebHookExecution{
+			RequestBody:   body,
+			RequestHeader: r.Header,
+			RequestURL:    r.URL.RawQuery,
+		},
+	}
+	if err := s.Dao.SaveTaskExecution(exec); err != nil {
+		log.Error(ctx, "checkRepositoryWebHookSignature> unable to save rejected execution of %s: %v", t.UUID, err)
+	}
+	return sdk.NewErrorFrom(sdk.ErrUnauthorized, "invalid webhook signature")
+}
+
+// verifyRepositoryWebHookSignature checks the signature of a payload according to the repository manager that sent it:
+// gitea and github sign the payload with HMAC-SHA256, bitbucket server and cloud too, and gitlab sends the secret as a token.
+func verifyRepositoryWebHookSignature(header http.Header, body []byte, secret string) error {
+	switch {
+	// Gitea also sends the github headers, it has to be checked first
+	case header.Get(GiteaHeader) != "":
+		return verifyHMACSignature(header.Get(GiteaSignatureHeader), "", body, secret)
+	case header.Get(GithubHeader) != "":
+		return verifyHMACSignature(header.Get(GithubSignatureHeader), "sha256=", body, secret)
+	case header.Get(GitlabHeader) != "":
+		token := header.Get(GitlabTokenHeader)
+		if token == "" {
+			return fmt.Errorf("missing %s header", GitlabTokenHeader)
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return fmt.Errorf("invalid %s header", GitlabTokenHeader)
+		}
+		return nil
+	case header.Get(BitbucketHeader) != "":
+		return verifyHMACSignature(header.Get(BitbucketSignatureHeader), "sha256=", body, secret)
+	}
+	return fmt.Errorf("unknown repository manager")
+}
+
+func verifyHMACSignature(signature, prefix string, body []byte, secret string) error {
+	if signature == "" {
+		return fmt.Errorf("missing signature")
+	}
+	if !strings.HasPrefix(signature, prefix) {
+		return fmt.Errorf("unsupported signature %q", strings.SplitN(signature, "=", 2)[0])
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return fmt.Errorf("invalid signature")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
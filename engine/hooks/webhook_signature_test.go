@@ -0,0 +1,137 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func Test_verifyRepositoryWebHookSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	secret := "my-secret"
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name   string
+		header http.Header
+		valid  bool
+	}{
+		{
+			name:   "github",
+			header: http.Header{GithubHeader: {"push"}, GithubSignatureHeader: {"sha256=" + signature}},
+			valid:  true,
+		},
+		{
+			name:   "github with a sha1 signature",
+			header: http.Header{GithubHeader: {"push"}, GithubSignatureHeader: {"sha1=" + signature}},
+		},
+		{
+			name:   "github without signature",
+			header: http.Header{GithubHeader: {"push"}},
+		},
+		{
+			name:   "gitlab",
+			header: http.Header{GitlabHeader: {"Push Hook"}, GitlabTokenHeader: {secret}},
+			valid:  true,
+		},
+		{
+			name:   "gitlab with a wrong token",
+			header: http.Header{GitlabHeader: {"Push Hook"}, GitlabTokenHeader: {"other-secret"}},
+		},
+		{
+			name:   "bitbucket",
+			header: http.Header{BitbucketHeader: {"repo:refs_changed"}, BitbucketSignatureHeader: {"sha256=" + signature}},
+			valid:  true,
+		},
+		{
+			name:   "bitbucket with a wrong signature",
+			header: http.Header{BitbucketHeader: {"repo:refs_changed"}, BitbucketSignatureHeader: {"sha256=0123456789abcdef"}},
+		},
+		{
+			name:   "gitea",
+			header: http.Header{GiteaHeader: {"push"}, GithubHeader: {"push"}, GiteaSignatureHeader: {signature}},
+			valid:  true,
+		},
+		{
+			name:   "gitea with the github signature of another secret",
+			header: http.Header{GiteaHeader: {"push"}, GithubHeader: {"push"}, GithubSignatureHeader: {"sha256=" + signature}, GiteaSignatureHeader: {"0123456789abcdef"}},
+		},
+		{
+			name:   "unknown repository manager",
+			header: http.Header{"X-Event": {"push"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyRepositoryWebHookSignature(tt.header, body, secret)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	// the signature depends on the payload
+	assert.Error(t, verifyRepositoryWebHookSignature(http.Header{GithubHeader: {"push"}, GithubSignatureHeader: {"sha256=" + signature}}, []byte(`{}`), secret))
+}
+
+func Test_webhookHandlerDoesNotSendSecret(t *testing.T) {
+	log.SetLogger(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+
+	secret := sdk.RandomString(20)
+	task := &sdk.Task{
+		UUID: sdk.UUID(),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.WebHookModelConfigMethod: {Value: http.MethodPost},
+			sdk.HookConfigWebHookSecret:  {Value: secret},
+		},
+	}
+	require.NoError(t, s.Dao.SaveTask(task))
+	defer s.Dao.DeleteTask(context.TODO(), task) // nolint
+
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint
+	req := httptest.NewRequest(http.MethodPost, "/webhook/"+task.UUID, bytes.NewReader(body))
+	req.Header.Set(GithubHeader, "push")
+	req.Header.Set(GithubSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	req = mux.SetURLVars(req, map[string]string{"uuid": task.UUID})
+	rec := httptest.NewRecorder()
+	require.NoError(t, s.webhookHandler()(context.TODO(), rec, req))
+	assert.NotContains(t, rec.Body.String(), secret)
+
+	// The executions and the task sent to users don't contain the secret either
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/task/"+task.UUID+"/execution", nil), map[string]string{"uuid": task.UUID})
+	rec = httptest.NewRecorder()
+	require.NoError(t, s.getTaskExecutionsHandler()(context.TODO(), rec, req))
+	assert.Contains(t, rec.Body.String(), task.UUID)
+	assert.NotContains(t, rec.Body.String(), secret)
+
+	// A hook without secret rejects all the payloads
+	delete(task.Config, sdk.HookConfigWebHookSecret)
+	require.NoError(t, s.Dao.SaveTask(task))
+	req = httptest.NewRequest(http.MethodPost, "/webhook/"+task.UUID, bytes.NewReader(body))
+	req.Header.Set(GithubHeader, "push")
+	req = mux.SetURLVars(req, map[string]string{"uuid": task.UUID})
+	err := s.webhookHandler()(context.TODO(), httptest.NewRecorder(), req)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+}
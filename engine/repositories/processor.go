@@ -70,6 +70,21 @@ func (s *Service) do(ctx context.Context, op sdk.Operation) error {
 					}
 					log.ErrorWithFields(ctx, fields, "%s", err)
 
+					op.Error = sdk.ExtractHTTPError(err, "").Error()
+					op.Status = sdk.OperationStatusError
+				} else {
+					op.Error = ""
+					op.Status = sdk.OperationStatusDone
+				}
+			case len(op.LoadChanges.Commits) > 0:
+				if err := s.processLoadChanges(ctx, &op); err != nil {
+					isErrWithStack := sdk.IsErrorWithStack(err)
+					fields := logrus.Fields{}
+					if isErrWithStack {
+						fields["stack_trace"] = fmt.Sprintf("%+v", err)
+					}
+					log.ErrorWithFields(ctx, fields, "%s", err)
+
 					op.Error = sdk.ExtractHTTPError(err, "").Error()
 					op.Status = sdk.OperationStatusError
 				} else {
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	repo "github.com/fsamin/go-repo"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (s *Service) processLoadChanges(ctx context.Context, op *sdk.Operation) error {
+	r := s.Repo(*op)
+
+	gitRepo, err := repo.New(r.Basedir)
+	if err != nil {
+		log.Error(ctx, "Repositories> processLoadChanges> repo.New > [%s] Error: %v", op.UUID, err)
+		return err
+	}
+
+	files := map[string]struct{}{}
+	for _, hash := range op.LoadChanges.Commits {
+		commit, err := gitRepo.GetCommitWithDiff(hash)
+		if err != nil {
+			log.Error(ctx, "Repositories> processLoadChanges> GetCommitWithDiff > [%s] Error: %v", op.UUID, err)
+			return sdk.WrapError(err, "unable to get changes of commit %s", hash)
+		}
+		for f := range commit.Files {
+			// A renamed file is listed with both its old and new names
+			for _, name := range strings.Split(f, "\t") {
+				files[strings.TrimSpace(name)] = struct{}{}
+			}
+		}
+	}
+
+	op.LoadChanges.Results = make([]string, 0, len(files))
+	for f := range files {
+		op.LoadChanges.Results = append(op.LoadChanges.Results, f)
+	}
+	sort.Strings(op.LoadChanges.Results)
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestProcessLoadChanges(t *testing.T) {
+	basedir, err := ioutil.TempDir("", "cds-repositories")
+	require.NoError(t, err)
+	defer os.RemoveAll(basedir) // nolint
+
+	s := Service{Cfg: Configuration{Basedir: basedir}}
+	op := sdk.Operation{UUID: sdk.UUID(), URL: "https://github.com/fsamin/go-repo.git"}
+	repoDir := s.Repo(op).Basedir
+	require.NoError(t, os.MkdirAll(repoDir, os.FileMode(0755)))
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=cds", "-c", "user.email=cds@localhost"}, args...)...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(files map[string]string, message string) string {
+		for f, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Join(repoDir, filepath.Dir(f)), os.FileMode(0755)))
+			require.NoError(t, ioutil.WriteFile(filepath.Join(repoDir, f), []byte(content), os.FileMode(0644)))
+		}
+		git("add", "-A")
+		git("commit", "-m", message)
+		return git("rev-parse", "HEAD")
+	}
+
+	git("init")
+	commit(map[string]string{"README.md": "readme"}, "init")
+	first := commit(map[string]string{"api/main.go": "package main"}, "api")
+	second := commit(map[string]string{"ui/index.html": "<html></html>", "README.md": "new readme"}, "ui")
+
+	op.LoadChanges.Commits = []string{second, first}
+	require.NoError(t, s.processLoadChanges(context.TODO(), &op))
+	require.Equal(t, []string{"README.md", "api/main.go", "ui/index.html"}, op.LoadChanges.Results)
+}
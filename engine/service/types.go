@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rsa"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -21,6 +22,13 @@ type APIServiceConfiguration struct {
 	MaxHeartbeatFailures int    `toml:"maxHeartbeatFailures" default:"10" json:"maxHeartbeatFailures"`
 }
 
+// HatcheryWarmPool is the configuration of the idle workers kept by a hatchery for a worker model.
+type HatcheryWarmPool struct {
+	Model       string `toml:"model" comment:"Worker model path: group/name" json:"model"`
+	Size        int    `toml:"size" comment:"Number of idle workers to keep" json:"size"`
+	MaxIdleTime int    `toml:"maxIdleTime" default:"1800" comment:"An idle worker is recycled after n Seconds" json:"maxIdleTime"`
+}
+
 // HatcheryCommonConfiguration is the base configuration for all hatcheries
 type HatcheryCommonConfiguration struct {
 	Name          string `toml:"name" default:"" comment:"Name of Hatchery" json:"name"`
@@ -40,12 +48,14 @@ type HatcheryCommonConfiguration struct {
 		MaxHeartbeatFailures int    `toml:"maxHeartbeatFailures" default:"10" comment:"Maximum allowed consecutives failures on heatbeat routine" json:"maxHeartbeatFailures"`
 	} `toml:"api" json:"api"`
 	Provision struct {
-		Disabled                  bool `toml:"disabled" default:"false" comment:"Disabled provisioning. Format:true or false" json:"disabled"`
-		RatioService              *int `toml:"ratioService" default:"50" commented:"true" comment:"Percent reserved for spawning worker with service requirement" json:"ratioService,omitempty" mapstructure:"ratioService"`
-		MaxWorker                 int  `toml:"maxWorker" default:"10" comment:"Maximum allowed simultaneous workers" json:"maxWorker"`
-		MaxConcurrentProvisioning int  `toml:"maxConcurrentProvisioning" default:"10" comment:"Maximum allowed simultaneous workers provisioning" json:"maxConcurrentProvisioning"`
-		MaxConcurrentRegistering  int  `toml:"maxConcurrentRegistering" default:"2" comment:"Maximum allowed simultaneous workers registering. -1 to disable registering on this hatchery" json:"maxConcurrentRegistering"`
-		RegisterFrequency         int  `toml:"registerFrequency" default:"60" comment:"Check if some worker model have to be registered each n Seconds" json:"registerFrequency"`
+		Disabled                  bool               `toml:"disabled" default:"false" comment:"Disabled provisioning. Format:true or false" json:"disabled"`
+		RatioService              *int               `toml:"ratioService" default:"50" commented:"true" comment:"Percent reserved for spawning worker with service requirement" json:"ratioService,omitempty" mapstructure:"ratioService"`
+		MaxWorker                 int                `toml:"maxWorker" default:"10" comment:"Maximum allowed simultaneous workers" json:"maxWorker"`
+		MaxConcurrentProvisioning int                `toml:"maxConcurrentProvisioning" default:"10" comment:"Maximum allowed simultaneous workers provisioning" json:"maxConcurrentProvisioning"`
+		MaxConcurrentRegistering  int                `toml:"maxConcurrentRegistering" default:"2" comment:"Maximum allowed simultaneous workers registering. -1 to disable registering on this hatchery" json:"maxConcurrentRegistering"`
+		RegisterFrequency         int                `toml:"registerFrequency" default:"60" comment:"Check if some worker model have to be registered each n Seconds" json:"registerFrequency"`
+		LocalServices             bool               `toml:"localServices" default:"false" comment:"Workers start the service requirements of the jobs on the Docker engine of their host, for hatcheries that can't start services themselves (local, openstack, vsphere). The worker models need a Docker engine" json:"localServices" mapstructure:"localServices"`
+		WarmPools                 []HatcheryWarmPool `toml:"warmPools" comment:"Keep pre-booted idle workers for some worker models, idle workers take a matching job as soon as it is in queue\n Example:\n [[hatchery.openstack.commonConfiguration.provision.warmPools]]\n model = \"shared.infra/debian10\"\n size = 2\n maxIdleTime = 1800" json:"warmPools,omitempty" mapstructure:"warmPools"`
 		WorkerLogsOptions         struct {
 			Graylog struct {
 				Host       string `toml:"host" comment:"Example: thot.ovh.com" json:"host"`
@@ -75,6 +85,20 @@ func (hcc HatcheryCommonConfiguration) Check() error {
 			hcc.Provision.MaxConcurrentRegistering, hcc.Provision.MaxWorker)
 	}
 
+	var nbWarmWorkers int
+	for _, p := range hcc.Provision.WarmPools {
+		if len(strings.Split(p.Model, "/")) != 2 {
+			return fmt.Errorf("invalid warm pool model %q, expected format is group/name", p.Model)
+		}
+		if p.Size < 0 || p.MaxIdleTime < 0 {
+			return fmt.Errorf("invalid warm pool for model %s: size and maxIdleTime cannot be negative", p.Model)
+		}
+		nbWarmWorkers += p.Size
+	}
+	if nbWarmWorkers > hcc.Provision.MaxWorker {
+		return fmt.Errorf("warm pools (%d workers) cannot be greater than maxWorker (value: %d)", nbWarmWorkers, hcc.Provision.MaxWorker)
+	}
+
 	if hcc.API.HTTP.URL == "" {
 		return fmt.Errorf("API HTTP(s) URL is mandatory")
 	}
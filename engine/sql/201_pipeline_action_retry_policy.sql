@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE "pipeline_action" ADD COLUMN IF NOT EXISTS retry_policy JSONB;
+
+-- +migrate Down
+ALTER TABLE "pipeline_action" DROP COLUMN retry_policy;
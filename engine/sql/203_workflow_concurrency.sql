@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE "workflow" ADD COLUMN IF NOT EXISTS concurrency JSONB;
+ALTER TABLE "workflow_run" ADD COLUMN IF NOT EXISTS concurrency_group VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "workflow_node_run" ADD COLUMN IF NOT EXISTS concurrency_group VARCHAR(256) NOT NULL DEFAULT '';
+SELECT create_index('workflow_run', 'IDX_WORKFLOW_RUN_CONCURRENCY_GROUP', 'project_id,concurrency_group');
+SELECT create_index('workflow_node_run', 'IDX_WORKFLOW_NODE_RUN_CONCURRENCY_GROUP', 'concurrency_group');
+
+-- +migrate Down
+DROP INDEX IF EXISTS IDX_WORKFLOW_RUN_CONCURRENCY_GROUP;
+DROP INDEX IF EXISTS IDX_WORKFLOW_NODE_RUN_CONCURRENCY_GROUP;
+ALTER TABLE "workflow" DROP COLUMN concurrency;
+ALTER TABLE "workflow_run" DROP COLUMN concurrency_group;
+ALTER TABLE "workflow_node_run" DROP COLUMN concurrency_group;
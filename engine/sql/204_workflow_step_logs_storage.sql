@@ -0,0 +1,14 @@
+-- +migrate Up
+ALTER TABLE "workflow_node_run_job_logs" ADD COLUMN IF NOT EXISTS storage VARCHAR(20) NOT NULL DEFAULT 'database';
+
+CREATE TABLE IF NOT EXISTS "workflow_node_run_job_logs_chunk" (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_node_run_job_logs_id BIGINT NOT NULL,
+  size BIGINT NOT NULL DEFAULT 0,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_JOB_LOGS_CHUNK_LOGS', 'workflow_node_run_job_logs_chunk', 'workflow_node_run_job_logs', 'workflow_node_run_job_logs_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "workflow_node_run_job_logs_chunk";
+ALTER TABLE "workflow_node_run_job_logs" DROP COLUMN storage;
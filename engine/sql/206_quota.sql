@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "quota" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT,
+  group_id BIGINT,
+  max_jobs BIGINT NOT NULL DEFAULT 0,
+  max_workers_by_model BIGINT NOT NULL DEFAULT 0,
+  max_memory BIGINT NOT NULL DEFAULT 0,
+  CHECK ((project_id IS NULL) <> (group_id IS NULL))
+);
+SELECT create_foreign_key_idx_cascade('FK_QUOTA_PROJECT', 'quota', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_QUOTA_GROUP', 'quota', 'group', 'group_id', 'id');
+SELECT create_unique_index('quota', 'IDX_QUOTA_PROJECT', 'project_id');
+SELECT create_unique_index('quota', 'IDX_QUOTA_GROUP', 'group_id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "quota";
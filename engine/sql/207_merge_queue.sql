@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "merge_queue_entry" (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  workflow_id BIGINT NOT NULL,
+  hook_uuid VARCHAR(256) NOT NULL,
+  repository VARCHAR(512) NOT NULL,
+  base_branch VARCHAR(512) NOT NULL,
+  pull_request_id BIGINT NOT NULL,
+  pull_request_title TEXT NOT NULL DEFAULT '',
+  pull_request_author VARCHAR(256) NOT NULL DEFAULT '',
+  head_hash VARCHAR(256) NOT NULL,
+  fork BOOLEAN NOT NULL DEFAULT false,
+  status VARCHAR(64) NOT NULL,
+  message TEXT NOT NULL DEFAULT '',
+  base_hash VARCHAR(256) NOT NULL DEFAULT '',
+  candidate_branch VARCHAR(512) NOT NULL DEFAULT '',
+  candidate_hash VARCHAR(256) NOT NULL DEFAULT '',
+  workflow_run_id BIGINT NOT NULL DEFAULT 0,
+  workflow_run_number BIGINT NOT NULL DEFAULT 0,
+  auth_consumer_id VARCHAR(64) NOT NULL DEFAULT '',
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_foreign_key_idx_cascade('FK_MERGE_QUEUE_ENTRY_PROJECT', 'merge_queue_entry', 'project', 'project_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_MERGE_QUEUE_ENTRY_WORKFLOW', 'merge_queue_entry', 'workflow', 'workflow_id', 'id');
+SELECT create_index('merge_queue_entry', 'IDX_MERGE_QUEUE_ENTRY_STATUS', 'status');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "merge_queue_entry";
@@ -107,3 +107,18 @@ func (client *bitbucketcloudClient) Branch(ctx context.Context, fullname, theBra
 
 	return branchResult, nil
 }
+
+// CreateBranch is not implemented for Bitbucket Cloud
+func (client *bitbucketcloudClient) CreateBranch(ctx context.Context, repo, branch, hash string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// DeleteBranch is not implemented for Bitbucket Cloud
+func (client *bitbucketcloudClient) DeleteBranch(ctx context.Context, repo, branch string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// MergeBranch is not implemented for Bitbucket Cloud
+func (client *bitbucketcloudClient) MergeBranch(ctx context.Context, repo string, merge sdk.VCSBranchMergeRequest) (sdk.VCSCommit, error) {
+	return sdk.VCSCommit{}, sdk.WithStack(sdk.ErrNotImplemented)
+}
@@ -167,3 +167,8 @@ func (pullr PullRequest) ToVCSPullRequest() sdk.VCSPullRequest {
 		Merged: pullr.State == "MERGED",
 	}
 }
+
+// PullRequestMerge is not implemented for Bitbucket Cloud
+func (client *bitbucketcloudClient) PullRequestMerge(ctx context.Context, repo string, id int, merge sdk.VCSPullRequestMergeRequest) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
@@ -86,3 +86,18 @@ func (b *bitbucketClient) Branch(ctx context.Context, fullname string, filter st
 	}
 	return nil, sdk.ErrNoBranch
 }
+
+// CreateBranch is not implemented for Bitbucket Server
+func (b *bitbucketClient) CreateBranch(ctx context.Context, repo, branch, hash string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// DeleteBranch is not implemented for Bitbucket Server
+func (b *bitbucketClient) DeleteBranch(ctx context.Context, repo, branch string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// MergeBranch is not implemented for Bitbucket Server
+func (b *bitbucketClient) MergeBranch(ctx context.Context, repo string, merge sdk.VCSBranchMergeRequest) (sdk.VCSCommit, error) {
+	return sdk.VCSCommit{}, sdk.WithStack(sdk.ErrNotImplemented)
+}
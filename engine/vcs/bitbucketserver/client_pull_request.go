@@ -164,9 +164,13 @@ func (b *bitbucketClient) PullRequestCreate(ctx context.Context, repo string, pr
 func (b *bitbucketClient) ToVCSPullRequest(ctx context.Context, repo string, pullRequest sdk.BitbucketServerPullRequest) (sdk.VCSPullRequest, error) {
 	pr := sdk.VCSPullRequest{
 		ID:     pullRequest.ID,
+		Title:  pullRequest.Title,
 		Closed: pullRequest.Closed,
 		Merged: pullRequest.State == "MERGED",
+		// Bitbucket computes the merge commit of the pull requests without conflict
+		MergeRef: fmt.Sprintf("refs/pull-requests/%d/merge", pullRequest.ID),
 		Base: sdk.VCSPushEvent{
+			Repo: fmt.Sprintf("%s/%s", pullRequest.ToRef.Repository.Project.Key, pullRequest.ToRef.Repository.Slug),
 			Branch: sdk.VCSBranch{
 				ID:           strings.Replace(pullRequest.ToRef.ID, "refs/heads/", "", 1),
 				DisplayID:    pullRequest.ToRef.DisplayID,
@@ -174,6 +178,7 @@ func (b *bitbucketClient) ToVCSPullRequest(ctx context.Context, repo string, pul
 			},
 		},
 		Head: sdk.VCSPushEvent{
+			Repo: fmt.Sprintf("%s/%s", pullRequest.FromRef.Repository.Project.Key, pullRequest.FromRef.Repository.Slug),
 			Branch: sdk.VCSBranch{
 				ID:           strings.Replace(pullRequest.FromRef.ID, "refs/heads/", "", 1),
 				DisplayID:    pullRequest.FromRef.DisplayID,
@@ -194,3 +199,8 @@ func (b *bitbucketClient) ToVCSPullRequest(ctx context.Context, repo string, pul
 
 	return pr, nil
 }
+
+// PullRequestMerge is not implemented for Bitbucket Server
+func (b *bitbucketClient) PullRequestMerge(ctx context.Context, repo string, id int, merge sdk.VCSPullRequestMergeRequest) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
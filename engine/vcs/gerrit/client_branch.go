@@ -71,3 +71,18 @@ func (c *gerritClient) Branch(ctx context.Context, fullname, branchName string)
 	}
 	return &newBranch, nil
 }
+
+// CreateBranch is not implemented for Gerrit
+func (c *gerritClient) CreateBranch(ctx context.Context, repo, branch, hash string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// DeleteBranch is not implemented for Gerrit
+func (c *gerritClient) DeleteBranch(ctx context.Context, repo, branch string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// MergeBranch is not implemented for Gerrit
+func (c *gerritClient) MergeBranch(ctx context.Context, repo string, merge sdk.VCSBranchMergeRequest) (sdk.VCSCommit, error) {
+	return sdk.VCSCommit{}, sdk.WithStack(sdk.ErrNotImplemented)
+}
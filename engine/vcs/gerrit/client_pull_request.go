@@ -43,3 +43,8 @@ func (c *gerritClient) PullRequestComment(ctx context.Context, repo string, prRe
 func (c *gerritClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	return sdk.VCSPullRequest{}, nil
 }
+
+// PullRequestMerge is not implemented for Gerrit
+func (c *gerritClient) PullRequestMerge(ctx context.Context, repo string, id int, merge sdk.VCSPullRequestMergeRequest) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
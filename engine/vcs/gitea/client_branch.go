@@ -0,0 +1,93 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Branches retrieves the branches
+func (c *giteaClient) Branches(ctx context.Context, fullname string) ([]sdk.VCSBranch, error) {
+	repo, err := c.repo(ctx, fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var branches []sdk.VCSBranch
+	err = c.getAll(ctx, "/repos/"+fullname+"/branches", nil, func(body []byte) (int, error) {
+		var page []Branch
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		for _, b := range page {
+			branches = append(branches, toVCSBranch(b, repo.DefaultBranch))
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+// Branch retrieves the branch
+func (c *giteaClient) Branch(ctx context.Context, fullname, branchName string) (*sdk.VCSBranch, error) {
+	repo, err := c.repo(ctx, fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Branch
+	if err := c.get(ctx, "/repos/"+fullname+"/branches/"+escapeRef(branchName), nil, &b); err != nil {
+		return nil, sdk.WrapError(err, "cannot get branch %s on repository %s", branchName, fullname)
+	}
+	br := toVCSBranch(b, repo.DefaultBranch)
+	return &br, nil
+}
+
+func (c *giteaClient) repo(ctx context.Context, fullname string) (Repository, error) {
+	var r Repository
+	if err := c.get(ctx, "/repos/"+fullname, nil, &r); err != nil {
+		return r, sdk.WrapError(err, "cannot get repository %s", fullname)
+	}
+	return r, nil
+}
+
+func toVCSBranch(b Branch, defaultBranch string) sdk.VCSBranch {
+	br := sdk.VCSBranch{
+		ID:        b.Name,
+		DisplayID: b.Name,
+		Default:   b.Name == defaultBranch,
+	}
+	if b.Commit != nil {
+		br.LatestCommit = b.Commit.ID
+	}
+	return br
+}
+
+// escapeRef escapes a git ref to be used in the path of an URL, slashes are kept as gitea expects them
+func escapeRef(ref string) string {
+	parts := strings.Split(ref, "/")
+	for i := range parts {
+		parts[i] = url.PathEscape(parts[i])
+	}
+	return strings.Join(parts, "/")
+}
+
+// CreateBranch is not implemented for Gitea
+func (c *giteaClient) CreateBranch(ctx context.Context, repo, branch, hash string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// DeleteBranch is not implemented for Gitea
+func (c *giteaClient) DeleteBranch(ctx context.Context, repo, branch string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// MergeBranch is not implemented for Gitea
+func (c *giteaClient) MergeBranch(ctx context.Context, repo string, merge sdk.VCSBranchMergeRequest) (sdk.VCSCommit, error) {
+	return sdk.VCSCommit{}, sdk.WithStack(sdk.ErrNotImplemented)
+}
@@ -0,0 +1,91 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Commits returns the commits of a branch from a commit SHA (since, excluded) until another commit SHA (until).
+// If until is empty, commits are listed until the head of the branch.
+func (c *giteaClient) Commits(ctx context.Context, repo, branch, since, until string) ([]sdk.VCSCommit, error) {
+	ref := until
+	if ref == "" {
+		ref = branch
+	}
+
+	var commits []sdk.VCSCommit
+	var found bool
+	params := url.Values{}
+	params.Set("sha", ref)
+	err := c.getAll(ctx, "/repos/"+repo+"/commits", params, func(body []byte) (int, error) {
+		if found {
+			return 0, nil
+		}
+		var page []Commit
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		for _, gc := range page {
+			if since != "" && gc.SHA == since {
+				found = true
+				return 0, nil
+			}
+			commits = append(commits, toVCSCommit(gc))
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// Commit retrieves a specific according to a hash
+func (c *giteaClient) Commit(ctx context.Context, repo, hash string) (sdk.VCSCommit, error) {
+	var gc Commit
+	if err := c.get(ctx, "/repos/"+repo+"/git/commits/"+url.PathEscape(hash), nil, &gc); err != nil {
+		return sdk.VCSCommit{}, sdk.WrapError(err, "cannot get commit %s on repository %s", hash, repo)
+	}
+	return toVCSCommit(gc), nil
+}
+
+// CommitsBetweenRefs returns the commits of head that are not in base
+func (c *giteaClient) CommitsBetweenRefs(ctx context.Context, repo, base, head string) ([]sdk.VCSCommit, error) {
+	var compare Compare
+	if err := c.get(ctx, "/repos/"+repo+"/compare/"+escapeRef(base)+"..."+escapeRef(head), nil, &compare); err != nil {
+		return nil, sdk.WrapError(err, "cannot compare %s and %s on repository %s", base, head, repo)
+	}
+
+	commits := make([]sdk.VCSCommit, len(compare.Commits))
+	for i := range compare.Commits {
+		commits[i] = toVCSCommit(compare.Commits[i])
+	}
+	return commits, nil
+}
+
+func toVCSCommit(gc Commit) sdk.VCSCommit {
+	commit := sdk.VCSCommit{
+		Hash: gc.SHA,
+		URL:  gc.HTMLURL,
+	}
+	if gc.RepoCommit != nil {
+		commit.Message = gc.RepoCommit.Message
+		if gc.RepoCommit.Author != nil {
+			commit.Author.Name = gc.RepoCommit.Author.Name
+			commit.Author.DisplayName = gc.RepoCommit.Author.Name
+			commit.Author.Email = gc.RepoCommit.Author.Email
+			if d, err := time.Parse(time.RFC3339, gc.RepoCommit.Author.Date); err == nil {
+				commit.Timestamp = d.Unix() * 1000
+			}
+		}
+	}
+	if gc.Author != nil {
+		commit.Author.DisplayName = gc.Author.Login
+		commit.Author.Avatar = gc.Author.Avatar
+	}
+	return commit
+}
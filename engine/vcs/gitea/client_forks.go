@@ -0,0 +1,27 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// ListForks returns the forks of a repository
+func (c *giteaClient) ListForks(ctx context.Context, repo string) ([]sdk.VCSRepo, error) {
+	var repos []sdk.VCSRepo
+	err := c.getAll(ctx, "/repos/"+repo+"/forks", nil, func(body []byte) (int, error) {
+		var page []Repository
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		for _, r := range page {
+			repos = append(repos, toVCSRepo(r))
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
@@ -0,0 +1,135 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// defaultHookEvents are the events sent to CDS when none are selected on the repository webhook
+var defaultHookEvents = []string{"push"}
+
+func (c *giteaClient) hookURL(hook sdk.VCSHook) string {
+	if c.proxyURL == "" || hook.Workflow {
+		return hook.URL
+	}
+	lastIndexSlash := strings.LastIndex(hook.URL, "/")
+	if c.proxyURL[len(c.proxyURL)-1] == '/' {
+		lastIndexSlash++
+	}
+	return c.proxyURL + hook.URL[lastIndexSlash:]
+}
+
+func (c *giteaClient) getHooks(ctx context.Context, repo string) ([]Hook, error) {
+	var hooks []Hook
+	err := c.getAll(ctx, "/repos/"+repo+"/hooks", nil, func(body []byte) (int, error) {
+		var page []Hook
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		hooks = append(hooks, page...)
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot list hooks of repository %s", repo)
+	}
+	return hooks, nil
+}
+
+// CreateHook creates a webhook on the repository, if a webhook with the same URL already exists it is kept
+func (c *giteaClient) CreateHook(ctx context.Context, repo string, hook *sdk.VCSHook) error {
+	hook.URL = c.hookURL(*hook)
+	if len(hook.Events) == 0 {
+		hook.Events = defaultHookEvents
+	}
+
+	hooks, err := c.getHooks(ctx, repo)
+	if err != nil {
+		return err
+	}
+	for _, h := range hooks {
+		if h.Config["url"] == hook.URL {
+			hook.ID = fmt.Sprintf("%d", h.ID)
+			return nil
+		}
+	}
+
+	opt := CreateHookOption{
+		Type: "gitea",
+		Config: map[string]string{
+			"url":          hook.URL,
+			"content_type": "json",
+			"secret":       hook.Secret,
+		},
+		Events: hook.Events,
+		Active: true,
+	}
+	var created Hook
+	log.Debug("GiteaClient.CreateHook: %s %s", repo, hook.URL)
+	if _, err := c.do(ctx, http.MethodPost, "/repos/"+repo+"/hooks", nil, opt, &created); err != nil {
+		return sdk.WrapError(err, "cannot create gitea hook with url: %s", hook.URL)
+	}
+	hook.ID = fmt.Sprintf("%d", created.ID)
+	return nil
+}
+
+// UpdateHook updates the events of a webhook
+func (c *giteaClient) UpdateHook(ctx context.Context, repo string, hook *sdk.VCSHook) error {
+	if len(hook.Events) == 0 {
+		hook.Events = defaultHookEvents
+	}
+	opt := EditHookOption{
+		Events: hook.Events,
+	}
+	if hook.Secret != "" {
+		opt.Config = map[string]string{
+			"content_type": "json",
+			"secret":       hook.Secret,
+		}
+	}
+	if _, err := c.do(ctx, http.MethodPatch, "/repos/"+repo+"/hooks/"+hook.ID, nil, opt, nil); err != nil {
+		return sdk.WrapError(err, "cannot update gitea hook %s", hook.ID)
+	}
+	return nil
+}
+
+// GetHook returns the webhook of the repository with the given URL
+func (c *giteaClient) GetHook(ctx context.Context, repo, webhookURL string) (sdk.VCSHook, error) {
+	hooks, err := c.getHooks(ctx, repo)
+	if err != nil {
+		return sdk.VCSHook{}, err
+	}
+	for _, h := range hooks {
+		if h.Config["url"] == webhookURL {
+			return sdk.VCSHook{
+				ID:          fmt.Sprintf("%d", h.ID),
+				Name:        h.Type,
+				Events:      h.Events,
+				URL:         h.Config["url"],
+				ContentType: h.Config["content_type"],
+				Disable:     !h.Active,
+			}, nil
+		}
+	}
+	return sdk.VCSHook{}, sdk.WithStack(sdk.ErrNotFound)
+}
+
+// DeleteHook deletes a webhook, from its ID if it is known or from its URL
+func (c *giteaClient) DeleteHook(ctx context.Context, repo string, hook sdk.VCSHook) error {
+	if hook.ID == "" {
+		h, err := c.GetHook(ctx, repo, c.hookURL(hook))
+		if err != nil {
+			return err
+		}
+		hook.ID = h.ID
+	}
+	if _, err := c.do(ctx, http.MethodDelete, "/repos/"+repo+"/hooks/"+hook.ID, nil, nil, nil); err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return sdk.WrapError(err, "cannot delete gitea hook %s", hook.ID)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// PullRequest returns a pull request from its number
+func (c *giteaClient) PullRequest(ctx context.Context, repo string, id int) (sdk.VCSPullRequest, error) {
+	var pr PullRequest
+	if err := c.get(ctx, fmt.Sprintf("/repos/%s/pulls/%d", repo, id), nil, &pr); err != nil {
+		return sdk.VCSPullRequest{}, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrNotFound,
+			"cannot found a pull request for repo %s with id %d", repo, id))
+	}
+	return toVCSPullRequest(pr), nil
+}
+
+// PullRequests fetch all the opened pull request for a repository
+func (c *giteaClient) PullRequests(ctx context.Context, repo string) ([]sdk.VCSPullRequest, error) {
+	var prs []sdk.VCSPullRequest
+	params := url.Values{}
+	params.Set("state", "open")
+	err := c.getAll(ctx, "/repos/"+repo+"/pulls", params, func(body []byte) (int, error) {
+		var page []PullRequest
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		for _, pr := range page {
+			prs = append(prs, toVCSPullRequest(pr))
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+// PullRequestComment push a new comment on a pull request
+func (c *giteaClient) PullRequestComment(ctx context.Context, repo string, prReq sdk.VCSPullRequestCommentRequest) error {
+	if c.disableStatus {
+		log.Warning(ctx, "gitea.PullRequestComment>  ⚠ Gitea statuses are disabled")
+		return nil
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, prReq.ID)
+	if _, err := c.do(ctx, http.MethodPost, path, nil, CreateIssueCommentOption{Body: prReq.Message}, nil); err != nil {
+		return sdk.WrapError(err, "unable to comment pull request %d on repository %s", prReq.ID, repo)
+	}
+	return nil
+}
+
+// PullRequestCreate create a new pullrequest
+func (c *giteaClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
+	opt := CreatePullRequestOption{
+		Title: pr.Title,
+		Head:  pr.Head.Branch.DisplayID,
+		Base:  pr.Base.Branch.DisplayID,
+	}
+	var created PullRequest
+	if _, err := c.do(ctx, http.MethodPost, "/repos/"+repo+"/pulls", nil, opt, &created); err != nil {
+		return sdk.VCSPullRequest{}, sdk.WrapError(err, "unable to create pull request on repository %s", repo)
+	}
+	return toVCSPullRequest(created), nil
+}
+
+func toVCSPullRequest(pr PullRequest) sdk.VCSPullRequest {
+	res := sdk.VCSPullRequest{
+		ID:     pr.Index,
+		URL:    pr.HTMLURL,
+		Title:  pr.Title,
+		Merged: pr.Merged,
+		Closed: pr.State == "closed",
+		Head:   toVCSPushEvent(pr.Head),
+		Base:   toVCSPushEvent(pr.Base),
+	}
+	if pr.Poster != nil {
+		res.User = sdk.VCSAuthor{
+			Name:        pr.Poster.Login,
+			DisplayName: pr.Poster.FullName,
+			Email:       pr.Poster.Email,
+			Avatar:      pr.Poster.Avatar,
+		}
+	}
+	for _, l := range pr.Labels {
+		res.Labels = append(res.Labels, l.Name)
+	}
+	return res
+}
+
+func toVCSPushEvent(b *PRBranchInfo) sdk.VCSPushEvent {
+	if b == nil {
+		return sdk.VCSPushEvent{}
+	}
+	e := sdk.VCSPushEvent{
+		Branch: sdk.VCSBranch{
+			ID:           b.Ref,
+			DisplayID:    b.Ref,
+			LatestCommit: b.Sha,
+		},
+		Commit: sdk.VCSCommit{
+			Hash: b.Sha,
+		},
+	}
+	if b.Repository != nil {
+		e.Repo = b.Repository.FullName
+		e.CloneURL = b.Repository.CloneURL
+	}
+	return e
+}
+
+// PullRequestMerge is not implemented for Gitea
+func (c *giteaClient) PullRequestMerge(ctx context.Context, repo string, id int, merge sdk.VCSPullRequestMergeRequest) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
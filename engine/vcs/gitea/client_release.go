@@ -0,0 +1,55 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Release creates a release on Gitea
+func (c *giteaClient) Release(ctx context.Context, repo string, tagName string, title string, releaseNote string) (*sdk.VCSRelease, error) {
+	opt := CreateReleaseOption{
+		TagName: tagName,
+		Title:   title,
+		Note:    releaseNote,
+	}
+	var release Release
+	if _, err := c.do(ctx, http.MethodPost, "/repos/"+repo+"/releases", nil, opt, &release); err != nil {
+		return nil, sdk.WrapError(err, "cannot create release %s on repository %s", tagName, repo)
+	}
+	return &sdk.VCSRelease{
+		ID:        release.ID,
+		UploadURL: fmt.Sprintf("/repos/%s/releases/%d/assets", repo, release.ID),
+	}, nil
+}
+
+// UploadReleaseFile attaches a file to a release, the upload URL is the path of the assets of the release in the gitea API
+func (c *giteaClient) UploadReleaseFile(ctx context.Context, repo string, releaseName string, uploadURL string, artifactName string, r io.ReadCloser) error {
+	defer r.Close()
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile("attachment", artifactName)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := w.Close(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	params := url.Values{}
+	params.Set("name", artifactName)
+	if _, err := c.doWithBody(ctx, http.MethodPost, uploadURL, params, w.FormDataContentType(), &body, nil); err != nil {
+		return sdk.WrapError(err, "cannot upload file %s on release %s", artifactName, releaseName)
+	}
+	return nil
+}
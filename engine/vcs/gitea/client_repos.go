@@ -0,0 +1,54 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Repos returns the list of accessible repositories
+func (c *giteaClient) Repos(ctx context.Context) ([]sdk.VCSRepo, error) {
+	var repos []sdk.VCSRepo
+	err := c.getAll(ctx, "/user/repos", nil, func(body []byte) (int, error) {
+		var page []Repository
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		for _, r := range page {
+			repos = append(repos, toVCSRepo(r))
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// RepoByFullname returns the repo from its fullname
+func (c *giteaClient) RepoByFullname(ctx context.Context, fullname string) (sdk.VCSRepo, error) {
+	var r Repository
+	if err := c.get(ctx, "/repos/"+fullname, nil, &r); err != nil {
+		return sdk.VCSRepo{}, sdk.WrapError(err, "cannot get repository %s", fullname)
+	}
+	return toVCSRepo(r), nil
+}
+
+// GrantWritePermission is not needed on gitea, the oauth2 token acts on behalf of the user
+func (c *giteaClient) GrantWritePermission(ctx context.Context, repo string) error {
+	return nil
+}
+
+func toVCSRepo(r Repository) sdk.VCSRepo {
+	return sdk.VCSRepo{
+		ID:           fmt.Sprintf("%d", r.ID),
+		Name:         r.Name,
+		Slug:         r.Name,
+		Fullname:     r.FullName,
+		URL:          r.HTMLURL,
+		HTTPCloneURL: r.CloneURL,
+		SSHCloneURL:  r.SSHURL,
+	}
+}
@@ -0,0 +1,140 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	giteaStatusPending = "pending"
+	giteaStatusSuccess = "success"
+	giteaStatusError   = "error"
+	giteaStatusFailure = "failure"
+	giteaStatusWarning = "warning"
+)
+
+type statusData struct {
+	status       string
+	url          string
+	desc         string
+	context      string
+	repoFullName string
+	hash         string
+}
+
+func getGiteaStateFromStatus(s string) string {
+	switch s {
+	case sdk.StatusWaiting, sdk.StatusChecking, sdk.StatusBuilding:
+		return giteaStatusPending
+	case sdk.StatusSuccess:
+		return giteaStatusSuccess
+	case sdk.StatusFail:
+		return giteaStatusFailure
+	case sdk.StatusDisabled, sdk.StatusNeverBuilt, sdk.StatusSkipped, sdk.StatusStopped:
+		return giteaStatusWarning
+	}
+	return giteaStatusError
+}
+
+// SetStatus set build status on Gitea
+func (c *giteaClient) SetStatus(ctx context.Context, event sdk.Event) error {
+	if c.disableStatus {
+		log.Warning(ctx, "gitea.SetStatus>  ⚠ Gitea statuses are disabled")
+		return nil
+	}
+
+	if event.EventType != fmt.Sprintf("%T", sdk.EventRunWorkflowNode{}) {
+		log.Debug("giteaClient.SetStatus> Unknown event %v", event)
+		return nil
+	}
+
+	data, err := processWorkflowNodeRunEvent(event, c.uiURL)
+	if err != nil {
+		return sdk.WrapError(err, "cannot process event %v", event)
+	}
+	if c.disableStatusDetail {
+		data.url = ""
+	}
+
+	opt := CreateStatusOption{
+		State:       getGiteaStateFromStatus(data.status),
+		TargetURL:   data.url,
+		Description: data.desc,
+		Context:     data.context,
+	}
+	path := fmt.Sprintf("/repos/%s/statuses/%s", data.repoFullName, data.hash)
+	if _, err := c.do(ctx, http.MethodPost, path, nil, opt, nil); err != nil {
+		return sdk.WrapError(err, "cannot set status - repo:%s hash:%s", data.repoFullName, data.hash)
+	}
+	return nil
+}
+
+// ListStatuses returns the CDS statuses of a ref
+func (c *giteaClient) ListStatuses(ctx context.Context, repo string, ref string) ([]sdk.VCSCommitStatus, error) {
+	var statuses []sdk.VCSCommitStatus
+	err := c.getAll(ctx, "/repos/"+repo+"/commits/"+escapeRef(ref)+"/statuses", nil, func(body []byte) (int, error) {
+		var page []Status
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		for _, s := range page {
+			if !strings.HasPrefix(s.Context, "CDS/") {
+				continue
+			}
+			statuses = append(statuses, sdk.VCSCommitStatus{
+				CreatedAt:  s.Created,
+				Decription: s.Context,
+				Ref:        ref,
+				State:      processGiteaState(s.State),
+			})
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to get commit statuses hash:%s", ref)
+	}
+	return statuses, nil
+}
+
+func processGiteaState(s string) string {
+	switch s {
+	case giteaStatusSuccess:
+		return sdk.StatusSuccess
+	case giteaStatusFailure, giteaStatusError:
+		return sdk.StatusFail
+	case giteaStatusWarning:
+		return sdk.StatusSkipped
+	case giteaStatusPending:
+		return sdk.StatusBuilding
+	default:
+		return sdk.StatusDisabled
+	}
+}
+
+func processWorkflowNodeRunEvent(event sdk.Event, uiURL string) (statusData, error) {
+	data := statusData{}
+	var eventNR sdk.EventRunWorkflowNode
+	if err := json.Unmarshal(event.Payload, &eventNR); err != nil {
+		return data, sdk.WrapError(err, "cannot read payload")
+	}
+
+	data.url = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d",
+		uiURL,
+		event.ProjectKey,
+		event.WorkflowName,
+		eventNR.Number,
+	)
+
+	data.context = sdk.VCSCommitStatusDescription(event.ProjectKey, event.WorkflowName, eventNR)
+	data.desc = eventNR.NodeName + ": " + eventNR.Status
+	data.hash = eventNR.Hash
+	data.repoFullName = eventNR.RepositoryFullName
+	data.status = eventNR.Status
+	return data, nil
+}
@@ -0,0 +1,35 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Tags retrieves the tags
+func (c *giteaClient) Tags(ctx context.Context, fullname string) ([]sdk.VCSTag, error) {
+	var tags []sdk.VCSTag
+	err := c.getAll(ctx, "/repos/"+fullname+"/tags", nil, func(body []byte) (int, error) {
+		var page []Tag
+		if err := json.Unmarshal(body, &page); err != nil {
+			return 0, err
+		}
+		for _, t := range page {
+			tag := sdk.VCSTag{
+				Tag:     t.Name,
+				Sha:     t.ID,
+				Message: t.Message,
+			}
+			if t.Commit != nil {
+				tag.Hash = t.Commit.SHA
+			}
+			tags = append(tags, tag)
+		}
+		return len(page), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
@@ -0,0 +1,61 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	_ sdk.VCSAuthorizedClient = &giteaClient{}
+	_ sdk.VCSServer           = &giteaConsumer{}
+)
+
+// giteaClient implements VCSAuthorizedClient interface
+type giteaClient struct {
+	httpClient          *http.Client
+	apiURL              string
+	accessToken         string
+	refreshToken        string
+	expiration          time.Time
+	cache               cache.Store
+	uiURL               string
+	proxyURL            string
+	disableStatus       bool
+	disableStatusDetail bool
+}
+
+// giteaConsumer implements vcs.Server and it's used to instantiate a giteaClient
+type giteaConsumer struct {
+	URL                      string `json:"url"`
+	clientID                 string
+	clientSecret             string
+	cache                    cache.Store
+	AuthorizationCallbackURL string
+	uiURL                    string
+	proxyURL                 string
+	disableStatus            bool
+	disableStatusDetail      bool
+}
+
+// New instantiates a new gitea consumer
+func New(clientID, clientSecret, URL, callbackURL, uiURL, proxyURL string, store cache.Store, disableStatus, disableStatusDetail bool) sdk.VCSServer {
+	return &giteaConsumer{
+		URL:                      URL,
+		clientID:                 clientID,
+		clientSecret:             clientSecret,
+		cache:                    store,
+		AuthorizationCallbackURL: callbackURL,
+		uiURL:                    uiURL,
+		proxyURL:                 proxyURL,
+		disableStatus:            disableStatus,
+		disableStatusDetail:      disableStatusDetail,
+	}
+}
+
+func (c *giteaClient) GetAccessToken(_ context.Context) string {
+	return c.accessToken
+}
@@ -0,0 +1,282 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// recordedRequest is a request received by the fake gitea server
+type recordedRequest struct {
+	method string
+	path   string
+	query  string
+	auth   string
+	body   []byte
+}
+
+// fakeGitea serves the responses of the gitea API recorded in the testdata directory
+type fakeGitea struct {
+	t        *testing.T
+	server   *httptest.Server
+	routes   map[string]string
+	requests []recordedRequest
+}
+
+func newFakeGitea(t *testing.T, routes map[string]string) *fakeGitea {
+	f := &fakeGitea{t: t, routes: routes}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+func (f *fakeGitea) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	f.requests = append(f.requests, recordedRequest{
+		method: r.Method,
+		path:   r.URL.Path,
+		query:  r.URL.RawQuery,
+		auth:   r.Header.Get("Authorization"),
+		body:   body,
+	})
+
+	// listing routes return an empty page after the first one
+	if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]")) // nolint
+		return
+	}
+
+	fixture, ok := f.routes[r.Method+" "+r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`)) // nolint
+		return
+	}
+	if fixture == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	btes, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+	require.NoError(f.t, err)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == http.MethodPost {
+		w.WriteHeader(http.StatusCreated)
+	}
+	w.Write(btes) // nolint
+}
+
+func (f *fakeGitea) client(t *testing.T) *giteaClient {
+	log.SetLogger(t)
+	consumer := New("client-id", "client-secret", f.server.URL, "https://cds.example.com/oauth/callback", "https://cds.example.com", "", nil, false, false)
+	c, err := consumer.GetAuthorizedClient(context.Background(), "access-token-"+t.Name(), "refresh-token", 0)
+	require.NoError(t, err)
+	return c.(*giteaClient)
+}
+
+func TestRepos(t *testing.T) {
+	f := newFakeGitea(t, map[string]string{
+		"GET /api/v1/user/repos": "repos.json",
+	})
+	defer f.server.Close()
+	c := f.client(t)
+
+	repos, err := c.Repos(context.Background())
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	assert.Equal(t, "1", repos[0].ID)
+	assert.Equal(t, "cds/demo", repos[0].Fullname)
+	assert.Equal(t, "https://gitea.example.com/cds/demo.git", repos[0].HTTPCloneURL)
+	assert.Equal(t, "git@gitea.example.com:cds/demo.git", repos[0].SSHCloneURL)
+
+	// the listing stops on the first empty page
+	require.Len(t, f.requests, 2)
+	assert.Equal(t, "limit=50&page=1", f.requests[0].query)
+	assert.Equal(t, "limit=50&page=2", f.requests[1].query)
+	assert.Equal(t, "token access-token-TestRepos", f.requests[0].auth)
+}
+
+func TestBranches(t *testing.T) {
+	f := newFakeGitea(t, map[string]string{
+		"GET /api/v1/repos/cds/demo":          "repo.json",
+		"GET /api/v1/repos/cds/demo/branches": "branches.json",
+	})
+	defer f.server.Close()
+	c := f.client(t)
+
+	branches, err := c.Branches(context.Background(), "cds/demo")
+	require.NoError(t, err)
+	require.Len(t, branches, 2)
+	assert.Equal(t, "feat/login", branches[0].DisplayID)
+	assert.False(t, branches[0].Default)
+	assert.Equal(t, "master", branches[1].DisplayID)
+	assert.True(t, branches[1].Default)
+	assert.Equal(t, "4f1c2e3d4b5a69788796a5b4c3d2e1f0a1b2c3d4", branches[1].LatestCommit)
+
+	_, err = c.Branch(context.Background(), "cds/demo", "unknown")
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+}
+
+func TestCommits(t *testing.T) {
+	f := newFakeGitea(t, map[string]string{
+		"GET /api/v1/repos/cds/demo/commits": "commits.json",
+	})
+	defer f.server.Close()
+	c := f.client(t)
+
+	commits, err := c.Commits(context.Background(), "cds/demo", "feat/login", "4f1c2e3d4b5a69788796a5b4c3d2e1f0a1b2c3d4", "")
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887", commits[0].Hash)
+	assert.Equal(t, "add login page\n", commits[0].Message)
+	assert.Equal(t, "John Doe", commits[0].Author.Name)
+	assert.Equal(t, "jdoe", commits[0].Author.DisplayName)
+	assert.Equal(t, "john.doe@example.com", commits[0].Author.Email)
+	assert.Equal(t, int64(1589271724000), commits[0].Timestamp)
+
+	// the listing stops when the since commit is found
+	require.Len(t, f.requests, 1)
+	assert.Contains(t, f.requests[0].query, "sha=feat%2Flogin")
+}
+
+func TestPullRequest(t *testing.T) {
+	f := newFakeGitea(t, map[string]string{
+		"GET /api/v1/repos/cds/demo/pulls/3":            "pull.json",
+		"POST /api/v1/repos/cds/demo/issues/3/comments": "",
+	})
+	defer f.server.Close()
+	c := f.client(t)
+
+	pr, err := c.PullRequest(context.Background(), "cds/demo", 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, pr.ID)
+	assert.Equal(t, "Add login page", pr.Title)
+	assert.False(t, pr.Closed)
+	assert.Equal(t, "jdoe", pr.User.Name)
+	assert.Equal(t, "feat/login", pr.Head.Branch.DisplayID)
+	assert.Equal(t, "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887", pr.Head.Commit.Hash)
+	assert.Equal(t, "cds/demo", pr.Head.Repo)
+	assert.Equal(t, "master", pr.Base.Branch.DisplayID)
+
+	_, err = c.PullRequest(context.Background(), "cds/demo", 4)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+
+	require.NoError(t, c.PullRequestComment(context.Background(), "cds/demo", sdk.VCSPullRequestCommentRequest{
+		VCSPullRequest: sdk.VCSPullRequest{ID: 3},
+		Message:        "Build succeeded",
+	}))
+	last := f.requests[len(f.requests)-1]
+	assert.JSONEq(t, `{"body":"Build succeeded"}`, string(last.body))
+}
+
+func TestCreateHook(t *testing.T) {
+	f := newFakeGitea(t, map[string]string{
+		"GET /api/v1/repos/cds/demo/hooks":  "hooks.json",
+		"POST /api/v1/repos/cds/demo/hooks": "hook.json",
+	})
+	defer f.server.Close()
+	c := f.client(t)
+
+	hook := sdk.VCSHook{
+		URL:    "https://cds.example.com/cdshooks/webhook/repository/2a3b4c5d",
+		Secret: "my-secret",
+	}
+	require.NoError(t, c.CreateHook(context.Background(), "cds/demo", &hook))
+	assert.Equal(t, "7", hook.ID)
+	assert.Equal(t, []string{"push"}, hook.Events)
+
+	last := f.requests[len(f.requests)-1]
+	assert.Equal(t, http.MethodPost, last.method)
+	var opt CreateHookOption
+	require.NoError(t, json.Unmarshal(last.body, &opt))
+	assert.Equal(t, "gitea", opt.Type)
+	assert.Equal(t, "json", opt.Config["content_type"])
+	assert.Equal(t, hook.URL, opt.Config["url"])
+	assert.Equal(t, "my-secret", opt.Config["secret"])
+	assert.True(t, opt.Active)
+}
+
+func TestSetStatus(t *testing.T) {
+	f := newFakeGitea(t, map[string]string{
+		"POST /api/v1/repos/cds/demo/statuses/9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887": "status.json",
+	})
+	defer f.server.Close()
+	c := f.client(t)
+
+	payload, _ := json.Marshal(sdk.EventRunWorkflowNode{
+		Number:             1,
+		NodeName:           "build",
+		Status:             sdk.StatusBuilding,
+		Hash:               "9c3b0c3e0f5d4d1e8b0a8c8e2f1d3c4b5a697887",
+		RepositoryFullName: "cds/demo",
+	})
+	require.NoError(t, c.SetStatus(context.Background(), sdk.Event{
+		EventType:    "sdk.EventRunWorkflowNode",
+		ProjectKey:   "KEY",
+		WorkflowName: "demo",
+		Payload:      payload,
+	}))
+
+	require.Len(t, f.requests, 1)
+	var opt CreateStatusOption
+	require.NoError(t, json.Unmarshal(f.requests[0].body, &opt))
+	assert.Equal(t, "pending", opt.State)
+	assert.Equal(t, "build: Building", opt.Description)
+	assert.Equal(t, "https://cds.example.com/project/KEY/workflow/demo/run/1", opt.TargetURL)
+	assert.True(t, strings.HasPrefix(opt.Context, "CDS/"))
+}
+
+func TestRelease(t *testing.T) {
+	f := newFakeGitea(t, map[string]string{
+		"POST /api/v1/repos/cds/demo/releases":          "release.json",
+		"POST /api/v1/repos/cds/demo/releases/5/assets": "",
+	})
+	defer f.server.Close()
+	c := f.client(t)
+
+	release, err := c.Release(context.Background(), "cds/demo", "v1.0.0", "v1.0.0", "first release")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), release.ID)
+
+	require.NoError(t, c.UploadReleaseFile(context.Background(), "cds/demo", "v1.0.0", release.UploadURL, "cds.tar.gz", ioutil.NopCloser(strings.NewReader("content"))))
+	last := f.requests[len(f.requests)-1]
+	assert.Equal(t, "/api/v1/repos/cds/demo/releases/5/assets", last.path)
+	assert.Equal(t, "name=cds.tar.gz", last.query)
+	assert.Contains(t, string(last.body), `name="attachment"; filename="cds.tar.gz"`)
+}
+
+func TestGetAuthorizedClientRefreshToken(t *testing.T) {
+	f := newFakeGitea(t, map[string]string{
+		"POST /login/oauth/access_token": "access_token.json",
+	})
+	defer f.server.Close()
+	log.SetLogger(t)
+	consumer := New("client-id", "client-secret", f.server.URL, "https://cds.example.com/oauth/callback", "", "", nil, false, false)
+
+	// the token has been created two hours ago, it has to be refreshed
+	created := time.Now().Add(-2 * time.Hour).Unix()
+	c, err := consumer.GetAuthorizedClient(context.Background(), "expired-access-token", "refresh-token", created)
+	require.NoError(t, err)
+	assert.Equal(t, "new-access-token", c.GetAccessToken(context.Background()))
+	require.Len(t, f.requests, 1)
+	assert.Contains(t, string(f.requests[0].body), "grant_type=refresh_token")
+	assert.Contains(t, string(f.requests[0].body), "refresh_token=refresh-token")
+
+	// the refreshed client is kept for the expired token
+	c, err = consumer.GetAuthorizedClient(context.Background(), "expired-access-token", "refresh-token", created)
+	require.NoError(t, err)
+	assert.Equal(t, "new-access-token", c.GetAccessToken(context.Background()))
+	assert.Len(t, f.requests, 1)
+}
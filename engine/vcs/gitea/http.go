@@ -0,0 +1,131 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Gitea http client
+var httpClient = cdsclient.NewHTTPClient(60*time.Second, false)
+
+// pageSize is the number of items asked by page
+const pageSize = 50
+
+// maxPages avoids infinite loops when listing items
+const maxPages = 100
+
+func (c *giteaClient) do(ctx context.Context, method, path string, params url.Values, in, out interface{}) (http.Header, error) {
+	var body io.Reader
+	var contentType string
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot marshal body %+v", in)
+		}
+		body = bytes.NewReader(b)
+		contentType = "application/json"
+	}
+	return c.doWithBody(ctx, method, path, params, contentType, body, out)
+}
+
+func (c *giteaClient) doWithBody(ctx context.Context, method, path string, params url.Values, contentType string, body io.Reader, out interface{}) (http.Header, error) {
+	uri := c.apiURL + path
+	if len(params) > 0 {
+		uri += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequest(method, uri, body)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "token "+c.accessToken)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	log.Debug("Gitea API>> %s %s", method, req.URL.String())
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, sdk.WrapError(err, "HTTP Error")
+	}
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	case res.StatusCode == http.StatusForbidden:
+		return nil, sdk.WithStack(sdk.ErrForbidden)
+	case res.StatusCode == http.StatusUnauthorized:
+		return nil, sdk.WithStack(sdk.ErrUnauthorized)
+	case res.StatusCode >= 400:
+		return nil, sdk.WithStack(errorAPI(res.StatusCode, resBody))
+	}
+
+	if out != nil && len(resBody) > 0 {
+		if err := json.Unmarshal(resBody, out); err != nil {
+			return nil, sdk.WrapError(err, "unable to parse gitea response for %s %s", method, path)
+		}
+	}
+	return res.Header, nil
+}
+
+func (c *giteaClient) get(ctx context.Context, path string, params url.Values, out interface{}) error {
+	_, err := c.do(ctx, http.MethodGet, path, params, nil, out)
+	return err
+}
+
+// getAll calls the given listing route page by page, next is called with each page
+// and returns the number of items of the page. It stops on the first empty page, as gitea may return less items
+// than asked if its MAX_RESPONSE_ITEMS setting is lower than the page size.
+func (c *giteaClient) getAll(ctx context.Context, path string, params url.Values, next func(body []byte) (int, error)) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("limit", strconv.Itoa(pageSize))
+	for page := 1; page <= maxPages; page++ {
+		if ctx.Err() != nil {
+			return sdk.WithStack(ctx.Err())
+		}
+		params.Set("page", strconv.Itoa(page))
+		var raw json.RawMessage
+		if err := c.get(ctx, path, params, &raw); err != nil {
+			return err
+		}
+		n, err := next(raw)
+		if err != nil {
+			return sdk.WrapError(err, "unable to parse gitea response for %s", path)
+		}
+		if n == 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+func errorAPI(status int, body []byte) error {
+	var e Error
+	if err := json.Unmarshal(body, &e); err != nil || e.Message == "" {
+		return fmt.Errorf("gitea error (%d): %s", status, string(body))
+	}
+	return fmt.Errorf("gitea error (%d): %s", status, e.Message)
+}
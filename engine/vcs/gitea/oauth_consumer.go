@@ -0,0 +1,149 @@
+package gitea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Gitea access tokens expire after one hour with the default configuration of gitea
+const accessTokenTTL = time.Hour
+
+// AuthorizeRedirect returns the request token, the Authorize URL
+func (g *giteaConsumer) AuthorizeRedirect(ctx context.Context) (string, string, error) {
+	// See https://docs.gitea.io/en-us/oauth2-provider/
+	requestToken, err := sdk.GenerateHash()
+	if err != nil {
+		return "", "", err
+	}
+
+	val := url.Values{}
+	val.Add("client_id", g.clientID)
+	val.Add("redirect_uri", g.AuthorizationCallbackURL)
+	val.Add("response_type", "code")
+	val.Add("state", requestToken)
+
+	return requestToken, fmt.Sprintf("%s/login/oauth/authorize?%s", g.URL, val.Encode()), nil
+}
+
+// AuthorizeToken returns the authorized token (and its refresh token)
+// from the request token and the verifier got on authorize url
+func (g *giteaConsumer) AuthorizeToken(ctx context.Context, state, code string) (string, string, error) {
+	log.Debug("GiteaDriver.AuthorizeToken: state:%s", state)
+
+	params := url.Values{}
+	params.Add("code", code)
+	params.Add("grant_type", "authorization_code")
+	params.Add("redirect_uri", g.AuthorizationCallbackURL)
+
+	token, err := g.postToken(params)
+	if err != nil {
+		return "", "", err
+	}
+	return token.AccessToken, token.RefreshToken, nil
+}
+
+// RefreshToken returns the refreshed authorized token
+func (g *giteaConsumer) RefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+	params := url.Values{}
+	params.Add("refresh_token", refreshToken)
+	params.Add("grant_type", "refresh_token")
+
+	token, err := g.postToken(params)
+	if err != nil {
+		return "", "", err
+	}
+	return token.AccessToken, token.RefreshToken, nil
+}
+
+func (g *giteaConsumer) postToken(params url.Values) (AccessToken, error) {
+	var token AccessToken
+	params.Add("client_id", g.clientID)
+	params.Add("client_secret", g.clientSecret)
+
+	req, err := http.NewRequest(http.MethodPost, g.URL+"/login/oauth/access_token", strings.NewReader(params.Encode()))
+	if err != nil {
+		return token, sdk.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return token, sdk.WrapError(err, "cannot get gitea access token")
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return token, sdk.WithStack(err)
+	}
+
+	if res.StatusCode >= 400 {
+		return token, sdk.WithStack(fmt.Errorf("Gitea error (%d) %s ", res.StatusCode, string(body)))
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return token, sdk.WithStack(fmt.Errorf("Unable to parse gitea response (%d) %s ", res.StatusCode, string(body)))
+	}
+	return token, nil
+}
+
+// keep client in memory
+var instancesAuthorizedClient = struct {
+	mu      sync.Mutex
+	clients map[string]*giteaClient
+}{
+	clients: map[string]*giteaClient{},
+}
+
+// GetAuthorizedClient returns an authorized client. The access token is refreshed when it has expired,
+// the client with the refreshed token is then kept for the given access token until it expires again.
+func (g *giteaConsumer) GetAuthorizedClient(ctx context.Context, accessToken, refreshToken string, created int64) (sdk.VCSAuthorizedClient, error) {
+	instancesAuthorizedClient.mu.Lock()
+	defer instancesAuthorizedClient.mu.Unlock()
+
+	key := accessToken
+	var expiration time.Time
+	if created > 0 {
+		expiration = time.Unix(created, 0).Add(accessTokenTTL)
+	}
+
+	if c, ok := instancesAuthorizedClient.clients[key]; ok {
+		if c.expiration.IsZero() || time.Now().Before(c.expiration) {
+			return c, nil
+		}
+		accessToken, refreshToken, expiration = c.accessToken, c.refreshToken, c.expiration
+	}
+
+	if !expiration.IsZero() && expiration.Before(time.Now()) && refreshToken != "" {
+		newAccessToken, newRefreshToken, err := g.RefreshToken(ctx, refreshToken)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot refresh token")
+		}
+		accessToken, refreshToken = newAccessToken, newRefreshToken
+		expiration = time.Now().Add(accessTokenTTL)
+	}
+
+	c := &giteaClient{
+		httpClient:          httpClient,
+		apiURL:              strings.TrimSuffix(g.URL, "/") + "/api/v1",
+		accessToken:         accessToken,
+		refreshToken:        refreshToken,
+		expiration:          expiration,
+		cache:               g.cache,
+		uiURL:               g.uiURL,
+		proxyURL:            g.proxyURL,
+		disableStatus:       g.disableStatus,
+		disableStatusDetail: g.disableStatusDetail,
+	}
+	instancesAuthorizedClient.clients[key] = c
+	return c, nil
+}
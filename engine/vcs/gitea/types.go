@@ -0,0 +1,214 @@
+package gitea
+
+import "time"
+
+// Gitea API v1 types, only the fields used by CDS are declared.
+// See https://try.gitea.io/api/swagger
+
+// User is a gitea user
+type User struct {
+	ID       int64  `json:"id"`
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+	Avatar   string `json:"avatar_url"`
+	Username string `json:"username"`
+}
+
+// Permission is the permission of the authenticated user on a repository
+type Permission struct {
+	Admin bool `json:"admin"`
+	Push  bool `json:"push"`
+	Pull  bool `json:"pull"`
+}
+
+// Repository is a gitea repository
+type Repository struct {
+	ID            int64       `json:"id"`
+	Owner         *User       `json:"owner"`
+	Name          string      `json:"name"`
+	FullName      string      `json:"full_name"`
+	Description   string      `json:"description"`
+	Fork          bool        `json:"fork"`
+	HTMLURL       string      `json:"html_url"`
+	CloneURL      string      `json:"clone_url"`
+	SSHURL        string      `json:"ssh_url"`
+	DefaultBranch string      `json:"default_branch"`
+	Permissions   *Permission `json:"permissions,omitempty"`
+}
+
+// PayloadCommit is the last commit of a branch
+type PayloadCommit struct {
+	ID        string    `json:"id"`
+	Message   string    `json:"message"`
+	URL       string    `json:"url"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Branch is a gitea branch
+type Branch struct {
+	Name   string         `json:"name"`
+	Commit *PayloadCommit `json:"commit"`
+}
+
+// CommitMeta is a reference to a commit
+type CommitMeta struct {
+	URL string `json:"url"`
+	SHA string `json:"sha"`
+}
+
+// Tag is a gitea tag
+type Tag struct {
+	Name    string      `json:"name"`
+	Message string      `json:"message"`
+	ID      string      `json:"id"`
+	Commit  *CommitMeta `json:"commit"`
+}
+
+// CommitUser is the author or the committer of a commit
+type CommitUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Date  string `json:"date"`
+}
+
+// RepoCommit is the git part of a commit
+type RepoCommit struct {
+	URL       string      `json:"url"`
+	Author    *CommitUser `json:"author"`
+	Committer *CommitUser `json:"committer"`
+	Message   string      `json:"message"`
+}
+
+// Commit is a gitea commit
+type Commit struct {
+	URL        string        `json:"url"`
+	SHA        string        `json:"sha"`
+	HTMLURL    string        `json:"html_url"`
+	RepoCommit *RepoCommit   `json:"commit"`
+	Author     *User         `json:"author"`
+	Committer  *User         `json:"committer"`
+	Parents    []*CommitMeta `json:"parents"`
+}
+
+// Compare is the result of the comparison of two refs
+type Compare struct {
+	TotalCommits int      `json:"total_commits"`
+	Commits      []Commit `json:"commits"`
+}
+
+// PRBranchInfo is the base or the head of a pull request
+type PRBranchInfo struct {
+	Name       string      `json:"label"`
+	Ref        string      `json:"ref"`
+	Sha        string      `json:"sha"`
+	RepoID     int64       `json:"repo_id"`
+	Repository *Repository `json:"repo"`
+}
+
+// PullRequest is a gitea pull request
+type PullRequest struct {
+	ID      int64         `json:"id"`
+	Index   int           `json:"number"`
+	Poster  *User         `json:"user"`
+	Title   string        `json:"title"`
+	Body    string        `json:"body"`
+	State   string        `json:"state"`
+	HTMLURL string        `json:"html_url"`
+	Merged  bool          `json:"merged"`
+	Labels  []Label       `json:"labels"`
+	Head    *PRBranchInfo `json:"head"`
+	Base    *PRBranchInfo `json:"base"`
+}
+
+// Label is a label of an issue or a pull request
+type Label struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// CreatePullRequestOption is the body of a pull request creation
+type CreatePullRequestOption struct {
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// CreateIssueCommentOption is the body of a comment creation
+type CreateIssueCommentOption struct {
+	Body string `json:"body"`
+}
+
+// Hook is a gitea webhook
+type Hook struct {
+	ID     int64             `json:"id"`
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// CreateHookOption is the body of a webhook creation
+type CreateHookOption struct {
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// EditHookOption is the body of a webhook update
+type EditHookOption struct {
+	Config map[string]string `json:"config,omitempty"`
+	Events []string          `json:"events,omitempty"`
+	Active *bool             `json:"active,omitempty"`
+}
+
+// Status is a commit status
+type Status struct {
+	ID          int64     `json:"id"`
+	State       string    `json:"status"`
+	TargetURL   string    `json:"target_url"`
+	Description string    `json:"description"`
+	Context     string    `json:"context"`
+	Created     time.Time `json:"created_at"`
+}
+
+// CreateStatusOption is the body of a commit status creation
+type CreateStatusOption struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+// Release is a gitea release
+type Release struct {
+	ID      int64  `json:"id"`
+	TagName string `json:"tag_name"`
+	Title   string `json:"name"`
+	Note    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// CreateReleaseOption is the body of a release creation
+type CreateReleaseOption struct {
+	TagName string `json:"tag_name"`
+	Title   string `json:"name"`
+	Note    string `json:"body"`
+}
+
+// AccessToken is the response of the OAuth2 token endpoint
+type AccessToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Error is the error returned by the gitea API
+type Error struct {
+	Message string `json:"message"`
+	URL     string `json:"url"`
+}
@@ -1,9 +1,11 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/ovh/cds/engine/api/cache"
@@ -165,3 +167,100 @@ func (g *githubClient) Branch(ctx context.Context, fullname, theBranch string) (
 
 	return branchResult, nil
 }
+
+// CreateBranch creates a branch on the given commit, the branch is moved if it already exists
+// https://developer.github.com/v3/git/refs/#create-a-reference
+func (g *githubClient) CreateBranch(ctx context.Context, repo, branch, hash string) error {
+	values, _ := json.Marshal(map[string]string{
+		"ref": "refs/heads/" + branch,
+		"sha": hash,
+	})
+	res, err := g.post("/repos/"+repo+"/git/refs", "application/json", bytes.NewReader(values), nil)
+	if err != nil {
+		return sdk.WrapError(err, "unable to create branch %s on %s", branch, repo)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.WrapError(err, "unable to read body")
+	}
+
+	switch res.StatusCode {
+	case http.StatusCreated:
+		return nil
+	case http.StatusUnprocessableEntity:
+		// The reference already exists, it is forced on the commit
+		values, _ := json.Marshal(map[string]interface{}{
+			"sha":   hash,
+			"force": true,
+		})
+		res, err := g.patch("/repos/"+repo+"/git/refs/heads/"+branch, "application/json", bytes.NewReader(values), nil)
+		if err != nil {
+			return sdk.WrapError(err, "unable to update branch %s on %s", branch, repo)
+		}
+		defer res.Body.Close()
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			return sdk.WrapError(err, "unable to read body")
+		}
+		if res.StatusCode != http.StatusOK {
+			return sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+		}
+		return nil
+	}
+	return sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+}
+
+// DeleteBranch deletes a branch
+// https://developer.github.com/v3/git/refs/#delete-a-reference
+func (g *githubClient) DeleteBranch(ctx context.Context, repo, branch string) error {
+	return g.delete("/repos/" + repo + "/git/refs/heads/" + branch)
+}
+
+// MergeBranch merges a commit in a branch and returns the head of the branch
+// https://developer.github.com/v3/repos/merging/#perform-a-merge
+func (g *githubClient) MergeBranch(ctx context.Context, repo string, merge sdk.VCSBranchMergeRequest) (sdk.VCSCommit, error) {
+	values, _ := json.Marshal(map[string]string{
+		"base":           merge.Branch,
+		"head":           merge.Head,
+		"commit_message": merge.Message,
+	})
+	res, err := g.post("/repos/"+repo+"/merges", "application/json", bytes.NewReader(values), nil)
+	if err != nil {
+		return sdk.VCSCommit{}, sdk.WrapError(err, "unable to merge %s in %s on %s", merge.Head, merge.Branch, repo)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.VCSCommit{}, sdk.WrapError(err, "unable to read body")
+	}
+
+	switch res.StatusCode {
+	case http.StatusCreated:
+		var c Commit
+		if err := json.Unmarshal(body, &c); err != nil {
+			return sdk.VCSCommit{}, sdk.WrapError(err, "unable to parse github commit")
+		}
+		return sdk.VCSCommit{
+			Timestamp: c.Commit.Author.Date.Unix() * 1000,
+			Message:   c.Commit.Message,
+			Hash:      c.Sha,
+			URL:       c.HTMLURL,
+			Author: sdk.VCSAuthor{
+				DisplayName: c.Commit.Author.Name,
+				Email:       c.Commit.Author.Email,
+				Name:        c.Commit.Author.Name,
+			},
+		}, nil
+	case http.StatusNoContent:
+		// The commit is already in the branch
+		b, err := g.Branch(ctx, repo, merge.Branch)
+		if err != nil {
+			return sdk.VCSCommit{}, err
+		}
+		return sdk.VCSCommit{Hash: b.LatestCommit}, nil
+	case http.StatusConflict:
+		return sdk.VCSCommit{}, sdk.NewErrorFrom(sdk.ErrConflict, "merge conflict between %s and %s", merge.Branch, merge.Head)
+	}
+	return sdk.VCSCommit{}, sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+}
@@ -118,8 +118,13 @@ func (g *githubClient) PushEvents(ctx context.Context, fullname string, iEvents
 	}
 
 	lastCommitPerBranch := map[string]sdk.VCSCommit{}
+	// the hash of the branch before its first push is used to compute the changed files
+	firstPushPerBranch := map[string]Event{}
 	for _, e := range events {
 		branch := strings.Replace(e.Payload.Ref, "refs/heads/", "", 1)
+		if f, ok := firstPushPerBranch[branch]; !ok || e.CreatedAt.Time.Before(f.CreatedAt.Time) {
+			firstPushPerBranch[branch] = e
+		}
 		for _, c := range e.Payload.Commits {
 			commit := sdk.VCSCommit{
 				Hash:      c.Sha,
@@ -155,6 +160,7 @@ func (g *githubClient) PushEvents(ctx context.Context, fullname string, iEvents
 			Branch: *branch,
 			Commit: c,
 			Repo:   fullname,
+			Before: firstPushPerBranch[b].Payload.Before,
 		})
 	}
 
@@ -256,7 +262,17 @@ func (g *githubClient) PullRequestEvents(ctx context.Context, fullname string, i
 		}
 		event := sdk.VCSPullRequestEvent{
 			Action: e.Payload.Action,
-			Repo:   e.Payload.PullRequest.Head.Repo.FullName,
+			ID:     e.Payload.PullRequest.Number,
+			Title:  e.Payload.PullRequest.Title,
+			URL:    e.Payload.PullRequest.HTMLURL,
+			User: sdk.VCSAuthor{
+				Name:        e.Payload.PullRequest.User.Login,
+				DisplayName: e.Payload.PullRequest.User.Name,
+				Avatar:      e.Payload.PullRequest.User.AvatarURL,
+			},
+			Labels:   e.Payload.PullRequest.labelNames(),
+			MergeRef: e.Payload.PullRequest.mergeRef(),
+			Repo:     e.Payload.PullRequest.Head.Repo.FullName,
 			Head: sdk.VCSPushEvent{
 				Branch: sdk.VCSBranch{
 					ID:           e.Payload.PullRequest.Head.Ref,
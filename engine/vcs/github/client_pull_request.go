@@ -217,6 +217,40 @@ func (g *githubClient) PullRequestCreate(ctx context.Context, repo string, pr sd
 	return prResponse.ToVCSPullRequest(), nil
 }
 
+// PullRequestMerge merges a pull request
+// https://developer.github.com/v3/pulls/#merge-a-pull-request-merge-button
+func (g *githubClient) PullRequestMerge(ctx context.Context, repo string, id int, merge sdk.VCSPullRequestMergeRequest) error {
+	payload := map[string]string{
+		"merge_method": merge.Method,
+	}
+	if merge.Message != "" {
+		payload["commit_message"] = merge.Message
+	}
+	if merge.Hash != "" {
+		payload["sha"] = merge.Hash
+	}
+	values, _ := json.Marshal(payload)
+	res, err := g.put(fmt.Sprintf("/repos/%s/pulls/%d/merge", repo, id), "application/json", bytes.NewReader(values), nil)
+	if err != nil {
+		return sdk.WrapError(err, "unable to merge pull request %d on %s", id, repo)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.WrapError(err, "Unable to read body")
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusMethodNotAllowed, http.StatusConflict:
+		// The pull request is not mergeable or its head was updated
+		return sdk.NewError(sdk.ErrConflict, errorAPI(body))
+	}
+	return sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+}
+
 func (pullr PullRequest) ToVCSPullRequest() sdk.VCSPullRequest {
 	return sdk.VCSPullRequest{
 		ID: pullr.Number,
@@ -264,7 +298,23 @@ func (pullr PullRequest) ToVCSPullRequest() sdk.VCSPullRequest {
 			DisplayName: pullr.User.Login,
 			Name:        pullr.User.Name,
 		},
-		Closed: pullr.State == "closed",
-		Merged: pullr.Merged,
+		Closed:   pullr.State == "closed",
+		Merged:   pullr.Merged,
+		Title:    pullr.Title,
+		Labels:   pullr.labelNames(),
+		MergeRef: pullr.mergeRef(),
 	}
 }
+
+func (pullr PullRequest) labelNames() []string {
+	var labels []string
+	for _, l := range pullr.Labels {
+		labels = append(labels, l.Name)
+	}
+	return labels
+}
+
+// mergeRef returns the ref of the merge commit computed by github for the pull requests without conflict
+func (pullr PullRequest) mergeRef() string {
+	return fmt.Sprintf("refs/pull/%d/merge", pullr.Number)
+}
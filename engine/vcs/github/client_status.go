@@ -37,6 +37,8 @@ func (g *githubClient) SetStatus(ctx context.Context, event sdk.Event) error {
 	switch event.EventType {
 	case fmt.Sprintf("%T", sdk.EventRunWorkflowNode{}):
 		data, err = processEventWorkflowNodeRun(event, g.uiURL, g.DisableStatusDetail)
+	case fmt.Sprintf("%T", sdk.EventMergeQueue{}):
+		data, err = processEventMergeQueue(event, g.uiURL, g.DisableStatusDetail)
 	default:
 		log.Error(ctx, "github.SetStatus> Unknown event %v", event)
 		return nil
@@ -191,3 +193,37 @@ func processEventWorkflowNodeRun(event sdk.Event, cdsUIURL string, disabledStatu
 	data.desc = eventNR.NodeName + ": " + eventNR.Status
 	return data, nil
 }
+
+func processEventMergeQueue(event sdk.Event, cdsUIURL string, disabledStatusDetail bool) (statusData, error) {
+	data := statusData{}
+	var eventMQ sdk.EventMergeQueue
+	if err := json.Unmarshal(event.Payload, &eventMQ); err != nil {
+		return data, sdk.WrapError(err, "cannot unmarshal payload")
+	}
+
+	switch eventMQ.BuildStatus() {
+	case sdk.StatusSuccess:
+		data.status = "success"
+	case sdk.StatusFail:
+		data.status = "failure"
+	case sdk.StatusStopped:
+		data.status = "error"
+	default:
+		data.status = "pending"
+	}
+	data.hash = eventMQ.Hash
+	data.repoFullName = eventMQ.RepositoryFullName
+
+	if eventMQ.WorkflowRunNumber > 0 && !disabledStatusDetail {
+		data.urlPipeline = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d",
+			cdsUIURL,
+			event.ProjectKey,
+			event.WorkflowName,
+			eventMQ.WorkflowRunNumber,
+		)
+	}
+
+	data.context = sdk.MergeQueueStatusContext(event.ProjectKey, event.WorkflowName)
+	data.desc = eventMQ.Description
+	return data, nil
+}
@@ -63,6 +63,7 @@ type Webhook struct {
 	Config  struct {
 		URL         string `json:"url"`
 		ContentType string `json:"content_type"`
+		Secret      string `json:"secret,omitempty"`
 	} `json:"config"`
 	UpdatedAt time.Time `json:"updated_at"`
 	CreatedAt time.Time `json:"created_at"`
@@ -72,6 +73,7 @@ type Webhook struct {
 type WebHookConfig struct {
 	URL         string `json:"url"`
 	ContentType string `json:"content_type"`
+	Secret      string `json:"secret,omitempty"`
 }
 
 // User represents a GitHub user.
@@ -508,6 +510,14 @@ type PullRequest struct {
 	Additions           int       `json:"additions"`
 	Deletions           int       `json:"deletions"`
 	ChangedFiles        int       `json:"changed_files"`
+	Labels              []Label   `json:"labels"`
+}
+
+// Label represents a label of an issue or a pull request
+type Label struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
 }
 
 // ReleaseRequest Request sent to Github to create a release
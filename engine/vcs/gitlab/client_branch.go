@@ -50,3 +50,18 @@ func (c *gitlabClient) Branch(ctx context.Context, fullname, branchName string)
 
 	return br, nil
 }
+
+// CreateBranch is not implemented for GitLab
+func (c *gitlabClient) CreateBranch(ctx context.Context, repo, branch, hash string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// DeleteBranch is not implemented for GitLab
+func (c *gitlabClient) DeleteBranch(ctx context.Context, repo, branch string) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
+
+// MergeBranch is not implemented for GitLab
+func (c *gitlabClient) MergeBranch(ctx context.Context, repo string, merge sdk.VCSBranchMergeRequest) (sdk.VCSCommit, error) {
+	return sdk.VCSCommit{}, sdk.WithStack(sdk.ErrNotImplemented)
+}
@@ -86,3 +86,8 @@ func (c *gitlabClient) PullRequestCreate(ctx context.Context, repo string, pr sd
 		Merged: mr.State == "merged",
 	}, nil
 }
+
+// PullRequestMerge is not implemented for GitLab
+func (c *gitlabClient) PullRequestMerge(ctx context.Context, repo string, id int, merge sdk.VCSPullRequestMergeRequest) error {
+	return sdk.WithStack(sdk.ErrNotImplemented)
+}
@@ -39,6 +39,8 @@ func (s *Service) getAllVCSServersHandler() service.Handler {
 				vcsType = "github"
 			} else if v.Gitlab != nil {
 				vcsType = "gitlab"
+			} else if v.Gitea != nil {
+				vcsType = "gitea"
 			}
 
 			servers[k] = sdk.VCSConfiguration{
@@ -71,6 +73,8 @@ func (s *Service) getVCSServersHandler() service.Handler {
 			s.Type = "github"
 		} else if cfg.Gitlab != nil {
 			s.Type = "gitlab"
+		} else if cfg.Gitea != nil {
+			s.Type = "gitea"
 		}
 		return service.WriteJSON(w, s, http.StatusOK)
 	}
@@ -180,8 +184,8 @@ func (s *Service) getVCSServersHooksHandler() service.Handler {
 				"project_column",
 				"project",
 				"public",
-				"pull-request_review_comment",
-				"pull-request_review",
+				"pull_request_review_comment",
+				"pull_request_review",
 				"pull_request",
 				"repository",
 				"repository_import",
@@ -208,6 +212,26 @@ func (s *Service) getVCSServersHooksHandler() service.Handler {
 				string(gitlab.EventTypePipeline),
 				"Job Hook", // TODO update gitlab sdk
 			}
+		case cfg.Gitea != nil:
+			res.WebhooksSupported = true
+			res.WebhooksDisabled = cfg.Gitea.DisableWebHooks
+			res.WebhooksIcon = sdk.GiteaIcon
+			// https://docs.gitea.io/en-us/webhooks/
+			res.Events = []string{
+				"push",
+				"create",
+				"delete",
+				"fork",
+				"issues",
+				"issue_comment",
+				"pull_request",
+				"pull_request_approved",
+				"pull_request_rejected",
+				"pull_request_comment",
+				"pull_request_sync",
+				"repository",
+				"release",
+			}
 		case cfg.Gerrit != nil:
 			res.WebhooksSupported = false
 			res.GerritHookDisabled = cfg.Gerrit.DisableGerritEvent
@@ -263,6 +287,8 @@ func (s *Service) getVCSServersPollingHandler() service.Handler {
 		case cfg.Gitlab != nil:
 			res.PollingSupported = false
 			res.PollingDisabled = cfg.Gitlab.DisablePolling
+		case cfg.Gitea != nil:
+			res.PollingSupported = false
 		}
 
 		return service.WriteJSON(w, res, http.StatusOK)
@@ -447,6 +473,117 @@ func (s *Service) getBranchHandler() service.Handler {
 	}
 }
 
+func (s *Service) postBranchHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+
+		var body sdk.VCSBranch
+		if err := service.UnmarshalBody(r, &body); err != nil {
+			return sdk.WithStack(err)
+		}
+		if body.DisplayID == "" || body.LatestCommit == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "branch name and commit are mandatory")
+		}
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		if err := client.CreateBranch(ctx, fmt.Sprintf("%s/%s", owner, repo), body.DisplayID, body.LatestCommit); err != nil {
+			return sdk.WrapError(err, "Unable to create branch %s on %s/%s", body.DisplayID, owner, repo)
+		}
+		return service.WriteJSON(w, body, http.StatusCreated)
+	}
+}
+
+func (s *Service) deleteBranchHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		branch := r.URL.Query().Get("branch")
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		if err := client.DeleteBranch(ctx, fmt.Sprintf("%s/%s", owner, repo), branch); err != nil {
+			return sdk.WrapError(err, "Unable to delete branch %s on %s/%s", branch, owner, repo)
+		}
+		return nil
+	}
+}
+
+func (s *Service) postBranchMergeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+
+		var body sdk.VCSBranchMergeRequest
+		if err := service.UnmarshalBody(r, &body); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		commit, err := client.MergeBranch(ctx, fmt.Sprintf("%s/%s", owner, repo), body)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to merge %s in branch %s on %s/%s", body.Head, body.Branch, owner, repo)
+		}
+		return service.WriteJSON(w, commit, http.StatusOK)
+	}
+}
+
 func (s *Service) getTagsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
@@ -773,6 +910,48 @@ func (s *Service) postPullRequestCommentHandler() service.Handler {
 	}
 }
 
+func (s *Service) postPullRequestMergeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		sid := muxVar(r, "id")
+		id, err := strconv.Atoi(sid)
+		if err != nil {
+			return sdk.WithStack(sdk.ErrWrongRequest)
+		}
+
+		var body sdk.VCSPullRequestMergeRequest
+		if err := service.UnmarshalBody(r, &body); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		if err := client.PullRequestMerge(ctx, fmt.Sprintf("%s/%s", owner, repo), id, body); err != nil {
+			return sdk.WrapError(err, "Unable to merge PR %d %s %s/%s", id, name, owner, repo)
+		}
+		return nil
+	}
+}
+
 func (s *Service) getEventsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
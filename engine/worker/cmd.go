@@ -34,6 +34,7 @@ const (
 	flagName                = "name"
 	flagModel               = "model"
 	flagHatcheryName        = "hatchery-name"
+	flagUpdatePublicKey     = "update-public-key"
 )
 
 func initFlagsRun(cmd *cobra.Command) {
@@ -53,6 +54,7 @@ func initFlagsRun(cmd *cobra.Command) {
 	flags.String(flagName, "", "Name of worker")
 	flags.String(flagModel, "", "Model of worker")
 	flags.String(flagHatcheryName, "", "Hatchery Name spawing worker")
+	flags.String(flagUpdatePublicKey, "", "RSA PEM public key, optionally base64 encoded, used to check the signature of the worker binary before an update")
 }
 
 // FlagBool replaces viper.GetBool
@@ -134,6 +136,10 @@ func initFromFlags(cmd *cobra.Command, w *internal.CurrentWorker) {
 		GraylogFieldCDSServiceType: "worker",
 	})
 
+	if k := FlagString(cmd, flagUpdatePublicKey); k != "" {
+		internal.UpdatePublicKey = k
+	}
+
 	hatcheryName := FlagString(cmd, flagHatcheryName)
 	apiEndpoint := FlagString(cmd, flagAPI)
 	if apiEndpoint == "" {
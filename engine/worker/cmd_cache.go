@@ -41,10 +41,9 @@ Here, an example of a script inside a CDS Job using the cache feature:
 
 	#!/bin/bash
 
-	tag=($(md5sum pom.xml))
-
-	# download the cache of .m2/
-	if worker cache pull $tag; then
+	# download the cache of .m2/ for the current pom.xml,
+	# or the last pushed one for any pom.xml if there is none
+	if worker cache pull --restore-keys=m2- 'm2-{{ hashFiles "**/pom.xml" }}'; then
 		echo ".m2/ getted from cache";
 	fi
 
@@ -54,8 +53,17 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	mvn install
 
 	# put in cache the updated .m2/ directory
-	worker cache push $tag .m2/
+	worker cache push 'm2-{{ hashFiles "**/pom.xml" }}' .m2/
+
+## Cache keys
+The function hashFiles computes a sha256 of the files matching the given glob patterns, relative to the current directory
+(** matches any number of directories). The key changes as soon as one of these files changes.
+
+Restore keys are prefixes checked in the given order when no cache exists for the key, the most recently pushed cache
+with a matching key is pulled.
 
+Caches are deleted from the least recently used when the caches of a project in a storage integration exceed the size
+limit of the CDS API.
     `,
 	}
 	cmdCacheRoot.AddCommand(cmdCachePush(), cmdCachePull())
@@ -63,7 +71,10 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	return cmdCacheRoot
 }
 
-var cmdStorageIntegrationName string
+var (
+	cmdStorageIntegrationName string
+	cmdCacheRestoreKeys       []string
+)
 
 func cmdCachePush() *cobra.Command {
 	c := &cobra.Command{
@@ -110,8 +121,13 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache push > Cannot find working directory : %s", err)
 		}
 
+		key, err := internal.ExpandCacheKey(cwd, args[0])
+		if err != nil {
+			sdk.Exit("worker cache push > %v", err)
+		}
+
 		c := sdk.Cache{
-			Tag:              base64.RawURLEncoding.EncodeToString([]byte(args[0])),
+			Tag:              base64.RawURLEncoding.EncodeToString([]byte(key)),
 			Files:            files,
 			WorkingDirectory: cwd,
 			IntegrationName:  cmdStorageIntegrationName,
@@ -122,7 +138,7 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache push > internal error (%s)", errMarshal)
 		}
 
-		fmt.Printf("Worker cache push in progress... (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache push in progress... (tag: %s)\n", key)
 		req, errRequest := http.NewRequest(
 			"POST",
 			fmt.Sprintf("http://127.0.0.1:%d/cache/push", port),
@@ -150,7 +166,7 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Error: http code %d : %v", resp.StatusCode, cdsError)
 		}
 
-		fmt.Printf("Worker cache push with success (tag: %s)\n", args[0])
+		fmt.Printf("Worker cache push with success (tag: %s)\n", key)
 	}
 }
 
@@ -178,10 +194,15 @@ If you want to push a cache into a storage integration:
 
 	worker cache push latest --from=MyStorageIntegration {{.cds.workspace}}/pathToUpload
 
+If there is no cache for the tag, you can fall back to the most recent cache whose tag starts with a restore key:
+
+	worker cache pull --restore-keys=deps-{{.cds.workflow}}- --restore-keys=deps- 'deps-{{.cds.workflow}}-{{ hashFiles "go.sum" }}'
+
 		`,
 		Run: cachePullCmd(),
 	}
 	c.Flags().StringVar(&cmdStorageIntegrationName, "from", "", "optional. Your storage integration name")
+	c.Flags().StringSliceVar(&cmdCacheRestoreKeys, "restore-keys", nil, "optional. Ordered prefixes of the tags of caches to pull if there is no cache for the tag")
 	return c
 }
 
@@ -206,17 +227,35 @@ func cachePullCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache pull > cannot get current path: %s", err)
 		}
 
-		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", args[0])
+		cwd, err := os.Getwd()
+		if err != nil {
+			sdk.Exit("worker cache pull > cannot find working directory: %s", err)
+		}
+
+		key, err := internal.ExpandCacheKey(cwd, args[0])
+		if err != nil {
+			sdk.Exit("worker cache pull > %v", err)
+		}
+
+		q := url.Values{}
+		q.Set("path", dir)
+		q.Set("integration", cmdStorageIntegrationName)
+		for _, k := range cmdCacheRestoreKeys {
+			restoreKey, err := internal.ExpandCacheKey(cwd, k)
+			if err != nil {
+				sdk.Exit("worker cache pull > %v", err)
+			}
+			q.Add("restoreKey", restoreKey)
+		}
+
+		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", key)
 		req, errRequest := http.NewRequest(
 			"GET",
-			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?path=%s&integration=%s", port,
-				base64.RawURLEncoding.EncodeToString([]byte(args[0])),
-				url.QueryEscape(dir),
-				url.QueryEscape(cmdStorageIntegrationName)),
+			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?%s", port, base64.RawURLEncoding.EncodeToString([]byte(key)), q.Encode()),
 			nil,
 		)
 		if errRequest != nil {
-			sdk.Exit("worker cache pull > cannot post worker cache pull with tag %s (Request): %s", args[0], errRequest)
+			sdk.Exit("worker cache pull > cannot post worker cache pull with tag %s (Request): %s", key, errRequest)
 		}
 
 		client := http.DefaultClient
@@ -236,6 +275,13 @@ func cachePullCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("Error: %v", cdsError)
 		}
 
-		fmt.Printf("Worker cache pull with success (tag: %s)\n", args[0])
+		// The worker returns the pulled cache when it was found with a restore key
+		var restored sdk.CacheEntry
+		if body, err := ioutil.ReadAll(resp.Body); err == nil && len(body) > 0 && json.Unmarshal(body, &restored) == nil {
+			fmt.Printf("Worker cache pull with success (tag: %s, restored from: %s)\n", key, restored.Key)
+			return
+		}
+
+		fmt.Printf("Worker cache pull with success (tag: %s)\n", key)
 	}
 }
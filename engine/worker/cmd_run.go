@@ -34,13 +34,9 @@ func runCmd() func(cmd *cobra.Command, args []string) {
 		// Setup workerfrom commandline flags or env variables
 		initFromFlags(cmd, w)
 
-		// Get the booked job ID
+		// Get the booked job ID, an idle worker is started without job and waits for one
 		bookedWJobID := FlagInt64(cmd, flagBookedWorkflowJobID)
 
-		if bookedWJobID == 0 {
-			sdk.Exit("flag --booked-workflow-job-id is mandatory")
-		}
-
 		ctx, cancel := context.WithCancel(ctx)
 		// Gracefully shutdown connections
 		c := make(chan os.Signal, 1)
@@ -144,6 +144,17 @@ func RunGitClone(ctx context.Context, wk workerruntime.Runtime, a sdk.Action, se
 		opts.CheckoutCommit = commit.Value
 	}
 
+	// The merge ref of a pull request is only available on the application repository, it is fetched on its base branch
+	mergeRef := sdk.ParameterValue(wk.Parameters(), "git.pr.merge.ref")
+	isAppRepository := gitURL == sdk.ParameterValue(wk.Parameters(), "git.url") || gitURL == sdk.ParameterValue(wk.Parameters(), "git.http_url")
+	if mergeRef != "" && isAppRepository && (tag == "" || tag == sdk.DefaultGitCloneParameterTagValue) {
+		opts.MergeRef = mergeRef
+		if baseBranch := sdk.ParameterValue(wk.Parameters(), "git.branch.dest"); baseBranch != "" {
+			opts.Branch = baseBranch
+		}
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("building the merge ref %s of the pull request on branch %s", mergeRef, opts.Branch))
+	}
+
 	var dir string
 	if directory != nil {
 		dir = directory.Value
@@ -198,7 +198,8 @@ func RunScriptAction(ctx context.Context, wk workerruntime.Runtime, a sdk.Action
 		}
 
 		log.Info(ctx, "runScriptAction> Running command %s %s in %s", script.shell, strings.Trim(fmt.Sprint(script.opts), "[]"), script.dir)
-		cmd := exec.CommandContext(ctx, script.shell, script.opts...)
+		cmd := exec.Command(script.shell, script.opts...)
+		setProcessGroup(cmd)
 		res.Status = sdk.StatusUnknown
 
 		cmd.Dir = script.dir
@@ -260,6 +261,21 @@ func RunScriptAction(ctx context.Context, wk workerruntime.Runtime, a sdk.Action
 			chanErr <- fmt.Errorf("unable to start command: %v", err)
 		}
 
+		// Kill the script and all its children when the step is canceled (i.e. timeout or job stopped),
+		// the pipes are closed only when all the processes are dead
+		cmdDone := make(chan struct{})
+		defer close(cmdDone)
+		go func() {
+			select {
+			case <-ctx.Done():
+				log.Info(ctx, "runScriptAction> killing command %s: %v", script.shell, ctx.Err())
+				if err := killProcessTree(cmd); err != nil {
+					log.Warning(ctx, "runScriptAction> unable to kill command %s: %v", script.shell, err)
+				}
+			case <-cmdDone:
+			}
+		}()
+
 		<-outchan
 		<-errchan
 		if err := cmd.Wait(); err != nil {
@@ -0,0 +1,22 @@
+// +build !windows
+
+package action
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group, so it can be killed with all its children.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessTree kills the process group of the command.
+func killProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	// a negative pid sends the signal to all the processes of the group
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
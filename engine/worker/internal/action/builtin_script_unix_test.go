@@ -0,0 +1,51 @@
+// +build !windows
+
+package action
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestRunScriptActionKillProcessTree(t *testing.T) {
+	wk, ctx := SetupTest(t)
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	// The script starts a child process which keeps the stdout pipe open
+	_, err := RunScriptAction(ctx, wk,
+		sdk.Action{
+			Parameters: []sdk.Parameter{
+				{
+					Name:  "script",
+					Value: "sleep 60 &\necho $! > child.pid\nsleep 60",
+				},
+			},
+		}, nil)
+	assert.Error(t, err)
+
+	btes, err := afero.ReadFile(wk.BaseDir(), "working_directory/child.pid")
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(btes)))
+	require.NoError(t, err)
+
+	var alive bool
+	for i := 0; i < 50; i++ {
+		alive = syscall.Kill(pid, 0) == nil
+		if !alive {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.False(t, alive, "child process %d should be killed", pid)
+}
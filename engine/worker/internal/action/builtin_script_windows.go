@@ -0,0 +1,17 @@
+package action
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup does nothing on windows, the process tree is killed with taskkill.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessTree kills the process started by the command and all its children.
+func killProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
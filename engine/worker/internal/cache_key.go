@@ -0,0 +1,141 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ExpandCacheKey computes a cache key from given template, the function hashFiles returns the sha256
+// of the files matching given glob patterns relative to dir (** matches any number of directories).
+// Example: deps-{{ hashFiles "go.sum" "**/package-lock.json" }}
+func ExpandCacheKey(dir, key string) (string, error) {
+	if !strings.Contains(key, "{{") {
+		return key, nil
+	}
+
+	t, err := template.New("key").Funcs(template.FuncMap{
+		"hashFiles": func(patterns ...string) (string, error) {
+			return hashFiles(dir, patterns...)
+		},
+	}).Parse(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid cache key %q: %v", key, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, nil); err != nil {
+		return "", fmt.Errorf("unable to compute cache key %q: %v", key, err)
+	}
+	return buf.String(), nil
+}
+
+func hashFiles(dir string, patterns ...string) (string, error) {
+	if len(patterns) == 0 {
+		return "", fmt.Errorf("hashFiles needs at least one pattern")
+	}
+
+	regs := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		r, err := globToRegexp(p)
+		if err != nil {
+			return "", err
+		}
+		regs[i] = r
+	}
+
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, r := range regs {
+			if r.MatchString(rel) {
+				files = append(files, rel)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to list files in %s: %v", dir, err)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no file matches %s", strings.Join(patterns, ", "))
+	}
+
+	// Files are sorted to get the same hash whatever the walk order is
+	sort.Strings(files)
+	h := sha256.New()
+	for _, f := range files {
+		fh, err := hashFile(filepath.Join(dir, filepath.FromSlash(f)))
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %s\n", fh, f)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to open file %s: %v", path, err)
+	}
+	defer f.Close() // nolint
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("unable to read file %s: %v", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// globToRegexp converts a slash separated glob pattern to a regexp, * and ? don't match a separator
+// where **/ matches zero or more directories.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	r, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+	}
+	return r, nil
+}
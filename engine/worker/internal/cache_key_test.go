@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk/interpolate"
+)
+
+func TestExpandCacheKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-key")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "front", "app"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte("v1"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("root"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "front", "app", "package-lock.json"), []byte("app"), 0644))
+
+	key, err := ExpandCacheKey(dir, "latest")
+	require.NoError(t, err)
+	assert.Equal(t, "latest", key)
+
+	goKey, err := ExpandCacheKey(dir, `go-{{ hashFiles "go.sum" }}`)
+	require.NoError(t, err)
+	assert.Len(t, goKey, len("go-")+64)
+
+	again, err := ExpandCacheKey(dir, `go-{{ hashFiles "./go.sum" }}`)
+	require.NoError(t, err)
+	assert.Equal(t, goKey, again)
+
+	npmKey, err := ExpandCacheKey(dir, `npm-{{ hashFiles "**/package-lock.json" }}`)
+	require.NoError(t, err)
+	rootOnly, err := ExpandCacheKey(dir, `npm-{{ hashFiles "package-lock.json" }}`)
+	require.NoError(t, err)
+	assert.NotEqual(t, npmKey, rootOnly, "**/ should also match files in sub directories")
+
+	// Any change of a matching file changes the key
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "front", "app", "package-lock.json"), []byte("app v2"), 0644))
+	npmKeyV2, err := ExpandCacheKey(dir, `npm-{{ hashFiles "**/package-lock.json" }}`)
+	require.NoError(t, err)
+	assert.NotEqual(t, npmKey, npmKeyV2)
+
+	_, err = ExpandCacheKey(dir, `{{ hashFiles "pom.xml" }}`)
+	assert.Error(t, err, "a key without any matching file should be rejected")
+}
+
+func TestExpandCacheKeyAfterInterpolation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-key")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "api"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "pom.xml"), []byte("root"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "api", "pom.xml"), []byte("api"), 0644))
+
+	// The step script is interpolated with the job parameters before the worker cache command computes the key
+	script, err := interpolate.Do(`worker cache pull --restore-keys=m2- 'm2-{{ hashFiles "**/pom.xml" }}'`, map[string]string{"cds.workflow": "build"})
+	require.NoError(t, err)
+	assert.Equal(t, `worker cache pull --restore-keys=m2- 'm2-{{ hashFiles "**/pom.xml" }}'`, script)
+
+	key, err := ExpandCacheKey(dir, `m2-{{ hashFiles "**/pom.xml" }}`)
+	require.NoError(t, err)
+	assert.Len(t, key, len("m2-")+64)
+}
+
+func Test_globToRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{pattern: "go.sum", path: "go.sum", match: true},
+		{pattern: "go.sum", path: "sub/go.sum", match: false},
+		{pattern: "*.lock", path: "yarn.lock", match: true},
+		{pattern: "*.lock", path: "front/yarn.lock", match: false},
+		{pattern: "**/*.lock", path: "yarn.lock", match: true},
+		{pattern: "**/*.lock", path: "front/app/yarn.lock", match: true},
+		{pattern: "front/**", path: "front/app/yarn.lock", match: true},
+		{pattern: "front/?pp/*", path: "front/app/yarn.lock", match: true},
+	}
+	for _, tt := range tests {
+		r, err := globToRegexp(tt.pattern)
+		require.NoError(t, err)
+		assert.Equal(t, tt.match, r.MatchString(tt.path), "%s on %s", tt.pattern, tt.path)
+	}
+}
@@ -0,0 +1,322 @@
+// Package compose runs the service requirements of a job as containers on the Docker engine of the worker host,
+// so jobs with services can run on any hatchery and not only on swarm and kubernetes hatcheries.
+package compose
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	docker "github.com/docker/docker/client"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	labelJobID   = "cds_worker_service_job_id"
+	labelService = "cds_worker_service_name"
+
+	defaultServiceMemory  = 1024
+	defaultServiceTimeout = 300 * time.Second
+)
+
+// Service is a service requirement of a job, started as a container.
+type Service struct {
+	Name    string
+	Image   string
+	Env     []string
+	Cmd     []string
+	Memory  int64
+	Timeout time.Duration
+	IP      string
+
+	containerID string
+}
+
+// ParseServices returns the services declared in the given requirements. The value of a service requirement
+// is the image followed by environment variables, with the same options as the swarm and kubernetes hatcheries:
+// CDS_SERVICE_MEMORY (in MB), CDS_SERVICE_ARGS and CDS_SERVICE_TIMEOUT (max seconds to wait for the service).
+func ParseServices(reqs []sdk.Requirement) []Service {
+	var services []Service
+	for _, r := range reqs {
+		if r.Type != sdk.ServiceRequirement {
+			continue
+		}
+		img, envm := hatchery.ParseRequirementModel(r.Value)
+		s := Service{
+			Name:    r.Name,
+			Image:   img,
+			Memory:  defaultServiceMemory,
+			Timeout: defaultServiceTimeout,
+		}
+		for k, v := range envm {
+			switch k {
+			case "CDS_SERVICE_MEMORY":
+				if m, err := strconv.ParseInt(v, 10, 64); err == nil && m > 4 {
+					s.Memory = m
+				}
+			case "CDS_SERVICE_ARGS":
+				s.Cmd = hatchery.ParseArgs(v)
+			case "CDS_SERVICE_TIMEOUT":
+				if t, err := strconv.Atoi(v); err == nil && t > 0 {
+					s.Timeout = time.Duration(t) * time.Second
+				}
+			default:
+				s.Env = append(s.Env, k+"="+v)
+			}
+		}
+		services = append(services, s)
+	}
+	return services
+}
+
+// Runner starts the services of a job in a dedicated network of the local Docker engine.
+type Runner struct {
+	client   *docker.Client
+	jobID    int64
+	network  string
+	services []Service
+	hosts    *hostsFile
+}
+
+// NewRunner returns a runner using the Docker engine configured in the environment (DOCKER_HOST, default: local socket).
+func NewRunner(ctx context.Context, jobID int64) (*Runner, error) {
+	c, err := docker.NewEnvClient()
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to create docker client")
+	}
+	c.NegotiateAPIVersion(ctx)
+	return &Runner{
+		client:  c,
+		jobID:   jobID,
+		network: fmt.Sprintf("cds-services-%d", jobID),
+		hosts:   &hostsFile{path: defaultHostsFile, marker: fmt.Sprintf("cds-services-%d", jobID)},
+	}, nil
+}
+
+// Ping checks that the Docker engine is reachable.
+func (r *Runner) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if _, err := r.client.Ping(ctx); err != nil {
+		return sdk.WrapError(err, "docker engine is not reachable")
+	}
+	return nil
+}
+
+// Services returns the started services, with their IP address.
+func (r *Runner) Services() []Service {
+	return r.services
+}
+
+// Up starts the services and waits for them to be ready. Containers left by a previous job with the same id are removed first.
+func (r *Runner) Up(ctx context.Context, services []Service, logf func(format string, args ...interface{})) error {
+	if err := r.Down(ctx); err != nil {
+		return err
+	}
+
+	if _, err := r.client.NetworkCreate(ctx, r.network, types.NetworkCreate{
+		CheckDuplicate: true,
+		Labels:         map[string]string{labelJobID: strconv.FormatInt(r.jobID, 10)},
+	}); err != nil {
+		return sdk.WrapError(err, "unable to create network %s", r.network)
+	}
+
+	for i := range services {
+		s := &services[i]
+		logf("Starting service %s (%s)", s.Name, s.Image)
+		if err := r.start(ctx, s); err != nil {
+			return err
+		}
+		r.services = append(r.services, *s)
+	}
+
+	for i := range r.services {
+		s := &r.services[i]
+		if err := r.wait(ctx, s); err != nil {
+			return err
+		}
+		logf("Service %s is ready on %s", s.Name, s.IP)
+	}
+
+	skipped, err := r.hosts.add(r.services)
+	if err != nil {
+		logf("Unable to add the services in %s: %v. Use the variables cds.service.<name>.host to reach the services", r.hosts.path, err)
+		return nil
+	}
+	for _, name := range skipped {
+		logf("Service %s is already declared by another job on this host, use the name %s or the variable cds.service.%s.host to reach it",
+			name, r.hosts.hostname(Service{Name: name}), name)
+	}
+	return nil
+}
+
+// Down removes the containers and the network of the services.
+func (r *Runner) Down(ctx context.Context) error {
+	if err := r.hosts.remove(); err != nil {
+		log.Warning(ctx, "compose> unable to clean %s: %v", r.hosts.path, err)
+	}
+
+	args := filters.NewArgs()
+	args.Add("label", fmt.Sprintf("%s=%d", labelJobID, r.jobID))
+	cnts, err := r.client.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: args})
+	if err != nil {
+		return sdk.WrapError(err, "unable to list service containers")
+	}
+	for _, c := range cnts {
+		log.Debug("compose> removing container %s", c.ID)
+		if err := r.client.ContainerRemove(ctx, c.ID, types.ContainerRemoveOptions{RemoveVolumes: true, Force: true}); err != nil {
+			return sdk.WrapError(err, "unable to remove service container %s", c.ID)
+		}
+	}
+	r.services = nil
+
+	nets, err := r.client.NetworkList(ctx, types.NetworkListOptions{Filters: args})
+	if err != nil {
+		return sdk.WrapError(err, "unable to list service networks")
+	}
+	for _, n := range nets {
+		if err := r.client.NetworkRemove(ctx, n.ID); err != nil {
+			return sdk.WrapError(err, "unable to remove network %s", n.Name)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) start(ctx context.Context, s *Service) error {
+	if _, _, err := r.client.ImageInspectWithRaw(ctx, s.Image); err != nil || strings.HasSuffix(s.Image, ":latest") {
+		res, err := r.client.ImagePull(ctx, s.Image, types.ImagePullOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "unable to pull image %s", s.Image)
+		}
+		_, _ = io.Copy(ioutil.Discard, res)
+		res.Close() // nolint
+	}
+
+	config := &container.Config{
+		Image:  s.Image,
+		Env:    s.Env,
+		Cmd:    s.Cmd,
+		Labels: map[string]string{labelJobID: strconv.FormatInt(r.jobID, 10), labelService: s.Name},
+	}
+	hostConfig := &container.HostConfig{
+		Resources: container.Resources{Memory: s.Memory * 1024 * 1024},
+	}
+	networkingConfig := &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			r.network: {Aliases: []string{s.Name}},
+		},
+	}
+
+	c, err := r.client.ContainerCreate(ctx, config, hostConfig, networkingConfig, fmt.Sprintf("%s-%s", s.Name, r.network))
+	if err != nil {
+		return sdk.WrapError(err, "unable to create container for service %s", s.Name)
+	}
+	s.containerID = c.ID
+
+	if err := r.client.ContainerStart(ctx, c.ID, types.ContainerStartOptions{}); err != nil {
+		return sdk.WrapError(err, "unable to start container for service %s", s.Name)
+	}
+	return nil
+}
+
+// wait waits for the health check of the service container if the image defines one,
+// otherwise for the exposed TCP ports of the container to accept connections.
+func (r *Runner) wait(ctx context.Context, s *Service) error {
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		c, err := r.client.ContainerInspect(ctx, s.containerID)
+		if err != nil {
+			return sdk.WrapError(err, "unable to inspect container of service %s", s.Name)
+		}
+		if n, ok := c.NetworkSettings.Networks[r.network]; ok {
+			s.IP = n.IPAddress
+		}
+
+		ready, err := r.ready(ctx, c, s)
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return sdk.WithStack(fmt.Errorf("service %s is not ready after %s: %s", s.Name, s.Timeout, r.logs(s)))
+		case <-tick.C:
+		}
+	}
+}
+
+func (r *Runner) ready(ctx context.Context, c types.ContainerJSON, s *Service) (bool, error) {
+	if c.State == nil || !c.State.Running {
+		if c.State != nil && (c.State.Status == "exited" || c.State.Status == "dead") {
+			return false, sdk.WithStack(fmt.Errorf("service %s exited with code %d: %s", s.Name, c.State.ExitCode, r.logs(s)))
+		}
+		return false, nil
+	}
+
+	if c.State.Health != nil {
+		switch c.State.Health.Status {
+		case types.Healthy:
+			return true, nil
+		case types.Unhealthy:
+			return false, sdk.WithStack(fmt.Errorf("service %s is unhealthy: %s", s.Name, r.logs(s)))
+		}
+		return false, nil
+	}
+
+	if s.IP == "" {
+		return false, nil
+	}
+	for p := range c.Config.ExposedPorts {
+		if p.Proto() != "tcp" {
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(s.IP, p.Port()), time.Second)
+		if err != nil {
+			log.Debug("compose> service %s: port %s not ready: %v", s.Name, p.Port(), err)
+			return false, nil
+		}
+		conn.Close() // nolint
+	}
+	return true, nil
+}
+
+// logs returns the last lines of the logs of a service container to help understanding why it failed.
+func (r *Runner) logs(s *Service) string {
+	rc, err := r.client.ContainerLogs(context.Background(), s.containerID, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Tail: "20"})
+	if err != nil {
+		return err.Error()
+	}
+	defer rc.Close() // nolint
+
+	var lines []string
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// remove the header of the multiplexed docker stream
+		if len(line) > 8 && line[0] <= 2 {
+			line = line[8:]
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
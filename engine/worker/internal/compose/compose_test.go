@@ -0,0 +1,119 @@
+package compose
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseServices(t *testing.T) {
+	services := ParseServices([]sdk.Requirement{
+		{Name: "bash", Type: sdk.BinaryRequirement, Value: "bash"},
+		{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.5 POSTGRES_PASSWORD=pg CDS_SERVICE_MEMORY=512 CDS_SERVICE_TIMEOUT=60"},
+		{Name: "redis", Type: sdk.ServiceRequirement, Value: "redis:5 CDS_SERVICE_ARGS='--port 6380'"},
+	})
+	require.Len(t, services, 2)
+
+	assert.Equal(t, "pg", services[0].Name)
+	assert.Equal(t, "postgres:9.5", services[0].Image)
+	assert.Equal(t, []string{"POSTGRES_PASSWORD=pg"}, services[0].Env)
+	assert.Equal(t, int64(512), services[0].Memory)
+	assert.Equal(t, time.Minute, services[0].Timeout)
+
+	assert.Equal(t, "redis", services[1].Name)
+	assert.Equal(t, []string{"--port", "6380"}, services[1].Cmd)
+	assert.Equal(t, int64(defaultServiceMemory), services[1].Memory)
+	assert.Equal(t, defaultServiceTimeout, services[1].Timeout)
+}
+
+func TestHostsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hosts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+	path := filepath.Join(dir, "hosts")
+	initial := "127.0.0.1\tlocalhost\n"
+	require.NoError(t, ioutil.WriteFile(path, []byte(initial), 0644))
+
+	h := hostsFile{path: path, marker: "cds-services-42"}
+	skipped, err := h.add([]Service{{Name: "pg", IP: "172.18.0.2"}, {Name: "redis", IP: "172.18.0.3"}})
+	require.NoError(t, err)
+	assert.Empty(t, skipped)
+
+	btes, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, initial+"# BEGIN cds-services-42\n172.18.0.2\tpg.cds-services-42 pg\n172.18.0.3\tredis.cds-services-42 redis\n# END cds-services-42\n", string(btes))
+
+	require.NoError(t, h.remove())
+	btes, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, initial, string(btes))
+
+	// removing twice or from a missing file is not an error
+	require.NoError(t, h.remove())
+	h.path = filepath.Join(dir, "unknown")
+	require.NoError(t, h.remove())
+}
+
+func TestHostsFileSharedByJobs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hosts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+	path := filepath.Join(dir, "hosts")
+	initial := "127.0.0.1\tlocalhost\n"
+	require.NoError(t, ioutil.WriteFile(path, []byte(initial), 0644))
+
+	// The jobs of the host update the file at the same time, no block is lost
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			h := hostsFile{path: path, marker: fmt.Sprintf("cds-services-%d", i)}
+			_, err := h.add([]Service{{Name: "pg", IP: fmt.Sprintf("172.18.%d.2", i)}})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	btes, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		assert.Contains(t, string(btes), fmt.Sprintf("172.18.%d.2\tpg.cds-services-%d", i, i))
+	}
+	assert.Len(t, declaredNames(string(btes)), 21, "each job has its own name and only one declares the short name")
+
+	for i := 0; i < 20; i++ {
+		h := hostsFile{path: path, marker: fmt.Sprintf("cds-services-%d", i)}
+		require.NoError(t, h.remove())
+	}
+
+	// The short name used by another job is not added
+	h1 := hostsFile{path: path, marker: "cds-services-1"}
+	h2 := hostsFile{path: path, marker: "cds-services-2"}
+	_, err = h1.add([]Service{{Name: "pg", IP: "172.18.0.2"}})
+	require.NoError(t, err)
+	skipped, err := h2.add([]Service{{Name: "pg", IP: "172.19.0.2"}, {Name: "redis", IP: "172.19.0.3"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pg"}, skipped)
+
+	btes, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, initial+
+		"# BEGIN cds-services-1\n172.18.0.2\tpg.cds-services-1 pg\n# END cds-services-1\n"+
+		"# BEGIN cds-services-2\n172.19.0.2\tpg.cds-services-2\n172.19.0.3\tredis.cds-services-2 redis\n# END cds-services-2\n", string(btes))
+
+	require.NoError(t, h1.remove())
+	require.NoError(t, h2.remove())
+	btes, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, initial, string(btes))
+}
@@ -0,0 +1,155 @@
+package compose
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	defaultHostsFile = "/etc/hosts"
+	hostsMarkerBegin = "# BEGIN "
+	hostsMarkerEnd   = "# END "
+)
+
+// hostsFile adds the services in a block of the hosts file of the worker host, so the steps reach them by their name.
+// The block is identified by a marker and removed when the services are stopped. The file can be shared by the
+// workers of a host: it is locked while it is updated, each service is added with a name suffixed by the marker
+// of the job and its short name is only added if no other job declares it.
+type hostsFile struct {
+	path   string
+	marker string
+}
+
+func (h *hostsFile) begin() string { return hostsMarkerBegin + h.marker }
+func (h *hostsFile) end() string   { return hostsMarkerEnd + h.marker }
+
+// hostname returns the name of a service that is unique on the host.
+func (h *hostsFile) hostname(s Service) string {
+	return s.Name + "." + h.marker
+}
+
+// add adds a block with the services and returns the short names that were not added because another job uses them.
+func (h *hostsFile) add(services []Service) ([]string, error) {
+	if len(services) == 0 {
+		return nil, nil
+	}
+	var skipped []string
+	err := h.update(func(content string) string {
+		content = h.removeBlock(content)
+		used := declaredNames(content)
+
+		var b strings.Builder
+		b.WriteString(content)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString(h.begin() + "\n")
+		for _, s := range services {
+			if s.IP == "" {
+				continue
+			}
+			if _, ok := used[s.Name]; ok {
+				skipped = append(skipped, s.Name)
+				fmt.Fprintf(&b, "%s\t%s\n", s.IP, h.hostname(s))
+				continue
+			}
+			fmt.Fprintf(&b, "%s\t%s %s\n", s.IP, h.hostname(s), s.Name)
+		}
+		b.WriteString(h.end() + "\n")
+		return b.String()
+	})
+	return skipped, err
+}
+
+func (h *hostsFile) remove() error {
+	err := h.update(h.removeBlock)
+	if err != nil && os.IsNotExist(sdk.Cause(err)) {
+		return nil
+	}
+	return err
+}
+
+// removeBlock returns the content without the block of the job.
+func (h *hostsFile) removeBlock(content string) string {
+	if !strings.Contains(content, h.begin()) {
+		return content
+	}
+
+	var lines []string
+	var inBlock bool
+	for _, l := range strings.SplitAfter(content, "\n") {
+		switch strings.TrimSpace(l) {
+		case h.begin():
+			inBlock = true
+			continue
+		case h.end():
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// declaredNames returns the names declared in the blocks of the other jobs.
+func declaredNames(content string) map[string]struct{} {
+	names := make(map[string]struct{})
+	var inBlock bool
+	for _, l := range strings.Split(content, "\n") {
+		l = strings.TrimSpace(l)
+		switch {
+		case strings.HasPrefix(l, hostsMarkerBegin):
+			inBlock = true
+			continue
+		case strings.HasPrefix(l, hostsMarkerEnd):
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			continue
+		}
+		fields := strings.Fields(l)
+		for i := 1; i < len(fields); i++ {
+			names[fields[i]] = struct{}{}
+		}
+	}
+	return names
+}
+
+// update replaces the content of the file while holding an exclusive lock on it. The file is updated in place
+// because /etc/hosts is often a bind mount that can't be replaced.
+func (h *hostsFile) update(fn func(content string) string) error {
+	f, err := os.OpenFile(h.path, os.O_RDWR, 0)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer f.Close() // nolint
+
+	if err := lockFile(f); err != nil {
+		return sdk.WrapError(err, "unable to lock %s", h.path)
+	}
+	defer unlockFile(f) // nolint
+
+	btes, err := ioutil.ReadAll(f)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	content := fn(string(btes))
+	if content == string(btes) {
+		return nil
+	}
+
+	if err := f.Truncate(0); err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := f.WriteAt([]byte(content), 0); err != nil {
+		return sdk.WithStack(err)
+	}
+	return nil
+}
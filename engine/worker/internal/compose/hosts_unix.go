@@ -0,0 +1,19 @@
+// +build !windows
+
+package compose
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// lockFile waits for an exclusive lock on the file, the lock is shared with the other workers of the host.
+func lockFile(f *os.File) error {
+	return sdk.WithStack(syscall.Flock(int(f.Fd()), syscall.LOCK_EX))
+}
+
+func unlockFile(f *os.File) error {
+	return sdk.WithStack(syscall.Flock(int(f.Fd()), syscall.LOCK_UN))
+}
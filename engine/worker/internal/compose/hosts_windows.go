@@ -0,0 +1,10 @@
+// +build windows
+
+package compose
+
+import "os"
+
+// lockFile does nothing on windows, there is no advisory lock shared with the other workers.
+func lockFile(f *os.File) error { return nil }
+
+func unlockFile(f *os.File) error { return nil }
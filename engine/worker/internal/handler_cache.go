@@ -130,7 +130,21 @@ func cachePullHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 		integrationName := sdk.DefaultIfEmptyStorage(req.FormValue("integration"))
 		params := wk.currentJob.wJob.Parameters
 		projectKey := sdk.ParameterValue(params, "cds.project")
-		r, err := wk.client.WorkflowCachePull(projectKey, integrationName, vars["ref"])
+
+		// With restore keys, the cache to pull can be an older one whose key starts with one of them
+		ref := vars["ref"]
+		var restored *sdk.CacheEntry
+		if restoreKeys := req.URL.Query()["restoreKey"]; len(restoreKeys) > 0 {
+			e, err := wk.client.WorkflowCacheSearch(projectKey, integrationName, sdk.CacheKeyFromTag(ref), restoreKeys)
+			if err != nil {
+				log.Info(ctx, "cachePullHandler> no cache found with restore keys %v: %v", restoreKeys, err)
+			} else {
+				ref = e.Tag
+				restored = e
+			}
+		}
+
+		r, err := wk.client.WorkflowCachePull(projectKey, integrationName, ref)
 		if err != nil {
 			err = sdk.Error{
 				Message: "worker cache pull > Cannot pull cache: " + err.Error(),
@@ -231,5 +245,9 @@ func cachePullHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 				_ = f.Close()
 			}
 		}
+
+		if restored != nil {
+			writeJSON(w, restored, http.StatusOK)
+		}
 	}
 }
@@ -175,3 +175,55 @@ func Test_cachePushPullHandler(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "absolute", string(btsAbsolute))
 }
+
+func Test_cachePullHandlerWithRestoreKeys(t *testing.T) {
+	wk := &CurrentWorker{}
+	wk.currentJob.wJob = &sdk.WorkflowNodeJobRun{
+		Parameters: []sdk.Parameter{{
+			Name:  "cds.project",
+			Value: "myProject",
+		}},
+	}
+
+	srcDir, err := ioutil.TempDir("", "cache-src")
+	require.NoError(t, err)
+	defer os.RemoveAll(srcDir) // nolint
+	require.NoError(t, ioutil.WriteFile(filepath.Join(srcDir, "vendor.txt"), []byte("vendor"), os.FileMode(0644)))
+	var tarContent bytes.Buffer
+	require.NoError(t, sdk.CreateTarFromPaths(afero.NewOsFs(), srcDir, []string{"vendor.txt"}, &tarContent, nil))
+
+	ctrl := gomock.NewController(t)
+	m := mock_cdsclient.NewMockInterface(ctrl)
+	wk.client = m
+
+	m.EXPECT().WorkflowCacheSearch("myProject", "shared.infra", "deps-abcd", []string{"deps-"}).Return(&sdk.CacheEntry{
+		Tag: "ZGVwcy0xMjM0",
+		Key: "deps-1234",
+	}, nil)
+	m.EXPECT().WorkflowCachePull("myProject", "shared.infra", "ZGVwcy0xMjM0").Return(&tarContent, nil)
+
+	pullPath, err := ioutil.TempDir("", "cache-pull")
+	require.NoError(t, err)
+	defer os.RemoveAll(pullPath) // nolint
+
+	req, err := http.NewRequest(http.MethodGet, "/cache/ZGVwcy1hYmNk/pull", nil)
+	require.NoError(t, err)
+	q := req.URL.Query()
+	q.Set("path", pullPath)
+	q.Add("restoreKey", "deps-")
+	req.URL.RawQuery = q.Encode()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/cache/{ref}/pull", cachePullHandler(context.Background(), wk))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var restored sdk.CacheEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Equal(t, "deps-1234", restored.Key)
+
+	btsVendor, err := ioutil.ReadFile(filepath.Join(pullPath, "vendor.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "vendor", string(btsVendor))
+}
@@ -14,9 +14,7 @@ func (wk *CurrentWorker) sendLog(buildID int64, value string, stepOrder int, fin
 		log.Error(wk.GetContext(), "unable to send log: %s", value)
 		return nil
 	}
-	if err := wk.Blur(&value); err != nil {
-		return err
-	}
+	value = wk.currentJob.secretMasker.Mask(value)
 	now := time.Now()
 	l := sdk.NewLog(buildID, wk.currentJob.wJob.WorkflowNodeRunID, value, stepOrder)
 	if final {
@@ -13,6 +13,10 @@ func (w *CurrentWorker) Register(ctx context.Context) error {
 	var form sdk.WorkerRegistrationForm
 	log.Info(ctx, "Registering with Token %s on %s", w.register.token[:12], w.register.apiEndpoint)
 
+	if err := w.Update(ctx); err != nil {
+		return err
+	}
+
 	requirements, errR := w.client.Requirements()
 	if errR != nil {
 		log.Warning(ctx, "register> unable to get requirements: %v", errR)
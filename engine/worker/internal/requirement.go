@@ -12,6 +12,7 @@ import (
 
 	"github.com/shirou/gopsutil/mem"
 
+	"github.com/ovh/cds/engine/worker/internal/compose"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -130,9 +131,18 @@ func checkNetworkAccessRequirement(w *CurrentWorker, r sdk.Requirement) (bool, e
 }
 
 func checkServiceRequirement(w *CurrentWorker, r sdk.Requirement) (bool, error) {
-	// service are supported only for Model Docker
+	// services of workers that are not spawned from a docker model are started by the worker on the local docker engine
 	if w.model.Type != sdk.Docker {
-		return false, nil
+		runner, err := compose.NewRunner(context.TODO(), 0)
+		if err != nil {
+			log.Debug("Error checking requirement : %s", err)
+			return false, nil
+		}
+		if err := runner.Ping(context.TODO()); err != nil {
+			log.Debug("Error checking requirement : %s", err)
+			return false, nil
+		}
+		return true, nil
 	}
 
 	retry := 3
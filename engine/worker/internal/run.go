@@ -13,6 +13,7 @@ import (
 
 	"github.com/spf13/afero"
 
+	"github.com/ovh/cds/engine/worker/internal/compose"
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/interpolate"
@@ -109,7 +110,7 @@ func (w *CurrentWorker) replaceVariablesPlaceholder(a *sdk.Action, params []sdk.
 	return nil
 }
 
-func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64, secrets []sdk.Variable) sdk.Result {
+func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64, secrets []sdk.Variable, timeout time.Duration) sdk.Result {
 	log.Info(ctx, "runJob> start job %s (%d)", a.Name, jobID)
 	defer func() { log.Info(ctx, "runJob> job %s (%d)", a.Name, jobID) }()
 
@@ -118,9 +119,19 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 		BuildID: jobID,
 	}
 
+	// Steps are executed with the job deadline, steps status are still sent with the parent context
+	stepsCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		stepsCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	var nDisabled, nCriticalFailed int
+	var timedOut bool
 	for jobStepIndex, step := range a.Actions {
 		ctx = workerruntime.SetStepOrder(ctx, jobStepIndex)
+		stepsCtx = workerruntime.SetStepOrder(stepsCtx, jobStepIndex)
 		if err := w.updateStepStatus(ctx, jobID, jobStepIndex, sdk.StatusBuilding); err != nil {
 			jobResult.Status = sdk.StatusFail
 			jobResult.Reason = fmt.Sprintf("Cannot update step (%d) status (%s): %v", jobStepIndex, sdk.StatusBuilding, err)
@@ -130,8 +141,16 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 			Status:  sdk.StatusNeverBuilt,
 			BuildID: jobID,
 		}
-		if nCriticalFailed == 0 || step.AlwaysExecuted {
-			stepResult = w.runAction(ctx, step, jobID, secrets, step.Name)
+		if stepsCtx.Err() == context.DeadlineExceeded {
+			// the job deadline is exceeded, following steps are not executed
+			timedOut = timedOut || nCriticalFailed == 0
+			nCriticalFailed++
+		} else if nCriticalFailed == 0 || step.AlwaysExecuted {
+			stepResult = w.runStep(stepsCtx, step, jobID, secrets, step.Name)
+			// a step interrupted by the job deadline is in timeout
+			if stepsCtx.Err() == context.DeadlineExceeded && stepResult.Status == sdk.StatusFail {
+				stepResult.Status = sdk.StatusTimeout
+			}
 
 			// Check if all newVariables are in currentJob.params
 			// variable can be add in w.currentJob.newVariables by worker command export
@@ -158,6 +177,14 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 				if !step.Optional {
 					nCriticalFailed++
 				}
+			case sdk.StatusTimeout:
+				if !step.Optional {
+					if nCriticalFailed == 0 {
+						timedOut = true
+						jobResult.Reason = stepResult.Reason
+					}
+					nCriticalFailed++
+				}
 			}
 		}
 		if err := w.updateStepStatus(ctx, jobID, jobStepIndex, stepResult.Status); err != nil {
@@ -178,9 +205,38 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 	if nCriticalFailed > 0 {
 		jobResult.Status = sdk.StatusFail
 	}
+	// The job is in timeout if the first critical failure is a timeout
+	if timedOut {
+		jobResult.Status = sdk.StatusTimeout
+		if stepsCtx.Err() == context.DeadlineExceeded {
+			jobResult.Reason = fmt.Sprintf("Job timed out after %s", timeout)
+			w.SendLog(ctx, workerruntime.LevelError, jobResult.Reason)
+		}
+	}
 	return jobResult
 }
 
+// runStep runs an action with its step timeout if any. The action context is canceled when
+// the timeout expires, so the running process is killed and the step is marked as timed out.
+func (w *CurrentWorker) runStep(ctx context.Context, a sdk.Action, jobID int64, secrets []sdk.Variable, actionName string) sdk.Result {
+	if a.Timeout <= 0 {
+		return w.runAction(ctx, a, jobID, secrets, actionName)
+	}
+
+	timeout := time.Duration(a.Timeout) * time.Second
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	res := w.runAction(stepCtx, a, jobID, secrets, actionName)
+	// the deadline of the parent context (i.e. job timeout) is handled by the caller
+	if stepCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil && res.Status != sdk.StatusSuccess {
+		res.Status = sdk.StatusTimeout
+		res.Reason = fmt.Sprintf("Step \"%s\" timed out after %s", actionName, timeout)
+		w.SendLog(ctx, workerruntime.LevelError, res.Reason)
+	}
+	return res
+}
+
 func (w *CurrentWorker) runAction(ctx context.Context, a sdk.Action, jobID int64, secrets []sdk.Variable, actionName string) sdk.Result {
 	log.Info(ctx, "runAction> start action %s %s %d", a.StepName, actionName, jobID)
 	defer func() { log.Info(ctx, "runAction> end action %s %s run %d", a.StepName, actionName, jobID) }()
@@ -253,7 +309,7 @@ func (w *CurrentWorker) runSteps(ctx context.Context, steps []sdk.Action, a sdk.
 	defer func() {
 		log.Info(ctx, "runSteps> end action steps %s %d len(steps):%d context=%p (%s)", stepName, jobID, len(steps), ctx, ctx.Err())
 	}()
-	var criticalStepFailed bool
+	var criticalStepFailed, childTimedOut bool
 	var nbDisabledChildren int
 
 	r := sdk.Result{
@@ -272,8 +328,11 @@ func (w *CurrentWorker) runSteps(ctx context.Context, steps []sdk.Action, a sdk.
 		}
 
 		if !criticalStepFailed || child.AlwaysExecuted {
-			r = w.runAction(ctx, child, jobID, secrets, childName)
+			r = w.runStep(ctx, child, jobID, secrets, childName)
 			if r.Status != sdk.StatusSuccess && !child.Optional {
+				if !criticalStepFailed && r.Status == sdk.StatusTimeout {
+					childTimedOut = true
+				}
 				criticalStepFailed = true
 			}
 		} else if criticalStepFailed && !child.AlwaysExecuted {
@@ -297,7 +356,9 @@ func (w *CurrentWorker) runSteps(ctx context.Context, steps []sdk.Action, a sdk.
 		}
 	}
 
-	if criticalStepFailed {
+	if childTimedOut {
+		r.Status = sdk.StatusTimeout
+	} else if criticalStepFailed {
 		r.Status = sdk.StatusFail
 	} else {
 		r.Status = sdk.StatusSuccess
@@ -561,6 +622,31 @@ func (w *CurrentWorker) ProcessJob(jobInfo sdk.WorkflowNodeJobRunData) (res sdk.
 
 	var jobParameters = jobInfo.NodeJobRun.Parameters
 
+	// Start the services of the job if they are not started by the hatchery
+	if services := compose.ParseServices(jobInfo.NodeJobRun.Job.Action.Requirements); len(services) > 0 && w.model.Type != sdk.Docker {
+		runner, err := w.startServices(ctx, jobInfo.NodeJobRun.ID, services)
+		if runner != nil {
+			defer func() {
+				if err := runner.Down(context.Background()); err != nil {
+					log.Error(ctx, "processJob> Unable to stop services: %v", err)
+				}
+			}()
+		}
+		if err != nil {
+			return sdk.Result{
+				Status: sdk.StatusFail,
+				Reason: fmt.Sprintf("Error: unable to start services: %v", err),
+			}
+		}
+		for _, s := range runner.Services() {
+			jobParameters = append(jobParameters, sdk.Parameter{
+				Name:  "cds.service." + s.Name + ".host",
+				Type:  sdk.StringParameter,
+				Value: s.IP,
+			})
+		}
+	}
+
 	//Add working directory as job parameter
 	jobParameters = append(jobParameters, sdk.Parameter{
 		Name:  "cds.workspace",
@@ -596,7 +682,7 @@ func (w *CurrentWorker) ProcessJob(jobInfo sdk.WorkflowNodeJobRunData) (res sdk.
 
 	w.currentJob.params = jobParameters
 
-	res = w.runJob(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, jobInfo.Secrets)
+	res = w.runJob(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, jobInfo.Secrets, time.Duration(jobInfo.NodeJobRun.Job.Timeout)*time.Second)
 
 	if len(res.NewVariables) > 0 {
 		log.Debug("processJob> new variables: %v", res.NewVariables)
@@ -621,3 +707,19 @@ func (w *CurrentWorker) ProcessJob(jobInfo sdk.WorkflowNodeJobRunData) (res sdk.
 
 	return res
 }
+
+// startServices starts the service requirements of a job on the local docker engine, the returned runner
+// has to be stopped at the end of the job even if an error occurred.
+func (w *CurrentWorker) startServices(ctx context.Context, jobID int64, services []compose.Service) (*compose.Runner, error) {
+	runner, err := compose.NewRunner(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	logf := func(format string, args ...interface{}) {
+		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf(format, args...))
+	}
+	if err := runner.Up(ctx, services, logf); err != nil {
+		return runner, err
+	}
+	return runner, nil
+}
@@ -11,10 +11,10 @@ import (
 )
 
 func StartWorker(ctx context.Context, w *CurrentWorker, bookedJobID int64) (mainError error) {
-	log.Info(ctx, "Starting worker %s on job %d", w.Name(), bookedJobID)
-
 	if bookedJobID == 0 {
-		return fmt.Errorf("startWorker: bookedJobID is mandatory. val:%d", bookedJobID)
+		log.Info(ctx, "Starting idle worker %s", w.Name())
+	} else {
+		log.Info(ctx, "Starting worker %s on job %d", w.Name(), bookedJobID)
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -55,23 +55,6 @@ func StartWorker(ctx context.Context, w *CurrentWorker, bookedJobID int64) (main
 		}
 	}
 
-	if err := processBookedWJob(ctx, w, jobsChan, bookedJobID); err != nil {
-		// Unbook job
-		if errR := w.Client().QueueJobRelease(ctx, bookedJobID); errR != nil {
-			log.Error(ctx, "runCmd> QueueJobRelease> Cannot release job")
-		}
-		// this worker was spawned for a job
-		// this job can't be process (err != nil)
-		// so, call endFunc() now, this worker don't have to work
-		// on another job
-		endFunc()
-		return sdk.WrapError(err, "unable to process booked job")
-	}
-
-	if err := w.Client().WorkerSetStatus(ctx, sdk.StatusWaiting); err != nil {
-		log.Error(ctx, "WorkerSetStatus> error on WorkerSetStatus(ctx, sdk.StatusWaiting): %s", err)
-	}
-
 	// Errors check loops
 	go func() {
 		for err := range errsChan {
@@ -103,6 +86,33 @@ func StartWorker(ctx context.Context, w *CurrentWorker, bookedJobID int64) (main
 		}
 	}()
 
+	// An idle worker waits for a job assigned by its hatchery
+	if bookedJobID == 0 {
+		id, err := waitAssignedJob(ctx, w)
+		if err != nil {
+			endFunc()
+			return err
+		}
+		bookedJobID = id
+	}
+
+	if err := processBookedWJob(ctx, w, jobsChan, bookedJobID); err != nil {
+		// Unbook job
+		if errR := w.Client().QueueJobRelease(ctx, bookedJobID); errR != nil {
+			log.Error(ctx, "runCmd> QueueJobRelease> Cannot release job")
+		}
+		// this worker was spawned for a job
+		// this job can't be process (err != nil)
+		// so, call endFunc() now, this worker don't have to work
+		// on another job
+		endFunc()
+		return sdk.WrapError(err, "unable to process booked job")
+	}
+
+	if err := w.Client().WorkerSetStatus(ctx, sdk.StatusWaiting); err != nil {
+		log.Error(ctx, "WorkerSetStatus> error on WorkerSetStatus(ctx, sdk.StatusWaiting): %s", err)
+	}
+
 	// main loop
 	for {
 		if ctx.Err() != nil {
@@ -127,11 +137,8 @@ func StartWorker(ctx context.Context, w *CurrentWorker, bookedJobID int64) (main
 				errsChan <- err
 			}
 
-			if err := w.Client().WorkerSetStatus(ctx, sdk.StatusWaiting); err != nil {
-				log.Error(ctx, "WorkerSetStatus> error on WorkerSetStatus(ctx, sdk.StatusWaiting): %v", err)
-			}
-
-			// Unregister from engine
+			// The worker is not set back to waiting, it would be seen as idle and a new job could be
+			// assigned to it before it unregisters
 			log.Info(ctx, "Job is done. Unregistering...")
 			endFunc()
 			return nil
@@ -139,6 +146,38 @@ func StartWorker(ctx context.Context, w *CurrentWorker, bookedJobID int64) (main
 	}
 }
 
+// waitAssignedJob waits until a job is assigned to the worker, or until the worker is disabled.
+func waitAssignedJob(ctx context.Context, w *CurrentWorker) (int64, error) {
+	log.Info(ctx, "Waiting for a job")
+	tick := time.NewTicker(2 * time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-tick.C:
+			wks, err := w.Client().WorkerList(ctx)
+			if err != nil {
+				if sdk.ErrorIs(err, sdk.ErrUnauthorized) || sdk.ErrorIs(err, sdk.ErrForbidden) {
+					return 0, sdk.WrapError(err, "unable to get worker state")
+				}
+				log.Warning(ctx, "Unable to get worker state: %v", err)
+				continue
+			}
+			if len(wks) != 1 {
+				continue
+			}
+			if wks[0].Status == sdk.StatusDisabled {
+				return 0, fmt.Errorf("worker has been disabled while waiting for a job")
+			}
+			if wks[0].JobRunID != nil {
+				log.Info(ctx, "Job %d assigned to worker %s", *wks[0].JobRunID, w.Name())
+				return *wks[0].JobRunID, nil
+			}
+		}
+	}
+}
+
 func processBookedWJob(ctx context.Context, w *CurrentWorker, wjobs chan<- sdk.WorkflowNodeJobRun, bookedWJobID int64) error {
 	log.Debug("Try to take the workflow node job %d", bookedWJobID)
 	wjob, err := w.Client().QueueJobInfo(ctx, bookedWJobID)
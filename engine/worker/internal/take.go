@@ -28,6 +28,7 @@ func (w *CurrentWorker) Take(ctx context.Context, job sdk.WorkflowNodeJobRun) er
 	// Set build variables
 	w.currentJob.wJob = &info.NodeJobRun
 	w.currentJob.secrets = info.Secrets
+	w.currentJob.secretMasker = newJobSecretMasker(info.Secrets, info.NodeJobRun.Parameters)
 	// Reset build variables
 	w.currentJob.newVariables = nil
 
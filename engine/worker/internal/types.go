@@ -46,6 +46,7 @@ type CurrentWorker struct {
 		newVariables []sdk.Variable
 		params       []sdk.Parameter
 		secrets      []sdk.Variable
+		secretMasker *sdk.SecretMasker
 		context      context.Context
 	}
 	status struct {
@@ -153,19 +154,27 @@ func (wk *CurrentWorker) Environ() []string {
 	return newEnv
 }
 
+// newJobSecretMasker returns a masker for the secrets of a job and its password parameters.
+func newJobSecretMasker(secrets []sdk.Variable, params []sdk.Parameter) *sdk.SecretMasker {
+	values := make([]string, 0, len(secrets)+len(params))
+	for _, s := range secrets {
+		values = append(values, s.Value)
+	}
+	for _, p := range params {
+		if p.Type == sdk.SecretVariable {
+			values = append(values, p.Value)
+		}
+	}
+	return sdk.NewSecretMasker(values...)
+}
+
 func (w *CurrentWorker) Blur(i interface{}) error {
 	data, err := json.Marshal(i)
 	if err != nil {
 		return err
 	}
 
-	dataS := string(data)
-	for i := range w.currentJob.secrets {
-		if len(w.currentJob.secrets[i].Value) >= sdk.SecretMinLength {
-			dataS = strings.Replace(dataS, w.currentJob.secrets[i].Value, sdk.PasswordPlaceholder, -1)
-		}
-	}
-
+	dataS := w.currentJob.secretMasker.Mask(string(data))
 	if err := json.Unmarshal([]byte(dataS), i); err != nil {
 		return err
 	}
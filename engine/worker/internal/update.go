@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	goUpdate "github.com/inconshreveable/go-update"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/jws"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// envWorkerUpdated is set on the updated worker process to avoid update loops
+// if the downloadable binary does not match the version advertised by the API.
+const envWorkerUpdated = "CDS_WORKER_UPDATED"
+
+// UpdatePublicKey is the RSA PEM public key used to check the signature of the worker binary before an update,
+// the worker never updates itself without it. It can be set at build time with
+// -ldflags "-X github.com/ovh/cds/engine/worker/internal.UpdatePublicKey=<key>" or overridden with the flag
+// --update-public-key. The key can be base64 encoded to avoid line breaks.
+var UpdatePublicKey string
+
+// updatePublicKey returns the RSA public key pinned in the worker.
+func updatePublicKey() (*rsa.PublicKey, error) {
+	if UpdatePublicKey == "" {
+		return nil, sdk.WithStack(fmt.Errorf("no update public key configured"))
+	}
+	key := []byte(UpdatePublicKey)
+	if !strings.HasPrefix(strings.TrimSpace(UpdatePublicKey), "-----BEGIN") {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(UpdatePublicKey))
+		if err != nil {
+			return nil, sdk.WrapError(err, "invalid update public key")
+		}
+		key = decoded
+	}
+	pk, err := jws.NewPublicKeyFromPEM(key)
+	if err != nil {
+		return nil, sdk.WrapError(err, "invalid update public key")
+	}
+	return pk, nil
+}
+
+// workerUpdateVersion returns the version the worker should update to, or an empty string if the worker
+// version is up to date. The returned boolean is true if the current version is older than the min version.
+func workerUpdateVersion(current string, v sdk.WorkerVersion) (string, bool) {
+	belowMin := v.MinVersion != "" && sdk.VersionIsOlder(current, v.MinVersion)
+	switch {
+	case v.RecommendedVersion != "" && sdk.VersionIsOlder(current, v.RecommendedVersion):
+		return v.RecommendedVersion, belowMin
+	case belowMin:
+		return v.MinVersion, belowMin
+	}
+	return "", false
+}
+
+// Update checks the worker versions advertised by the API, and if the worker is too old, it replaces
+// its binary with the one downloaded from the API and restarts. The update fails only if the worker
+// is older than the min version, because such a worker would be rejected at registration.
+func (w *CurrentWorker) Update(ctx context.Context) error {
+	if os.Getenv(envWorkerUpdated) != "" {
+		log.Debug("worker binary already updated to %s", sdk.VERSION)
+		return nil
+	}
+
+	v, err := w.client.WorkerVersion(ctx)
+	if err != nil {
+		log.Warning(ctx, "update> unable to get worker versions: %v", err)
+		return nil
+	}
+
+	version, belowMin := workerUpdateVersion(sdk.VERSION, *v)
+	if version == "" {
+		return nil
+	}
+
+	log.Info(ctx, "Updating worker from version %s to %s", sdk.VERSION, version)
+	if err := w.updateBinary(ctx); err != nil {
+		if belowMin {
+			return sdk.NewErrorFrom(sdk.ErrWorkerVersionNotSupported, "worker version %s is older than the min version %s and it cannot be updated: %v", sdk.VERSION, v.MinVersion, err)
+		}
+		log.Warning(ctx, "update> unable to update worker binary: %v", err)
+		return nil
+	}
+
+	return restart()
+}
+
+func (w *CurrentWorker) updateBinary(ctx context.Context) error {
+	pk, err := updatePublicKey()
+	if err != nil {
+		return err
+	}
+
+	infos, err := w.client.DownloadInfos("worker", sdk.GOOS, sdk.GOARCH, "")
+	if err != nil {
+		return sdk.WrapError(err, "unable to get worker binary infos")
+	}
+
+	checksum, err := hex.DecodeString(infos.SHA256)
+	if err != nil || len(checksum) == 0 {
+		return sdk.WithStack(fmt.Errorf("invalid checksum %q for binary %s", infos.SHA256, infos.Filename))
+	}
+
+	if len(infos.Signature) == 0 {
+		return sdk.WithStack(fmt.Errorf("missing signature for binary %s", infos.Filename))
+	}
+	opts := goUpdate.Options{
+		Checksum:  checksum,
+		PublicKey: pk,
+		Signature: infos.Signature,
+		Verifier:  goUpdate.NewRSAVerifier(),
+	}
+
+	body, err := w.client.DownloadBinary(ctx, "worker", sdk.GOOS, sdk.GOARCH, "")
+	if err != nil {
+		return err
+	}
+	defer body.Close() // nolint
+
+	if err := goUpdate.Apply(body, opts); err != nil {
+		if rerr := goUpdate.RollbackError(err); rerr != nil {
+			log.Error(ctx, "update> unable to rollback worker binary: %v", rerr)
+		}
+		return sdk.WrapError(err, "unable to apply worker binary")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/jws"
+)
+
+func Test_workerUpdateVersion(t *testing.T) {
+	v, belowMin := workerUpdateVersion("0.46.0", sdk.WorkerVersion{})
+	assert.Equal(t, "", v)
+	assert.False(t, belowMin)
+
+	v, belowMin = workerUpdateVersion("0.46.0", sdk.WorkerVersion{MinVersion: "0.45.0", RecommendedVersion: "0.47.0"})
+	assert.Equal(t, "0.47.0", v)
+	assert.False(t, belowMin)
+
+	v, belowMin = workerUpdateVersion("0.44.0", sdk.WorkerVersion{MinVersion: "0.45.0", RecommendedVersion: "0.47.0"})
+	assert.Equal(t, "0.47.0", v)
+	assert.True(t, belowMin)
+
+	v, belowMin = workerUpdateVersion("0.44.0", sdk.WorkerVersion{MinVersion: "0.45.0"})
+	assert.Equal(t, "0.45.0", v)
+	assert.True(t, belowMin)
+
+	v, belowMin = workerUpdateVersion("snapshot", sdk.WorkerVersion{MinVersion: "0.45.0", RecommendedVersion: "0.47.0"})
+	assert.Equal(t, "", v)
+	assert.False(t, belowMin)
+}
+
+func Test_updatePublicKey(t *testing.T) {
+	defer func(k string) { UpdatePublicKey = k }(UpdatePublicKey)
+
+	// The worker refuses to update without a pinned key
+	UpdatePublicKey = ""
+	_, err := updatePublicKey()
+	assert.Error(t, err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pem, err := jws.ExportPublicKey(key)
+	require.NoError(t, err)
+
+	UpdatePublicKey = string(pem)
+	pk, err := updatePublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey, *pk)
+
+	UpdatePublicKey = base64.StdEncoding.EncodeToString(pem)
+	pk, err = updatePublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey, *pk)
+
+	UpdatePublicKey = "invalid"
+	_, err = updatePublicKey()
+	assert.Error(t, err)
+}
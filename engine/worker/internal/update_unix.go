@@ -0,0 +1,20 @@
+// +build !windows
+
+package internal
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// restart replaces the current process with the updated worker binary, with the same arguments.
+func restart() error {
+	bin, err := os.Executable()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	env := append(os.Environ(), envWorkerUpdated+"=true")
+	return sdk.WithStack(syscall.Exec(bin, os.Args, env))
+}
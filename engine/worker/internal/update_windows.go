@@ -0,0 +1,15 @@
+// +build windows
+
+package internal
+
+import (
+	"context"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+// restart can't replace the current process on windows, the updated binary is used by the next worker.
+func restart() error {
+	log.Warning(context.Background(), "update> worker binary updated, it will be used by the next worker")
+	return nil
+}
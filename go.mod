@@ -212,7 +212,7 @@ require (
 	k8s.io/klog v0.2.0 // indirect
 	labix.org/v2/mgo v0.0.0-20140701140051-000000000287 // indirect
 	launchpad.net/gocheck v0.0.0-20140225173054-000000000087 // indirect
-	sigs.k8s.io/yaml v1.1.0 // indirect
+	sigs.k8s.io/yaml v1.1.0
 )
 
 replace github.com/alecthomas/jsonschema => github.com/sguiheux/jsonschema v0.2.0
@@ -50,6 +50,7 @@ type Action struct {
 	StepName       string `json:"step_name,omitempty" yaml:"step_name,omitempty" db:"-"`
 	Optional       bool   `json:"optional" yaml:"-" db:"-"`
 	AlwaysExecuted bool   `json:"always_executed" yaml:"-" db:"-"`
+	Timeout        int64  `json:"timeout,omitempty" yaml:"-" db:"-"` // in seconds
 	// aggregates
 	Requirements RequirementList `json:"requirements" db:"-"`
 	Parameters   []Parameter     `json:"parameters" db:"-"`
@@ -140,6 +141,9 @@ func (a Action) IsValid() error {
 		if a.Actions[i].ID == 0 {
 			return NewErrorFrom(ErrWrongRequest, "invalid action id for child")
 		}
+		if a.Actions[i].Timeout < 0 {
+			return NewErrorFrom(ErrWrongRequest, "step timeout should be positive")
+		}
 		for j := range a.Actions[i].Parameters {
 			if err := a.Actions[i].Parameters[j].IsValid(); err != nil {
 				return err
@@ -27,6 +27,26 @@ type ExecutedJob struct {
 	Reason     string       `json:"reason" db:"-"`
 	WorkerName string       `json:"worker_name" db:"-"`
 	WorkerID   string       `json:"worker_id" db:"-"`
+	// MatrixValues contains the values of the matrix variables for a job run expanded from a matrix job
+	MatrixValues map[string]string `json:"matrix_values,omitempty" db:"-"`
+	// Attempts contains the previous attempts of the job run when it was retried
+	Attempts []ExecutedJobAttempt `json:"attempts,omitempty" db:"-"`
+}
+
+// ExecutedJobAttempt represents a previous attempt of a retried job run.
+// Logs of the attempt are stored with its job run id.
+type ExecutedJobAttempt struct {
+	Attempt      int          `json:"attempt"`
+	JobRunID     int64        `json:"job_run_id"`
+	Status       string       `json:"status"`
+	FailureKind  string       `json:"failure_kind"`
+	Start        time.Time    `json:"start,omitempty"`
+	Done         time.Time    `json:"done,omitempty"`
+	Model        string       `json:"model,omitempty"`
+	WorkerName   string       `json:"worker_name,omitempty"`
+	HatcheryName string       `json:"hatchery_name,omitempty"`
+	StepStatus   []StepStatus `json:"step_status,omitempty"`
+	SpawnInfos   []SpawnInfo  `json:"spawninfos,omitempty"`
 }
 
 // ExecutedJobSummary is a light representation of ExecutedJob for CDS event
@@ -110,6 +130,7 @@ const (
 	StatusUnknown           = "Unknown"
 	StatusSkipped           = "Skipped"
 	StatusStopped           = "Stopped"
+	StatusTimeout           = "Timeout"
 	StatusWorkerPending     = "Pending"
 	StatusWorkerRegistering = "Registering"
 )
@@ -2,12 +2,14 @@ package sdk
 
 import (
 	"archive/tar"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 )
@@ -39,6 +41,27 @@ func (c *Cache) GetPath() string {
 	return container
 }
 
+// CacheEntry is the metadata of a cache stored in a storage integration, the key is the decoded value of the tag.
+type CacheEntry struct {
+	ID              int64     `json:"id" db:"id" cli:"-"`
+	ProjectID       int64     `json:"project_id" db:"project_id" cli:"-"`
+	IntegrationName string    `json:"integration_name" db:"integration_name" cli:"integration"`
+	Tag             string    `json:"tag" db:"tag" cli:"-"`
+	Key             string    `json:"key" db:"cache_key" cli:"key,key"`
+	Size            int64     `json:"size" db:"size" cli:"size"`
+	Created         time.Time `json:"created" db:"created" cli:"created"`
+	LastAccess      time.Time `json:"last_access" db:"last_access" cli:"last_access"`
+}
+
+// CacheKeyFromTag returns the key of a cache from its tag that is encoded in base64 by workers.
+func CacheKeyFromTag(tag string) string {
+	b, err := base64.RawURLEncoding.DecodeString(tag)
+	if err != nil {
+		return tag
+	}
+	return string(b)
+}
+
 // TarOptions useful to indicate some options when we want to tar directory or files
 type TarOptions struct {
 	TrimDirName string
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -23,6 +24,33 @@ func (c *client) DownloadURLFromAPI(name, os, arch, variant string) string {
 	return fmt.Sprintf("%s/download/%s/%s/%s?variant=%s", c.APIURL(), name, os, arch, variant)
 }
 
+func (c *client) DownloadInfos(name, os, arch, variant string) (*sdk.DownloadableResource, error) {
+	var res sdk.DownloadableResource
+	path := fmt.Sprintf("/download/%s/%s/%s/infos?variant=%s", name, os, arch, variant)
+	if _, err := c.GetJSON(context.Background(), path, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DownloadBinary returns the content of a binary downloaded from the API, the caller has to close it.
+func (c *client) DownloadBinary(ctx context.Context, name, os, arch, variant string) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/download/%s/%s/%s?variant=%s", name, os, arch, variant)
+	body, header, code, err := c.Stream(ctx, http.MethodGet, path, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		body.Close() // nolint
+		return nil, fmt.Errorf("cannot download %s: HTTP %d", path, code)
+	}
+	if contentType := header.Get("Content-Type"); contentType != "application/octet-stream" {
+		body.Close() // nolint
+		return nil, fmt.Errorf("invalid binary %s (Content-Type: %s)", path, contentType)
+	}
+	return body, nil
+}
+
 func (c *client) DownloadURLFromGithub(filename string) (string, error) {
 	var httpClient = &http.Client{Timeout: 10 * time.Second}
 
@@ -1,7 +1,9 @@
 package cdsclient
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -25,3 +27,30 @@ func (c *client) PollVCSEvents(uuid string, workflowID int64, vcsServer string,
 
 	return events, interval, nil
 }
+
+func (c *client) HookChangedFiles(uuid, branch, base, head string) ([]string, error) {
+	var files []string
+	path := fmt.Sprintf("/hook/%s/changes?branch=%s&base=%s&head=%s", uuid, url.QueryEscape(branch), url.QueryEscape(base), url.QueryEscape(head))
+	if _, err := c.GetJSON(context.Background(), path, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (c *client) HookPullRequests(uuid, base string) ([]sdk.VCSPullRequest, error) {
+	var prs []sdk.VCSPullRequest
+	path := fmt.Sprintf("/hook/%s/pullrequests?base=%s", uuid, url.QueryEscape(base))
+	if _, err := c.GetJSON(context.Background(), path, &prs); err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+func (c *client) HookMergeQueueAdd(uuid string, pullRequestID int) error {
+	path := fmt.Sprintf("/hook/%s/mergequeue", uuid)
+	e := sdk.MergeQueueEntry{PullRequestID: pullRequestID}
+	if _, err := c.PostJSON(context.Background(), path, e, nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -239,6 +239,13 @@ func (c *client) QueueJobBook(ctx context.Context, id int64) error {
 	return err
 }
 
+// QueueJobAssign books a job for a Hatchery and assigns it to one of its idle workers
+func (c *client) QueueJobAssign(ctx context.Context, id int64, workerID string) error {
+	path := fmt.Sprintf("/queue/workflows/%d/assign/%s", id, workerID)
+	_, err := c.PostJSON(ctx, path, nil, nil)
+	return err
+}
+
 // QueueJobRelease release a job for a worker
 func (c *client) QueueJobRelease(ctx context.Context, id int64) error {
 	path := fmt.Sprintf("/queue/workflows/%d/book", id)
@@ -0,0 +1,39 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) AdminQuotaList() ([]sdk.Quota, error) {
+	var qs []sdk.Quota
+	if _, err := c.GetJSON(context.Background(), "/admin/quota", &qs); err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (c *client) AdminQuotaCreate(q *sdk.Quota) error {
+	_, err := c.PostJSON(context.Background(), "/admin/quota", q, q)
+	return err
+}
+
+func (c *client) AdminQuotaUpdate(q *sdk.Quota) error {
+	_, err := c.PutJSON(context.Background(), fmt.Sprintf("/admin/quota/%d", q.ID), q, q)
+	return err
+}
+
+func (c *client) AdminQuotaDelete(id int64) error {
+	_, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/admin/quota/%d", id), nil)
+	return err
+}
+
+func (c *client) ProjectQuotaUsage(projectKey string) ([]sdk.QuotaUsage, error) {
+	var us []sdk.QuotaUsage
+	if _, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/quota", &us); err != nil {
+		return nil, err
+	}
+	return us, nil
+}
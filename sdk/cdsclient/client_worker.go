@@ -97,3 +97,13 @@ func (c *client) WorkerSetStatus(ctx context.Context, status string) error {
 
 	return nil
 }
+
+func (c *client) WorkerVersion(ctx context.Context) (*sdk.WorkerVersion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var v sdk.WorkerVersion
+	if _, err := c.GetJSON(ctx, "/worker/version", &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
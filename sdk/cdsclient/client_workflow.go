@@ -10,6 +10,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -195,6 +196,67 @@ func (c *client) WorkflowNodeRunJobStep(projectKey string, workflowName string,
 	return &buildState, nil
 }
 
+// WorkflowNodeRunJobStepLogStream sends to given channel the parts of a step log after given offset as soon as they are received by the API.
+// It returns when the step is terminated, after sending a chunk with the Done flag.
+func (c *client) WorkflowNodeRunJobStepLogStream(ctx context.Context, projectKey string, workflowName string, number int64, nodeRunID, job int64, step int, offset int64, chunks chan<- sdk.StepLogChunk) error {
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/job/%d/step/%d/stream", projectKey, workflowName, number, nodeRunID, job, step)
+	con, err := c.dialWebsocket(path, url.Values{"offset": []string{strconv.FormatInt(offset, 10)}})
+	if err != nil {
+		return err
+	}
+	defer con.Close() // nolint
+
+	// Close the connection to stop reading when the context is done
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			con.Close() // nolint
+		case <-done:
+		}
+	}()
+
+	for {
+		var chunk sdk.StepLogChunk
+		if err := con.ReadJSON(&chunk); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return sdk.WithStack(err)
+		}
+		chunks <- chunk
+		if chunk.Done {
+			return nil
+		}
+	}
+}
+
+// WorkflowLogsSearch returns the lines of step logs matching a regexp, in all readable workflows of the project if workflowName is empty.
+// Zero dates and limit are not sent, the API uses the last 24 hours and its default limit.
+func (c *client) WorkflowLogsSearch(projectKey, workflowName, regex string, from, to time.Time, limit int) ([]sdk.WorkflowLogSearchResult, error) {
+	path := fmt.Sprintf("/project/%s/logs/search", projectKey)
+	if workflowName != "" {
+		path = fmt.Sprintf("/project/%s/workflows/%s/logs/search", projectKey, workflowName)
+	}
+	q := url.Values{}
+	q.Set("regex", regex)
+	if !from.IsZero() {
+		q.Set("from", from.Format(time.RFC3339))
+	}
+	if !to.IsZero() {
+		q.Set("to", to.Format(time.RFC3339))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var results []sdk.WorkflowLogSearchResult
+	if _, err := c.GetJSON(context.Background(), path+"?"+q.Encode(), &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, workflowName string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error {
 	var url = fmt.Sprintf("/project/%s/workflows/%s/artifact/%d", projectKey, workflowName, a.ID)
 	var reader io.ReadCloser
@@ -283,6 +345,22 @@ func (c *client) WorkflowRunFromManual(projectKey string, workflowName string, m
 	return run, nil
 }
 
+func (c *client) WorkflowRunPlan(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRunPlan, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/plan", projectKey, workflowName)
+	content := sdk.WorkflowRunPostHandlerOption{Manual: &manual}
+	if number > 0 {
+		content.Number = &number
+	}
+	if fromNodeID > 0 {
+		content.FromNodeIDs = []int64{fromNodeID}
+	}
+	var plan sdk.WorkflowRunPlan
+	if _, err := c.PostJSON(context.Background(), url, &content, &plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
 func (c *client) WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/stop", projectKey, workflowName, number)
 
@@ -355,7 +433,22 @@ func (c *client) workflowCachePushIndirectUpload(projectKey, integrationName, re
 		return fmt.Errorf("HTTP Code %d", code)
 	}
 
-	return c.workflowCachePushIndirectUploadPost(cacheObj.TmpURL, tarContent, size)
+	if err := c.workflowCachePushIndirectUploadPost(cacheObj.TmpURL, tarContent, size); err != nil {
+		return err
+	}
+
+	// The cache is recorded by the API once it checked that the content is in the storage
+	var callbackErr error
+	for i := 0; i < 10; i++ {
+		uri := fmt.Sprintf("/project/%s/storage/%s/cache/%s/url/callback", projectKey, integrationName, ref)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, callbackErr = c.PostJSON(ctx, uri, nil, nil)
+		cancel()
+		if callbackErr == nil {
+			return nil
+		}
+	}
+	return callbackErr
 }
 
 func (c *client) workflowCachePushIndirectUploadPost(url string, tarContent io.Reader, size int) error {
@@ -399,6 +492,21 @@ func (c *client) workflowCachePushIndirectUploadPost(url string, tarContent io.R
 	return globalErr
 }
 
+// WorkflowCacheSearch returns the cache matching given key, or else the last pushed cache with a key starting with one of the restore keys.
+func (c *client) WorkflowCacheSearch(projectKey, integrationName, key string, restoreKeys []string) (*sdk.CacheEntry, error) {
+	q := url.Values{}
+	q.Set("key", key)
+	for _, k := range restoreKeys {
+		q.Add("restoreKey", k)
+	}
+	uri := fmt.Sprintf("/project/%s/storage/%s/cache?%s", projectKey, integrationName, q.Encode())
+	var e sdk.CacheEntry
+	if _, err := c.GetJSON(context.Background(), uri, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
 func (c *client) WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error) {
 	uri := fmt.Sprintf("/project/%s/storage/%s", projectKey, integrationName)
 	store := new(sdk.ArtifactsStore)
@@ -16,44 +16,18 @@ import (
 )
 
 func (c *client) RequestWebsocket(ctx context.Context, path string, msgToSend <-chan sdk.WebsocketFilter, msgReceived chan<- sdk.WebsocketEvent) error {
-	wsContext, wsContextCancel := context.WithCancel(ctx)
-
-	// Checks that current session_token is still valid
-	// If not, challenge a new one against the authenticationToken
-	if !c.config.HasValidSessionToken() && c.config.BuitinConsumerAuthenticationToken != "" {
-		resp, err := c.AuthConsumerSignin(sdk.ConsumerBuiltin, sdk.AuthConsumerSigninRequest{"token": c.config.BuitinConsumerAuthenticationToken})
-		if err != nil {
-			return err
-		}
-		c.config.SessionToken = resp.Token
+	con, err := c.dialWebsocket(path, nil)
+	if err != nil {
+		return err
 	}
+	defer con.Close() // nolint
+
+	wsContext, wsContextCancel := context.WithCancel(ctx)
 
 	labels := pprof.Labels("path", path, "method", "GET")
 	wsContext = pprof.WithLabels(wsContext, labels)
 	pprof.SetGoroutineLabels(wsContext)
 
-	uHost, err := url.Parse(c.config.Host)
-	if err != nil {
-		return sdk.WrapError(err, "wrong Host configuration")
-	}
-	urlWebsocket := url.URL{
-		Scheme: strings.Replace(uHost.Scheme, "http", "ws", -1),
-		Host:   uHost.Host,
-		Path:   "/ws",
-	}
-
-	headers := make(map[string][]string)
-	date := sdk.FormatDateRFC5322(time.Now())
-	headers["Date"] = []string{date}
-	headers["X-CDS-RemoteTime"] = []string{date}
-	auth := "Bearer " + c.config.SessionToken
-	headers["Authorization"] = []string{auth}
-	con, _, err := c.httpWebsocketClient.Dial(urlWebsocket.String(), headers)
-	if err != nil {
-		return sdk.WithStack(err)
-	}
-	defer con.Close() // nolint
-
 	// Message to send
 	sdk.GoRoutine(wsContext, fmt.Sprintf("RequestWebsocket-%s-%s", c.config.User, sdk.UUID()), func(ctx context.Context) {
 		for {
@@ -91,3 +65,39 @@ func (c *client) RequestWebsocket(ctx context.Context, path string, msgToSend <-
 		msgReceived <- wsEvent
 	}
 }
+
+// dialWebsocket opens a websocket connection on given API path.
+func (c *client) dialWebsocket(path string, query url.Values) (*websocket.Conn, error) {
+	// Checks that current session_token is still valid
+	// If not, challenge a new one against the authenticationToken
+	if !c.config.HasValidSessionToken() && c.config.BuitinConsumerAuthenticationToken != "" {
+		resp, err := c.AuthConsumerSignin(sdk.ConsumerBuiltin, sdk.AuthConsumerSigninRequest{"token": c.config.BuitinConsumerAuthenticationToken})
+		if err != nil {
+			return nil, err
+		}
+		c.config.SessionToken = resp.Token
+	}
+
+	uHost, err := url.Parse(c.config.Host)
+	if err != nil {
+		return nil, sdk.WrapError(err, "wrong Host configuration")
+	}
+	urlWebsocket := url.URL{
+		Scheme:   strings.Replace(uHost.Scheme, "http", "ws", -1),
+		Host:     uHost.Host,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+
+	headers := make(map[string][]string)
+	date := sdk.FormatDateRFC5322(time.Now())
+	headers["Date"] = []string{date}
+	headers["X-CDS-RemoteTime"] = []string{date}
+	auth := "Bearer " + c.config.SessionToken
+	headers["Authorization"] = []string{auth}
+	con, _, err := c.httpWebsocketClient.Dial(urlWebsocket.String(), headers)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return con, nil
+}
@@ -47,6 +47,10 @@ type Admin interface {
 	AdminCDSMigrationList() ([]sdk.Migration, error)
 	AdminCDSMigrationCancel(id int64) error
 	AdminCDSMigrationReset(id int64) error
+	AdminQuotaList() ([]sdk.Quota, error)
+	AdminQuotaCreate(q *sdk.Quota) error
+	AdminQuotaUpdate(q *sdk.Quota) error
+	AdminQuotaDelete(id int64) error
 	Services() ([]sdk.Service, error)
 	ServicesByName(name string) (*sdk.Service, error)
 	ServiceDelete(name string) error
@@ -153,6 +157,8 @@ type DownloadClient interface {
 	Download() ([]sdk.DownloadableResource, error)
 	DownloadURLFromAPI(name, os, arch, variant string) string
 	DownloadURLFromGithub(filename string) (string, error)
+	DownloadInfos(name, os, arch, variant string) (*sdk.DownloadableResource, error)
+	DownloadBinary(ctx context.Context, name, os, arch, variant string) (io.ReadCloser, error)
 }
 
 // ActionClient exposes actions related functions
@@ -218,6 +224,7 @@ type ProjectClient interface {
 	ProjectIntegrationDelete(projectKey string, integrationName string) error
 	ProjectRepositoryManagerList(projectKey string) ([]sdk.ProjectVCSServer, error)
 	ProjectRepositoryManagerDelete(projectKey string, repoManagerName string, force bool) error
+	ProjectQuotaUsage(projectKey string) ([]sdk.QuotaUsage, error)
 }
 
 // ProjectKeysClient exposes project keys related functions
@@ -244,6 +251,7 @@ type QueueClient interface {
 	QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJobRun, errs chan<- error, delay time.Duration, modelType string, ratioService *int) error
 	QueueTakeJob(ctx context.Context, job sdk.WorkflowNodeJobRun) (*sdk.WorkflowNodeJobRunData, error)
 	QueueJobBook(ctx context.Context, id int64) error
+	QueueJobAssign(ctx context.Context, id int64, workerID string) error
 	QueueJobRelease(ctx context.Context, id int64) error
 	QueueJobInfo(ctx context.Context, id int64) (*sdk.WorkflowNodeJobRun, error)
 	QueueJobSendSpawnInfo(ctx context.Context, id int64, in []sdk.SpawnInfo) error
@@ -284,10 +292,14 @@ type WorkerClient interface {
 	WorkerModelsEnabled() ([]sdk.Model, error)
 	WorkerRegister(ctx context.Context, authToken string, form sdk.WorkerRegistrationForm) (*sdk.Worker, bool, error)
 	WorkerSetStatus(ctx context.Context, status string) error
+	WorkerVersion(ctx context.Context) (*sdk.WorkerVersion, error)
 }
 
 // HookClient exposes functions used for hooks services
 type HookClient interface {
+	HookChangedFiles(uuid, branch, base, head string) ([]string, error)
+	HookMergeQueueAdd(uuid string, pullRequestID int) error
+	HookPullRequests(uuid, base string) ([]sdk.VCSPullRequest, error)
 	PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (events sdk.RepositoryEvents, interval time.Duration, err error)
 	VCSConfiguration() (map[string]sdk.VCSConfiguration, error)
 }
@@ -310,6 +322,7 @@ type WorkflowClient interface {
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error)
 	WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
 	WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error)
+	WorkflowRunPlan(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRunPlan, error)
 	WorkflowRunNumberGet(projectKey string, workflowName string) (*sdk.WorkflowRunNumber, error)
 	WorkflowRunNumberSet(projectKey string, workflowName string, number int64) error
 	WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
@@ -317,10 +330,13 @@ type WorkflowClient interface {
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
 	WorkflowNodeRunJobStep(projectKey string, workflowName string, number int64, nodeRunID, job int64, step int) (*sdk.BuildState, error)
+	WorkflowLogsSearch(projectKey, workflowName, regex string, from, to time.Time, limit int) ([]sdk.WorkflowLogSearchResult, error)
+	WorkflowNodeRunJobStepLogStream(ctx context.Context, projectKey string, workflowName string, number int64, nodeRunID, job int64, step int, offset int64, chunks chan<- sdk.StepLogChunk) error
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
 	WorkflowAllHooksList() ([]sdk.NodeHook, error)
 	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error
 	WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error)
+	WorkflowCacheSearch(projectKey, integrationName, key string, restoreKeys []string) (*sdk.CacheEntry, error)
 	WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error)
 	WorkflowTransformAsCode(projectKey, workflowName string) (*sdk.Operation, error)
 	WorkflowTransformAsCodeFollow(projectKey, workflowName string, ope *sdk.Operation) error
@@ -380,6 +396,7 @@ type Interface interface {
 }
 
 type WorkerInterface interface {
+	DownloadClient
 	GRPCPluginsClient
 	ProjectIntegrationGet(projectKey string, integrationName string, clearPassword bool) (sdk.ProjectIntegration, error)
 	QueueClient
@@ -388,6 +405,7 @@ type WorkerInterface interface {
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error)
 	WorkflowCachePush(projectKey, integrationName, ref string, tarContent io.Reader, size int) error
 	WorkflowCachePull(projectKey, integrationName, ref string) (io.Reader, error)
+	WorkflowCacheSearch(projectKey, integrationName, key string, restoreKeys []string) (*sdk.CacheEntry, error)
 	WorkflowRunSearch(projectKey string, offset, limit int64, filter ...Filter) ([]sdk.WorkflowRun, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
 	WorkflowNodeRunRelease(projectKey string, workflowName string, runNumber int64, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error
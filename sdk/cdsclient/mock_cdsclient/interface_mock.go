@@ -386,6 +386,63 @@ func (mr *MockAdminMockRecorder) AdminCDSMigrationReset(id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminCDSMigrationReset", reflect.TypeOf((*MockAdmin)(nil).AdminCDSMigrationReset), id)
 }
 
+// AdminQuotaList mocks base method
+func (m *MockAdmin) AdminQuotaList() ([]sdk.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminQuotaList")
+	ret0, _ := ret[0].([]sdk.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminQuotaList indicates an expected call of AdminQuotaList
+func (mr *MockAdminMockRecorder) AdminQuotaList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminQuotaList", reflect.TypeOf((*MockAdmin)(nil).AdminQuotaList))
+}
+
+// AdminQuotaCreate mocks base method
+func (m *MockAdmin) AdminQuotaCreate(q *sdk.Quota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminQuotaCreate", q)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminQuotaCreate indicates an expected call of AdminQuotaCreate
+func (mr *MockAdminMockRecorder) AdminQuotaCreate(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminQuotaCreate", reflect.TypeOf((*MockAdmin)(nil).AdminQuotaCreate), q)
+}
+
+// AdminQuotaUpdate mocks base method
+func (m *MockAdmin) AdminQuotaUpdate(q *sdk.Quota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminQuotaUpdate", q)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminQuotaUpdate indicates an expected call of AdminQuotaUpdate
+func (mr *MockAdminMockRecorder) AdminQuotaUpdate(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminQuotaUpdate", reflect.TypeOf((*MockAdmin)(nil).AdminQuotaUpdate), q)
+}
+
+// AdminQuotaDelete mocks base method
+func (m *MockAdmin) AdminQuotaDelete(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminQuotaDelete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminQuotaDelete indicates an expected call of AdminQuotaDelete
+func (mr *MockAdminMockRecorder) AdminQuotaDelete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminQuotaDelete", reflect.TypeOf((*MockAdmin)(nil).AdminQuotaDelete), id)
+}
+
 // Services mocks base method
 func (m *MockAdmin) Services() ([]sdk.Service, error) {
 	m.ctrl.T.Helper()
@@ -1800,6 +1857,36 @@ func (mr *MockDownloadClientMockRecorder) DownloadURLFromGithub(filename interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadURLFromGithub", reflect.TypeOf((*MockDownloadClient)(nil).DownloadURLFromGithub), filename)
 }
 
+// DownloadInfos mocks base method
+func (m *MockDownloadClient) DownloadInfos(name, os, arch, variant string) (*sdk.DownloadableResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadInfos", name, os, arch, variant)
+	ret0, _ := ret[0].(*sdk.DownloadableResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadInfos indicates an expected call of DownloadInfos
+func (mr *MockDownloadClientMockRecorder) DownloadInfos(name, os, arch, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadInfos", reflect.TypeOf((*MockDownloadClient)(nil).DownloadInfos), name, os, arch, variant)
+}
+
+// DownloadBinary mocks base method
+func (m *MockDownloadClient) DownloadBinary(ctx context.Context, name, os, arch, variant string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadBinary", ctx, name, os, arch, variant)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadBinary indicates an expected call of DownloadBinary
+func (mr *MockDownloadClientMockRecorder) DownloadBinary(ctx, name, os, arch, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBinary", reflect.TypeOf((*MockDownloadClient)(nil).DownloadBinary), ctx, name, os, arch, variant)
+}
+
 // MockActionClient is a mock of ActionClient interface
 type MockActionClient struct {
 	ctrl     *gomock.Controller
@@ -2690,6 +2777,21 @@ func (mr *MockProjectClientMockRecorder) ProjectRepositoryManagerDelete(projectK
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectRepositoryManagerDelete", reflect.TypeOf((*MockProjectClient)(nil).ProjectRepositoryManagerDelete), projectKey, repoManagerName, force)
 }
 
+// ProjectQuotaUsage mocks base method
+func (m *MockProjectClient) ProjectQuotaUsage(projectKey string) ([]sdk.QuotaUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectQuotaUsage", projectKey)
+	ret0, _ := ret[0].([]sdk.QuotaUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectQuotaUsage indicates an expected call of ProjectQuotaUsage
+func (mr *MockProjectClientMockRecorder) ProjectQuotaUsage(projectKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectQuotaUsage", reflect.TypeOf((*MockProjectClient)(nil).ProjectQuotaUsage), projectKey)
+}
+
 // MockProjectKeysClient is a mock of ProjectKeysClient interface
 type MockProjectKeysClient struct {
 	ctrl     *gomock.Controller
@@ -2966,6 +3068,20 @@ func (mr *MockQueueClientMockRecorder) QueueJobBook(ctx, id interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobBook", reflect.TypeOf((*MockQueueClient)(nil).QueueJobBook), ctx, id)
 }
 
+// QueueJobAssign mocks base method
+func (m *MockQueueClient) QueueJobAssign(ctx context.Context, id int64, workerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobAssign", ctx, id, workerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueJobAssign indicates an expected call of QueueJobAssign
+func (mr *MockQueueClientMockRecorder) QueueJobAssign(ctx, id, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobAssign", reflect.TypeOf((*MockQueueClient)(nil).QueueJobAssign), ctx, id, workerID)
+}
+
 // QueueJobRelease mocks base method
 func (m *MockQueueClient) QueueJobRelease(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
@@ -3479,6 +3595,21 @@ func (mr *MockWorkerClientMockRecorder) WorkerSetStatus(ctx, status interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkerSetStatus", reflect.TypeOf((*MockWorkerClient)(nil).WorkerSetStatus), ctx, status)
 }
 
+// WorkerVersion mocks base method
+func (m *MockWorkerClient) WorkerVersion(ctx context.Context) (*sdk.WorkerVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkerVersion", ctx)
+	ret0, _ := ret[0].(*sdk.WorkerVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkerVersion indicates an expected call of WorkerVersion
+func (mr *MockWorkerClientMockRecorder) WorkerVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkerVersion", reflect.TypeOf((*MockWorkerClient)(nil).WorkerVersion), ctx)
+}
+
 // MockHookClient is a mock of HookClient interface
 type MockHookClient struct {
 	ctrl     *gomock.Controller
@@ -3502,6 +3633,50 @@ func (m *MockHookClient) EXPECT() *MockHookClientMockRecorder {
 	return m.recorder
 }
 
+// HookChangedFiles mocks base method
+func (m *MockHookClient) HookChangedFiles(uuid, branch, base, head string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookChangedFiles", uuid, branch, base, head)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookChangedFiles indicates an expected call of HookChangedFiles
+func (mr *MockHookClientMockRecorder) HookChangedFiles(uuid, branch, base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookChangedFiles", reflect.TypeOf((*MockHookClient)(nil).HookChangedFiles), uuid, branch, base, head)
+}
+
+// HookMergeQueueAdd mocks base method
+func (m *MockHookClient) HookMergeQueueAdd(uuid string, pullRequestID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookMergeQueueAdd", uuid, pullRequestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HookMergeQueueAdd indicates an expected call of HookMergeQueueAdd
+func (mr *MockHookClientMockRecorder) HookMergeQueueAdd(uuid, pullRequestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookMergeQueueAdd", reflect.TypeOf((*MockHookClient)(nil).HookMergeQueueAdd), uuid, pullRequestID)
+}
+
+// HookPullRequests mocks base method
+func (m *MockHookClient) HookPullRequests(uuid, base string) ([]sdk.VCSPullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookPullRequests", uuid, base)
+	ret0, _ := ret[0].([]sdk.VCSPullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookPullRequests indicates an expected call of HookPullRequests
+func (mr *MockHookClientMockRecorder) HookPullRequests(uuid, base interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookPullRequests", reflect.TypeOf((*MockHookClient)(nil).HookPullRequests), uuid, base)
+}
+
 // PollVCSEvents mocks base method
 func (m *MockHookClient) PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (sdk.RepositoryEvents, time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -3804,6 +3979,21 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowRunFromManual(projectKey, work
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunFromManual", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowRunFromManual), projectKey, workflowName, manual, number, fromNodeID)
 }
 
+// WorkflowRunPlan mocks base method
+func (m *MockWorkflowClient) WorkflowRunPlan(projectKey, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRunPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowRunPlan", projectKey, workflowName, manual, number, fromNodeID)
+	ret0, _ := ret[0].(*sdk.WorkflowRunPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowRunPlan indicates an expected call of WorkflowRunPlan
+func (mr *MockWorkflowClientMockRecorder) WorkflowRunPlan(projectKey, workflowName, manual, number, fromNodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunPlan", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowRunPlan), projectKey, workflowName, manual, number, fromNodeID)
+}
+
 // WorkflowRunNumberGet mocks base method
 func (m *MockWorkflowClient) WorkflowRunNumberGet(projectKey, workflowName string) (*sdk.WorkflowRunNumber, error) {
 	m.ctrl.T.Helper()
@@ -3907,6 +4097,35 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunJobStep(projectKey, wor
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunJobStep", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunJobStep), projectKey, workflowName, number, nodeRunID, job, step)
 }
 
+// WorkflowLogsSearch mocks base method
+func (m *MockWorkflowClient) WorkflowLogsSearch(projectKey, workflowName, regex string, from, to time.Time, limit int) ([]sdk.WorkflowLogSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowLogsSearch", projectKey, workflowName, regex, from, to, limit)
+	ret0, _ := ret[0].([]sdk.WorkflowLogSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowLogsSearch indicates an expected call of WorkflowLogsSearch
+func (mr *MockWorkflowClientMockRecorder) WorkflowLogsSearch(projectKey, workflowName, regex, from, to, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowLogsSearch", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowLogsSearch), projectKey, workflowName, regex, from, to, limit)
+}
+
+// WorkflowNodeRunJobStepLogStream mocks base method
+func (m *MockWorkflowClient) WorkflowNodeRunJobStepLogStream(ctx context.Context, projectKey, workflowName string, number, nodeRunID, job int64, step int, offset int64, chunks chan<- sdk.StepLogChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunJobStepLogStream", ctx, projectKey, workflowName, number, nodeRunID, job, step, offset, chunks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WorkflowNodeRunJobStepLogStream indicates an expected call of WorkflowNodeRunJobStepLogStream
+func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunJobStepLogStream(ctx, projectKey, workflowName, number, nodeRunID, job, step, offset, chunks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunJobStepLogStream", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunJobStepLogStream), ctx, projectKey, workflowName, number, nodeRunID, job, step, offset, chunks)
+}
+
 // WorkflowNodeRunRelease mocks base method
 func (m *MockWorkflowClient) WorkflowNodeRunRelease(projectKey, workflowName string, runNumber, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error {
 	m.ctrl.T.Helper()
@@ -3965,6 +4184,21 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowCachePull(projectKey, integrat
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheSearch mocks base method
+func (m *MockWorkflowClient) WorkflowCacheSearch(projectKey, integrationName, key string, restoreKeys []string) (*sdk.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheSearch", projectKey, integrationName, key, restoreKeys)
+	ret0, _ := ret[0].(*sdk.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheSearch indicates an expected call of WorkflowCacheSearch
+func (mr *MockWorkflowClientMockRecorder) WorkflowCacheSearch(projectKey, integrationName, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheSearch", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowCacheSearch), projectKey, integrationName, key, restoreKeys)
+}
+
 // WorkflowTemplateInstanceGet mocks base method
 func (m *MockWorkflowClient) WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error) {
 	m.ctrl.T.Helper()
@@ -4896,6 +5130,63 @@ func (mr *MockInterfaceMockRecorder) AdminCDSMigrationReset(id interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminCDSMigrationReset", reflect.TypeOf((*MockInterface)(nil).AdminCDSMigrationReset), id)
 }
 
+// AdminQuotaList mocks base method
+func (m *MockInterface) AdminQuotaList() ([]sdk.Quota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminQuotaList")
+	ret0, _ := ret[0].([]sdk.Quota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminQuotaList indicates an expected call of AdminQuotaList
+func (mr *MockInterfaceMockRecorder) AdminQuotaList() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminQuotaList", reflect.TypeOf((*MockInterface)(nil).AdminQuotaList))
+}
+
+// AdminQuotaCreate mocks base method
+func (m *MockInterface) AdminQuotaCreate(q *sdk.Quota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminQuotaCreate", q)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminQuotaCreate indicates an expected call of AdminQuotaCreate
+func (mr *MockInterfaceMockRecorder) AdminQuotaCreate(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminQuotaCreate", reflect.TypeOf((*MockInterface)(nil).AdminQuotaCreate), q)
+}
+
+// AdminQuotaUpdate mocks base method
+func (m *MockInterface) AdminQuotaUpdate(q *sdk.Quota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminQuotaUpdate", q)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminQuotaUpdate indicates an expected call of AdminQuotaUpdate
+func (mr *MockInterfaceMockRecorder) AdminQuotaUpdate(q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminQuotaUpdate", reflect.TypeOf((*MockInterface)(nil).AdminQuotaUpdate), q)
+}
+
+// AdminQuotaDelete mocks base method
+func (m *MockInterface) AdminQuotaDelete(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminQuotaDelete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminQuotaDelete indicates an expected call of AdminQuotaDelete
+func (mr *MockInterfaceMockRecorder) AdminQuotaDelete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminQuotaDelete", reflect.TypeOf((*MockInterface)(nil).AdminQuotaDelete), id)
+}
+
 // Services mocks base method
 func (m *MockInterface) Services() ([]sdk.Service, error) {
 	m.ctrl.T.Helper()
@@ -5308,6 +5599,36 @@ func (mr *MockInterfaceMockRecorder) DownloadURLFromGithub(filename interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadURLFromGithub", reflect.TypeOf((*MockInterface)(nil).DownloadURLFromGithub), filename)
 }
 
+// DownloadInfos mocks base method
+func (m *MockInterface) DownloadInfos(name, os, arch, variant string) (*sdk.DownloadableResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadInfos", name, os, arch, variant)
+	ret0, _ := ret[0].(*sdk.DownloadableResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadInfos indicates an expected call of DownloadInfos
+func (mr *MockInterfaceMockRecorder) DownloadInfos(name, os, arch, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadInfos", reflect.TypeOf((*MockInterface)(nil).DownloadInfos), name, os, arch, variant)
+}
+
+// DownloadBinary mocks base method
+func (m *MockInterface) DownloadBinary(ctx context.Context, name, os, arch, variant string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadBinary", ctx, name, os, arch, variant)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadBinary indicates an expected call of DownloadBinary
+func (mr *MockInterfaceMockRecorder) DownloadBinary(ctx, name, os, arch, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBinary", reflect.TypeOf((*MockInterface)(nil).DownloadBinary), ctx, name, os, arch, variant)
+}
+
 // EnvironmentCreate mocks base method
 func (m *MockInterface) EnvironmentCreate(projectKey string, env *sdk.Environment) error {
 	m.ctrl.T.Helper()
@@ -6606,6 +6927,21 @@ func (mr *MockInterfaceMockRecorder) ProjectRepositoryManagerDelete(projectKey,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectRepositoryManagerDelete", reflect.TypeOf((*MockInterface)(nil).ProjectRepositoryManagerDelete), projectKey, repoManagerName, force)
 }
 
+// ProjectQuotaUsage mocks base method
+func (m *MockInterface) ProjectQuotaUsage(projectKey string) ([]sdk.QuotaUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectQuotaUsage", projectKey)
+	ret0, _ := ret[0].([]sdk.QuotaUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectQuotaUsage indicates an expected call of ProjectQuotaUsage
+func (mr *MockInterfaceMockRecorder) ProjectQuotaUsage(projectKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectQuotaUsage", reflect.TypeOf((*MockInterface)(nil).ProjectQuotaUsage), projectKey)
+}
+
 // QueueWorkflowNodeJobRun mocks base method
 func (m *MockInterface) QueueWorkflowNodeJobRun(status ...string) ([]sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -6683,6 +7019,20 @@ func (mr *MockInterfaceMockRecorder) QueueJobBook(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobBook", reflect.TypeOf((*MockInterface)(nil).QueueJobBook), ctx, id)
 }
 
+// QueueJobAssign mocks base method
+func (m *MockInterface) QueueJobAssign(ctx context.Context, id int64, workerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobAssign", ctx, id, workerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueJobAssign indicates an expected call of QueueJobAssign
+func (mr *MockInterfaceMockRecorder) QueueJobAssign(ctx, id, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobAssign", reflect.TypeOf((*MockInterface)(nil).QueueJobAssign), ctx, id, workerID)
+}
+
 // QueueJobRelease mocks base method
 func (m *MockInterface) QueueJobRelease(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
@@ -7224,6 +7574,21 @@ func (mr *MockInterfaceMockRecorder) WorkerSetStatus(ctx, status interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkerSetStatus", reflect.TypeOf((*MockInterface)(nil).WorkerSetStatus), ctx, status)
 }
 
+// WorkerVersion mocks base method
+func (m *MockInterface) WorkerVersion(ctx context.Context) (*sdk.WorkerVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkerVersion", ctx)
+	ret0, _ := ret[0].(*sdk.WorkerVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkerVersion indicates an expected call of WorkerVersion
+func (mr *MockInterfaceMockRecorder) WorkerVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkerVersion", reflect.TypeOf((*MockInterface)(nil).WorkerVersion), ctx)
+}
+
 // WorkflowList mocks base method
 func (m *MockInterface) WorkflowList(projectKey string, opts ...cdsclient.RequestModifier) ([]sdk.Workflow, error) {
 	m.ctrl.T.Helper()
@@ -7472,6 +7837,21 @@ func (mr *MockInterfaceMockRecorder) WorkflowRunFromManual(projectKey, workflowN
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunFromManual", reflect.TypeOf((*MockInterface)(nil).WorkflowRunFromManual), projectKey, workflowName, manual, number, fromNodeID)
 }
 
+// WorkflowRunPlan mocks base method
+func (m *MockInterface) WorkflowRunPlan(projectKey, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRunPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowRunPlan", projectKey, workflowName, manual, number, fromNodeID)
+	ret0, _ := ret[0].(*sdk.WorkflowRunPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowRunPlan indicates an expected call of WorkflowRunPlan
+func (mr *MockInterfaceMockRecorder) WorkflowRunPlan(projectKey, workflowName, manual, number, fromNodeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowRunPlan", reflect.TypeOf((*MockInterface)(nil).WorkflowRunPlan), projectKey, workflowName, manual, number, fromNodeID)
+}
+
 // WorkflowRunNumberGet mocks base method
 func (m *MockInterface) WorkflowRunNumberGet(projectKey, workflowName string) (*sdk.WorkflowRunNumber, error) {
 	m.ctrl.T.Helper()
@@ -7575,6 +7955,35 @@ func (mr *MockInterfaceMockRecorder) WorkflowNodeRunJobStep(projectKey, workflow
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunJobStep", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunJobStep), projectKey, workflowName, number, nodeRunID, job, step)
 }
 
+// WorkflowLogsSearch mocks base method
+func (m *MockInterface) WorkflowLogsSearch(projectKey, workflowName, regex string, from, to time.Time, limit int) ([]sdk.WorkflowLogSearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowLogsSearch", projectKey, workflowName, regex, from, to, limit)
+	ret0, _ := ret[0].([]sdk.WorkflowLogSearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowLogsSearch indicates an expected call of WorkflowLogsSearch
+func (mr *MockInterfaceMockRecorder) WorkflowLogsSearch(projectKey, workflowName, regex, from, to, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowLogsSearch", reflect.TypeOf((*MockInterface)(nil).WorkflowLogsSearch), projectKey, workflowName, regex, from, to, limit)
+}
+
+// WorkflowNodeRunJobStepLogStream mocks base method
+func (m *MockInterface) WorkflowNodeRunJobStepLogStream(ctx context.Context, projectKey, workflowName string, number, nodeRunID, job int64, step int, offset int64, chunks chan<- sdk.StepLogChunk) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunJobStepLogStream", ctx, projectKey, workflowName, number, nodeRunID, job, step, offset, chunks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WorkflowNodeRunJobStepLogStream indicates an expected call of WorkflowNodeRunJobStepLogStream
+func (mr *MockInterfaceMockRecorder) WorkflowNodeRunJobStepLogStream(ctx, projectKey, workflowName, number, nodeRunID, job, step, offset, chunks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunJobStepLogStream", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunJobStepLogStream), ctx, projectKey, workflowName, number, nodeRunID, job, step, offset, chunks)
+}
+
 // WorkflowNodeRunRelease mocks base method
 func (m *MockInterface) WorkflowNodeRunRelease(projectKey, workflowName string, runNumber, nodeRunID int64, release sdk.WorkflowNodeRunRelease) error {
 	m.ctrl.T.Helper()
@@ -7633,6 +8042,21 @@ func (mr *MockInterfaceMockRecorder) WorkflowCachePull(projectKey, integrationNa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockInterface)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheSearch mocks base method
+func (m *MockInterface) WorkflowCacheSearch(projectKey, integrationName, key string, restoreKeys []string) (*sdk.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheSearch", projectKey, integrationName, key, restoreKeys)
+	ret0, _ := ret[0].(*sdk.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheSearch indicates an expected call of WorkflowCacheSearch
+func (mr *MockInterfaceMockRecorder) WorkflowCacheSearch(projectKey, integrationName, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheSearch", reflect.TypeOf((*MockInterface)(nil).WorkflowCacheSearch), projectKey, integrationName, key, restoreKeys)
+}
+
 // WorkflowTemplateInstanceGet mocks base method
 func (m *MockInterface) WorkflowTemplateInstanceGet(projectKey, workflowName string) (*sdk.WorkflowTemplateInstance, error) {
 	m.ctrl.T.Helper()
@@ -7737,6 +8161,50 @@ func (mr *MockInterfaceMockRecorder) MonErrorsGet(requestID interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MonErrorsGet", reflect.TypeOf((*MockInterface)(nil).MonErrorsGet), requestID)
 }
 
+// HookChangedFiles mocks base method
+func (m *MockInterface) HookChangedFiles(uuid, branch, base, head string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookChangedFiles", uuid, branch, base, head)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookChangedFiles indicates an expected call of HookChangedFiles
+func (mr *MockInterfaceMockRecorder) HookChangedFiles(uuid, branch, base, head interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookChangedFiles", reflect.TypeOf((*MockInterface)(nil).HookChangedFiles), uuid, branch, base, head)
+}
+
+// HookMergeQueueAdd mocks base method
+func (m *MockInterface) HookMergeQueueAdd(uuid string, pullRequestID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookMergeQueueAdd", uuid, pullRequestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HookMergeQueueAdd indicates an expected call of HookMergeQueueAdd
+func (mr *MockInterfaceMockRecorder) HookMergeQueueAdd(uuid, pullRequestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookMergeQueueAdd", reflect.TypeOf((*MockInterface)(nil).HookMergeQueueAdd), uuid, pullRequestID)
+}
+
+// HookPullRequests mocks base method
+func (m *MockInterface) HookPullRequests(uuid, base string) ([]sdk.VCSPullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookPullRequests", uuid, base)
+	ret0, _ := ret[0].([]sdk.VCSPullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookPullRequests indicates an expected call of HookPullRequests
+func (mr *MockInterfaceMockRecorder) HookPullRequests(uuid, base interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookPullRequests", reflect.TypeOf((*MockInterface)(nil).HookPullRequests), uuid, base)
+}
+
 // PollVCSEvents mocks base method
 func (m *MockInterface) PollVCSEvents(uuid string, workflowID int64, vcsServer string, timestamp int64) (sdk.RepositoryEvents, time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -8176,6 +8644,94 @@ func (mr *MockWorkerInterfaceMockRecorder) QueueJobBook(ctx, id interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobBook", reflect.TypeOf((*MockWorkerInterface)(nil).QueueJobBook), ctx, id)
 }
 
+// Download mocks base method
+func (m *MockWorkerInterface) Download() ([]sdk.DownloadableResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Download")
+	ret0, _ := ret[0].([]sdk.DownloadableResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Download indicates an expected call of Download
+func (mr *MockWorkerInterfaceMockRecorder) Download() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Download", reflect.TypeOf((*MockWorkerInterface)(nil).Download))
+}
+
+// DownloadURLFromAPI mocks base method
+func (m *MockWorkerInterface) DownloadURLFromAPI(name, os, arch, variant string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadURLFromAPI", name, os, arch, variant)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DownloadURLFromAPI indicates an expected call of DownloadURLFromAPI
+func (mr *MockWorkerInterfaceMockRecorder) DownloadURLFromAPI(name, os, arch, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadURLFromAPI", reflect.TypeOf((*MockWorkerInterface)(nil).DownloadURLFromAPI), name, os, arch, variant)
+}
+
+// DownloadURLFromGithub mocks base method
+func (m *MockWorkerInterface) DownloadURLFromGithub(filename string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadURLFromGithub", filename)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadURLFromGithub indicates an expected call of DownloadURLFromGithub
+func (mr *MockWorkerInterfaceMockRecorder) DownloadURLFromGithub(filename interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadURLFromGithub", reflect.TypeOf((*MockWorkerInterface)(nil).DownloadURLFromGithub), filename)
+}
+
+// DownloadInfos mocks base method
+func (m *MockWorkerInterface) DownloadInfos(name, os, arch, variant string) (*sdk.DownloadableResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadInfos", name, os, arch, variant)
+	ret0, _ := ret[0].(*sdk.DownloadableResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadInfos indicates an expected call of DownloadInfos
+func (mr *MockWorkerInterfaceMockRecorder) DownloadInfos(name, os, arch, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadInfos", reflect.TypeOf((*MockWorkerInterface)(nil).DownloadInfos), name, os, arch, variant)
+}
+
+// DownloadBinary mocks base method
+func (m *MockWorkerInterface) DownloadBinary(ctx context.Context, name, os, arch, variant string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadBinary", ctx, name, os, arch, variant)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadBinary indicates an expected call of DownloadBinary
+func (mr *MockWorkerInterfaceMockRecorder) DownloadBinary(ctx, name, os, arch, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadBinary", reflect.TypeOf((*MockWorkerInterface)(nil).DownloadBinary), ctx, name, os, arch, variant)
+}
+
+// QueueJobAssign mocks base method
+func (m *MockWorkerInterface) QueueJobAssign(ctx context.Context, id int64, workerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobAssign", ctx, id, workerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueJobAssign indicates an expected call of QueueJobAssign
+func (mr *MockWorkerInterfaceMockRecorder) QueueJobAssign(ctx, id, workerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobAssign", reflect.TypeOf((*MockWorkerInterface)(nil).QueueJobAssign), ctx, id, workerID)
+}
+
 // QueueJobRelease mocks base method
 func (m *MockWorkerInterface) QueueJobRelease(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
@@ -8568,6 +9124,21 @@ func (mr *MockWorkerInterfaceMockRecorder) WorkerSetStatus(ctx, status interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkerSetStatus", reflect.TypeOf((*MockWorkerInterface)(nil).WorkerSetStatus), ctx, status)
 }
 
+// WorkerVersion mocks base method
+func (m *MockWorkerInterface) WorkerVersion(ctx context.Context) (*sdk.WorkerVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkerVersion", ctx)
+	ret0, _ := ret[0].(*sdk.WorkerVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkerVersion indicates an expected call of WorkerVersion
+func (mr *MockWorkerInterfaceMockRecorder) WorkerVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkerVersion", reflect.TypeOf((*MockWorkerInterface)(nil).WorkerVersion), ctx)
+}
+
 // WorkflowRunArtifacts mocks base method
 func (m *MockWorkerInterface) WorkflowRunArtifacts(projectKey, name string, number int64) ([]sdk.WorkflowNodeRunArtifact, error) {
 	m.ctrl.T.Helper()
@@ -8612,6 +9183,21 @@ func (mr *MockWorkerInterfaceMockRecorder) WorkflowCachePull(projectKey, integra
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCachePull", reflect.TypeOf((*MockWorkerInterface)(nil).WorkflowCachePull), projectKey, integrationName, ref)
 }
 
+// WorkflowCacheSearch mocks base method
+func (m *MockWorkerInterface) WorkflowCacheSearch(projectKey, integrationName, key string, restoreKeys []string) (*sdk.CacheEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowCacheSearch", projectKey, integrationName, key, restoreKeys)
+	ret0, _ := ret[0].(*sdk.CacheEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowCacheSearch indicates an expected call of WorkflowCacheSearch
+func (mr *MockWorkerInterfaceMockRecorder) WorkflowCacheSearch(projectKey, integrationName, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowCacheSearch", reflect.TypeOf((*MockWorkerInterface)(nil).WorkflowCacheSearch), projectKey, integrationName, key, restoreKeys)
+}
+
 // WorkflowRunSearch mocks base method
 func (m *MockWorkerInterface) WorkflowRunSearch(projectKey string, offset, limit int64, filter ...cdsclient.Filter) ([]sdk.WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,69 @@
+// Package condition implements the expression language used in workflow run conditions.
+//
+// An expression is evaluated against the build parameters of a node run and must return a boolean:
+//
+//	git.branch == 'master' || git.tag =~ '^v.*'
+//	not (cds.manual == true) and git.branch in ['master', 'develop']
+//	semver(git.tag) >= semver('1.2.0') && 'deploy' in split(git.message, ' ')
+//
+// Supported operators are ||, &&, ! (or, and, not), ==, !=, <, <=, >, >=, =~, !~, in, contains,
+// startsWith, endsWith and matches; "not" can prefix in, contains, startsWith, endsWith and matches.
+// Available functions are semver, lower, upper, trim, split, len and empty.
+package condition
+
+import (
+	"fmt"
+)
+
+// Expression is a parsed condition expression.
+type Expression struct {
+	source string
+	root   node
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Parse parses and validates an expression.
+func Parse(source string) (*Expression, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %v", source, err)
+	}
+	if len(tokens) == 1 {
+		return nil, fmt.Errorf("invalid condition: empty expression")
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %v", source, err)
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("invalid condition %q: unexpected %s at position %d", source, t, t.pos)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Eval evaluates the expression with given variables.
+func (e *Expression) Eval(vars map[string]string) (bool, error) {
+	v, err := e.root.eval(vars)
+	if err != nil {
+		return false, fmt.Errorf("unable to evaluate condition %q: %v", e.source, err)
+	}
+	b, err := toBool(v)
+	if err != nil {
+		return false, fmt.Errorf("condition %q must return a boolean: %v", e.source, err)
+	}
+	return b, nil
+}
+
+// Check parses and evaluates an expression with given variables.
+func Check(source string, vars map[string]string) (bool, error) {
+	e, err := Parse(source)
+	if err != nil {
+		return false, err
+	}
+	return e.Eval(vars)
+}
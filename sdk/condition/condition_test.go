@@ -0,0 +1,82 @@
+package condition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	vars := map[string]string{
+		"git.branch":  "master",
+		"git.tag":     "v1.4.2",
+		"git.message": "fix: deploy on prod",
+		"cds.manual":  "true",
+		"cds.version": "42",
+		"labels":      "backend, urgent",
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`git.branch == 'master'`, true},
+		{`git.branch != "master"`, false},
+		{`git.branch == 'develop' || git.tag =~ '^v.*'`, true},
+		{`git.branch == 'develop' or git.tag matches '^v.*'`, true},
+		{`!(git.branch == 'master')`, false},
+		{`not git.branch == 'master' and cds.manual`, false},
+		{`(git.branch == 'develop' || git.branch == 'master') && cds.manual == true`, true},
+		{`git.branch in ['master', 'develop']`, true},
+		{`git.branch not in ['master', 'develop']`, false},
+		{`git.message contains 'deploy'`, true},
+		{`git.message not contains 'deploy'`, false},
+		{`git.branch startsWith 'mas' && git.branch endsWith 'ter'`, true},
+		{`semver(git.tag) >= semver('1.2.0')`, true},
+		{`semver(git.tag) < '1.4.10'`, true},
+		{`cds.version > 9`, true},
+		{`cds.version > '9'`, false},
+		{`'urgent' in split(labels, ',')`, true},
+		{`len(split(labels, ',')) == 2`, true},
+		{`empty(unknown.variable)`, true},
+		{`lower('MASTER') == git.branch`, true},
+		{`git.tag !~ '^release'`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := Check(tt.expr, vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`git.branch ==`,
+		`(git.branch == 'master'`,
+		`git.branch == 'master'))`,
+		`git.branch =~ '[a-z'`,
+		`unknown(git.branch)`,
+		`semver('1.0.0', '2.0.0')`,
+		`git.branch == 'master`,
+		`git.branch not 'master'`,
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, "expression %q should be invalid", expr)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	vars := map[string]string{"git.branch": "master"}
+	for _, expr := range []string{
+		`git.branch`,
+		`git.branch > 3`,
+		`semver(git.branch) > semver('1.0.0')`,
+	} {
+		_, err := Check(expr, vars)
+		assert.Error(t, err, "expression %q should fail", expr)
+	}
+}
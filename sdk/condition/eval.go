@@ -0,0 +1,413 @@
+package condition
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+// Values handled by the evaluator are string, float64, bool, []interface{} and semver.Version.
+
+type node interface {
+	eval(vars map[string]string) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(map[string]string) (interface{}, error) {
+	return n.value, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+// An unknown variable is evaluated as an empty string, like for plain conditions.
+func (n *variableNode) eval(vars map[string]string) (interface{}, error) {
+	return vars[n.name], nil
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) eval(vars map[string]string) (interface{}, error) {
+	res := make([]interface{}, 0, len(n.items))
+	for _, i := range n.items {
+		v, err := i.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(vars map[string]string) (interface{}, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	b, err := toBool(v)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right node
+}
+
+func (n *logicalNode) eval(vars map[string]string) (interface{}, error) {
+	lv, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	l, err := toBool(lv)
+	if err != nil {
+		return nil, err
+	}
+	// Short-circuit evaluation
+	if (n.op == "&&" && !l) || (n.op == "||" && l) {
+		return l, nil
+	}
+	rv, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return toBool(rv)
+}
+
+type comparisonNode struct {
+	op          string
+	left, right node
+	regexp      *regexp.Regexp
+}
+
+func (n *comparisonNode) eval(vars map[string]string) (interface{}, error) {
+	l, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equals(l, r)
+	case "!=":
+		eq, err := equals(l, r)
+		if err != nil {
+			return nil, err
+		}
+		return !eq, nil
+	case "<", "<=", ">", ">=":
+		c, err := compare(l, r)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "=~", "!~", keywordMatches:
+		re := n.regexp
+		if re == nil {
+			pattern, ok := r.(string)
+			if !ok {
+				return nil, fmt.Errorf("regular expression must be a string, got %s", typeName(r))
+			}
+			re, err = regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %v", pattern, err)
+			}
+		}
+		match := re.MatchString(toString(l))
+		if n.op == "!~" {
+			return !match, nil
+		}
+		return match, nil
+	case keywordIn:
+		return contains(r, l)
+	case keywordContains:
+		return contains(l, r)
+	case keywordStartsWith:
+		return strings.HasPrefix(toString(l), toString(r)), nil
+	case keywordEndsWith:
+		return strings.HasSuffix(toString(l), toString(r)), nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", n.op)
+}
+
+type function struct {
+	arity int
+	call  func(args []interface{}) (interface{}, error)
+}
+
+var functions = map[string]function{
+	"semver": {arity: 1, call: func(args []interface{}) (interface{}, error) {
+		return toSemver(args[0])
+	}},
+	"lower": {arity: 1, call: func(args []interface{}) (interface{}, error) {
+		return strings.ToLower(toString(args[0])), nil
+	}},
+	"upper": {arity: 1, call: func(args []interface{}) (interface{}, error) {
+		return strings.ToUpper(toString(args[0])), nil
+	}},
+	"trim": {arity: 1, call: func(args []interface{}) (interface{}, error) {
+		return strings.TrimSpace(toString(args[0])), nil
+	}},
+	"split": {arity: 2, call: func(args []interface{}) (interface{}, error) {
+		s := toString(args[0])
+		if s == "" {
+			return []interface{}{}, nil
+		}
+		parts := strings.Split(s, toString(args[1]))
+		res := make([]interface{}, len(parts))
+		for i := range parts {
+			res[i] = strings.TrimSpace(parts[i])
+		}
+		return res, nil
+	}},
+	"len": {arity: 1, call: func(args []interface{}) (interface{}, error) {
+		if l, ok := args[0].([]interface{}); ok {
+			return float64(len(l)), nil
+		}
+		return float64(len(toString(args[0]))), nil
+	}},
+	"empty": {arity: 1, call: func(args []interface{}) (interface{}, error) {
+		if l, ok := args[0].([]interface{}); ok {
+			return len(l) == 0, nil
+		}
+		return toString(args[0]) == "", nil
+	}},
+}
+
+type callNode struct {
+	name string
+	fn   function
+	args []node
+}
+
+func (n *callNode) eval(vars map[string]string) (interface{}, error) {
+	args := make([]interface{}, 0, len(n.args))
+	for _, a := range n.args {
+		v, err := a.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	res, err := n.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", n.name, err)
+	}
+	return res, nil
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "list"
+	case semver.Version:
+		return "semver"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func toString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case semver.Version:
+		return x.String()
+	case []interface{}:
+		s := make([]string, len(x))
+		for i := range x {
+			s[i] = toString(x[i])
+		}
+		return strings.Join(s, ",")
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func toBool(v interface{}) (bool, error) {
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case string:
+		b, err := strconv.ParseBool(x)
+		if err != nil {
+			return false, fmt.Errorf("cannot use %q as boolean", x)
+		}
+		return b, nil
+	}
+	return false, fmt.Errorf("cannot use %s as boolean", typeName(v))
+}
+
+func toNumber(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot use %q as number", x)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("cannot use %s as number", typeName(v))
+}
+
+func toSemver(v interface{}) (semver.Version, error) {
+	switch x := v.(type) {
+	case semver.Version:
+		return x, nil
+	case string:
+		sv, err := semver.ParseTolerant(x)
+		if err != nil {
+			return semver.Version{}, fmt.Errorf("cannot use %q as semver: %v", x, err)
+		}
+		return sv, nil
+	case float64:
+		return toSemver(toString(x))
+	}
+	return semver.Version{}, fmt.Errorf("cannot use %s as semver", typeName(v))
+}
+
+// equals compares two values. When types differ, the string operand is converted to
+// the type of the other one; variables are always strings.
+func equals(l, r interface{}) (bool, error) {
+	switch lv := l.(type) {
+	case semver.Version:
+		rv, err := toSemver(r)
+		if err != nil {
+			return false, err
+		}
+		return lv.Equals(rv), nil
+	case float64:
+		rv, err := toNumber(r)
+		if err != nil {
+			return false, nil
+		}
+		return lv == rv, nil
+	case bool:
+		rv, err := toBool(r)
+		if err != nil {
+			return false, nil
+		}
+		return lv == rv, nil
+	case []interface{}:
+		rv, ok := r.([]interface{})
+		if !ok || len(lv) != len(rv) {
+			return false, nil
+		}
+		for i := range lv {
+			eq, err := equals(lv[i], rv[i])
+			if err != nil || !eq {
+				return false, err
+			}
+		}
+		return true, nil
+	case string:
+		if _, isString := r.(string); !isString {
+			return equals(r, l)
+		}
+		return lv == r.(string), nil
+	}
+	return false, fmt.Errorf("cannot compare %s and %s", typeName(l), typeName(r))
+}
+
+// compare returns -1, 0 or 1. Numbers and semvers are compared by value,
+// strings are compared lexicographically.
+func compare(l, r interface{}) (int, error) {
+	_, lIsSemver := l.(semver.Version)
+	_, rIsSemver := r.(semver.Version)
+	if lIsSemver || rIsSemver {
+		lv, err := toSemver(l)
+		if err != nil {
+			return 0, err
+		}
+		rv, err := toSemver(r)
+		if err != nil {
+			return 0, err
+		}
+		return lv.Compare(rv), nil
+	}
+
+	_, lIsNumber := l.(float64)
+	_, rIsNumber := r.(float64)
+	if lIsNumber || rIsNumber {
+		lv, err := toNumber(l)
+		if err != nil {
+			return 0, err
+		}
+		rv, err := toNumber(r)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case lv < rv:
+			return -1, nil
+		case lv > rv:
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	ls, lIsString := l.(string)
+	rs, rIsString := r.(string)
+	if lIsString && rIsString {
+		return strings.Compare(ls, rs), nil
+	}
+	return 0, fmt.Errorf("cannot compare %s and %s", typeName(l), typeName(r))
+}
+
+// contains checks if container (a list or a string) contains the given element.
+func contains(container, elem interface{}) (bool, error) {
+	switch c := container.(type) {
+	case []interface{}:
+		for _, i := range c {
+			eq, err := equals(i, elem)
+			if err != nil {
+				return false, err
+			}
+			if eq {
+				return true, nil
+			}
+		}
+		return false, nil
+	case string:
+		return strings.Contains(c, toString(elem)), nil
+	}
+	return false, fmt.Errorf("cannot search in %s", typeName(container))
+}
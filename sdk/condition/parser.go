@@ -0,0 +1,243 @@
+package condition
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Keyword operators, they are lexed as identifiers
+const (
+	keywordAnd        = "and"
+	keywordOr         = "or"
+	keywordNot        = "not"
+	keywordIn         = "in"
+	keywordContains   = "contains"
+	keywordStartsWith = "startsWith"
+	keywordEndsWith   = "endsWith"
+	keywordMatches    = "matches"
+	keywordTrue       = "true"
+	keywordFalse      = "false"
+)
+
+var comparisonOperators = map[string]bool{
+	"==":              true,
+	"!=":              true,
+	"<":               true,
+	"<=":              true,
+	">":               true,
+	">=":              true,
+	"=~":              true,
+	"!~":              true,
+	keywordIn:         true,
+	keywordContains:   true,
+	keywordStartsWith: true,
+	keywordEndsWith:   true,
+	keywordMatches:    true,
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOperator(t token, ops ...string) bool {
+	if t.kind != tokenOperator && t.kind != tokenIdent {
+		return false
+	}
+	for _, op := range ops {
+		if t.value == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, fmt.Errorf("expected %s at position %d, got %s", what, t.pos, t)
+	}
+	return t, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(p.peek(), "||", keywordOr) {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator(p.peek(), "&&", keywordAnd) {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isOperator(p.peek(), "!", keywordNot) {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	negate := false
+	// Handle "not in", "not contains"...
+	if p.isOperator(t, keywordNot) && p.pos+1 < len(p.tokens) && comparisonOperators[p.tokens[p.pos+1].value] {
+		p.next()
+		t = p.peek()
+		negate = true
+	}
+	if (t.kind != tokenOperator && t.kind != tokenIdent) || !comparisonOperators[t.value] {
+		if negate {
+			return nil, fmt.Errorf("expected operator after 'not' at position %d", t.pos)
+		}
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	n := &comparisonNode{op: t.value, left: left, right: right}
+	if n.op == "=~" || n.op == "!~" || n.op == keywordMatches {
+		// Precompile literal regexp to report errors at parse time
+		if lit, ok := right.(*literalNode); ok {
+			s, isString := lit.value.(string)
+			if !isString {
+				return nil, fmt.Errorf("regular expression at position %d must be a string", t.pos)
+			}
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %v", s, err)
+			}
+			n.regexp = re
+		}
+	}
+
+	var res node = n
+	if negate {
+		res = &notNode{operand: n}
+	}
+	return res, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return &literalNode{value: t.value}, nil
+	case tokenNumber:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.value, t.pos)
+		}
+		return &literalNode{value: f}, nil
+	case tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case tokenLBracket:
+		items, err := p.parseList(tokenRBracket, "']'")
+		if err != nil {
+			return nil, err
+		}
+		return &listNode{items: items}, nil
+	case tokenIdent:
+		switch t.value {
+		case keywordTrue:
+			return &literalNode{value: true}, nil
+		case keywordFalse:
+			return &literalNode{value: false}, nil
+		}
+		if p.peek().kind == tokenLParen {
+			p.next()
+			fn, has := functions[t.value]
+			if !has {
+				return nil, fmt.Errorf("unknown function %q at position %d", t.value, t.pos)
+			}
+			args, err := p.parseList(tokenRParen, "')'")
+			if err != nil {
+				return nil, err
+			}
+			if len(args) != fn.arity {
+				return nil, fmt.Errorf("function %q expects %d argument(s), got %d", t.value, fn.arity, len(args))
+			}
+			return &callNode{name: t.value, fn: fn, args: args}, nil
+		}
+		return &variableNode{name: t.value}, nil
+	}
+	return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+}
+
+func (p *parser) parseList(end tokenKind, endName string) ([]node, error) {
+	var items []node
+	if p.peek().kind == end {
+		p.next()
+		return items, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		t := p.next()
+		if t.kind == end {
+			return items, nil
+		}
+		if t.kind != tokenComma {
+			return nil, fmt.Errorf("expected ',' or %s at position %d, got %s", endName, t.pos, t)
+		}
+	}
+}
@@ -21,6 +21,8 @@ type DownloadableResource struct {
 	Variant   string `json:"variant,omitempty"`
 	Filename  string `json:"filename,omitempty"`
 	Available *bool  `json:"available,omitempty"`
+	SHA256    string `json:"sha256,omitempty"`
+	Signature []byte `json:"signature,omitempty"`
 }
 
 var (
@@ -200,6 +200,8 @@ var (
 	ErrWorkflowAsCodeResync                          = Error{ID: 186, Status: http.StatusForbidden}
 	ErrWorkflowNodeNameDuplicate                     = Error{ID: 187, Status: http.StatusBadRequest}
 	ErrUnsupportedMediaType                          = Error{ID: 188, Status: http.StatusUnsupportedMediaType}
+	ErrJobQuotaExceeded                              = Error{ID: 189, Status: http.StatusConflict}
+	ErrWorkerVersionNotSupported                     = Error{ID: 190, Status: http.StatusBadRequest}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -382,6 +384,8 @@ var errorsAmericanEnglish = map[int]string{
 	ErrWorkflowAsCodeResync.ID:                          "You cannot resynchronize an as-code workflow",
 	ErrWorkflowNodeNameDuplicate.ID:                     "You cannot have same name for different pipelines in your workflow",
 	ErrUnsupportedMediaType.ID:                          "Request format invalid",
+	ErrJobQuotaExceeded.ID:                              "Job quota exceeded",
+	ErrWorkerVersionNotSupported.ID:                     "Worker version not supported",
 }
 
 var errorsFrench = map[int]string{
@@ -564,6 +568,8 @@ var errorsFrench = map[int]string{
 	ErrWorkflowAsCodeResync.ID:                          "Impossible de resynchroniser un workflow en mode as-code",
 	ErrWorkflowNodeNameDuplicate.ID:                     "Vous ne pouvez pas avoir plusieurs fois le même nom de pipeline dans votre workflow",
 	ErrUnsupportedMediaType.ID:                          "Le format de la requête est invalide",
+	ErrJobQuotaExceeded.ID:                              "Quota de jobs dépassé",
+	ErrWorkerVersionNotSupported.ID:                     "Version du worker non supportée",
 }
 
 var errorsLanguages = []map[int]string{
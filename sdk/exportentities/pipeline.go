@@ -21,6 +21,7 @@ type PipelineV1 struct {
 	Stages       []string                  `json:"stages,omitempty" yaml:"stages,omitempty" jsonschema_description:"The list of stage's names for the pipeline."`
 	StageOptions map[string]Stage          `json:"options,omitempty" yaml:"options,omitempty" jsonschema_description:"The options for stages of the pipeline."` //Here Stage.Jobs will NEVER be set
 	Jobs         []Job                     `json:"jobs,omitempty" yaml:"jobs,omitempty" jsonschema_description:"The list of jobs for the pipeline."`
+	RetryPolicy  *sdk.JobRetryPolicy       `json:"retry_policy,omitempty" yaml:"retry_policy,omitempty" jsonschema_description:"The default retry policy for the jobs of the pipeline."`
 }
 
 // PipelineVersion is a version
@@ -40,14 +41,17 @@ type Stage struct {
 
 // Job represents exported sdk.Job
 type Job struct {
-	Name           string        `json:"job,omitempty" yaml:"job,omitempty" jsonschema_description:"The name of the job."`
-	Stage          string        `json:"stage,omitempty" yaml:"stage,omitempty" jsonschema_description:"The name of the stage for the job."`
-	Description    string        `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"The description of the job."`
-	Enabled        *bool         `json:"enabled,omitempty" yaml:"enabled,omitempty" jsonschema_description:"Job is enabled by default, you can set this option to disable a job."`
-	Steps          []Step        `json:"steps,omitempty" yaml:"steps,omitempty" jsonschema_description:"The list of steps for the job."`
-	Requirements   []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty" jsonschema_description:"The list of requirements for the jobs."`
-	Optional       *bool         `json:"optional,omitempty" yaml:"optional,omitempty" jsonschema_description:"Set this option to ignore job's errors."`
-	AlwaysExecuted *bool         `json:"always_executed,omitempty" yaml:"always_executed,omitempty" jsonschema_description:"Set this option to execute the job even if a previous step failed."`
+	Name           string              `json:"job,omitempty" yaml:"job,omitempty" jsonschema_description:"The name of the job."`
+	Stage          string              `json:"stage,omitempty" yaml:"stage,omitempty" jsonschema_description:"The name of the stage for the job."`
+	Description    string              `json:"description,omitempty" yaml:"description,omitempty" jsonschema_description:"The description of the job."`
+	Enabled        *bool               `json:"enabled,omitempty" yaml:"enabled,omitempty" jsonschema_description:"Job is enabled by default, you can set this option to disable a job."`
+	Steps          []Step              `json:"steps,omitempty" yaml:"steps,omitempty" jsonschema_description:"The list of steps for the job."`
+	Requirements   []Requirement       `json:"requirements,omitempty" yaml:"requirements,omitempty" jsonschema_description:"The list of requirements for the jobs."`
+	Optional       *bool               `json:"optional,omitempty" yaml:"optional,omitempty" jsonschema_description:"Set this option to ignore job's errors."`
+	AlwaysExecuted *bool               `json:"always_executed,omitempty" yaml:"always_executed,omitempty" jsonschema_description:"Set this option to execute the job even if a previous step failed."`
+	Matrix         sdk.JobMatrix       `json:"matrix,omitempty" yaml:"matrix,omitempty" jsonschema_description:"Values of the matrix variables, the job will run once for each combination.\nValues are available as {{.cds.matrix.<name>}} in steps and requirements."`
+	RetryPolicy    *sdk.JobRetryPolicy `json:"retry_policy,omitempty" yaml:"retry_policy,omitempty" jsonschema_description:"Retry the job when it fails, overrides the pipeline retry policy."`
+	Timeout        int64               `json:"timeout,omitempty" yaml:"timeout,omitempty" jsonschema_description:"Maximum duration of the job in seconds."`
 }
 
 // Requirement represents an exported sdk.Requirement
@@ -166,6 +170,9 @@ func newJob(j sdk.Job) Job {
 	jo.Steps = newSteps(j.Action)
 	jo.Description = j.Action.Description
 	jo.Requirements = newRequirements(j.Action.Requirements)
+	jo.Matrix = j.Matrix
+	jo.RetryPolicy = j.RetryPolicy
+	jo.Timeout = j.Timeout
 	return jo
 }
 
@@ -256,6 +263,23 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 	job.Action.Enabled = job.Enabled
 	job.Action.Requirements = computeJobRequirements(j.Requirements)
 
+	if err := j.Matrix.IsValid(); err != nil {
+		return nil, sdk.WrapError(err, "invalid matrix for job %s", name)
+	}
+	job.Matrix = j.Matrix
+
+	if j.RetryPolicy != nil {
+		if err := j.RetryPolicy.IsValid(); err != nil {
+			return nil, sdk.WrapError(err, "invalid retry policy for job %s", name)
+		}
+		job.RetryPolicy = j.RetryPolicy
+	}
+
+	if j.Timeout < 0 {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid timeout for job %s", name)
+	}
+	job.Timeout = j.Timeout
+
 	//Compute steps for the jobs
 	children, err := computeSteps(j.Steps)
 	if err != nil {
@@ -332,6 +356,11 @@ func (p PipelineV1) Pipeline() (pip *sdk.Pipeline, err error) {
 			}
 		}
 
+		// Jobs without their own retry policy inherit the pipeline one
+		if j.RetryPolicy == nil {
+			j.RetryPolicy = p.RetryPolicy
+		}
+
 		job, err := computeJob(j.Name, j)
 		if err != nil {
 			return pip, err
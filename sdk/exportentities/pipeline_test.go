@@ -535,6 +535,95 @@ jobs:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Requirements, 2)
 }
 
+func Test_ImportPipelineWithMatrix(t *testing.T) {
+	in := `name: build-all
+jobs:
+- job: build
+  matrix:
+    go: ["1.12", "1.13"]
+    os: [linux, windows]
+  requirements:
+  - model: go-{{.cds.matrix.os}}
+  steps:
+  - script: go build ./...
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	assert.Equal(t, sdk.JobMatrix{"go": {"1.12", "1.13"}, "os": {"linux", "windows"}}, p.Stages[0].Jobs[0].Matrix)
+
+	exported := exportentities.NewPipelineV1(*p)
+	assert.Equal(t, p.Stages[0].Jobs[0].Matrix, exported.Jobs[0].Matrix)
+
+	payload.Jobs[0].Matrix["os"] = nil
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithRetryPolicy(t *testing.T) {
+	in := `name: build-all
+retry_policy:
+  max_attempts: 2
+jobs:
+- job: build
+  steps:
+  - script: go build ./...
+- job: test
+  retry_policy:
+    max_attempts: 3
+    backoff: 30
+    on: [worker_lost, failure]
+  steps:
+  - script: go test ./...
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	test.Equal(t, 2, len(p.Stages[0].Jobs))
+	assert.Equal(t, &sdk.JobRetryPolicy{MaxAttempts: 2}, p.Stages[0].Jobs[0].RetryPolicy)
+	assert.Equal(t, &sdk.JobRetryPolicy{MaxAttempts: 3, Backoff: 30, On: []string{sdk.JobRetryOnWorkerLost, sdk.JobRetryOnFailure}}, p.Stages[0].Jobs[1].RetryPolicy)
+
+	payload.Jobs[1].RetryPolicy.On = []string{"unknown"}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithTimeout(t *testing.T) {
+	in := `name: build-all
+jobs:
+- job: build
+  timeout: 3600
+  steps:
+  - script: make build
+    timeout: 600
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	assert.Equal(t, int64(3600), p.Stages[0].Jobs[0].Timeout)
+	assert.Equal(t, int64(600), p.Stages[0].Jobs[0].Action.Actions[0].Timeout)
+
+	exported := exportentities.NewPipelineV1(*p)
+	assert.Equal(t, int64(3600), exported.Jobs[0].Timeout)
+	assert.Equal(t, int64(600), exported.Jobs[0].Steps[0].Timeout)
+
+	payload.Jobs[0].Steps[0].Timeout = -1
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
 func Test_ImportPipelineWithGitClone(t *testing.T) {
 	in := `name: build-all-images
 jobs:
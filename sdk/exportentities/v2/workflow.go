@@ -22,37 +22,40 @@ type Workflow struct {
 	Hooks    map[string][]HookEntry `json:"hooks,omitempty" yaml:"hooks,omitempty" jsonschema_description:"Workflow hooks list."`
 
 	// extra workflow data
-	Permissions   map[string]int      `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the workflow (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
-	Metadata      map[string]string   `json:"metadata,omitempty" yaml:"metadata,omitempty"`
-	PurgeTags     []string            `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
-	Notifications []NotificationEntry `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have only one pipeline
-	HistoryLength *int64              `json:"history_length,omitempty" yaml:"history_length,omitempty"`
+	Permissions   map[string]int           `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the workflow (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
+	Metadata      map[string]string        `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	PurgeTags     []string                 `json:"purge_tags,omitempty" yaml:"purge_tags,omitempty"`
+	Notifications []NotificationEntry      `json:"notifications,omitempty" yaml:"notifications,omitempty"` // This is used when the workflow have only one pipeline
+	HistoryLength *int64                   `json:"history_length,omitempty" yaml:"history_length,omitempty"`
+	Concurrency   *sdk.WorkflowConcurrency `json:"concurrency,omitempty" yaml:"concurrency,omitempty" jsonschema_description:"Concurrency group of the workflow runs (ex: group: '{{.git.branch}}').\nhttps://ovh.github.io/cds/docs/concepts/workflow/concurrency"`
 }
 
 // NodeEntry represents a node as code
 type NodeEntry struct {
-	ID                     int64                  `json:"-" yaml:"-"`
-	DependsOn              []string               `json:"depends_on,omitempty" yaml:"depends_on,omitempty" jsonschema_description:"Names of the parent nodes, can be pipelines, forks or joins."`
-	Conditions             *ConditionEntry        `json:"conditions,omitempty" yaml:"conditions,omitempty" jsonschema_description:"Conditions to run this node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/run-conditions."`
-	When                   []string               `json:"when,omitempty" yaml:"when,omitempty" jsonschema_description:"Set manual and status condition (ex: 'success')."` //This is used only for manual and success condition
-	PipelineName           string                 `json:"pipeline,omitempty" yaml:"pipeline,omitempty" jsonschema_description:"The name of a pipeline used for pipeline node."`
-	ApplicationName        string                 `json:"application,omitempty" yaml:"application,omitempty" jsonschema_description:"The application to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
-	EnvironmentName        string                 `json:"environment,omitempty" yaml:"environment,omitempty" jsonschema_description:"The environment to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
-	ProjectIntegrationName string                 `json:"integration,omitempty" yaml:"integration,omitempty" jsonschema_description:"The integration to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
-	OneAtATime             *bool                  `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty" jsonschema_description:"Set to true if you want to limit the execution of this node to one at a time."`
-	Payload                map[string]interface{} `json:"payload,omitempty" yaml:"payload,omitempty"`
-	Parameters             map[string]string      `json:"parameters,omitempty" yaml:"parameters,omitempty" jsonschema_description:"List of parameters for the workflow."`
-	OutgoingHookModelName  string                 `json:"trigger,omitempty" yaml:"trigger,omitempty"`
-	OutgoingHookConfig     map[string]string      `json:"config,omitempty" yaml:"config,omitempty"`
-	Permissions            map[string]int         `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the node (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
+	ID                     int64                    `json:"-" yaml:"-"`
+	DependsOn              []string                 `json:"depends_on,omitempty" yaml:"depends_on,omitempty" jsonschema_description:"Names of the parent nodes, can be pipelines, forks or joins."`
+	Conditions             *ConditionEntry          `json:"conditions,omitempty" yaml:"conditions,omitempty" jsonschema_description:"Conditions to run this node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/run-conditions."`
+	When                   []string                 `json:"when,omitempty" yaml:"when,omitempty" jsonschema_description:"Set manual and status condition (ex: 'success')."` //This is used only for manual and success condition
+	PipelineName           string                   `json:"pipeline,omitempty" yaml:"pipeline,omitempty" jsonschema_description:"The name of a pipeline used for pipeline node."`
+	ApplicationName        string                   `json:"application,omitempty" yaml:"application,omitempty" jsonschema_description:"The application to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
+	EnvironmentName        string                   `json:"environment,omitempty" yaml:"environment,omitempty" jsonschema_description:"The environment to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
+	ProjectIntegrationName string                   `json:"integration,omitempty" yaml:"integration,omitempty" jsonschema_description:"The integration to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
+	OneAtATime             *bool                    `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty" jsonschema_description:"Set to true if you want to limit the execution of this node to one at a time."`
+	Concurrency            *sdk.WorkflowConcurrency `json:"concurrency,omitempty" yaml:"concurrency,omitempty" jsonschema_description:"Concurrency group of the node runs (ex: group: 'deploy-{{.cds.env.name}}').\nhttps://ovh.github.io/cds/docs/concepts/workflow/concurrency"`
+	Payload                map[string]interface{}   `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Parameters             map[string]string        `json:"parameters,omitempty" yaml:"parameters,omitempty" jsonschema_description:"List of parameters for the workflow."`
+	OutgoingHookModelName  string                   `json:"trigger,omitempty" yaml:"trigger,omitempty"`
+	OutgoingHookConfig     map[string]string        `json:"config,omitempty" yaml:"config,omitempty"`
+	Permissions            map[string]int           `json:"permissions,omitempty" yaml:"permissions,omitempty" jsonschema_description:"The permissions for the node (ex: myGroup: 7).\nhttps://ovh.github.io/cds/docs/concepts/permissions"`
 }
 
 type ConditionEntry struct {
 	PlainConditions []PlainConditionEntry `json:"plain,omitempty" yaml:"check,omitempty"`
 	LuaScript       string                `json:"script,omitempty" yaml:"script,omitempty"`
+	Expression      string                `json:"expression,omitempty" yaml:"expression,omitempty" jsonschema_description:"Condition expression (ex: git.branch == 'master' || git.tag =~ '^v.*')."`
 }
 
-//WorkflowNodeCondition represents a condition to trigger ot not a pipeline in a workflow. Operator can be =, !=, regex
+// WorkflowNodeCondition represents a condition to trigger ot not a pipeline in a workflow. Operator can be =, !=, regex
 type PlainConditionEntry struct {
 	Variable string `json:"variable" yaml:"variable"`
 	Operator string `json:"operator" yaml:"operator"`
@@ -68,7 +71,7 @@ type HookEntry struct {
 
 type ExportOptions func(w sdk.Workflow, exportedWorkflow *Workflow) error
 
-//NewWorkflow creates a new exportable workflow
+// NewWorkflow creates a new exportable workflow
 func NewWorkflow(ctx context.Context, w sdk.Workflow, version string, opts ...ExportOptions) (Workflow, error) {
 	exportedWorkflow := Workflow{}
 	exportedWorkflow.Name = w.Name
@@ -91,6 +94,7 @@ func NewWorkflow(ctx context.Context, w sdk.Workflow, version string, opts ...Ex
 	}
 
 	exportedWorkflow.PurgeTags = w.PurgeTags
+	exportedWorkflow.Concurrency = w.Concurrency
 
 	nodes := w.WorkflowData.Array()
 
@@ -120,7 +124,7 @@ func NewWorkflow(ctx context.Context, w sdk.Workflow, version string, opts ...Ex
 				Conditions: &h.Conditions,
 			}
 
-			if h.Conditions.LuaScript == "" && len(h.Conditions.PlainConditions) == 0 {
+			if h.Conditions.IsEmpty() {
 				pipHook.Conditions = nil
 			}
 
@@ -143,7 +147,7 @@ func NewWorkflow(ctx context.Context, w sdk.Workflow, version string, opts ...Ex
 }
 
 func joinAsNode(n *sdk.Node) bool {
-	return n.Context != nil && (n.Context.Conditions.LuaScript != "" || len(n.Context.Conditions.PlainConditions) > 0)
+	return n.Context != nil && !n.Context.Conditions.IsEmpty()
 }
 
 func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
@@ -202,10 +206,11 @@ func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
 			}
 		}
 
-		if len(conditions) > 0 || n.Context.Conditions.LuaScript != "" {
+		if len(conditions) > 0 || n.Context.Conditions.LuaScript != "" || n.Context.Conditions.Expression != "" {
 			entry.Conditions = &ConditionEntry{
 				PlainConditions: make([]PlainConditionEntry, 0, len(conditions)),
 				LuaScript:       n.Context.Conditions.LuaScript,
+				Expression:      n.Context.Conditions.Expression,
 			}
 			for _, c := range conditions {
 				entry.Conditions.PlainConditions = append(entry.Conditions.PlainConditions, PlainConditionEntry{
@@ -229,6 +234,7 @@ func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
 		if n.Context.Mutex {
 			entry.OneAtATime = &n.Context.Mutex
 		}
+		entry.Concurrency = n.Context.Concurrency
 
 		if n.Context.HasDefaultPayload() {
 			enc := dump.NewDefaultEncoder()
@@ -347,6 +353,7 @@ func (w Workflow) GetWorkflow() (*sdk.Workflow, error) {
 		return nil, sdk.WrapError(err, "Unable to check dependencies")
 	}
 	wf.PurgeTags = w.PurgeTags
+	wf.Concurrency = w.Concurrency
 	if len(w.Metadata) > 0 {
 		wf.Metadata = make(map[string]string, len(w.Metadata))
 		for k, v := range w.Metadata {
@@ -419,6 +426,19 @@ func (w Workflow) CheckValidity() error {
 	//Checks map notifications validity
 	mError.Append(CheckWorkflowNotificationsValidity(w))
 
+	if w.Concurrency != nil {
+		if err := w.Concurrency.IsValid(); err != nil {
+			mError.Append(err)
+		}
+	}
+	for name, e := range w.Workflow {
+		if e.Concurrency != nil {
+			if err := e.Concurrency.IsValid(); err != nil {
+				mError.Append(fmt.Errorf("error: invalid concurrency on %s: %v", name, err))
+			}
+		}
+	}
+
 	if mError.IsEmpty() {
 		return nil
 	}
@@ -619,6 +639,7 @@ func (e *NodeEntry) getNode(name string) (*sdk.Node, error) {
 		node.Context.Conditions = sdk.WorkflowNodeConditions{
 			PlainConditions: make([]sdk.WorkflowNodeCondition, 0, len(e.Conditions.PlainConditions)),
 			LuaScript:       e.Conditions.LuaScript,
+			Expression:      e.Conditions.Expression,
 		}
 		for _, c := range e.Conditions.PlainConditions {
 			node.Context.Conditions.PlainConditions = append(node.Context.Conditions.PlainConditions, sdk.WorkflowNodeCondition{
@@ -661,6 +682,7 @@ func (e *NodeEntry) getNode(name string) (*sdk.Node, error) {
 	if e.OneAtATime != nil {
 		node.Context.Mutex = *e.OneAtATime
 	}
+	node.Context.Concurrency = e.Concurrency
 
 	if e.OutgoingHookModelName != "" {
 		node.Type = sdk.NodeTypeOutGoingHook
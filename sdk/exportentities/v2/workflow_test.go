@@ -120,6 +120,20 @@ func TestWorkflow_checkValidity(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Should raise an error because of a concurrency without group",
+			fields: fields{
+				Name:    "myWorkflow",
+				Version: exportentities.WorkflowVersion2,
+				Workflow: map[string]v2.NodeEntry{
+					"root": {
+						PipelineName: "pipeline",
+						Concurrency:  &sdk.WorkflowConcurrency{CancelInProgress: true},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1025,6 +1039,25 @@ workflow:
     depends_on:
     - p1
     pipeline: env
+`,
+		}, {
+			name: "test with concurrency groups",
+			yaml: `name: concurrency
+version: v2.0
+workflow:
+  build:
+    pipeline: build
+  deploy:
+    depends_on:
+    - build
+    when:
+    - success
+    pipeline: deploy
+    concurrency:
+      group: deploy-{{.cds.env.name}}
+concurrency:
+  group: '{{.git.branch}}'
+  cancel_in_progress: true
 `,
 		},
 	}
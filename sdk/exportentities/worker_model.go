@@ -16,12 +16,14 @@ type WorkerModel struct {
 	Description   string            `json:"description" yaml:"description"`
 	Type          string            `json:"type" yaml:"type"`
 	Flavor        string            `json:"flavor,omitempty" yaml:"flavor,omitempty"`
+	Provision     string            `json:"provision,omitempty" yaml:"provision,omitempty"`
 	Envs          map[string]string `json:"envs,omitempty" yaml:"envs,omitempty"`
 	PatternName   string            `json:"pattern_name,omitempty" yaml:"pattern_name,omitempty"`
 	Shell         string            `json:"shell,omitempty" yaml:"shell,omitempty"`
 	PreCmd        string            `json:"pre_cmd,omitempty" yaml:"pre_cmd,omitempty"`
 	Cmd           string            `json:"cmd,omitempty" yaml:"cmd,omitempty"`
 	PostCmd       string            `json:"post_cmd,omitempty" yaml:"post_cmd,omitempty"`
+	PodTemplate   string            `json:"pod_template,omitempty" yaml:"pod_template,omitempty"`
 	Restricted    bool              `json:"restricted,omitempty" yaml:"restricted,omitempty"`
 	IsDeprecated  bool              `json:"is_deprecated,omitempty" yaml:"is_deprecated,omitempty"`
 }
@@ -40,6 +42,7 @@ func loadWorkerModelWithoutAdminFields(_ sdk.Model, wm *WorkerModel) error {
 	wm.Cmd = ""
 	wm.PostCmd = ""
 	wm.Envs = nil
+	wm.PodTemplate = ""
 	return nil
 }
 
@@ -62,6 +65,7 @@ func NewWorkerModel(wm sdk.Model, opts ...WorkerModelOption) WorkerModel {
 		model.Image = wm.ModelDocker.Image
 		model.Cmd = wm.ModelDocker.Cmd
 		model.Envs = wm.ModelDocker.Envs
+		model.PodTemplate = wm.ModelDocker.PodTemplate
 		if wm.ModelDocker.Private {
 			model.Registry = wm.ModelDocker.Registry
 			model.Username = wm.ModelDocker.Username
@@ -70,6 +74,7 @@ func NewWorkerModel(wm sdk.Model, opts ...WorkerModelOption) WorkerModel {
 	case sdk.VSphere, sdk.Openstack:
 		model.Flavor = wm.ModelVirtualMachine.Flavor
 		model.Image = wm.ModelVirtualMachine.Image
+		model.Provision = wm.ModelVirtualMachine.Provision
 		model.PreCmd = wm.ModelVirtualMachine.PreCmd
 		model.Cmd = wm.ModelVirtualMachine.Cmd
 		model.PostCmd = wm.ModelVirtualMachine.PostCmd
@@ -100,10 +105,11 @@ func (wm WorkerModel) GetWorkerModel() sdk.Model {
 	switch wm.Type {
 	case sdk.Docker:
 		model.ModelDocker = sdk.ModelDocker{
-			Shell: wm.Shell,
-			Image: wm.Image,
-			Cmd:   wm.Cmd,
-			Envs:  wm.Envs,
+			Shell:       wm.Shell,
+			Image:       wm.Image,
+			Cmd:         wm.Cmd,
+			Envs:        wm.Envs,
+			PodTemplate: wm.PodTemplate,
 		}
 		if wm.Username != "" || wm.Registry != "" || wm.Password != "" {
 			model.ModelDocker.Registry = wm.Registry
@@ -113,11 +119,12 @@ func (wm WorkerModel) GetWorkerModel() sdk.Model {
 		}
 	case sdk.VSphere, sdk.Openstack:
 		model.ModelVirtualMachine = sdk.ModelVirtualMachine{
-			Image:   wm.Image,
-			Flavor:  wm.Flavor,
-			Cmd:     wm.Cmd,
-			PostCmd: wm.PostCmd,
-			PreCmd:  wm.PreCmd,
+			Image:     wm.Image,
+			Flavor:    wm.Flavor,
+			Provision: wm.Provision,
+			Cmd:       wm.Cmd,
+			PostCmd:   wm.PostCmd,
+			PreCmd:    wm.PreCmd,
 		}
 	}
 
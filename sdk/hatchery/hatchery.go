@@ -65,7 +65,7 @@ func Create(ctx context.Context, h Interface) error {
 		return fmt.Errorf("Create> Init error: %v", err)
 	}
 
-	var chanRegister, chanGetModels <-chan time.Time
+	var chanRegister, chanGetModels, chanWarmPools <-chan time.Time
 	var modelType string
 
 	hWithModels, isWithModels := h.(InterfaceWithModels)
@@ -81,6 +81,9 @@ func Create(ctx context.Context, h Interface) error {
 		// using time.Tick leaks the underlying ticker but we don't care about it because it is an endless function
 		chanRegister = time.Tick(time.Duration(h.Configuration().Provision.RegisterFrequency) * time.Second) // nolint
 		chanGetModels = time.Tick(10 * time.Second)                                                          // nolint
+		if len(h.Configuration().Provision.WarmPools) > 0 {
+			chanWarmPools = time.Tick(10 * time.Second) // nolint
+		}
 
 		modelType = hWithModels.ModelType()
 	}
@@ -199,13 +202,6 @@ func Create(ctx context.Context, h Interface) error {
 				continue
 			}
 
-			//Check if hatchery if able to start a new worker
-			if !checkCapacities(ctx, h) {
-				log.Info(ctx, "hatchery %s is not able to provision new worker", h.Service().Name)
-				endTrace("no capacities")
-				continue
-			}
-
 			workerRequest := workerStarterRequest{
 				ctx:               currentCtx,
 				cancel:            endTrace,
@@ -248,6 +244,19 @@ func Create(ctx context.Context, h Interface) error {
 				continue
 			}
 
+			// An idle worker of a warm pool can take the job without spawning a new worker
+			if chosenModel != nil && assignWarmWorker(currentCtx, h, j.ID, j.Job.Action.Requirements, chosenModel) {
+				endTrace("assigned to an idle worker")
+				continue
+			}
+
+			//Check if hatchery if able to start a new worker
+			if !checkCapacities(ctx, h) {
+				log.Info(ctx, "hatchery %s is not able to provision new worker", h.Service().Name)
+				endTrace("no capacities")
+				continue
+			}
+
 			if chosenModel != nil {
 				//We got a model, let's start a worker
 				workerRequest.model = chosenModel
@@ -261,6 +270,11 @@ func Create(ctx context.Context, h Interface) error {
 			if err := workerRegister(ctx, hWithModels, workersStartChan); err != nil {
 				log.Warning(ctx, "Error on workerRegister: %s", err)
 			}
+
+		case <-chanWarmPools:
+			if err := provisionWarmPools(ctx, hWithModels, workersStartChan); err != nil {
+				log.Warning(ctx, "Error on provisionWarmPools: %s", err)
+			}
 		}
 	}
 }
@@ -353,8 +367,8 @@ func canRunJobWithModel(ctx context.Context, h InterfaceWithModels, j workerStar
 			}
 		}
 
-		// service and memory requirements are only supported by docker model
-		if model.Type != sdk.Docker && (r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement) {
+		// service and memory requirements are only supported by docker model, services can also be started by the workers
+		if model.Type != sdk.Docker && (r.Type == sdk.MemoryRequirement || (r.Type == sdk.ServiceRequirement && !h.Configuration().Provision.LocalServices)) {
 			log.Debug("canRunJob> %d - job %d - job with service requirement or memory requirement: only for model docker. current model:%s", j.timestamp, j.id, model.Type)
 			return false
 		}
@@ -45,7 +45,7 @@ func WorkerPool(ctx context.Context, h Interface, status ...string) ([]sdk.Worke
 				startedWorkers = append(startedWorkers[:i], startedWorkers[i+1:]...)
 				found = true
 
-				if strings.HasPrefix(w.Name, "register-") {
+				if strings.HasPrefix(w.Name, registerWorkerPrefix) {
 					registeredWorkers[k].Status = sdk.StatusWorkerRegistering
 				}
 
@@ -78,7 +78,7 @@ func WorkerPool(ctx context.Context, h Interface, status ...string) ([]sdk.Worke
 			continue // worker is registered
 		}
 
-		if strings.HasPrefix(w, "register-") {
+		if strings.HasPrefix(w, registerWorkerPrefix) {
 			status = sdk.StatusWorkerRegistering
 		}
 
@@ -2,9 +2,15 @@ package hatchery
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"go.opencensus.io/stats"
+
+	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -57,3 +63,267 @@ func checkCapacities(ctx context.Context, h Interface) bool {
 
 	return true
 }
+
+// warmWorkers contains the workers spawned by the hatchery for warm pools, by worker name.
+var warmWorkers = struct {
+	sync.Mutex
+	workers map[string]*warmWorker
+}{workers: make(map[string]*warmWorker)}
+
+type warmWorker struct {
+	modelID int64
+	since   time.Time // spawn date, or the date the worker was seen for the first time
+	started bool      // true once the worker has been spawned by the orchestrator
+}
+
+type warmPoolStatus struct {
+	config    service.HatcheryWarmPool
+	model     *sdk.Model
+	nbWorkers int          // idle and starting workers
+	expired   []sdk.Worker // idle workers to recycle
+}
+
+func isIdleWarmWorker(w sdk.Worker) bool {
+	return strings.HasPrefix(w.Name, warmWorkerPrefix) && w.Status == sdk.StatusWaiting && w.JobRunID == nil
+}
+
+// computeWarmPools returns the status of the warm pools configured for given models, and forgets the workers
+// that left their pool: the workers that took a job and the removed ones.
+func computeWarmPools(now time.Time, pools []service.HatcheryWarmPool, models []sdk.Model, workerPool []sdk.Worker) []warmPoolStatus {
+	warmWorkers.Lock()
+	defer warmWorkers.Unlock()
+
+	res := make([]warmPoolStatus, 0, len(pools))
+	byModelID := make(map[int64]*warmPoolStatus, len(pools))
+	for _, p := range pools {
+		for i := range models {
+			if p.Model == models[i].Group.Name+"/"+models[i].Name {
+				res = append(res, warmPoolStatus{config: p, model: &models[i]})
+				break
+			}
+		}
+	}
+	for i := range res {
+		byModelID[res[i].model.ID] = &res[i]
+	}
+
+	inPool := make(map[string]struct{}, len(workerPool))
+	for _, w := range workerPool {
+		if !strings.HasPrefix(w.Name, warmWorkerPrefix) {
+			continue
+		}
+		inPool[w.Name] = struct{}{}
+		ww, tracked := warmWorkers.workers[w.Name]
+
+		switch {
+		case isIdleWarmWorker(w):
+			if w.ModelID == nil {
+				continue
+			}
+			if !tracked {
+				ww = &warmWorker{modelID: *w.ModelID, since: now}
+				warmWorkers.workers[w.Name] = ww
+			}
+			ww.started = true
+			s, ok := byModelID[ww.modelID]
+			if !ok {
+				continue
+			}
+			if s.config.MaxIdleTime > 0 && now.Sub(ww.since) > time.Duration(s.config.MaxIdleTime)*time.Second {
+				s.expired = append(s.expired, w)
+				delete(warmWorkers.workers, w.Name)
+				continue
+			}
+			s.nbWorkers++
+		case w.Status == sdk.StatusWorkerPending || w.Status == sdk.StatusWorkerRegistering:
+			if !tracked {
+				continue
+			}
+			ww.started = true
+			if s, ok := byModelID[ww.modelID]; ok {
+				s.nbWorkers++
+			}
+		default:
+			// The worker has taken a job or has been disabled, it is not part of its pool anymore
+			delete(warmWorkers.workers, w.Name)
+		}
+	}
+
+	for name, ww := range warmWorkers.workers {
+		if _, ok := inPool[name]; ok {
+			continue
+		}
+		if ww.started {
+			delete(warmWorkers.workers, name)
+			continue
+		}
+		if s, ok := byModelID[ww.modelID]; ok {
+			s.nbWorkers++
+		}
+	}
+
+	return res
+}
+
+// provisionWarmPools is called by a ticker. It recycles the idle workers of the warm pools that exceeded
+// their max idle time and asks for new workers until each pool has its configured size.
+func provisionWarmPools(ctx context.Context, h InterfaceWithModels, startWorkerChan chan<- workerStarterRequest) error {
+	pools := h.Configuration().Provision.WarmPools
+	if len(pools) == 0 {
+		return nil
+	}
+
+	workerPool, err := WorkerPool(ctx, h)
+	if err != nil {
+		return err
+	}
+
+	var nbWorkers, nbIdle int
+	for _, w := range workerPool {
+		if w.Status != sdk.StatusDisabled {
+			nbWorkers++
+		}
+		if isIdleWarmWorker(w) {
+			nbIdle++
+		}
+	}
+	stats.Record(ctx, GetMetrics().IdleWarmWorkers.M(int64(nbIdle)))
+	capacity := h.Configuration().Provision.MaxWorker - nbWorkers - int(atomic.LoadInt64(&nbWorkerToStart))
+
+	for _, s := range computeWarmPools(time.Now(), pools, models, workerPool) {
+		for _, w := range s.expired {
+			log.Info(ctx, "hatchery> provisionWarmPools> recycling idle worker %s of model %s", w.Name, s.config.Model)
+			if err := h.CDSClient().WorkerDisable(ctx, w.ID); err != nil {
+				log.Error(ctx, "hatchery> provisionWarmPools> unable to disable worker %s: %v", w.Name, err)
+				continue
+			}
+			stats.Record(ctx, GetMetrics().RecycledWarmWorkers.M(1))
+		}
+
+		if s.model.Type != h.ModelType() || h.NeedRegistration(ctx, s.model) || s.model.NbSpawnErr > 5 {
+			log.Debug("hatchery> provisionWarmPools> cannot spawn worker for model %s", s.config.Model)
+			continue
+		}
+
+		for i := s.nbWorkers; i < s.config.Size && capacity > 0; i++ {
+			name := generateWorkerName(h.Service().Name, warmWorkerPrefix, s.config.Model)
+			warmWorkers.Lock()
+			warmWorkers.workers[name] = &warmWorker{modelID: s.model.ID, since: time.Now()}
+			warmWorkers.Unlock()
+			capacity--
+
+			log.Info(ctx, "hatchery> provisionWarmPools> spawning idle worker %s of model %s", name, s.config.Model)
+			startWorkerChan <- workerStarterRequest{
+				warmWorkerModel: s.model,
+				workerName:      name,
+			}
+		}
+	}
+	return nil
+}
+
+func spawnWarmWorker(ctx context.Context, h Interface, m *sdk.Model, workerName string) {
+	forget := func() {
+		warmWorkers.Lock()
+		delete(warmWorkers.workers, workerName)
+		warmWorkers.Unlock()
+	}
+
+	atomic.AddInt64(&nbWorkerToStart, 1)
+	defer atomic.AddInt64(&nbWorkerToStart, -1)
+
+	arg := SpawnArguments{
+		WorkerName:   workerName,
+		Model:        m,
+		Warm:         true,
+		HatcheryName: h.Service().Name,
+	}
+
+	// Get a JWT to authentified the worker
+	jwt, err := NewWorkerToken(h.Service().Name, h.GetPrivateKey(), time.Now().Add(1*time.Hour), arg)
+	if err != nil {
+		log.Error(ctx, "hatchery> spawnWarmWorker> cannot get a token for worker %s: %v", workerName, err)
+		forget()
+		return
+	}
+	arg.WorkerToken = jwt
+
+	if err := h.SpawnWorker(ctx, arg); err != nil {
+		log.Warning(ctx, "hatchery> spawnWarmWorker> cannot spawn worker %s: %v", workerName, err)
+		var spawnError = sdk.SpawnErrorForm{
+			Error: fmt.Sprintf("cannot spawn idle worker: %v", err),
+		}
+		if err := h.CDSClient().WorkerModelSpawnError(m.Group.Name, m.Name, spawnError); err != nil {
+			log.Error(ctx, "hatchery> spawnWarmWorker> error on call client.WorkerModelSpawnError on worker model %s: %s", m.Name, err)
+		}
+		forget()
+		return
+	}
+
+	warmWorkers.Lock()
+	if ww, ok := warmWorkers.workers[workerName]; ok {
+		ww.started = true
+	}
+	warmWorkers.Unlock()
+}
+
+// canUseWarmWorker returns false if the job needs a worker spawned for it: services, memory, volumes and hostname
+// are set when the worker is spawned, an idle worker can't fulfill them.
+func canUseWarmWorker(requirements []sdk.Requirement) bool {
+	for _, r := range requirements {
+		switch r.Type {
+		case sdk.ServiceRequirement, sdk.MemoryRequirement, sdk.VolumeRequirement, sdk.HostnameRequirement:
+			return false
+		}
+	}
+	return true
+}
+
+// assignWarmWorker assigns a job to an idle worker of the warm pool of given model. It returns false if
+// the job needs a dedicated worker, if the model has no warm pool, if there is no idle worker or if the
+// job cannot be assigned.
+func assignWarmWorker(ctx context.Context, h Interface, jobID int64, requirements []sdk.Requirement, model *sdk.Model) bool {
+	if !canUseWarmWorker(requirements) {
+		return false
+	}
+
+	var hasPool bool
+	for _, p := range h.Configuration().Provision.WarmPools {
+		if p.Size > 0 && p.Model == model.Group.Name+"/"+model.Name {
+			hasPool = true
+			break
+		}
+	}
+	if !hasPool {
+		return false
+	}
+
+	workerPool, err := WorkerPool(ctx, h, sdk.StatusWaiting)
+	if err != nil {
+		log.Error(ctx, "hatchery> assignWarmWorker> Pool> Error: %v", err)
+		return false
+	}
+
+	for _, w := range workerPool {
+		if !isIdleWarmWorker(w) || w.ModelID == nil || *w.ModelID != model.ID {
+			continue
+		}
+		if err := h.CDSClient().QueueJobAssign(ctx, jobID, w.ID); err != nil {
+			log.Info(ctx, "hatchery> assignWarmWorker> cannot assign job %d to worker %s: %v", jobID, w.Name, err)
+			return false
+		}
+
+		warmWorkers.Lock()
+		delete(warmWorkers.workers, w.Name)
+		warmWorkers.Unlock()
+
+		stats.Record(ctx, GetMetrics().AssignedWarmWorkers.M(1))
+		SendSpawnInfo(ctx, h, jobID, sdk.SpawnMsg{
+			ID:   sdk.MsgSpawnInfoHatcheryAssignsWarmWorker.ID,
+			Args: []interface{}{h.Service().Name, w.Name},
+		})
+		log.Info(ctx, "hatchery> assignWarmWorker> job %d assigned to idle worker %s", jobID, w.Name)
+		return true
+	}
+	return false
+}
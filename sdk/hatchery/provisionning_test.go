@@ -0,0 +1,70 @@
+package hatchery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_computeWarmPools(t *testing.T) {
+	warmWorkers.workers = make(map[string]*warmWorker)
+	defer func() { warmWorkers.workers = make(map[string]*warmWorker) }()
+
+	now := time.Now()
+	debianID, alpineID := int64(1), int64(2)
+	models := []sdk.Model{
+		{ID: debianID, Name: "debian10", Group: &sdk.Group{Name: sdk.SharedInfraGroupName}},
+		{ID: alpineID, Name: "alpine", Group: &sdk.Group{Name: sdk.SharedInfraGroupName}},
+	}
+	pools := []service.HatcheryWarmPool{
+		{Model: "shared.infra/debian10", Size: 3, MaxIdleTime: 600},
+		{Model: "shared.infra/unknown", Size: 1},
+	}
+
+	jobID := int64(42)
+	warmWorkers.workers["warm-debian-starting"] = &warmWorker{modelID: debianID, since: now}
+	warmWorkers.workers["warm-debian-pending"] = &warmWorker{modelID: debianID, since: now, started: true}
+	warmWorkers.workers["warm-debian-expired"] = &warmWorker{modelID: debianID, since: now.Add(-time.Hour), started: true}
+	warmWorkers.workers["warm-debian-building"] = &warmWorker{modelID: debianID, since: now, started: true}
+	warmWorkers.workers["warm-debian-removed"] = &warmWorker{modelID: debianID, since: now, started: true}
+	workerPool := []sdk.Worker{
+		{Name: "warm-debian-pending", Status: sdk.StatusWorkerPending},
+		{Name: "warm-debian-expired", Status: sdk.StatusWaiting, ModelID: &debianID},
+		{Name: "warm-debian-building", Status: sdk.StatusBuilding, ModelID: &debianID, JobRunID: &jobID},
+		{Name: "warm-debian-after-restart", Status: sdk.StatusWaiting, ModelID: &debianID},
+		{Name: "warm-alpine-idle", Status: sdk.StatusWaiting, ModelID: &alpineID},
+		{Name: "hatchery-debian-for-job", Status: sdk.StatusWaiting, ModelID: &debianID, JobRunID: &jobID},
+	}
+
+	res := computeWarmPools(now, pools, models, workerPool)
+	require.Len(t, res, 1, "pools of unknown models should be ignored")
+	assert.Equal(t, debianID, res[0].model.ID)
+	assert.Equal(t, 3, res[0].nbWorkers, "starting, pending and idle workers should be counted")
+	require.Len(t, res[0].expired, 1)
+	assert.Equal(t, "warm-debian-expired", res[0].expired[0].Name)
+
+	assert.Contains(t, warmWorkers.workers, "warm-debian-starting")
+	assert.Contains(t, warmWorkers.workers, "warm-debian-after-restart", "an idle worker unknown by the hatchery should be tracked")
+	assert.NotContains(t, warmWorkers.workers, "warm-debian-building", "a worker that took a job leaves its pool")
+	assert.NotContains(t, warmWorkers.workers, "warm-debian-removed")
+	assert.NotContains(t, warmWorkers.workers, "warm-debian-expired")
+}
+
+func Test_canUseWarmWorker(t *testing.T) {
+	assert.True(t, canUseWarmWorker(nil))
+	assert.True(t, canUseWarmWorker([]sdk.Requirement{
+		{Name: "bash", Type: sdk.BinaryRequirement, Value: "bash"},
+		{Name: "shared.infra/debian10", Type: sdk.ModelRequirement, Value: "shared.infra/debian10"},
+	}))
+	for _, typ := range []string{sdk.ServiceRequirement, sdk.MemoryRequirement, sdk.VolumeRequirement, sdk.HostnameRequirement} {
+		assert.False(t, canUseWarmWorker([]sdk.Requirement{
+			{Name: "bash", Type: sdk.BinaryRequirement, Value: "bash"},
+			{Name: typ, Type: typ, Value: "value"},
+		}), "a job with a %s requirement needs a dedicated worker", typ)
+	}
+}
@@ -27,6 +27,8 @@ type workerStarterRequest struct {
 	timestamp           int64
 	workflowNodeRunID   int64
 	registerWorkerModel *sdk.Model
+	warmWorkerModel     *sdk.Model
+	workerName          string
 }
 
 func PanicDump(h Interface) func(s string) (io.WriteCloser, error) {
@@ -58,6 +60,13 @@ func startWorkerStarters(ctx context.Context, h Interface) chan<- workerStarterR
 
 func workerStarter(ctx context.Context, h Interface, workerNum string, jobs <-chan workerStarterRequest) {
 	for j := range jobs {
+		// Start an idle worker for a warm pool
+		if m := j.warmWorkerModel; m != nil {
+			log.Debug("Spawning idle worker %s for model %s", j.workerName, m.Name)
+			spawnWarmWorker(ctx, h, m, j.workerName)
+			continue
+		}
+
 		// Start a worker for a job
 		if m := j.registerWorkerModel; m == nil {
 			_ = spawnWorkerForJob(ctx, h, j)
@@ -73,7 +82,7 @@ func workerStarter(ctx context.Context, h Interface, workerNum string, jobs <-ch
 			// this counter is reset with func workerRegister
 			atomic.AddInt64(&nbRegisteringWorkerModels, 1)
 			arg := SpawnArguments{
-				WorkerName:   generateWorkerName(h.Service().Name, true, m.Name),
+				WorkerName:   generateWorkerName(h.Service().Name, registerWorkerPrefix, m.Name),
 				Model:        m,
 				RegisterOnly: true,
 				HatcheryName: h.Service().Name,
@@ -171,7 +180,7 @@ func spawnWorkerForJob(ctx context.Context, h Interface, j workerStarterRequest)
 
 	_, next = observability.Span(ctxJob, "hatchery.SpawnWorker")
 	arg := SpawnArguments{
-		WorkerName:   generateWorkerName(h.Service().Name, false, modelName),
+		WorkerName:   generateWorkerName(h.Service().Name, "", modelName),
 		Model:        j.model,
 		JobID:        j.id,
 		Requirements: j.requirements,
@@ -226,13 +235,14 @@ func spawnWorkerForJob(ctx context.Context, h Interface, j workerStarterRequest)
 	return true // ok for this job
 }
 
-// a worker name must be 60 char max, without '.' and '_', "/" -> replaced by '-'
-func generateWorkerName(hatcheryName string, isRegister bool, modelName string) string {
-	prefix := ""
-	if isRegister {
-		prefix = "register-"
-	}
+// Prefixes of the names of the workers that are not spawned for a job
+const (
+	registerWorkerPrefix = "register-"
+	warmWorkerPrefix     = "warm-"
+)
 
+// a worker name must be 60 char max, without '.' and '_', "/" -> replaced by '-'
+func generateWorkerName(hatcheryName string, prefix string, modelName string) string {
 	maxLength := 63
 	hName := hatcheryName + "-"
 	random := namesgenerator.GetRandomNameCDS(0)
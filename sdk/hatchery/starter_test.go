@@ -8,7 +8,7 @@ import (
 func Test_generateWorkerName(t *testing.T) {
 	type args struct {
 		hatcheryName string
-		isRegister   bool
+		prefix       string
 		model        string
 	}
 	tests := []struct {
@@ -18,28 +18,33 @@ func Test_generateWorkerName(t *testing.T) {
 	}{
 		{
 			name: "simple",
-			args: args{hatcheryName: "p999-prod", isRegister: true, model: "shared.infra-rust-official-1.41"},
+			args: args{hatcheryName: "p999-prod", prefix: registerWorkerPrefix, model: "shared.infra-rust-official-1.41"},
 			want: "register-p999-prod-shared-infra-ru",
 		},
 		{
 			name: "simple special char",
-			args: args{hatcheryName: "p999/prod", isRegister: true, model: "shared.infra-rust-official-1.41"},
+			args: args{hatcheryName: "p999/prod", prefix: registerWorkerPrefix, model: "shared.infra-rust-official-1.41"},
 			want: "register-p999-prod-shared-infra-ru",
 		},
 		{
 			name: "long hatchery name",
-			args: args{hatcheryName: "p999-prod-xxxx-xxxx-xxxx-xxxx-xxxx", isRegister: true, model: "shared.infra-rust-official-1.41"},
+			args: args{hatcheryName: "p999-prod-xxxx-xxxx-xxxx-xxxx-xxxx", prefix: registerWorkerPrefix, model: "shared.infra-rust-official-1.41"},
 			want: "register-shared-infra-ru",
 		},
+		{
+			name: "warm worker",
+			args: args{hatcheryName: "hname", prefix: warmWorkerPrefix, model: "shared.infra/debian10"},
+			want: "warm-hname-shared-infra-debian10",
+		},
 		{
 			name: "long model name",
-			args: args{hatcheryName: "hname", isRegister: true, model: "shared.infra-rust-official-1.41-xxx-xxx-xxx-xxx"},
+			args: args{hatcheryName: "hname", prefix: registerWorkerPrefix, model: "shared.infra-rust-official-1.41-xxx-xxx-xxx-xxx"},
 			want: "register-hname-shared-infra-ru",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := generateWorkerName(tt.args.hatcheryName, tt.args.isRegister, tt.args.model)
+			got := generateWorkerName(tt.args.hatcheryName, tt.args.prefix, tt.args.model)
 			if len(got) > 64 {
 				t.Errorf("len must be < 64() = %d - got:%s", len(got), got)
 			}
@@ -33,6 +33,9 @@ func initMetrics() error {
 		metrics.CheckingWorkers = stats.Int64("cds/checking_workers", "number of checking workers", stats.UnitDimensionless)
 		metrics.BuildingWorkers = stats.Int64("cds/building_workers", "number of building workers", stats.UnitDimensionless)
 		metrics.DisabledWorkers = stats.Int64("cds/disabled_workers", "number of disabled workers", stats.UnitDimensionless)
+		metrics.IdleWarmWorkers = stats.Int64("cds/idle_warm_workers", "number of idle workers in warm pools", stats.UnitDimensionless)
+		metrics.AssignedWarmWorkers = stats.Int64("cds/assigned_warm_workers", "number of jobs assigned to idle workers of warm pools", stats.UnitDimensionless)
+		metrics.RecycledWarmWorkers = stats.Int64("cds/recycled_warm_workers", "number of idle workers of warm pools recycled after their max idle time", stats.UnitDimensionless)
 
 		tags := []tag.Key{observability.MustNewKey(observability.TagServiceType), observability.MustNewKey(observability.TagServiceName)}
 		err = observability.RegisterView(
@@ -45,6 +48,9 @@ func initMetrics() error {
 			observability.NewViewLast("cds/hatchery/checking_workers", metrics.CheckingWorkers, tags),
 			observability.NewViewLast("cds/hatchery/building_workers", metrics.BuildingWorkers, tags),
 			observability.NewViewLast("cds/hatchery/disabled_workers", metrics.DisabledWorkers, tags),
+			observability.NewViewLast("cds/hatchery/idle_warm_workers", metrics.IdleWarmWorkers, tags),
+			observability.NewViewCount("cds/hatchery/assigned_warm_worker_count", metrics.AssignedWarmWorkers, tags),
+			observability.NewViewCount("cds/hatchery/recycled_warm_worker_count", metrics.RecycledWarmWorkers, tags),
 		)
 	})
 	return err
@@ -67,6 +67,7 @@ type SpawnArguments struct {
 	JobID        int64             `json:"job_id"`
 	Requirements []sdk.Requirement `json:"requirements"`
 	RegisterOnly bool              `json:"register_only"`
+	Warm         bool              `json:"warm,omitempty"`
 	HatcheryName string            `json:"hatchery_name"`
 }
 
@@ -112,13 +113,16 @@ type InterfaceWithModels interface {
 }
 
 type Metrics struct {
-	Jobs               *stats.Int64Measure
-	JobsSSE            *stats.Int64Measure
-	SpawnedWorkers     *stats.Int64Measure
-	PendingWorkers     *stats.Int64Measure
-	RegisteringWorkers *stats.Int64Measure
-	CheckingWorkers    *stats.Int64Measure
-	WaitingWorkers     *stats.Int64Measure
-	BuildingWorkers    *stats.Int64Measure
-	DisabledWorkers    *stats.Int64Measure
+	Jobs                *stats.Int64Measure
+	JobsSSE             *stats.Int64Measure
+	SpawnedWorkers      *stats.Int64Measure
+	PendingWorkers      *stats.Int64Measure
+	RegisteringWorkers  *stats.Int64Measure
+	CheckingWorkers     *stats.Int64Measure
+	WaitingWorkers      *stats.Int64Measure
+	BuildingWorkers     *stats.Int64Measure
+	DisabledWorkers     *stats.Int64Measure
+	IdleWarmWorkers     *stats.Int64Measure
+	AssignedWarmWorkers *stats.Int64Measure
+	RecycledWarmWorkers *stats.Int64Measure
 }
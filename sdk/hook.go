@@ -17,8 +17,16 @@ const (
 	HookConfigTargetHook          = "target_hook"
 	HookConfigWorkflowID          = "workflow_id"
 	HookConfigWebHookID           = "webHookID"
+	HookConfigWebHookSecret       = "webHookSecret"
 	HookConfigVCSServer           = "vcsServer"
 	HookConfigEventFilter         = "eventFilter"
+	HookConfigPathFilterInclude   = "pathFilterInclude"
+	HookConfigPathFilterExclude   = "pathFilterExclude"
+	HookConfigPullRequestRef      = "pullRequestRef"
+	HookConfigPullRequestForks    = "pullRequestForks"
+	HookConfigForkSecrets         = "pullRequestForkSecrets"
+	HookConfigMergeQueue          = "mergeQueue"
+	HookConfigMergeQueueDepth     = "mergeQueueDepth"
 	HookConfigRepoFullName        = "repoFullName"
 	HookConfigModelType           = "model_type"
 	HookConfigModelName           = "model_name"
@@ -38,6 +46,15 @@ const (
 	RabbitMQHookModelConsumerTag  = "consumer_tag"
 )
 
+// Values of the pull request configuration of the repository hooks
+const (
+	HookPullRequestRefHead   = "head"
+	HookPullRequestRefMerge  = "merge"
+	HookPullRequestForkAllow = "allow"
+	HookPullRequestForkDeny  = "deny"
+	HookMergeQueueDisabled   = "disabled"
+)
+
 // Here are the default hooks
 var (
 	BuiltinHookModels = []*WorkflowHookModel{
@@ -143,6 +160,44 @@ var (
 				Configurable: false,
 				Type:         HookConfigTypeString,
 			},
+			HookConfigPathFilterInclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigPathFilterExclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigPullRequestRef: {
+				Value:              HookPullRequestRefHead,
+				Configurable:       true,
+				Type:               HookConfigTypeMultiChoice,
+				MultipleChoiceList: []string{HookPullRequestRefHead, HookPullRequestRefMerge},
+			},
+			HookConfigPullRequestForks: {
+				Value:              HookPullRequestForkAllow,
+				Configurable:       true,
+				Type:               HookConfigTypeMultiChoice,
+				MultipleChoiceList: []string{HookPullRequestForkAllow, HookPullRequestForkDeny},
+			},
+			HookConfigForkSecrets: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigMergeQueue: {
+				Value:              HookMergeQueueDisabled,
+				Configurable:       true,
+				Type:               HookConfigTypeMultiChoice,
+				MultipleChoiceList: []string{HookMergeQueueDisabled, VCSMergeMethodMerge, VCSMergeMethodSquash, VCSMergeMethodRebase},
+			},
+			HookConfigMergeQueueDepth: {
+				Value:        "1",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
@@ -158,6 +213,33 @@ var (
 				Configurable: true,
 				Type:         HookConfigTypeString,
 			},
+			HookConfigPathFilterInclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigPathFilterExclude: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
+			HookConfigPullRequestRef: {
+				Value:              HookPullRequestRefHead,
+				Configurable:       true,
+				Type:               HookConfigTypeMultiChoice,
+				MultipleChoiceList: []string{HookPullRequestRefHead, HookPullRequestRefMerge},
+			},
+			HookConfigPullRequestForks: {
+				Value:              HookPullRequestForkAllow,
+				Configurable:       true,
+				Type:               HookConfigTypeMultiChoice,
+				MultipleChoiceList: []string{HookPullRequestForkAllow, HookPullRequestForkDeny},
+			},
+			HookConfigForkSecrets: {
+				Value:        "",
+				Configurable: true,
+				Type:         HookConfigTypeString,
+			},
 		},
 	}
 
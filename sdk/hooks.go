@@ -12,6 +12,19 @@ type Task struct {
 	NbExecutionsTodo  int                    `json:"nb_executions_todo" cli:"nb_executions_todo"`
 }
 
+// WithoutSecrets returns a copy of the task and of its executions without the secrets of their configuration
+func (t Task) WithoutSecrets() Task {
+	t.Config = t.Config.WithoutSecrets()
+	if t.Executions != nil {
+		execs := make([]TaskExecution, len(t.Executions))
+		for i := range t.Executions {
+			execs[i] = t.Executions[i].WithoutSecrets()
+		}
+		t.Executions = execs
+	}
+	return t
+}
+
 // TaskExecution represents an execution instance of a task. It the task is a webhook; this represents the call of the webhook
 type TaskExecution struct {
 	UUID                string                  `json:"uuid" cli:"uuid,key"`
@@ -30,6 +43,12 @@ type TaskExecution struct {
 	Status              string                  `json:"status" cli:"status"`
 }
 
+// WithoutSecrets returns a copy of the execution without the secrets of its configuration
+func (e TaskExecution) WithoutSecrets() TaskExecution {
+	e.Config = e.Config.WithoutSecrets()
+	return e
+}
+
 // GerritEventExecution contains specific data for a gerrit event execution
 type GerritEventExecution struct {
 	Message []byte `json:"message"`
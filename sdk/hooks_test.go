@@ -0,0 +1,27 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_WithoutSecrets(t *testing.T) {
+	cfg := WorkflowNodeHookConfig{
+		WebHookModelConfigMethod: {Value: "POST"},
+		HookConfigWebHookSecret:  {Value: "my-secret"},
+	}
+	task := Task{
+		UUID:       "uuid",
+		Config:     cfg,
+		Executions: []TaskExecution{{UUID: "uuid", Config: cfg}},
+	}
+
+	res := task.WithoutSecrets()
+	assert.Equal(t, WorkflowNodeHookConfig{WebHookModelConfigMethod: {Value: "POST"}}, res.Config)
+	assert.Equal(t, WorkflowNodeHookConfig{WebHookModelConfigMethod: {Value: "POST"}}, res.Executions[0].Config)
+
+	// The task itself is not modified
+	assert.Equal(t, "my-secret", task.Config[HookConfigWebHookSecret].Value)
+	assert.Equal(t, "my-secret", task.Executions[0].Config[HookConfigWebHookSecret].Value)
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -63,6 +64,7 @@ func init() {
 		"b64enc":       base64encode,
 		"b64dec":       base64decode,
 		"escape":       escape,
+		"hashFiles":    hashFiles,
 	})
 }
 
@@ -360,3 +362,13 @@ func escape(s string) string {
 	s1 = strings.Replace(s1, ".", "-", -1)
 	return s1
 }
+
+// hashFiles is computed by the worker from the files of the job workspace (ex: worker cache push),
+// the expression is kept as is.
+func hashFiles(patterns ...string) string {
+	quoted := make([]string, len(patterns))
+	for i := range patterns {
+		quoted[i] = strconv.Quote(patterns[i])
+	}
+	return "{{ hashFiles " + strings.Join(quoted, " ") + " }}"
+}
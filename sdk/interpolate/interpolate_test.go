@@ -437,6 +437,17 @@ func TestDo(t *testing.T) {
 			want:   `test_myWorkflow_863ddke1`,
 			enable: true,
 		},
+		{
+			name: "hashFiles is kept for the worker",
+			args: args{
+				input: `worker cache push '{{.cds.workflow}}-{{ hashFiles "**/pom.xml" "go.sum" }}' .m2/`,
+				vars: map[string]string{
+					"cds.workflow": "myWorkflow",
+				},
+			},
+			want:   `worker cache push 'myWorkflow-{{ hashFiles "**/pom.xml" "go.sum" }}' .m2/`,
+			enable: true,
+		},
 	}
 	for _, tt := range tests {
 		if !tt.enable {
@@ -1,5 +1,31 @@
 package sdk
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JobMatrixMaxCombinations is the maximum number of job runs that a matrix can generate
+const JobMatrixMaxCombinations = 256
+
+// Failure kinds that can be retried by a JobRetryPolicy
+const (
+	JobRetryOnWorkerLost = "worker_lost"
+	JobRetryOnSpawnError = "spawn_error"
+	JobRetryOnTimeout    = "timeout"
+	JobRetryOnFailure    = "failure"
+)
+
+// JobRetryOnKinds is the list of failure kinds that can be retried
+var JobRetryOnKinds = []string{JobRetryOnWorkerLost, JobRetryOnSpawnError, JobRetryOnTimeout, JobRetryOnFailure}
+
+// JobRetryMaxAttempts is the maximum number of attempts for a job run
+const JobRetryMaxAttempts = 10
+
 // Job is the element of a stage
 type Job struct {
 	PipelineActionID int64                  `json:"pipeline_action_id"`
@@ -8,6 +34,9 @@ type Job struct {
 	LastModified     int64                  `json:"last_modified"`
 	Action           Action                 `json:"action"`
 	Warnings         []PipelineBuildWarning `json:"warnings"`
+	Matrix           JobMatrix              `json:"matrix,omitempty"`
+	RetryPolicy      *JobRetryPolicy        `json:"retry_policy,omitempty"`
+	Timeout          int64                  `json:"timeout,omitempty"` // in seconds
 }
 
 // IsValid returns job's validity.
@@ -16,5 +45,174 @@ func (j Job) IsValid() error {
 		return NewErrorFrom(ErrWrongRequest, "invalid given stage id")
 	}
 
+	if err := j.Matrix.IsValid(); err != nil {
+		return err
+	}
+
+	if j.RetryPolicy != nil {
+		if err := j.RetryPolicy.IsValid(); err != nil {
+			return err
+		}
+	}
+
+	if j.Timeout < 0 {
+		return NewErrorFrom(ErrWrongRequest, "job timeout should be positive")
+	}
+
 	return j.Action.IsValid()
 }
+
+// JobMatrix defines the list of values for each matrix variable of a job.
+// A job run is created for each combination of values.
+type JobMatrix map[string][]string
+
+// Value returns driver.Value from JobMatrix.
+func (m JobMatrix) Value() (driver.Value, error) {
+	j, err := json.Marshal(m)
+	return j, WrapError(err, "cannot marshal JobMatrix")
+}
+
+// Scan job matrix.
+func (m *JobMatrix) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, m), "cannot unmarshal JobMatrix")
+}
+
+// IsValid returns an error if a matrix variable is empty or if there are too many combinations.
+func (m JobMatrix) IsValid() error {
+	nb := 1
+	for k, values := range m {
+		if k == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid matrix variable name")
+		}
+		if len(values) == 0 {
+			return NewErrorFrom(ErrWrongRequest, "matrix variable %s should have at least one value", k)
+		}
+		nb *= len(values)
+		if nb > JobMatrixMaxCombinations {
+			return NewErrorFrom(ErrWrongRequest, "matrix cannot generate more than %d jobs", JobMatrixMaxCombinations)
+		}
+	}
+	return nil
+}
+
+// Keys returns matrix variable names sorted.
+func (m JobMatrix) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Combinations returns all the combinations of matrix values, ordered by sorted variable names.
+// It returns nil for an empty matrix.
+func (m JobMatrix) Combinations() []map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	res := []map[string]string{{}}
+	for _, k := range m.Keys() {
+		next := make([]map[string]string, 0, len(res)*len(m[k]))
+		for _, c := range res {
+			for _, v := range m[k] {
+				combination := make(map[string]string, len(c)+1)
+				for ck, cv := range c {
+					combination[ck] = cv
+				}
+				combination[k] = v
+				next = append(next, combination)
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+// JobMatrixName returns the name of a job run for given matrix values (ex: "build (go=1.13, os=linux)").
+func JobMatrixName(name string, values map[string]string) string {
+	if len(values) == 0 {
+		return name
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + values[k]
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(parts, ", "))
+}
+
+// JobRetryPolicy defines how a job run is retried when it fails.
+// If On is empty, only infrastructure failures (worker lost and spawn error) are retried.
+type JobRetryPolicy struct {
+	MaxAttempts int      `json:"max_attempts" yaml:"max_attempts" jsonschema_description:"Maximum number of attempts, including the first one."`
+	Backoff     int64    `json:"backoff,omitempty" yaml:"backoff,omitempty" jsonschema_description:"Delay in seconds before the second attempt, doubled for each next attempt."`
+	On          []string `json:"on,omitempty" yaml:"on,omitempty" jsonschema_description:"Failure kinds to retry: worker_lost, spawn_error, timeout, failure."`
+}
+
+// Value returns driver.Value from JobRetryPolicy.
+func (p JobRetryPolicy) Value() (driver.Value, error) {
+	j, err := json.Marshal(p)
+	return j, WrapError(err, "cannot marshal JobRetryPolicy")
+}
+
+// Scan job retry policy.
+func (p *JobRetryPolicy) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, p), "cannot unmarshal JobRetryPolicy")
+}
+
+// IsValid checks the max attempts, the backoff and the failure kinds.
+func (p JobRetryPolicy) IsValid() error {
+	if p.MaxAttempts < 1 || p.MaxAttempts > JobRetryMaxAttempts {
+		return NewErrorFrom(ErrWrongRequest, "retry policy max attempts should be between 1 and %d", JobRetryMaxAttempts)
+	}
+	if p.Backoff < 0 {
+		return NewErrorFrom(ErrWrongRequest, "retry policy backoff should be positive")
+	}
+	for _, kind := range p.On {
+		if !IsInArray(kind, JobRetryOnKinds) {
+			return NewErrorFrom(ErrWrongRequest, "invalid retry policy failure kind %s, should be one of %s", kind, strings.Join(JobRetryOnKinds, ", "))
+		}
+	}
+	return nil
+}
+
+// Retries returns true if given failure kind should be retried.
+func (p JobRetryPolicy) Retries(kind string) bool {
+	if len(p.On) == 0 {
+		return kind == JobRetryOnWorkerLost || kind == JobRetryOnSpawnError
+	}
+	return IsInArray(kind, p.On)
+}
+
+// ShouldRetry returns true if a job run that failed with given kind at given attempt (starting at 1)
+// should be retried.
+func (p JobRetryPolicy) ShouldRetry(kind string, attempt int) bool {
+	return p.Retries(kind) && attempt < p.MaxAttempts
+}
+
+// BackoffDuration returns the delay before given attempt (starting at 1).
+func (p JobRetryPolicy) BackoffDuration(attempt int) time.Duration {
+	if p.Backoff == 0 || attempt <= 1 {
+		return 0
+	}
+	return time.Duration(p.Backoff) * time.Second * time.Duration(1<<uint(attempt-2))
+}
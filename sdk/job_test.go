@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobMatrixCombinations(t *testing.T) {
+	m := JobMatrix{
+		"os": {"linux", "windows"},
+		"go": {"1.12", "1.13", "1.14"},
+	}
+	assert.NoError(t, m.IsValid())
+
+	combinations := m.Combinations()
+	assert.Len(t, combinations, 6)
+	assert.Equal(t, map[string]string{"go": "1.12", "os": "linux"}, combinations[0])
+	assert.Equal(t, map[string]string{"go": "1.14", "os": "windows"}, combinations[5])
+	assert.Equal(t, "build (go=1.12, os=linux)", JobMatrixName("build", combinations[0]))
+
+	assert.Nil(t, JobMatrix{}.Combinations())
+	assert.Equal(t, "build", JobMatrixName("build", nil))
+}
+
+func TestJobMatrixIsValid(t *testing.T) {
+	assert.Error(t, JobMatrix{"os": {}}.IsValid())
+
+	values := make([]string, 20)
+	assert.Error(t, JobMatrix{"a": values, "b": values}.IsValid())
+}
+
+func TestJobRetryPolicy(t *testing.T) {
+	p := JobRetryPolicy{MaxAttempts: 3, Backoff: 10}
+	assert.NoError(t, p.IsValid())
+	assert.True(t, p.ShouldRetry(JobRetryOnWorkerLost, 1))
+	assert.True(t, p.ShouldRetry(JobRetryOnSpawnError, 2))
+	assert.False(t, p.ShouldRetry(JobRetryOnWorkerLost, 3))
+	assert.False(t, p.ShouldRetry(JobRetryOnFailure, 1))
+
+	p.On = []string{JobRetryOnFailure}
+	assert.True(t, p.ShouldRetry(JobRetryOnFailure, 1))
+	assert.False(t, p.ShouldRetry(JobRetryOnWorkerLost, 1))
+
+	assert.Equal(t, time.Duration(0), p.BackoffDuration(1))
+	assert.Equal(t, 10*time.Second, p.BackoffDuration(2))
+	assert.Equal(t, 40*time.Second, p.BackoffDuration(4))
+
+	assert.Error(t, JobRetryPolicy{}.IsValid())
+	assert.Error(t, JobRetryPolicy{MaxAttempts: JobRetryMaxAttempts + 1}.IsValid())
+	assert.Error(t, JobRetryPolicy{MaxAttempts: 2, Backoff: -1}.IsValid())
+	assert.Error(t, JobRetryPolicy{MaxAttempts: 2, On: []string{"unknown"}}.IsValid())
+}
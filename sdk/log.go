@@ -41,3 +41,29 @@ type ServiceLog struct {
 	ServiceRequirementName string     `json:"requirement_service_name" db:"requirement_service_name"`
 	Val                    string     `json:"val,omitempty" db:"value"`
 }
+
+// StepLogChunk is a part of a step log pushed by the step log streaming endpoint.
+// Offset is the position in bytes of the value in the step log, it can be given to resume a stream.
+type StepLogChunk struct {
+	JobID     int64  `json:"workflow_node_run_job_id"`
+	StepOrder int64  `json:"step_order"`
+	Offset    int64  `json:"offset"`
+	Val       string `json:"val,omitempty"`
+	Done      bool   `json:"done,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+// WorkflowLogSearchResult is a line of a step log matching a log search.
+type WorkflowLogSearchResult struct {
+	WorkflowName string    `json:"workflow_name" cli:"workflow"`
+	RunNumber    int64     `json:"run_number" cli:"run"`
+	SubNumber    int64     `json:"sub_number" cli:"-"`
+	NodeRunID    int64     `json:"workflow_node_run_id" cli:"-"`
+	NodeName     string    `json:"node_name" cli:"node"`
+	JobID        int64     `json:"workflow_node_run_job_id" cli:"-"`
+	JobName      string    `json:"job_name" cli:"job"`
+	StepOrder    int64     `json:"step_order" cli:"step"`
+	LineNumber   int64     `json:"line_number" cli:"line_number"`
+	Line         string    `json:"line" cli:"line"`
+	Date         time.Time `json:"date" cli:"-"`
+}
@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Statuses of the entries of a merge queue
+const (
+	// MergeQueueStatusQueued is the status of a pull request waiting for its candidate to be built
+	MergeQueueStatusQueued = "Queued"
+	// MergeQueueStatusBuilding is the status of a pull request whose candidate is built by the workflow
+	MergeQueueStatusBuilding = "Building"
+	// MergeQueueStatusPassed is the status of a pull request whose candidate succeeded, it waits for the previous pull requests to be merged
+	MergeQueueStatusPassed = "Passed"
+	MergeQueueStatusMerged = "Merged"
+	// MergeQueueStatusEjected is the status of a pull request removed from the queue because of a failure
+	MergeQueueStatusEjected = "Ejected"
+	// MergeQueueStatusRemoved is the status of a pull request closed or removed by a user
+	MergeQueueStatusRemoved = "Removed"
+)
+
+// MergeQueueDefaultDepth is the default number of candidates built at the same time for a merge queue
+const MergeQueueDefaultDepth = 1
+
+// MergeQueueEntry is a pull request in the merge queue of a workflow.
+// Its candidate is a branch created from the base branch where the previous pull requests of the queue and this one are merged.
+type MergeQueueEntry struct {
+	ID                int64     `json:"id" db:"id" cli:"id,key"`
+	ProjectID         int64     `json:"project_id" db:"project_id" cli:"-"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	HookUUID          string    `json:"hook_uuid" db:"hook_uuid" cli:"-"`
+	Repository        string    `json:"repository" db:"repository" cli:"repository"`
+	BaseBranch        string    `json:"base_branch" db:"base_branch" cli:"base"`
+	PullRequestID     int       `json:"pull_request_id" db:"pull_request_id" cli:"pull_request"`
+	PullRequestTitle  string    `json:"pull_request_title" db:"pull_request_title" cli:"title"`
+	PullRequestAuthor string    `json:"pull_request_author" db:"pull_request_author" cli:"author"`
+	HeadHash          string    `json:"head_hash" db:"head_hash" cli:"-"`
+	Fork              bool      `json:"fork" db:"fork" cli:"-"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Message           string    `json:"message,omitempty" db:"message" cli:"message"`
+	BaseHash          string    `json:"base_hash,omitempty" db:"base_hash" cli:"-"`
+	CandidateBranch   string    `json:"candidate_branch,omitempty" db:"candidate_branch" cli:"-"`
+	CandidateHash     string    `json:"candidate_hash,omitempty" db:"candidate_hash" cli:"-"`
+	WorkflowRunID     int64     `json:"workflow_run_id,omitempty" db:"workflow_run_id" cli:"-"`
+	WorkflowRunNumber int64     `json:"workflow_run_number,omitempty" db:"workflow_run_number" cli:"run"`
+	AuthConsumerID    string    `json:"-" db:"auth_consumer_id" cli:"-"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+	LastModified      time.Time `json:"last_modified" db:"last_modified" cli:"-"`
+}
+
+// IsActive returns true if the pull request is still in the queue
+func (e MergeQueueEntry) IsActive() bool {
+	switch e.Status {
+	case MergeQueueStatusQueued, MergeQueueStatusBuilding, MergeQueueStatusPassed:
+		return true
+	}
+	return false
+}
+
+// MergeQueueCandidateBranchPrefix is the prefix of the branches of the candidates created by the merge queues
+const MergeQueueCandidateBranchPrefix = "cds-merge-queue/"
+
+// MergeQueueCandidateBranch returns the name of the branch of the candidate of a pull request
+func MergeQueueCandidateBranch(base string, pullRequestID int) string {
+	return fmt.Sprintf("%s%s/pr-%d", MergeQueueCandidateBranchPrefix, base, pullRequestID)
+}
+
+// IsMergeQueueCandidateBranch returns true if the branch, or its ref, is the branch of a merge queue candidate
+func IsMergeQueueCandidateBranch(branch string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(branch, "refs/heads/"), MergeQueueCandidateBranchPrefix)
+}
+
+// MergeQueueStatusContext returns the context of the statuses set by the merge queue on the pull requests
+func MergeQueueStatusContext(projKey, workflowName string) string {
+	return fmt.Sprintf("CDS/%s-%s-merge-queue", projKey, workflowName)
+}
+
+// MergeQueueConfig returns the merge method and the depth of the merge queue of a repository hook.
+// The merge method is empty if the merge queue is disabled.
+func MergeQueueConfig(config WorkflowNodeHookConfig) (string, int) {
+	method := config[HookConfigMergeQueue].Value
+	switch method {
+	case VCSMergeMethodMerge, VCSMergeMethodSquash, VCSMergeMethodRebase:
+	default:
+		return "", 0
+	}
+	depth, err := strconv.Atoi(config[HookConfigMergeQueueDepth].Value)
+	if err != nil || depth < 1 {
+		depth = MergeQueueDefaultDepth
+	}
+	return method, depth
+}
+
+// EventMergeQueue is sent to the repository manager to set the status of a pull request in a merge queue
+type EventMergeQueue struct {
+	RepositoryFullName string `json:"repository_full_name"`
+	Hash               string `json:"hash"`
+	PullRequestID      int    `json:"pull_request_id"`
+	Status             string `json:"status"`
+	Description        string `json:"description"`
+	WorkflowRunNumber  int64  `json:"workflow_run_number,omitempty"`
+}
+
+// BuildStatus returns the status of the build of the pull request matching the status of the entry
+func (e EventMergeQueue) BuildStatus() string {
+	switch e.Status {
+	case MergeQueueStatusMerged:
+		return StatusSuccess
+	case MergeQueueStatusEjected:
+		return StatusFail
+	case MergeQueueStatusRemoved:
+		return StatusStopped
+	}
+	return StatusBuilding
+}
@@ -0,0 +1,32 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeQueueConfig(t *testing.T) {
+	method, _ := MergeQueueConfig(WorkflowNodeHookConfig{})
+	assert.Equal(t, "", method)
+
+	method, _ = MergeQueueConfig(WorkflowNodeHookConfig{HookConfigMergeQueue: {Value: HookMergeQueueDisabled}})
+	assert.Equal(t, "", method)
+
+	method, depth := MergeQueueConfig(WorkflowNodeHookConfig{HookConfigMergeQueue: {Value: VCSMergeMethodSquash}})
+	assert.Equal(t, VCSMergeMethodSquash, method)
+	assert.Equal(t, MergeQueueDefaultDepth, depth)
+
+	_, depth = MergeQueueConfig(WorkflowNodeHookConfig{
+		HookConfigMergeQueue:      {Value: VCSMergeMethodMerge},
+		HookConfigMergeQueueDepth: {Value: "3"},
+	})
+	assert.Equal(t, 3, depth)
+}
+
+func TestIsMergeQueueCandidateBranch(t *testing.T) {
+	assert.True(t, IsMergeQueueCandidateBranch(MergeQueueCandidateBranch("master", 7)))
+	assert.True(t, IsMergeQueueCandidateBranch("refs/heads/"+MergeQueueCandidateBranch("release/1.0", 7)))
+	assert.False(t, IsMergeQueueCandidateBranch("master"))
+	assert.False(t, IsMergeQueueCandidateBranch("feat/cds-merge-queue/login"))
+}
@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Quota limits the resources used by the jobs of a project, or by the jobs of all the projects owned by a group.
+// A zero value means no limit. Memory is expressed in megabytes like memory requirements.
+type Quota struct {
+	ID                int64  `json:"id" db:"id" cli:"id,key"`
+	ProjectID         *int64 `json:"project_id,omitempty" db:"project_id" cli:"-"`
+	GroupID           *int64 `json:"group_id,omitempty" db:"group_id" cli:"-"`
+	MaxJobs           int64  `json:"max_jobs" db:"max_jobs" cli:"max_jobs"`
+	MaxWorkersByModel int64  `json:"max_workers_by_model" db:"max_workers_by_model" cli:"max_workers_by_model"`
+	MaxMemory         int64  `json:"max_memory" db:"max_memory" cli:"max_memory"`
+	// aggregates
+	ProjectKey string `json:"project_key,omitempty" db:"-" cli:"project"`
+	GroupName  string `json:"group_name,omitempty" db:"-" cli:"group"`
+}
+
+// IsValid returns an error if the quota is not attached to exactly one project or group, or if a limit is negative.
+func (q Quota) IsValid() error {
+	if (q.ProjectKey == "") == (q.GroupName == "") {
+		return NewErrorFrom(ErrWrongRequest, "a quota should be attached to a project or to a group")
+	}
+	if q.MaxJobs < 0 || q.MaxWorkersByModel < 0 || q.MaxMemory < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid negative quota limit")
+	}
+	return nil
+}
+
+// String returns the target of the quota.
+func (q Quota) String() string {
+	if q.GroupName != "" {
+		return fmt.Sprintf("group %s", q.GroupName)
+	}
+	return fmt.Sprintf("project %s", q.ProjectKey)
+}
+
+// QuotaUsage is the current usage of a quota, building jobs and jobs booked by a hatchery are counted.
+type QuotaUsage struct {
+	Quota          Quota            `json:"quota"`
+	Jobs           int64            `json:"jobs" cli:"jobs"`
+	WorkersByModel map[string]int64 `json:"workers_by_model,omitempty"`
+	Memory         int64            `json:"memory" cli:"memory"`
+}
+
+// Add counts given job in the usage.
+func (u *QuotaUsage) Add(j WorkflowNodeJobRun) {
+	u.Jobs++
+	u.Memory += JobMemory(j)
+	if j.Model != "" {
+		if u.WorkersByModel == nil {
+			u.WorkersByModel = make(map[string]int64)
+		}
+		u.WorkersByModel[j.Model]++
+	}
+}
+
+// Check returns an error if starting given job with given worker model would exceed the quota,
+// the model is not checked if empty.
+func (u QuotaUsage) Check(j WorkflowNodeJobRun, model string) error {
+	if u.Quota.MaxJobs > 0 && u.Jobs+1 > u.Quota.MaxJobs {
+		return NewErrorFrom(ErrJobQuotaExceeded, "%d/%d jobs running for %s", u.Jobs, u.Quota.MaxJobs, u.Quota)
+	}
+	if mem := JobMemory(j); u.Quota.MaxMemory > 0 && u.Memory+mem > u.Quota.MaxMemory {
+		return NewErrorFrom(ErrJobQuotaExceeded, "%dMB/%dMB of memory used by %s, the job needs %dMB", u.Memory, u.Quota.MaxMemory, u.Quota, mem)
+	}
+	if model != "" && u.Quota.MaxWorkersByModel > 0 && u.WorkersByModel[model]+1 > u.Quota.MaxWorkersByModel {
+		return NewErrorFrom(ErrJobQuotaExceeded, "%d/%d workers with model %s running for %s", u.WorkersByModel[model], u.Quota.MaxWorkersByModel, model, u.Quota)
+	}
+	return nil
+}
+
+// JobMemory returns the memory requirement of a job in megabytes, or 0 if the job doesn't have one.
+func JobMemory(j WorkflowNodeJobRun) int64 {
+	for _, r := range j.Job.Action.Requirements {
+		if r.Type == MemoryRequirement {
+			mem, err := strconv.ParseInt(r.Value, 10, 64)
+			if err == nil {
+				return mem
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,45 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota_IsValid(t *testing.T) {
+	assert.Error(t, Quota{}.IsValid())
+	assert.Error(t, Quota{ProjectKey: "PROJ", GroupName: "grp"}.IsValid())
+	assert.Error(t, Quota{ProjectKey: "PROJ", MaxJobs: -1}.IsValid())
+	assert.NoError(t, Quota{ProjectKey: "PROJ", MaxJobs: 10}.IsValid())
+	assert.NoError(t, Quota{GroupName: "grp"}.IsValid())
+}
+
+func TestQuotaUsage_Check(t *testing.T) {
+	jobWithMemory := func(model, mem string) WorkflowNodeJobRun {
+		j := WorkflowNodeJobRun{Model: model}
+		if mem != "" {
+			j.Job.Action.Requirements = []Requirement{{Name: mem, Type: MemoryRequirement, Value: mem}}
+		}
+		return j
+	}
+
+	u := QuotaUsage{Quota: Quota{ProjectKey: "PROJ", MaxJobs: 3, MaxWorkersByModel: 1, MaxMemory: 4096}}
+	u.Add(jobWithMemory("docker", "2048"))
+	assert.Equal(t, int64(1), u.Jobs)
+	assert.Equal(t, int64(2048), u.Memory)
+	assert.Equal(t, int64(1), u.WorkersByModel["docker"])
+
+	assert.NoError(t, u.Check(jobWithMemory("", "1024"), ""), "the model is unknown when booking a job")
+	err := u.Check(jobWithMemory("docker", ""), "docker")
+	assert.True(t, ErrorIs(err, ErrJobQuotaExceeded))
+	assert.NoError(t, u.Check(jobWithMemory("", ""), "openstack"))
+
+	err = u.Check(jobWithMemory("", "4096"), "")
+	assert.True(t, ErrorIs(err, ErrJobQuotaExceeded), "the job would exceed the memory quota")
+
+	u.Add(jobWithMemory("openstack", ""))
+	u.Add(jobWithMemory("vsphere", ""))
+	err = u.Check(jobWithMemory("", ""), "")
+	assert.True(t, ErrorIs(err, ErrJobQuotaExceeded))
+	assert.Contains(t, err.Error(), "3/3 jobs running for project PROJ")
+}
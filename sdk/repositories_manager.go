@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"strings"
 	"time"
 )
 
@@ -95,6 +96,15 @@ type VCSPullRequest struct {
 	Merged   bool         `json:"merged"`
 	Closed   bool         `json:"closed"`
 	Revision string       `json:"revision"`
+	Labels   []string     `json:"labels,omitempty"`
+	// MergeRef is the ref of the merge commit computed by the repository manager, empty if it is not supported
+	MergeRef string `json:"merge_ref,omitempty"`
+}
+
+// IsFork returns true if the head branch of the pull request comes from another repository.
+// A pull request with an unknown head repository is considered as a fork.
+func (pr VCSPullRequest) IsFork() bool {
+	return pr.Head.Repo == "" || !strings.EqualFold(pr.Head.Repo, pr.Base.Repo)
 }
 
 type VCSPullRequestCommentRequest struct {
@@ -102,12 +112,36 @@ type VCSPullRequestCommentRequest struct {
 	Message string `json:"message"`
 }
 
+// Merge methods of the pull requests
+const (
+	VCSMergeMethodMerge  = "merge"
+	VCSMergeMethodSquash = "squash"
+	VCSMergeMethodRebase = "rebase"
+)
+
+// VCSPullRequestMergeRequest represents the merge of a pull request
+type VCSPullRequestMergeRequest struct {
+	Method  string `json:"method"`
+	Message string `json:"message,omitempty"`
+	// Hash is the head commit expected for the pull request, the merge fails if the pull request was updated
+	Hash string `json:"hash,omitempty"`
+}
+
+// VCSBranchMergeRequest represents the merge of a commit in a branch
+type VCSBranchMergeRequest struct {
+	Branch  string `json:"branch"`
+	Head    string `json:"head"`
+	Message string `json:"message,omitempty"`
+}
+
 //VCSPushEvent represents a push events for polling
 type VCSPushEvent struct {
 	Repo     string    `json:"repo"`
 	Branch   VCSBranch `json:"branch"`
 	Commit   VCSCommit `json:"commit"`
 	CloneURL string    `json:"clone_url"`
+	// Before is the hash of the branch before the first push, if known
+	Before string `json:"before,omitempty"`
 }
 
 //VCSCreateEvent represents a push events for polling
@@ -120,13 +154,31 @@ type VCSDeleteEvent struct {
 
 //VCSPullRequestEvent represents a push events for polling
 type VCSPullRequestEvent struct {
-	Action string       `json:"action"` // opened | closed
-	URL    string       `json:"url"`
-	Repo   string       `json:"repo"`
-	User   VCSAuthor    `json:"user"`
-	Head   VCSPushEvent `json:"head"`
-	Base   VCSPushEvent `json:"base"`
-	Branch VCSBranch    `json:"branch"`
+	Action   string       `json:"action"` // opened | closed
+	ID       int          `json:"id"`
+	Title    string       `json:"title"`
+	URL      string       `json:"url"`
+	Repo     string       `json:"repo"`
+	User     VCSAuthor    `json:"user"`
+	Head     VCSPushEvent `json:"head"`
+	Base     VCSPushEvent `json:"base"`
+	Branch   VCSBranch    `json:"branch"`
+	Labels   []string     `json:"labels,omitempty"`
+	MergeRef string       `json:"merge_ref,omitempty"`
+}
+
+// PullRequest returns the pull request of the event
+func (e VCSPullRequestEvent) PullRequest() VCSPullRequest {
+	return VCSPullRequest{
+		ID:       e.ID,
+		URL:      e.URL,
+		User:     e.User,
+		Head:     e.Head,
+		Base:     e.Base,
+		Title:    e.Title,
+		Labels:   e.Labels,
+		MergeRef: e.MergeRef,
+	}
 }
 
 // VCSHook represents a hook on a VCS repository
@@ -141,6 +193,7 @@ type VCSHook struct {
 	Disable     bool     `json:"disable"`
 	InsecureSSL bool     `json:"insecure_ssl"`
 	Workflow    bool     `json:"workflow"`
+	Secret      string   `json:"secret,omitempty"`
 }
 
 // VCSCommitStatus represents a status on a VCS repository
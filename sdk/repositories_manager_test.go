@@ -0,0 +1,25 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVCSPullRequestIsFork(t *testing.T) {
+	pr := VCSPullRequest{
+		Head: VCSPushEvent{Repo: "ovh/cds"},
+		Base: VCSPushEvent{Repo: "OVH/cds"},
+	}
+	assert.False(t, pr.IsFork())
+
+	pr.Head.Repo = "john/cds"
+	assert.True(t, pr.IsFork())
+
+	// Unknown repositories can't be trusted
+	pr.Head.Repo = ""
+	assert.True(t, pr.IsFork())
+	pr.Head.Repo = "ovh/cds"
+	pr.Base.Repo = ""
+	assert.True(t, pr.IsFork())
+}
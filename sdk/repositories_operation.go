@@ -14,6 +14,7 @@ type Operation struct {
 	RepositoryStrategy RepositoryStrategy       `json:"strategy,omitempty"`
 	Setup              OperationSetup           `json:"setup,omitempty"`
 	LoadFiles          OperationLoadFiles       `json:"load_files,omitempty"`
+	LoadChanges        OperationLoadChanges     `json:"load_changes,omitempty"`
 	Status             OperationStatus          `json:"status"`
 	Error              string                   `json:"error,omitempty"`
 	RepositoryInfo     *OperationRepositoryInfo `json:"repository_info,omitempty"`
@@ -44,6 +45,12 @@ type OperationLoadFiles struct {
 	Results map[string][]byte `json:"results,omitempty"`
 }
 
+// OperationLoadChanges represents the loading of the files changed by a list of commits
+type OperationLoadChanges struct {
+	Commits []string `json:"commits,omitempty"`
+	Results []string `json:"results,omitempty"`
+}
+
 // OperationCheckout represents a smart git checkout
 type OperationCheckout struct {
 	Tag    string `json:"tag,omitempty"`
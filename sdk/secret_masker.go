@@ -0,0 +1,82 @@
+package sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SecretMasker replaces secret values in a text by PasswordPlaceholder. The base64, URL and JSON encoded forms
+// of secrets are also masked, multi-line secrets (like private keys) are masked line by line.
+type SecretMasker struct {
+	replacer *strings.Replacer
+}
+
+// NewSecretMasker returns a masker for given secret values, values shorter than SecretMinLength are ignored.
+func NewSecretMasker(values ...string) *SecretMasker {
+	forms := make(map[string]struct{})
+	add := func(s string) {
+		if len(s) >= SecretMinLength {
+			forms[s] = struct{}{}
+		}
+	}
+
+	for _, v := range values {
+		if len(v) < SecretMinLength {
+			continue
+		}
+		candidates := []string{v}
+		if strings.Contains(v, "\n") {
+			for _, l := range strings.Split(v, "\n") {
+				candidates = append(candidates, strings.TrimSpace(l))
+			}
+		}
+		for _, c := range candidates {
+			if len(c) < SecretMinLength {
+				continue
+			}
+			add(c)
+			add(base64.StdEncoding.EncodeToString([]byte(c)))
+			add(base64.RawStdEncoding.EncodeToString([]byte(c)))
+			add(base64.URLEncoding.EncodeToString([]byte(c)))
+			add(base64.RawURLEncoding.EncodeToString([]byte(c)))
+			add(url.QueryEscape(c))
+			add(url.PathEscape(c))
+			if b, err := json.Marshal(c); err == nil {
+				add(string(b[1 : len(b)-1]))
+			}
+		}
+	}
+
+	if len(forms) == 0 {
+		return &SecretMasker{}
+	}
+
+	// At a given position the replacer uses the first matching value, so longest values must come first
+	sorted := make([]string, 0, len(forms))
+	for f := range forms {
+		sorted = append(sorted, f)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+	oldnew := make([]string, 0, 2*len(sorted))
+	for _, f := range sorted {
+		oldnew = append(oldnew, f, PasswordPlaceholder)
+	}
+
+	return &SecretMasker{replacer: strings.NewReplacer(oldnew...)}
+}
+
+// Mask returns given text with all secrets replaced by PasswordPlaceholder.
+func (m *SecretMasker) Mask(s string) string {
+	if m == nil || m.replacer == nil {
+		return s
+	}
+	return m.replacer.Replace(s)
+}
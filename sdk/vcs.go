@@ -63,6 +63,12 @@ type VCSAuthorizedClient interface {
 	PullRequests(context.Context, string) ([]VCSPullRequest, error)
 	PullRequestComment(context.Context, string, VCSPullRequestCommentRequest) error
 	PullRequestCreate(context.Context, string, VCSPullRequest) (VCSPullRequest, error)
+	PullRequestMerge(ctx context.Context, repo string, id int, merge VCSPullRequestMergeRequest) error
+
+	// Merge queue
+	CreateBranch(ctx context.Context, repo, branch, hash string) error
+	DeleteBranch(ctx context.Context, repo, branch string) error
+	MergeBranch(ctx context.Context, repo string, merge VCSBranchMergeRequest) (VCSCommit, error)
 
 	//Hooks
 	CreateHook(ctx context.Context, repo string, hook *VCSHook) error
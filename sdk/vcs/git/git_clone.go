@@ -19,6 +19,7 @@ type CloneOpts struct {
 	Verbose                 bool
 	Quiet                   bool
 	CheckoutCommit          string
+	MergeRef                string
 	NoStrictHostKeyChecking bool
 	ForceGetGitDescribe     bool
 }
@@ -97,6 +98,25 @@ func prepareGitCloneCommands(repo, workdirPath, path string, opts *CloneOpts) (s
 
 	allCmd = append(allCmd, gitcmd)
 
+	// if a merge ref is given, the cloned branch is the base of the pull request and the merge commit is checked out
+	// the commit hash is ignored as it is the head of the pull request
+	if opts != nil && opts.MergeRef != "" && (opts.Tag == "" || opts.Tag == sdk.DefaultGitCloneParameterTagValue) {
+		fetchCmd := cmd{
+			cmd:     "git",
+			args:    []string{"fetch", "origin", opts.MergeRef},
+			workdir: cloneWorkdir(repo, workdirPath, path),
+		}
+		resetCmd := cmd{
+			cmd:     "git",
+			args:    []string{"reset", "--hard", "FETCH_HEAD"},
+			workdir: cloneWorkdir(repo, workdirPath, path),
+		}
+		userLogCommand += "\n\rExecuting: git " + strings.Join(fetchCmd.args, " ")
+		userLogCommand += "\n\rExecuting: git " + strings.Join(resetCmd.args, " ")
+		allCmd = append(allCmd, fetchCmd, resetCmd)
+		return userLogCommand, cmds(allCmd), nil
+	}
+
 	// if a specific commit hash is given, try to reset current repo to this commit
 	// when a tag is given the commit hash is ignored
 	if opts != nil && opts.CheckoutCommit != "" && opts.Tag == "" {
@@ -109,14 +129,7 @@ func prepareGitCloneCommands(repo, workdirPath, path string, opts *CloneOpts) (s
 			}
 			userLogCommand += "\n\rExecuting: git " + strings.Join(fetchCmd.args, " ")
 			//Locate the git reset cmd to the right directory
-			if path == "" {
-				t := strings.Split(repo, "/")
-				fetchCmd.workdir = filepath.Join(workdirPath, strings.TrimSuffix(t[len(t)-1], ".git"))
-			} else if strings.HasPrefix(path, "/") {
-				fetchCmd.workdir = path
-			} else {
-				fetchCmd.workdir = filepath.Join(workdirPath, path)
-			}
+			fetchCmd.workdir = cloneWorkdir(repo, workdirPath, path)
 
 			allCmd = append(allCmd, fetchCmd)
 		}
@@ -128,17 +141,22 @@ func prepareGitCloneCommands(repo, workdirPath, path string, opts *CloneOpts) (s
 		}
 		userLogCommand += "\n\rExecuting: git " + strings.Join(resetCmd.args, " ")
 		// locate the git reset cmd to the right directory
-		if path == "" {
-			t := strings.Split(repo, "/")
-			resetCmd.workdir = filepath.Join(workdirPath, strings.TrimSuffix(t[len(t)-1], ".git"))
-		} else if strings.HasPrefix(path, "/") {
-			resetCmd.workdir = path
-		} else {
-			resetCmd.workdir = filepath.Join(workdirPath, path)
-		}
+		resetCmd.workdir = cloneWorkdir(repo, workdirPath, path)
 
 		allCmd = append(allCmd, resetCmd)
 	}
 
 	return userLogCommand, cmds(allCmd), nil
 }
+
+// cloneWorkdir returns the directory of the cloned repository
+func cloneWorkdir(repo, workdirPath, path string) string {
+	if path == "" {
+		t := strings.Split(repo, "/")
+		return filepath.Join(workdirPath, strings.TrimSuffix(t[len(t)-1], ".git"))
+	}
+	if strings.HasPrefix(path, "/") {
+		return path
+	}
+	return filepath.Join(workdirPath, path)
+}
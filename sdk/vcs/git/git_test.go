@@ -227,6 +227,24 @@ func Test_gitCommand(t *testing.T) {
 				"git reset --hard eb8b87a",
 			},
 		},
+		{
+			name: "Clone public repo over http with the merge ref of a pull request",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "tmp/Test_gitCommand-4",
+				opts: &CloneOpts{
+					Branch:         "master",
+					Quiet:          true,
+					CheckoutCommit: "eb8b87a",
+					MergeRef:       "refs/pull/42/merge",
+				},
+			},
+			want: []string{
+				"git clone --quiet --branch master https://github.com/ovh/cds.git tmp/Test_gitCommand-4",
+				"git fetch origin refs/pull/42/merge",
+				"git reset --hard FETCH_HEAD",
+			},
+		},
 	}
 	for _, tt := range tests {
 		os.RemoveAll(test.GetTestName(t))
@@ -3,6 +3,8 @@ package sdk
 import (
 	"fmt"
 	"runtime"
+
+	"github.com/blang/semver"
 )
 
 var (
@@ -64,3 +66,17 @@ func VersionCurrent() Version {
 func VersionString() string {
 	return fmt.Sprintf("CDS %s version:%s os:%s architecture:%s git.hash:%s build.time:%s db.migrate:%s", BINARY, VERSION, GOOS, GOARCH, GITHASH, BUILDTIME, DBMIGRATE)
 }
+
+// VersionIsOlder returns true if version v is older than version ref. Versions that are not semver
+// like snapshot builds are never considered as older.
+func VersionIsOlder(v, ref string) bool {
+	sv, err := semver.ParseTolerant(v)
+	if err != nil {
+		return false
+	}
+	sr, err := semver.ParseTolerant(ref)
+	if err != nil {
+		return false
+	}
+	return sv.LT(sr)
+}
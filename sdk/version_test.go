@@ -0,0 +1,17 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionIsOlder(t *testing.T) {
+	assert.True(t, VersionIsOlder("0.45.3", "0.46.0"))
+	assert.True(t, VersionIsOlder("v0.45.3", "0.46.0"))
+	assert.True(t, VersionIsOlder("0.46.0-rc.1", "0.46.0"))
+	assert.False(t, VersionIsOlder("0.46.0", "0.46.0"))
+	assert.False(t, VersionIsOlder("0.47.0", "0.46.0"))
+	assert.False(t, VersionIsOlder("snapshot", "0.46.0"))
+	assert.False(t, VersionIsOlder("0.46.0", ""))
+}
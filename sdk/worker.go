@@ -22,6 +22,13 @@ type Worker struct {
 	Arch       string    `json:"arch" cli:"arch"  db:"arch"`
 }
 
+// WorkerVersion contains the worker versions advertised by the API. Workers older than the min version are rejected
+// at registration, workers older than the recommended version update themselves before taking a job.
+type WorkerVersion struct {
+	MinVersion         string `json:"min_version,omitempty"`
+	RecommendedVersion string `json:"recommended_version,omitempty"`
+}
+
 // WorkerRegistrationForm represents the arguments needed to register a worker
 type WorkerRegistrationForm struct {
 	BinaryCapabilities []string
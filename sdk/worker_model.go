@@ -3,6 +3,8 @@ package sdk
 import (
 	"fmt"
 	"time"
+
+	yaml "gopkg.in/yaml.v2"
 )
 
 // Existing worker model type
@@ -112,6 +114,12 @@ func (m Model) IsValidType() error {
 		if m.PatternName == "" && (m.ModelDocker.Cmd == "" || m.ModelDocker.Shell == "") {
 			return WrapError(ErrWrongRequest, "invalid worker model command or shell command")
 		}
+		if m.ModelDocker.PodTemplate != "" {
+			var tmpl map[string]interface{}
+			if err := yaml.Unmarshal([]byte(m.ModelDocker.PodTemplate), &tmpl); err != nil {
+				return NewErrorFrom(ErrWrongRequest, "invalid worker model pod template: %v", err)
+			}
+		}
 	case Openstack:
 		if m.ModelVirtualMachine.Image == "" {
 			return WrapError(ErrWrongRequest, "invalid worker model image")
@@ -145,11 +153,13 @@ func (m Model) GetPath(groupName string) string {
 
 // ModelVirtualMachine for openstack or vsphere
 type ModelVirtualMachine struct {
-	Image   string `json:"image,omitempty"`
-	Flavor  string `json:"flavor,omitempty"`
-	PreCmd  string `json:"pre_cmd,omitempty"`
-	Cmd     string `json:"cmd,omitempty"`
-	PostCmd string `json:"post_cmd,omitempty"`
+	Image  string `json:"image,omitempty"`
+	Flavor string `json:"flavor,omitempty"`
+	// Provision is a script run once on the base image when the hatchery builds the image of the model
+	Provision string `json:"provision,omitempty"`
+	PreCmd    string `json:"pre_cmd,omitempty"`
+	Cmd       string `json:"cmd,omitempty"`
+	PostCmd   string `json:"post_cmd,omitempty"`
 }
 
 // ModelDocker for swarm, marathon and kubernetes
@@ -163,6 +173,8 @@ type ModelDocker struct {
 	Envs     map[string]string `json:"envs,omitempty"`
 	Shell    string            `json:"shell,omitempty"`
 	Cmd      string            `json:"cmd,omitempty"`
+	// PodTemplate is a Kubernetes pod manifest in yaml merged with the worker pods by the kubernetes hatchery
+	PodTemplate string `json:"pod_template,omitempty"`
 }
 
 // ModelPattern represent patterns for users and admin when creating a worker model
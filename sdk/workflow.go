@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -35,6 +36,7 @@ type Workflow struct {
 	HistoryLength           int64                        `json:"history_length" db:"history_length" cli:"-"`
 	PurgeTags               []string                     `json:"purge_tags,omitempty" db:"-" cli:"-"`
 	Notifications           []WorkflowNotification       `json:"notifications,omitempty" db:"-" cli:"-"`
+	Concurrency             *WorkflowConcurrency         `json:"concurrency,omitempty" db:"-" cli:"-"`
 	FromRepository          string                       `json:"from_repository,omitempty" db:"from_repository" cli:"from"`
 	DerivedFromWorkflowID   int64                        `json:"derived_from_workflow_id,omitempty" db:"derived_from_workflow_id" cli:"-"`
 	DerivedFromWorkflowName string                       `json:"derived_from_workflow_name,omitempty" db:"derived_from_workflow_name" cli:"-"`
@@ -59,6 +61,23 @@ type Workflow struct {
 	URLs             URL                       `json:"urls" yaml:"-" db:"-" cli:"-"`
 }
 
+// WorkflowConcurrency defines a concurrency group for the runs of a workflow or of a node.
+// Group is interpolated with the build parameters of the run, runs with the same resulting group
+// in a project are executed one at a time. If CancelInProgress is true, a new run cancels the
+// runs in progress of its group, else it waits for them to end.
+type WorkflowConcurrency struct {
+	Group            string `json:"group" yaml:"group"`
+	CancelInProgress bool   `json:"cancel_in_progress,omitempty" yaml:"cancel_in_progress,omitempty"`
+}
+
+// IsValid checks the concurrency group definition.
+func (c WorkflowConcurrency) IsValid() error {
+	if strings.TrimSpace(c.Group) == "" {
+		return NewErrorFrom(ErrWrongRequest, "invalid concurrency: group is mandatory")
+	}
+	return nil
+}
+
 type Workflows []Workflow
 
 func (workflows Workflows) Names() []string {
@@ -210,10 +229,16 @@ func (w *Workflow) ValidateType() error {
 	return nil
 }
 
-//WorkflowNodeConditions is either an array of WorkflowNodeCondition or a lua script
+//WorkflowNodeConditions is either an array of WorkflowNodeCondition, a lua script or a condition expression
 type WorkflowNodeConditions struct {
 	PlainConditions []WorkflowNodeCondition `json:"plain,omitempty" yaml:"check,omitempty"`
 	LuaScript       string                  `json:"lua_script,omitempty" yaml:"script,omitempty"`
+	Expression      string                  `json:"expression,omitempty" yaml:"expression,omitempty"`
+}
+
+// IsEmpty returns true if there is no condition.
+func (w WorkflowNodeConditions) IsEmpty() bool {
+	return len(w.PlainConditions) == 0 && w.LuaScript == "" && w.Expression == ""
 }
 
 // Value returns driver.Value from WorkflowNodeConditions request.
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/ovh/cds/sdk/condition"
 	"github.com/ovh/cds/sdk/interpolate"
 )
 
@@ -84,3 +85,16 @@ func WorkflowCheckConditions(conditions []WorkflowNodeCondition, params []Parame
 
 	return conditionsOK, nil
 }
+
+//WorkflowCheckConditionExpression checks a condition expression given a list of parameters
+func WorkflowCheckConditionExpression(expression string, params []Parameter) (bool, error) {
+	mapParams := ParametersToMap(params)
+	for k, v := range mapParams {
+		var err error
+		mapParams[k], err = interpolate.Do(v, mapParams)
+		if err != nil {
+			return false, fmt.Errorf("Unable to interpolate %s (%v)", v, err)
+		}
+	}
+	return condition.Check(expression, mapParams)
+}
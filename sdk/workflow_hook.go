@@ -15,6 +15,7 @@ const (
 	GitHubIcon    = "Github"
 	BitbucketIcon = "Bitbucket"
 	GerritIcon    = "git"
+	GiteaIcon     = "git"
 )
 
 //NodeHook represents a hook which cann trigger the workflow from a given node
@@ -146,6 +147,16 @@ func (cfg WorkflowNodeHookConfig) Clone() WorkflowNodeHookConfig {
 	return m
 }
 
+// WithoutSecrets returns a copy of cfg without the secrets that are never sent to users
+func (cfg WorkflowNodeHookConfig) WithoutSecrets() WorkflowNodeHookConfig {
+	if cfg == nil {
+		return nil
+	}
+	m := cfg.Clone()
+	delete(m, HookConfigWebHookSecret)
+	return m
+}
+
 // WorkflowNodeHookConfigValue represents the value of a node hook config
 type WorkflowNodeHookConfigValue struct {
 	Value              string   `json:"value"`
@@ -52,6 +52,7 @@ type WorkflowRun struct {
 	ToDelete         bool                             `json:"to_delete" db:"to_delete" cli:"-"`
 	JoinTriggersRun  map[int64]WorkflowNodeTriggerRun `json:"join_triggers_run,omitempty" db:"-"`
 	Header           WorkflowRunHeaders               `json:"header,omitempty" db:"-"`
+	ConcurrencyGroup string                           `json:"concurrency_group,omitempty" db:"concurrency_group"`
 }
 
 // WorkflowNodeRunRelease represents the request struct use by release builtin action for workflow
@@ -75,6 +76,54 @@ type WorkflowRunNumber struct {
 	Num int64 `json:"num" cli:"run-number"`
 }
 
+// WorkflowRunPlan is the result of a workflow run request processed in plan mode, nothing is persisted.
+// Jobs are supposed to succeed to know which nodes would be triggered after them.
+type WorkflowRunPlan struct {
+	WorkflowName string                `json:"workflow_name"`
+	Number       int64                 `json:"number"`
+	Nodes        []WorkflowRunPlanNode `json:"nodes"`
+	Infos        []WorkflowRunInfo     `json:"infos,omitempty"`
+}
+
+// Translate translates messages in WorkflowRunPlan
+func (p *WorkflowRunPlan) Translate(lang string) {
+	for ki, info := range p.Infos {
+		m := NewMessage(Messages[info.Message.ID], info.Message.Args...)
+		p.Infos[ki].UserMessage = m.String(lang)
+	}
+}
+
+// WorkflowRunPlanNode describes what would happen for a node of a workflow run processed in plan mode.
+// BlockingCondition is set when the node would not be triggered because of its run conditions.
+type WorkflowRunPlanNode struct {
+	NodeID            int64                      `json:"node_id" cli:"-"`
+	NodeName          string                     `json:"node_name" cli:"node"`
+	NodeType          string                     `json:"node_type" cli:"type"`
+	Triggered         bool                       `json:"triggered" cli:"triggered"`
+	Status            string                     `json:"status,omitempty" cli:"status"`
+	BlockingCondition string                     `json:"blocking_condition,omitempty" cli:"blocking_condition"`
+	Conditions        []WorkflowRunPlanCondition `json:"conditions,omitempty" cli:"-"`
+	BuildParameters   []Parameter                `json:"build_parameters,omitempty" cli:"-"`
+	Jobs              []WorkflowRunPlanJob       `json:"jobs,omitempty" cli:"-"`
+}
+
+// WorkflowRunPlanCondition is the result of a node run condition.
+type WorkflowRunPlanCondition struct {
+	Condition string `json:"condition"`
+	Result    bool   `json:"result"`
+	Error     string `json:"error,omitempty"`
+}
+
+// WorkflowRunPlanJob describes a job that would be added to the queue by a workflow run processed in plan mode.
+type WorkflowRunPlanJob struct {
+	Stage        string        `json:"stage"`
+	Name         string        `json:"name"`
+	Status       string        `json:"status"`
+	Model        string        `json:"model,omitempty"`
+	Requirements []Requirement `json:"requirements,omitempty"`
+	Errors       []string      `json:"errors,omitempty"`
+}
+
 // Translate translates messages in WorkflowNodeRun
 func (r *WorkflowRun) Translate(lang string) {
 	for ki, info := range r.Infos {
@@ -246,6 +295,7 @@ type WorkflowNodeRun struct {
 	HookExecutionID        string                               `json:"execution_id,omitempty"`
 	Callback               *WorkflowNodeOutgoingHookRunCallback `json:"callback,omitempty"`
 	VCSReport              string                               `json:"vcs_report,omitempty"`
+	ConcurrencyGroup       string                               `json:"concurrency_group,omitempty"`
 }
 
 // WorkflowNodeOutgoingHookRunCallback is the callback coming from hooks uservice avec an outgoing hook execution
@@ -374,6 +424,7 @@ type WorkflowNodeJobRun struct {
 	ContainsService           bool               `json:"contains_service,omitempty"`
 	HatcheryName              string             `json:"hatchery_name,omitempty"`
 	WorkerName                string             `json:"worker_name,omitempty"`
+	Priority                  int64              `json:"priority,omitempty"`
 }
 
 // WorkflowNodeJobRunSummary is a light representation of WorkflowNodeJobRun for CDS event
@@ -465,19 +516,29 @@ func (w *WorkflowNodeRunArtifact) GetPath() string {
 
 type WorkflowQueue []WorkflowNodeJobRun
 
+// Sort orders the queue by priority while sharing it between projects: each job of a project
+// lowers by one the priority of its next jobs, so a project with a lot of waiting jobs can't starve
+// the other ones. Jobs with the same score are sorted by queued date.
 func (q WorkflowQueue) Sort() {
-	//Count the number of WorkflowNodeJobRun per project_id
-	n := make(map[int64]int, len(q))
+	sort.SliceStable(q, func(i, j int) bool {
+		if q[i].Priority != q[j].Priority {
+			return q[i].Priority > q[j].Priority
+		}
+		return q[i].Queued.Before(q[j].Queued)
+	})
+
+	scores := make(map[int64]int64, len(q))
+	ranks := make(map[int64]int64)
 	for _, j := range q {
-		nb := n[j.ProjectID]
-		nb++
-		n[j.ProjectID] = nb
+		scores[j.ID] = j.Priority - ranks[j.ProjectID]
+		ranks[j.ProjectID]++
 	}
 
-	sort.Slice(q, func(i, j int) bool {
-		p1 := n[q[i].ProjectID]
-		p2 := n[q[j].ProjectID]
-		return p1 < p2
+	sort.SliceStable(q, func(i, j int) bool {
+		si, sj := scores[q[i].ID], scores[q[j].ID]
+		if si != sj {
+			return si > sj
+		}
+		return q[i].Queued.Before(q[j].Queued)
 	})
-
 }
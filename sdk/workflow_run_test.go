@@ -186,30 +186,30 @@ func TestWorkflowQueue_Sort(t *testing.T) {
 				},
 			},
 			expected: WorkflowQueue{
-				{
-					ProjectID:     2,
-					ID:            3,
-					Queued:        t12,
-					QueuedSeconds: now.Unix() - t12.Unix(),
-				},
-				{
-					ProjectID:     2,
-					ID:            6,
-					Queued:        t15,
-					QueuedSeconds: now.Unix() - t15.Unix(),
-				},
 				{
 					ProjectID:     1,
 					ID:            1,
 					Queued:        t10,
 					QueuedSeconds: now.Unix() - t10.Unix(),
 				},
+				{
+					ProjectID:     2,
+					ID:            3,
+					Queued:        t12,
+					QueuedSeconds: now.Unix() - t12.Unix(),
+				},
 				{
 					ProjectID:     1,
 					ID:            2,
 					Queued:        t11,
 					QueuedSeconds: now.Unix() - t11.Unix(),
 				},
+				{
+					ProjectID:     2,
+					ID:            6,
+					Queued:        t15,
+					QueuedSeconds: now.Unix() - t15.Unix(),
+				},
 				{
 					ProjectID:     1,
 					ID:            4,
@@ -230,6 +230,25 @@ func TestWorkflowQueue_Sort(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "test sort with priorities",
+			q: WorkflowQueue{
+				{ProjectID: 1, ID: 1, Queued: t10},
+				{ProjectID: 1, ID: 2, Queued: t11},
+				{ProjectID: 1, ID: 3, Queued: t12, Priority: 10},
+				{ProjectID: 2, ID: 4, Queued: t13},
+				{ProjectID: 2, ID: 5, Queued: t14, Priority: -2},
+				{ProjectID: 3, ID: 6, Queued: t15, Priority: 1},
+			},
+			expected: WorkflowQueue{
+				{ProjectID: 1, ID: 3, Queued: t12, Priority: 10},
+				{ProjectID: 3, ID: 6, Queued: t15, Priority: 1},
+				{ProjectID: 2, ID: 4, Queued: t13},
+				{ProjectID: 1, ID: 1, Queued: t10},
+				{ProjectID: 1, ID: 2, Queued: t11},
+				{ProjectID: 2, ID: 5, Queued: t14, Priority: -2},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
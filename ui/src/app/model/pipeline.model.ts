@@ -16,6 +16,7 @@ export class PipelineStatus {
     static NEVER_BUILT = 'Never Built';
     static STOPPED = 'Stopped';
     static PENDING = 'Pending';
+    static TIMEOUT = 'Timeout';
 
     static neverRun(status: string) {
         return status === this.SKIPPED || status === this.NEVER_BUILT || status === this.SKIPPED || status === this.DISABLED;
@@ -27,7 +28,7 @@ export class PipelineStatus {
 
     static isDone(status: string) {
         return status === this.SUCCESS || status === this.STOPPED || status === this.FAIL ||
-            status === this.SKIPPED || status === this.DISABLED;
+            status === this.SKIPPED || status === this.DISABLED || status === this.TIMEOUT;
     }
 }
 
@@ -48,6 +48,7 @@ export class ModelDocker {
 export class ModelVirtualMachine {
     image: string;
     flavor: string;
+    provision: string;
     pre_cmd: string;
     cmd: string;
     post_cmd: string;